@@ -16,8 +16,10 @@ import (
 	"defi-aggregator/api-gateway/internal/middleware"
 	"defi-aggregator/api-gateway/internal/proxy"
 	"defi-aggregator/api-gateway/internal/types"
+	"defi-aggregator/api-gateway/pkg/apikey"
 	"defi-aggregator/api-gateway/pkg/balancer"
 	"defi-aggregator/api-gateway/pkg/config"
+	"defi-aggregator/api-gateway/pkg/ratelimit"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -30,6 +32,8 @@ type Application struct {
 	Proxy        *proxy.ReverseProxy      // 反向代理
 	Handler      *handlers.GatewayHandler // 网关处理器
 	RateLimiter  *middleware.RateLimiter  // 限流器
+	RateStore    ratelimit.Store          // 限流存储
+	APIKeys      *apikey.Resolver         // API Key解析器(未启用时为nil)
 	Server       *http.Server             // HTTP服务器
 	Logger       *logrus.Logger           // 日志记录器
 }
@@ -79,23 +83,31 @@ func NewApplication() (*Application, error) {
 
 	// 6. 初始化限流器
 	logger.Info("初始化限流器...")
-	rateLimiter := middleware.NewRateLimiter(&cfg.RateLimit, logger)
+	rateStore := ratelimit.NewStore(cfg, logger)
+	rateLimiter := middleware.NewRateLimiter(&cfg.RateLimit, rateStore, logger)
+
+	// 7. 初始化API Key解析器
+	var apiKeys *apikey.Resolver
+	if cfg.Security.APIKey.Enabled {
+		logger.Info("初始化API Key解析器...")
+		apiKeys = apikey.NewResolver(&cfg.Security.APIKey, lb, logger)
+	}
 
-	// 7. 初始化网关处理器
+	// 8. 初始化网关处理器
 	logger.Info("初始化网关处理器...")
 	gatewayHandler := handlers.NewGatewayHandler(cfg, reverseProxy, lb, logger)
 
-	// 8. 设置Gin模式
+	// 9. 设置Gin模式
 	if cfg.Server.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
 	}
 
-	// 9. 创建HTTP路由器
-	router := setupRouter(cfg, gatewayHandler, rateLimiter, logger)
+	// 10. 创建HTTP路由器
+	router := setupRouter(cfg, gatewayHandler, rateLimiter, apiKeys, logger)
 
-	// 10. 创建HTTP服务器
+	// 11. 创建HTTP服务器
 	server := &http.Server{
 		Addr:           fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:        router,
@@ -111,6 +123,8 @@ func NewApplication() (*Application, error) {
 		Proxy:        reverseProxy,
 		Handler:      gatewayHandler,
 		RateLimiter:  rateLimiter,
+		RateStore:    rateStore,
+		APIKeys:      apiKeys,
 		Server:       server,
 		Logger:       logger,
 	}, nil
@@ -164,6 +178,16 @@ func (app *Application) Shutdown() error {
 	// 停止健康检查
 	app.LoadBalancer.StopHealthChecks()
 
+	// 关闭限流存储
+	if err := app.RateStore.Close(); err != nil {
+		app.Logger.Warnf("限流存储关闭失败: %v", err)
+	}
+
+	// 停止API Key缓存清理
+	if app.APIKeys != nil {
+		app.APIKeys.Close()
+	}
+
 	app.Logger.Info("API网关已优雅关闭")
 	return nil
 }
@@ -202,7 +226,7 @@ func initLogger(cfg *types.Config) *logrus.Logger {
 
 // setupRouter 设置HTTP路由器
 // 配置中间件栈和路由规则
-func setupRouter(cfg *types.Config, handler *handlers.GatewayHandler, rateLimiter *middleware.RateLimiter, logger *logrus.Logger) *gin.Engine {
+func setupRouter(cfg *types.Config, handler *handlers.GatewayHandler, rateLimiter *middleware.RateLimiter, apiKeys *apikey.Resolver, logger *logrus.Logger) *gin.Engine {
 	router := gin.New()
 
 	// ========================================
@@ -218,8 +242,10 @@ func setupRouter(cfg *types.Config, handler *handlers.GatewayHandler, rateLimite
 	router.Use(middleware.Security())               // 安全头
 	router.Use(middleware.CORS(&cfg.Security.CORS)) // CORS处理
 
-	// 3. 限流中间件
-	router.Use(rateLimiter.RateLimit()) // 限流控制
+	// 3. 限流中间件（认证前检查全局和API Key解析配额，识别API Key或用户后再按身份限流）
+	router.Use(rateLimiter.PreAuthRateLimit(apiKeys))                          // 认证前限流
+	router.Use(middleware.OptionalJWTAuth(&cfg.Security.JWT, apiKeys, logger)) // 可选身份识别
+	router.Use(rateLimiter.RateLimit())                                        // 按身份限流
 
 	// ========================================
 	// 网关自身路由
@@ -265,6 +291,9 @@ func setupRouter(cfg *types.Config, handler *handlers.GatewayHandler, rateLimite
 	router.Any("/api/v1/users/*path", handler.HandleRequest)
 	// 认证相关路由
 	router.Any("/api/v1/auth/*path", handler.HandleRequest)
+	// API Key管理路由
+	router.Any("/api/v1/api-keys", handler.HandleRequest)
+	router.Any("/api/v1/api-keys/*path", handler.HandleRequest)
 	// 交易记录相关路由
 	router.Any("/api/v1/transactions", handler.HandleRequest)
 	router.Any("/api/v1/transactions/*path", handler.HandleRequest)
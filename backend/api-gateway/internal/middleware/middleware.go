@@ -4,19 +4,21 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"defi-aggregator/api-gateway/internal/types"
+	"defi-aggregator/api-gateway/pkg/apikey"
+	"defi-aggregator/api-gateway/pkg/ratelimit"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
 )
 
 // ========================================
@@ -133,6 +135,9 @@ func CORS(config *types.CORSConfig) gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
 		c.Header("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
 		c.Header("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+		if len(config.ExposedHeaders) > 0 {
+			c.Header("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+		}
 
 		// 处理预检请求
 		if c.Request.Method == "OPTIONS" {
@@ -149,10 +154,19 @@ func CORS(config *types.CORSConfig) gin.HandlerFunc {
 // ========================================
 
 // JWTAuth JWT认证中间件
-// 验证JWT令牌的有效性
-func JWTAuth(config *types.JWTConfig, logger *logrus.Logger) gin.HandlerFunc {
+// 验证JWT令牌的有效性；携带X-API-Key时改为API Key认证(apiKeys为nil表示未启用API Key认证)
+func JWTAuth(config *types.JWTConfig, apiKeys *apikey.Resolver, logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetString("request_id")
+		stripInternalHeaders(c.Request)
+
+		// API Key认证优先
+		if c.GetHeader(types.HeaderAPIKey) != "" {
+			if authenticateAPIKey(c, apiKeys, logger) {
+				c.Next()
+			}
+			return
+		}
 
 		// 获取Authorization头
 		authHeader := c.GetHeader(types.HeaderAuthorization)
@@ -184,17 +198,9 @@ func JWTAuth(config *types.JWTConfig, logger *logrus.Logger) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := tokenParts[1]
-
 		// 解析JWT令牌
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(config.SecretKey), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := parseJWTClaims(tokenParts[1], config)
+		if err != nil {
 			logger.Warnf("[%s] JWT令牌验证失败: %v", requestID, err)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, types.APIResponse{
 				Success: false,
@@ -209,42 +215,271 @@ func JWTAuth(config *types.JWTConfig, logger *logrus.Logger) gin.HandlerFunc {
 		}
 
 		// 提取用户信息
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			if userID, exists := claims["user_id"]; exists {
-				c.Set("user_id", userID)
-			}
-			if walletAddr, exists := claims["wallet_address"]; exists {
-				c.Set("wallet_address", walletAddr)
+		setUserContext(c, claims)
+
+		c.Next()
+	}
+}
+
+// OptionalJWTAuth 可选JWT认证中间件
+// 携带有效令牌时提取用户信息供限流等中间件使用，缺少或无效令牌时按匿名请求继续，
+// 是否必须认证由后端服务决定；携带X-API-Key时必须通过API Key认证，无效密钥直接拒绝
+func OptionalJWTAuth(config *types.JWTConfig, apiKeys *apikey.Resolver, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stripInternalHeaders(c.Request)
+
+		if c.GetHeader(types.HeaderAPIKey) != "" {
+			if authenticateAPIKey(c, apiKeys, logger) {
+				c.Next()
 			}
+			return
+		}
+
+		tokenParts := strings.SplitN(c.GetHeader(types.HeaderAuthorization), " ", 2)
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			c.Next()
+			return
 		}
 
+		claims, err := parseJWTClaims(tokenParts[1], config)
+		if err != nil {
+			logger.Debugf("[%s] 忽略无效的JWT令牌: %v", c.GetString("request_id"), err)
+			c.Next()
+			return
+		}
+
+		setUserContext(c, claims)
 		c.Next()
 	}
 }
 
+// parseJWTClaims 验证JWT令牌签名和有效期并返回声明
+func parseJWTClaims(tokenString string, config *types.JWTConfig) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(config.SecretKey), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("令牌无效")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("无效的令牌声明")
+	}
+	return claims, nil
+}
+
+// setUserContext 将令牌中的用户信息设置到上下文
+func setUserContext(c *gin.Context, claims jwt.MapClaims) {
+	if userID, exists := claims["user_id"]; exists {
+		c.Set("user_id", userID)
+	}
+	if walletAddr, exists := claims["wallet_address"]; exists {
+		c.Set("wallet_address", walletAddr)
+	}
+}
+
+// ========================================
+// API Key认证
+// ========================================
+
+// API Key权限范围(与业务逻辑服务保持一致)
+const (
+	apiKeyScopeQuote = "quote" // 报价
+	apiKeyScopeSwap  = "swap"  // 交易构建和交易记录
+	apiKeyScopeStats = "stats" // 统计数据
+)
+
+// apiKeyRouteScope API Key可访问的路由及所需权限范围
+type apiKeyRouteScope struct {
+	pathPrefix string // 路径前缀
+	scope      string // 所需权限范围，为空表示公开接口
+}
+
+// apiKeyRouteScopes API Key路由权限表，按最长前缀匹配
+// 未列出的路由(用户、认证、API Key管理等)只允许JWT登录用户访问
+var apiKeyRouteScopes = []apiKeyRouteScope{
+	{pathPrefix: "/api/v1/tokens", scope: ""},
+	{pathPrefix: "/api/v1/chains", scope: ""},
+	{pathPrefix: "/api/v1/quotes", scope: apiKeyScopeQuote},
+	{pathPrefix: "/api/v1/router/quote", scope: apiKeyScopeQuote},
+	{pathPrefix: "/api/v1/swaps", scope: apiKeyScopeSwap},
+	{pathPrefix: "/api/v1/transactions", scope: apiKeyScopeSwap},
+	{pathPrefix: "/api/v1/router/swap", scope: apiKeyScopeSwap},
+	{pathPrefix: "/api/v1/stats", scope: apiKeyScopeStats},
+}
+
+// authenticateAPIKey 解析X-API-Key并校验路由权限
+// 认证成功时设置调用方身份到上下文并返回true，失败时已写入错误响应
+func authenticateAPIKey(c *gin.Context, apiKeys *apikey.Resolver, logger *logrus.Logger) bool {
+	requestID := c.GetString("request_id")
+
+	if apiKeys == nil {
+		abortAuth(c, http.StatusUnauthorized, types.ErrCodeUnauthorized, "网关未启用API Key认证", nil)
+		return false
+	}
+
+	principal, err := apiKeys.Resolve(c.Request.Context(), c.GetHeader(types.HeaderAPIKey))
+	if err != nil {
+		if rejected, ok := err.(*apikey.RejectedError); ok {
+			logger.Warnf("[%s] API Key认证失败: %s", requestID, rejected.Message)
+			abortAuth(c, http.StatusUnauthorized, types.ErrCodeUnauthorized, rejected.Message, nil)
+			return false
+		}
+		logger.Errorf("[%s] API Key解析失败: %v", requestID, err)
+		abortAuth(c, http.StatusServiceUnavailable, types.ErrCodeServiceUnavailable, "API Key认证服务暂时不可用", nil)
+		return false
+	}
+
+	route := matchAPIKeyRoute(c.Request.URL.Path)
+	if route == nil {
+		abortAuth(c, http.StatusForbidden, types.ErrCodeForbidden, "该接口不支持API Key访问", nil)
+		return false
+	}
+	if route.scope != "" && !principal.HasScope(route.scope) {
+		abortAuth(c, http.StatusForbidden, types.ErrCodeForbidden, "API Key缺少访问该接口的权限范围",
+			map[string]interface{}{"required_scope": route.scope})
+		return false
+	}
+
+	// 同时携带JWT时以API Key身份为准，避免后端按另一个用户处理请求
+	c.Request.Header.Del(types.HeaderAuthorization)
+	setPrincipalHeaders(c.Request, apiKeys.InternalToken(), principal)
+
+	c.Set("api_key_id", principal.KeyID)
+	c.Set("api_key_tier", principal.Tier)
+	c.Set("api_key_scopes", principal.Scopes)
+	c.Set("user_id", principal.UserID)
+	c.Set("wallet_address", principal.WalletAddress)
+	return true
+}
+
+// matchAPIKeyRoute 匹配API Key路由权限，多条规则匹配时取最长前缀
+func matchAPIKeyRoute(path string) *apiKeyRouteScope {
+	var matched *apiKeyRouteScope
+	for i := range apiKeyRouteScopes {
+		route := &apiKeyRouteScopes[i]
+		if strings.HasPrefix(path, route.pathPrefix) && (matched == nil || len(route.pathPrefix) > len(matched.pathPrefix)) {
+			matched = route
+		}
+	}
+	return matched
+}
+
+// internalHeaders 只能由网关设置的内部请求头
+var internalHeaders = []string{
+	types.HeaderInternalAuth,
+	types.HeaderPrincipalUserID,
+	types.HeaderPrincipalKeyID,
+	types.HeaderPrincipalScopes,
+	types.HeaderPrincipalWallet,
+}
+
+// stripInternalHeaders 移除客户端携带的内部请求头，防止伪造网关转发的调用方身份
+func stripInternalHeaders(r *http.Request) {
+	for _, header := range internalHeaders {
+		r.Header.Del(header)
+	}
+}
+
+// setPrincipalHeaders 将API Key调用方身份写入转发请求头
+// 业务逻辑服务校验X-Internal-Token后按该身份处理请求，与JWT登录用户一致
+func setPrincipalHeaders(r *http.Request, internalToken string, principal *apikey.Principal) {
+	r.Header.Set(types.HeaderInternalAuth, internalToken)
+	r.Header.Set(types.HeaderPrincipalUserID, strconv.FormatUint(uint64(principal.UserID), 10))
+	r.Header.Set(types.HeaderPrincipalKeyID, strconv.FormatUint(uint64(principal.KeyID), 10))
+	r.Header.Set(types.HeaderPrincipalScopes, strings.Join(principal.Scopes, ","))
+	if principal.WalletAddress != "" {
+		r.Header.Set(types.HeaderPrincipalWallet, principal.WalletAddress)
+	}
+}
+
+// abortAuth 返回认证错误并终止请求
+func abortAuth(c *gin.Context, status int, code, message string, details map[string]interface{}) {
+	c.AbortWithStatusJSON(status, types.APIResponse{
+		Success: false,
+		Error: &types.APIError{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Timestamp: time.Now().Unix(),
+		RequestID: c.GetString("request_id"),
+	})
+}
+
 // ========================================
 // 限流中间件
 // ========================================
 
+// rateLimitStoreTimeout 单次限流存储操作超时时间
+// 限流存储不可用时不能拖慢请求转发
+const rateLimitStoreTimeout = 200 * time.Millisecond
+
 // RateLimiter 限流中间件
-// 实现基于IP和用户的限流控制
+// 认证前先检查全局配额，并对需要向业务逻辑服务解析的API Key按IP计算配额；
+// 认证后按请求身份(API Key > 认证用户 > IP)计算配额，匹配路由规则的请求额外按身份+路由计算配额，
+// 配额保存在可插拔的限流存储中，多个网关实例共享Redis时配额全局生效
 type RateLimiter struct {
-	config   *types.RateLimitConfig
-	limiters map[string]*rate.Limiter
-	mutex    sync.RWMutex
-	logger   *logrus.Logger
+	config *types.RateLimitConfig // 限流配置
+	store  ratelimit.Store        // 限流存储
+	logger *logrus.Logger         // 日志记录器
+}
+
+// rateLimitCheck 单个限流桶
+type rateLimitCheck struct {
+	key  string           // 限流键
+	rate types.RateConfig // 限流速率
+}
+
+// rateLimitTightest 本次请求已检查的限流桶中剩余配额最少的一个，两阶段共同决定响应头
+type rateLimitTightest struct {
+	result *ratelimit.Result // 限流结果
+	rate   types.RateConfig  // 限流速率
 }
 
 // NewRateLimiter 创建限流中间件
-func NewRateLimiter(config *types.RateLimitConfig, logger *logrus.Logger) *RateLimiter {
+func NewRateLimiter(config *types.RateLimitConfig, store ratelimit.Store, logger *logrus.Logger) *RateLimiter {
 	return &RateLimiter{
-		config:   config,
-		limiters: make(map[string]*rate.Limiter),
-		logger:   logger,
+		config: config,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// PreAuthRateLimit 认证前限流中间件函数
+// 必须在OptionalJWTAuth之前使用：全局配额对所有请求生效；携带未缓存的API Key时先按客户端IP计算配额，
+// 避免大量随机密钥绕过限流直接打到业务逻辑服务和数据库
+func (rl *RateLimiter) PreAuthRateLimit(apiKeys *apikey.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rl.config.Enabled {
+			c.Next()
+			return
+		}
+
+		checks := make([]rateLimitCheck, 0, 2)
+		if rl.config.GlobalRate.Requests > 0 {
+			checks = append(checks, rateLimitCheck{key: "global", rate: rl.config.GlobalRate})
+		}
+		if rawKey := c.GetHeader(types.HeaderAPIKey); rawKey != "" && (apiKeys == nil || !apiKeys.IsCachedValid(rawKey)) {
+			checks = append(checks, rateLimitCheck{key: "ip:" + c.ClientIP(), rate: rl.config.PerIPRate})
+			c.Set("ip_rate_limited", true)
+		}
+
+		if rl.enforce(c, checks) {
+			c.Next()
+		}
 	}
 }
 
 // RateLimit 限流中间件函数
+// 必须在OptionalJWTAuth之后使用，以便按认证用户计算配额
 func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if !rl.config.Enabled {
@@ -252,44 +487,118 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 			return
 		}
 
-		requestID := c.GetString("request_id")
-		clientIP := c.ClientIP()
+		identity, identityRate := rl.identify(c)
 
-		// 检查IP限流
-		if !rl.checkIPRateLimit(clientIP) {
-			rl.logger.Warnf("[%s] IP限流触发: %s", requestID, clientIP)
+		checks := make([]rateLimitCheck, 0, 2)
+		// 认证前已按IP计算过配额的匿名请求不重复计数
+		if !(strings.HasPrefix(identity, "ip:") && c.GetBool("ip_rate_limited")) {
+			checks = append(checks, rateLimitCheck{key: identity, rate: identityRate})
+		}
+		if route := rl.matchRoute(c.Request.URL.Path); route != nil {
+			checks = append(checks, rateLimitCheck{key: identity + ":route:" + route.PathPrefix, rate: route.Rate})
+		}
+
+		if rl.enforce(c, checks) {
+			c.Next()
+		}
+	}
+}
+
+// enforce 依次检查限流桶，超限时返回429并中止请求
+// 返回false表示请求已被拒绝
+func (rl *RateLimiter) enforce(c *gin.Context, checks []rateLimitCheck) bool {
+	requestID := c.GetString("request_id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), rateLimitStoreTimeout)
+	defer cancel()
+
+	// 响应头展示剩余配额最少的限流桶(包括认证前已检查的限流桶)
+	var tightest *rateLimitTightest
+	if value, exists := c.Get("rate_limit_tightest"); exists {
+		tightest = value.(*rateLimitTightest)
+	}
+	for _, check := range checks {
+		result, err := rl.store.Allow(ctx, check.key, ratelimit.Limit{
+			Requests: check.rate.Requests,
+			Period:   check.rate.Duration,
+		})
+		if err != nil {
+			// 限流存储故障时放行，避免限流组件成为单点故障
+			rl.logger.Warnf("[%s] 限流检查失败，放行请求: key=%s, 错误=%v", requestID, check.key, err)
+			continue
+		}
+
+		if !result.Allowed {
+			setRateLimitHeaders(c, result, check.rate)
+			c.Header(types.HeaderRetryAfter, strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
+
+			rl.logger.Warnf("[%s] 限流触发: key=%s, limit=%d/%v", requestID, check.key, check.rate.Requests, check.rate.Duration)
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, types.APIResponse{
 				Success: false,
 				Error: &types.APIError{
 					Code:    types.ErrCodeRateLimitExceeded,
 					Message: "请求频率过高，请稍后再试",
+					Details: map[string]interface{}{
+						"retry_after": ceilSeconds(result.RetryAfter),
+					},
 				},
 				Timestamp: time.Now().Unix(),
 				RequestID: requestID,
 			})
-			return
+			return false
 		}
 
-		c.Next()
+		if tightest == nil || result.Remaining < tightest.result.Remaining {
+			tightest = &rateLimitTightest{result: result, rate: check.rate}
+		}
 	}
+
+	if tightest != nil {
+		c.Set("rate_limit_tightest", tightest)
+		setRateLimitHeaders(c, tightest.result, tightest.rate)
+	}
+	return true
 }
 
-// checkIPRateLimit 检查IP限流
-func (rl *RateLimiter) checkIPRateLimit(ip string) bool {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
-
-	limiter, exists := rl.limiters[ip]
-	if !exists {
-		// 创建新的限流器
-		limiter = rate.NewLimiter(
-			rate.Every(rl.config.PerIPRate.Duration/time.Duration(rl.config.PerIPRate.Requests)),
-			rl.config.PerIPRate.Requests,
-		)
-		rl.limiters[ip] = limiter
+// identify 识别请求身份及其限流速率
+// API Key由API Key认证设置api_key_id和配额等级，用户由OptionalJWTAuth设置user_id，否则按客户端IP
+func (rl *RateLimiter) identify(c *gin.Context) (string, types.RateConfig) {
+	if apiKeyID, exists := c.Get("api_key_id"); exists {
+		rate := rl.config.PerAPIKeyRate
+		if tierRate, ok := rl.config.APIKeyTiers[c.GetString("api_key_tier")]; ok {
+			rate = tierRate
+		}
+		return fmt.Sprintf("apikey:%v", apiKeyID), rate
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%v", userID), rl.config.PerUserRate
 	}
+	return "ip:" + c.ClientIP(), rl.config.PerIPRate
+}
+
+// matchRoute 匹配路由限流规则，多条规则匹配时取最长前缀
+func (rl *RateLimiter) matchRoute(path string) *types.RouteRateConfig {
+	var matched *types.RouteRateConfig
+	for i := range rl.config.RouteRates {
+		rule := &rl.config.RouteRates[i]
+		if strings.HasPrefix(path, rule.PathPrefix) && (matched == nil || len(rule.PathPrefix) > len(matched.PathPrefix)) {
+			matched = rule
+		}
+	}
+	return matched
+}
+
+// setRateLimitHeaders 设置标准限流响应头
+func setRateLimitHeaders(c *gin.Context, result *ratelimit.Result, rate types.RateConfig) {
+	c.Header(types.HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+	c.Header(types.HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	c.Header(types.HeaderRateLimitReset, strconv.FormatInt(ceilSeconds(result.ResetAfter), 10))
+	c.Header(types.HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", rate.Requests, ceilSeconds(rate.Duration)))
+}
 
-	return limiter.Allow()
+// ceilSeconds 将时间间隔向上取整为秒
+func ceilSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
 }
 
 // ========================================
@@ -342,6 +342,19 @@ func (w *responseWriterWrapper) Write(data []byte) (int, error) {
 	return w.ResponseWriter.Write(data)
 }
 
+// Flush 将缓冲数据立即发送给客户端
+// httputil.ReverseProxy对text/event-stream响应逐条刷新，流式报价依赖该方法
+func (w *responseWriterWrapper) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap 返回底层ResponseWriter，供http.ResponseController使用
+func (w *responseWriterWrapper) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // ========================================
 // 工具函数
 // ========================================
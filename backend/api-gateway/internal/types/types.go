@@ -20,6 +20,7 @@ type Config struct {
 	LoadBalancer LoadBalancerConfig `json:"load_balancer"` // 负载均衡配置
 	Monitoring   MonitoringConfig   `json:"monitoring"`    // 监控配置
 	RateLimit    RateLimitConfig    `json:"rate_limit"`    // 限流配置
+	Redis        RedisConfig        `json:"redis"`         // Redis配置
 }
 
 // ServerConfig 服务器基础配置
@@ -81,10 +82,11 @@ type HealthStatus struct {
 
 // SecurityConfig 安全配置
 type SecurityConfig struct {
-	CORS           CORSConfig `json:"cors"`            // CORS配置
-	JWT            JWTConfig  `json:"jwt"`             // JWT配置
-	TLS            TLSConfig  `json:"tls"`             // TLS配置
-	TrustedProxies []string   `json:"trusted_proxies"` // 信任的代理IP
+	CORS           CORSConfig   `json:"cors"`            // CORS配置
+	JWT            JWTConfig    `json:"jwt"`             // JWT配置
+	APIKey         APIKeyConfig `json:"api_key"`         // API Key认证配置
+	TLS            TLSConfig    `json:"tls"`             // TLS配置
+	TrustedProxies []string     `json:"trusted_proxies"` // 信任的代理IP
 }
 
 // CORSConfig CORS配置
@@ -104,6 +106,17 @@ type JWTConfig struct {
 	Issuer    string `json:"issuer"`    // 签发者
 }
 
+// APIKeyConfig API Key认证配置
+// 网关通过业务逻辑服务的内部接口解析X-API-Key，解析结果在本地缓存
+type APIKeyConfig struct {
+	Enabled          bool          `json:"enabled"`            // 是否启用API Key认证
+	InternalToken    string        `json:"-"`                  // 服务间调用令牌（不序列化）
+	CacheTTL         time.Duration `json:"cache_ttl"`          // 有效密钥缓存时间(撤销或轮换后最长生效延迟)
+	NegativeCacheTTL time.Duration `json:"negative_cache_ttl"` // 无效密钥缓存时间
+	ResolveTimeout   time.Duration `json:"resolve_timeout"`    // 解析请求超时
+	MaxCacheEntries  int           `json:"max_cache_entries"`  // 有效/无效密钥缓存各自的容量上限
+}
+
 // TLSConfig TLS配置
 type TLSConfig struct {
 	Enabled  bool   `json:"enabled"`   // 是否启用TLS
@@ -138,11 +151,16 @@ type CircuitBreakerConfig struct {
 
 // RateLimitConfig 限流配置
 type RateLimitConfig struct {
-	Enabled     bool          `json:"enabled"`       // 是否启用限流
-	GlobalRate  RateConfig    `json:"global_rate"`   // 全局限流配置
-	PerIPRate   RateConfig    `json:"per_ip_rate"`   // 单IP限流配置
-	PerUserRate RateConfig    `json:"per_user_rate"` // 单用户限流配置
-	Window      time.Duration `json:"window"`        // 时间窗口
+	Enabled       bool                  `json:"enabled"`          // 是否启用限流
+	Backend       string                `json:"backend"`          // 限流存储后端: redis, memory
+	KeyPrefix     string                `json:"key_prefix"`       // 限流键前缀
+	GlobalRate    RateConfig            `json:"global_rate"`      // 全局限流配置
+	PerIPRate     RateConfig            `json:"per_ip_rate"`      // 单IP限流配置(匿名请求)
+	PerUserRate   RateConfig            `json:"per_user_rate"`    // 单用户限流配置(JWT认证请求)
+	PerAPIKeyRate RateConfig            `json:"per_api_key_rate"` // 单API Key限流配置(未配置配额等级时使用)
+	APIKeyTiers   map[string]RateConfig `json:"api_key_tiers"`    // 按API Key配额等级的限流配置
+	RouteRates    []RouteRateConfig     `json:"route_rates"`      // 按路由前缀的额外限流
+	Window        time.Duration         `json:"window"`           // 时间窗口
 }
 
 // RateConfig 限流速率配置
@@ -151,6 +169,22 @@ type RateConfig struct {
 	Duration time.Duration `json:"duration"` // 时间间隔
 }
 
+// RouteRateConfig 路由限流配置
+// 匹配路径前缀的请求在身份限流之外再按身份+路由单独计算配额，多条规则匹配时取最长前缀
+type RouteRateConfig struct {
+	PathPrefix string     `json:"path_prefix"` // 路径前缀
+	Rate       RateConfig `json:"rate"`        // 限流速率
+}
+
+// RedisConfig Redis配置
+type RedisConfig struct {
+	Host     string `json:"host"`      // Redis主机地址
+	Port     int    `json:"port"`      // Redis端口
+	Password string `json:"-"`         // 密码，不序列化到JSON
+	DB       int    `json:"db"`        // 数据库编号
+	PoolSize int    `json:"pool_size"` // 连接池大小
+}
+
 // ========================================
 // 监控配置
 // ========================================
@@ -303,6 +337,25 @@ const (
 	HeaderUserAgent      = "User-Agent"        // 用户代理头
 	HeaderAuthorization  = "Authorization"     // 认证头
 	HeaderContentType    = "Content-Type"      // 内容类型头
+	HeaderAPIKey         = "X-API-Key"         // API Key头
+	HeaderInternalAuth   = "X-Internal-Token"  // 服务间调用认证头
+)
+
+// 网关转发API Key调用方身份的内部请求头，需与X-Internal-Token一起发送，业务逻辑服务据此识别调用方
+const (
+	HeaderPrincipalUserID = "X-Principal-User-ID"        // 调用方用户ID
+	HeaderPrincipalKeyID  = "X-Principal-API-Key-ID"     // API Key ID
+	HeaderPrincipalScopes = "X-Principal-Scopes"         // 权限范围(逗号分隔)
+	HeaderPrincipalWallet = "X-Principal-Wallet-Address" // 调用方钱包地址
+)
+
+// 限流响应头常量(IETF RateLimit Header Fields草案)
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"     // 窗口内请求上限
+	HeaderRateLimitRemaining = "RateLimit-Remaining" // 剩余请求数
+	HeaderRateLimitReset     = "RateLimit-Reset"     // 配额恢复秒数
+	HeaderRateLimitPolicy    = "RateLimit-Policy"    // 限流策略
+	HeaderRetryAfter         = "Retry-After"         // 重试等待秒数
 )
 
 // 服务发现相关常量
@@ -0,0 +1,245 @@
+// Package apikey 网关API Key解析
+// 通过业务逻辑服务的内部接口(/internal/api-keys/resolve)将X-API-Key解析为调用方身份，
+// 解析结果按密钥哈希缓存在进程内：有效密钥缓存CacheTTL，被拒绝的密钥缓存NegativeCacheTTL，
+// 因此撤销或轮换后的旧密钥最长在CacheTTL内仍可通过网关。
+// 格式不符(非dfa_<64位十六进制>)的密钥在本地直接拒绝，不请求业务逻辑服务也不占用缓存
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"defi-aggregator/api-gateway/internal/types"
+	"defi-aggregator/api-gateway/pkg/balancer"
+
+	"github.com/sirupsen/logrus"
+)
+
+// resolvePath 业务逻辑服务的API Key解析接口
+const resolvePath = "/internal/api-keys/resolve"
+
+// cacheCleanupInterval 过期缓存清理间隔
+const cacheCleanupInterval = time.Minute
+
+// defaultMaxCacheEntries 有效/无效密钥缓存各自的默认容量上限
+const defaultMaxCacheEntries = 10000
+
+const (
+	keyPrefix    = "dfa_" // 密钥固定前缀，与业务逻辑服务生成规则一致
+	keySecretLen = 64     // 前缀后的十六进制字符数(32字节随机数)
+)
+
+// Principal API Key对应的调用方身份
+type Principal struct {
+	KeyID         uint       `json:"key_id"`               // API Key ID
+	UserID        uint       `json:"user_id"`              // 所属用户ID
+	WalletAddress string     `json:"wallet_address"`       // 所属用户钱包地址
+	Scopes        []string   `json:"scopes"`               // 权限范围
+	Tier          string     `json:"tier"`                 // 配额等级
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"` // 过期时间
+}
+
+// HasScope 是否拥有指定权限范围
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// RejectedError 密钥被业务逻辑服务拒绝(不存在、已撤销、已过期或账户停用)
+type RejectedError struct {
+	Message string // 拒绝原因
+}
+
+func (e *RejectedError) Error() string {
+	return e.Message
+}
+
+// Resolver API Key解析器
+type Resolver struct {
+	config    *types.APIKeyConfig   // API Key认证配置
+	balancer  balancer.LoadBalancer // 负载均衡器(选择业务逻辑服务实例)
+	client    *http.Client          // HTTP客户端
+	accepted  *lruCache             // 有效密钥缓存
+	rejected  *lruCache             // 无效密钥缓存，与有效密钥分开计数，随机密钥不会挤掉有效密钥
+	logger    *logrus.Logger        // 日志记录器
+	stopChan  chan struct{}         // 清理协程停止信号
+	closeOnce sync.Once             // 保证只关闭一次
+}
+
+// NewResolver 创建API Key解析器并启动缓存清理协程
+func NewResolver(config *types.APIKeyConfig, lb balancer.LoadBalancer, logger *logrus.Logger) *Resolver {
+	r := &Resolver{
+		config:   config,
+		balancer: lb,
+		client:   &http.Client{Timeout: config.ResolveTimeout},
+		accepted: newLRUCache(config.MaxCacheEntries),
+		rejected: newLRUCache(config.MaxCacheEntries),
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+
+	go r.cleanupLoop()
+
+	logger.Info("API Key解析器初始化完成")
+	return r
+}
+
+// Resolve 解析API Key
+// 密钥被拒绝时返回*RejectedError，业务逻辑服务不可用时返回其他错误
+func (r *Resolver) Resolve(ctx context.Context, rawKey string) (*Principal, error) {
+	if !ValidFormat(rawKey) {
+		return nil, &RejectedError{Message: "API Key格式无效"}
+	}
+
+	cacheKey := hashKey(rawKey)
+	if entry := r.accepted.get(cacheKey); entry != nil {
+		return entry.principal, nil
+	}
+	if entry := r.rejected.get(cacheKey); entry != nil {
+		return nil, entry.rejected
+	}
+
+	principal, err := r.fetch(ctx, rawKey)
+	if err != nil {
+		if rejected, ok := err.(*RejectedError); ok {
+			r.setCached(r.rejected, &cacheEntry{key: cacheKey, rejected: rejected}, r.config.NegativeCacheTTL)
+		}
+		return nil, err
+	}
+
+	ttl := r.config.CacheTTL
+	if principal.ExpiresAt != nil {
+		if untilExpiry := time.Until(*principal.ExpiresAt); untilExpiry < ttl {
+			ttl = untilExpiry
+		}
+	}
+	r.setCached(r.accepted, &cacheEntry{key: cacheKey, principal: principal}, ttl)
+	return principal, nil
+}
+
+// IsCachedValid 密钥是否已解析为有效身份且仍在缓存中
+// 限流中间件据此判断认证前是否需要按IP限制解析请求
+func (r *Resolver) IsCachedValid(rawKey string) bool {
+	if !ValidFormat(rawKey) {
+		return false
+	}
+	return r.accepted.get(hashKey(rawKey)) != nil
+}
+
+// ValidFormat 密钥格式是否为dfa_<64位十六进制>
+func ValidFormat(rawKey string) bool {
+	if len(rawKey) != len(keyPrefix)+keySecretLen || !strings.HasPrefix(rawKey, keyPrefix) {
+		return false
+	}
+	_, err := hex.DecodeString(rawKey[len(keyPrefix):])
+	return err == nil
+}
+
+// InternalToken 服务间调用令牌，转发调用方身份时用于向业务逻辑服务证明请求来自网关
+func (r *Resolver) InternalToken() string {
+	return r.config.InternalToken
+}
+
+// Close 停止缓存清理协程
+func (r *Resolver) Close() {
+	r.closeOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+// fetch 调用业务逻辑服务解析密钥
+func (r *Resolver) fetch(ctx context.Context, rawKey string) (*Principal, error) {
+	target, err := r.balancer.SelectTarget(types.ServiceBusinessLogic)
+	if err != nil {
+		return nil, fmt.Errorf("选择业务逻辑服务实例失败: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]string{"key": rawKey})
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL.String()+resolvePath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set(types.HeaderContentType, "application/json")
+	req.Header.Set(types.HeaderInternalAuth, r.config.InternalToken)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求业务逻辑服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool            `json:"success"`
+		Data    *Principal      `json:"data"`
+		Error   *types.APIError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: status=%d, error=%w", resp.StatusCode, err)
+	}
+
+	// 401表示密钥本身不可用；403(内部令牌不匹配)、404(内部接口未开放)等属于配置或服务故障
+	switch {
+	case resp.StatusCode == http.StatusOK && result.Success && result.Data != nil:
+		return result.Data, nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, &RejectedError{Message: errorMessage(result.Error, "无效的API Key")}
+	default:
+		return nil, fmt.Errorf("业务逻辑服务解析API Key失败: status=%d, error=%s", resp.StatusCode, errorMessage(result.Error, "未知错误"))
+	}
+}
+
+// setCached 写入缓存项，ttl<=0时不缓存
+func (r *Resolver) setCached(cache *lruCache, entry *cacheEntry, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	entry.expiresAt = time.Now().Add(ttl)
+	cache.set(entry)
+}
+
+// cleanupLoop 定期清理过期缓存
+func (r *Resolver) cleanupLoop() {
+	ticker := time.NewTicker(cacheCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case <-ticker.C:
+			r.accepted.purgeExpired()
+			r.rejected.purgeExpired()
+		}
+	}
+}
+
+// hashKey 计算缓存键，避免在内存中保留密钥明文
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// errorMessage 提取错误消息
+func errorMessage(apiErr *types.APIError, defaultMessage string) string {
+	if apiErr != nil && apiErr.Message != "" {
+		return apiErr.Message
+	}
+	return defaultMessage
+}
@@ -0,0 +1,94 @@
+// Package apikey 网关API Key解析
+// 本文件实现有容量上限的LRU解析结果缓存，防止大量随机密钥撑满网关内存
+package apikey
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cacheEntry 解析结果缓存项
+type cacheEntry struct {
+	key       string         // 密钥哈希
+	principal *Principal     // 解析成功时的调用方身份
+	rejected  *RejectedError // 解析被拒绝时的原因
+	expiresAt time.Time      // 缓存过期时间
+}
+
+// lruCache 有容量上限的LRU缓存
+// 超出容量时淘汰最久未访问的缓存项
+type lruCache struct {
+	capacity int                      // 最大缓存项数量
+	items    map[string]*list.Element // 密钥哈希 -> 链表节点
+	order    *list.List               // 访问顺序，队首为最近访问
+	mutex    sync.Mutex               // 保护items和order(读取也会调整访问顺序)
+}
+
+// newLRUCache 创建LRU缓存，capacity<=0时使用默认容量
+func newLRUCache(capacity int) *lruCache {
+	if capacity <= 0 {
+		capacity = defaultMaxCacheEntries
+	}
+	return &lruCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// get 读取未过期的缓存项
+func (c *lruCache) get(key string) *cacheEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return entry
+}
+
+// set 写入缓存项，超出容量时淘汰最久未访问的缓存项
+func (c *lruCache) set(entry *cacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, ok := c.items[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// purgeExpired 清理过期缓存项
+func (c *lruCache) purgeExpired() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if now.After(elem.Value.(*cacheEntry).expiresAt) {
+			c.removeElement(elem)
+		}
+		elem = prev
+	}
+}
+
+// removeElement 删除缓存项，调用方需持有锁
+func (c *lruCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*cacheEntry).key)
+}
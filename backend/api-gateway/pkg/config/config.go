@@ -46,7 +46,10 @@ func Load() (*types.Config, error) {
 					"GET", "POST", "PUT", "DELETE", "OPTIONS",
 				}),
 				AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{
-					"Content-Type", "Authorization", "X-Request-ID", "X-User-Agent",
+					"Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "X-User-Agent",
+				}),
+				ExposedHeaders: getEnvAsSlice("CORS_EXPOSED_HEADERS", []string{
+					"X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After",
 				}),
 				AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
 				MaxAge:           getEnvAsInt("CORS_MAX_AGE", 86400),
@@ -56,6 +59,14 @@ func Load() (*types.Config, error) {
 				Algorithm: getEnv("JWT_ALGORITHM", "HS256"),
 				Issuer:    getEnv("JWT_ISSUER", "defi-aggregator-gateway"),
 			},
+			APIKey: types.APIKeyConfig{
+				Enabled:          getEnvAsBool("API_KEY_AUTH_ENABLED", true),
+				InternalToken:    getEnv("INTERNAL_SERVICE_TOKEN", ""), // 从全局配置读取，需与业务逻辑服务一致
+				CacheTTL:         getEnvAsDuration("API_KEY_CACHE_TTL", 60*time.Second),
+				NegativeCacheTTL: getEnvAsDuration("API_KEY_NEGATIVE_CACHE_TTL", 30*time.Second),
+				ResolveTimeout:   getEnvAsDuration("API_KEY_RESOLVE_TIMEOUT", 3*time.Second),
+				MaxCacheEntries:  getEnvAsInt("API_KEY_CACHE_MAX_ENTRIES", 10000),
+			},
 			TrustedProxies: getEnvAsSlice("TRUSTED_PROXIES", []string{"127.0.0.1", "::1"}),
 		},
 		LoadBalancer: types.LoadBalancerConfig{
@@ -79,7 +90,9 @@ func Load() (*types.Config, error) {
 			SlowRequestMs:   getEnvAsInt("SLOW_REQUEST_MS", 1000),
 		},
 		RateLimit: types.RateLimitConfig{
-			Enabled: getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Enabled:   getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Backend:   getEnv("RATE_LIMIT_BACKEND", "redis"),
+			KeyPrefix: getEnv("RATE_LIMIT_KEY_PREFIX", "gateway:ratelimit:"),
 			GlobalRate: types.RateConfig{
 				Requests: getEnvAsInt("GLOBAL_RATE_REQUESTS", 1000),
 				Duration: getEnvAsDuration("GLOBAL_RATE_DURATION", 1*time.Minute),
@@ -88,9 +101,38 @@ func Load() (*types.Config, error) {
 				Requests: getEnvAsInt("PER_IP_RATE_REQUESTS", 100),
 				Duration: getEnvAsDuration("PER_IP_RATE_DURATION", 1*time.Minute),
 			},
+			PerUserRate: types.RateConfig{
+				Requests: getEnvAsInt("PER_USER_RATE_REQUESTS", 300),
+				Duration: getEnvAsDuration("PER_USER_RATE_DURATION", 1*time.Minute),
+			},
+			PerAPIKeyRate: types.RateConfig{
+				Requests: getEnvAsInt("PER_API_KEY_RATE_REQUESTS", 600),
+				Duration: getEnvAsDuration("PER_API_KEY_RATE_DURATION", 1*time.Minute),
+			},
 			Window: getEnvAsDuration("RATE_LIMIT_WINDOW", 1*time.Minute),
 		},
+		Redis: types.RedisConfig{
+			Host:     getEnv("REDIS_HOST", ""),
+			Port:     getEnvAsInt("REDIS_PORT", 6379),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       getEnvAsInt("REDIS_DB_API_GATEWAY", 0),
+			PoolSize: getEnvAsInt("REDIS_POOL_SIZE", 10),
+		},
+	}
+
+	// 路由限流规则格式: 路径前缀:请求数/时间窗口，逗号分隔
+	routeRates, err := parseRouteRates(getEnv("RATE_LIMIT_ROUTES", "/api/v1/router/quote:30/1m,/api/v1/quotes:30/1m,/api/v1/quotes/price-impact:5/1m"))
+	if err != nil {
+		return nil, fmt.Errorf("解析RATE_LIMIT_ROUTES失败: %w", err)
 	}
+	config.RateLimit.RouteRates = routeRates
+
+	// API Key配额等级格式: 等级:请求数/时间窗口，逗号分隔
+	tierRates, err := parseTierRates(getEnv("API_KEY_TIER_RATES", "free:600/1m,standard:3000/1m,premium:12000/1m"))
+	if err != nil {
+		return nil, fmt.Errorf("解析API_KEY_TIER_RATES失败: %w", err)
+	}
+	config.RateLimit.APIKeyTiers = tierRates
 
 	// 验证配置
 	if err := validateConfig(config); err != nil {
@@ -176,6 +218,86 @@ func parseTargets(targetURLs string) []types.Target {
 	return targets
 }
 
+// parseRouteRates 解析路由限流规则
+// 示例: "/api/v1/router/quote:30/1m,/api/v1/tokens:600/1m"
+func parseRouteRates(value string) ([]types.RouteRateConfig, error) {
+	var rules []types.RouteRateConfig
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		sep := strings.LastIndex(item, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("无效的路由限流规则: %s", item)
+		}
+		rate, err := parseRate(item[sep+1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, item)
+		}
+
+		rules = append(rules, types.RouteRateConfig{
+			PathPrefix: item[:sep],
+			Rate:       rate,
+		})
+	}
+
+	return rules, nil
+}
+
+// parseTierRates 解析API Key配额等级限流规则
+// 示例: "free:600/1m,premium:12000/1m"
+func parseTierRates(value string) (map[string]types.RateConfig, error) {
+	tiers := make(map[string]types.RateConfig)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		sep := strings.Index(item, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("无效的配额等级规则: %s", item)
+		}
+
+		rate, err := parseRate(item[sep+1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, item)
+		}
+		tiers[item[:sep]] = rate
+	}
+
+	return tiers, nil
+}
+
+// parseRate 解析"请求数/时间窗口"格式的限流速率
+func parseRate(value string) (types.RateConfig, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return types.RateConfig{}, fmt.Errorf("无效的限流速率")
+	}
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return types.RateConfig{}, fmt.Errorf("无效的请求数量")
+	}
+	duration, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return types.RateConfig{}, fmt.Errorf("无效的时间窗口")
+	}
+	return types.RateConfig{Requests: requests, Duration: duration}, nil
+}
+
+// validateRate 验证限流速率配置
+func validateRate(name string, rate types.RateConfig) error {
+	if rate.Requests <= 0 || rate.Duration <= 0 {
+		return fmt.Errorf("%s限流配置无效: requests=%d, duration=%v", name, rate.Requests, rate.Duration)
+	}
+	return nil
+}
+
 // validateConfig 验证配置的有效性
 func validateConfig(cfg *types.Config) error {
 	// 验证必填的服务器配置
@@ -232,6 +354,52 @@ func validateConfig(cfg *types.Config) error {
 		}
 	}
 
+	// 验证限流配置
+	if cfg.RateLimit.Enabled {
+		switch cfg.RateLimit.Backend {
+		case "redis", "memory":
+		default:
+			return fmt.Errorf("无效的限流存储后端: %s (可选: redis, memory)", cfg.RateLimit.Backend)
+		}
+		if cfg.RateLimit.GlobalRate.Requests > 0 {
+			if err := validateRate("全局", cfg.RateLimit.GlobalRate); err != nil {
+				return err
+			}
+		}
+		if err := validateRate("IP", cfg.RateLimit.PerIPRate); err != nil {
+			return err
+		}
+		if err := validateRate("用户", cfg.RateLimit.PerUserRate); err != nil {
+			return err
+		}
+		if err := validateRate("API Key", cfg.RateLimit.PerAPIKeyRate); err != nil {
+			return err
+		}
+		for _, rule := range cfg.RateLimit.RouteRates {
+			if err := validateRate("路由"+rule.PathPrefix, rule.Rate); err != nil {
+				return err
+			}
+		}
+		for tier, rate := range cfg.RateLimit.APIKeyTiers {
+			if err := validateRate("API Key等级"+tier, rate); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 验证API Key认证配置
+	if cfg.Security.APIKey.Enabled {
+		if cfg.Security.APIKey.InternalToken == "" {
+			logrus.Warn("⚠️ 未配置INTERNAL_SERVICE_TOKEN，API Key认证已禁用")
+			cfg.Security.APIKey.Enabled = false
+		} else if len(cfg.Security.APIKey.InternalToken) < 32 {
+			return fmt.Errorf("INTERNAL_SERVICE_TOKEN长度必须至少32个字符")
+		}
+		if cfg.Security.APIKey.CacheTTL < 0 || cfg.Security.APIKey.NegativeCacheTTL < 0 || cfg.Security.APIKey.ResolveTimeout <= 0 {
+			return fmt.Errorf("API Key缓存时间不能为负且解析超时必须大于0")
+		}
+	}
+
 	// 生产环境额外验证
 	if cfg.Server.Environment == "production" {
 		if cfg.Server.Debug {
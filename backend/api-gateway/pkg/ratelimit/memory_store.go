@@ -0,0 +1,97 @@
+// Package ratelimit 进程内限流存储实现
+// 用于本地开发和Redis不可用时的降级，后台协程定期清理配额已完全恢复的限流键，避免内存无限增长
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// memoryCleanupInterval 过期限流键清理间隔
+const memoryCleanupInterval = time.Minute
+
+// MemoryStore 进程内限流存储
+type MemoryStore struct {
+	prefix    string               // 限流键前缀
+	tats      map[string]time.Time // 限流键 -> 理论到达时间
+	mutex     sync.Mutex           // 保护tats
+	logger    *logrus.Logger       // 日志记录器
+	stopChan  chan struct{}        // 清理协程停止信号
+	closeOnce sync.Once            // 保证只关闭一次
+}
+
+// NewMemoryStore 创建进程内限流存储并启动后台清理协程
+func NewMemoryStore(prefix string, logger *logrus.Logger) *MemoryStore {
+	s := &MemoryStore{
+		prefix:   prefix,
+		tats:     make(map[string]time.Time),
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+
+	go s.cleanupLoop()
+
+	logger.Info("内存限流存储初始化完成")
+	return s
+}
+
+// Allow 对限流键消耗一次配额
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	fullKey := s.prefix + key
+	newTat, result := gcra(s.tats[fullKey], time.Now(), limit)
+	if result.Allowed {
+		s.tats[fullKey] = newTat
+	}
+	return result, nil
+}
+
+// Backend 存储后端名称
+func (s *MemoryStore) Backend() string {
+	return BackendMemory
+}
+
+// Close 停止后台清理协程
+func (s *MemoryStore) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stopChan)
+	})
+	return nil
+}
+
+// cleanupLoop 定期清理配额已完全恢复的限流键
+func (s *MemoryStore) cleanupLoop() {
+	ticker := time.NewTicker(memoryCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.cleanup(time.Now())
+		}
+	}
+}
+
+// cleanup 删除理论到达时间已过的限流键，这些键再次出现时等同于新键
+func (s *MemoryStore) cleanup(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	removed := 0
+	for key, tat := range s.tats {
+		if !tat.After(now) {
+			delete(s.tats, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		s.logger.Debugf("清理过期限流键: %d个, 剩余%d个", removed, len(s.tats))
+	}
+}
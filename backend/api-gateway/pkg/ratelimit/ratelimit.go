@@ -0,0 +1,98 @@
+// Package ratelimit 网关限流存储
+// 基于GCRA(通用信元速率算法)实现令牌桶语义的限流判定，每个限流键只需保存一个理论到达时间(TAT)，
+// 提供Redis(多网关实例共享)和进程内两种存储
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"defi-aggregator/api-gateway/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 限流存储后端
+const (
+	BackendRedis  = "redis"  // Redis存储，多实例共享配额
+	BackendMemory = "memory" // 进程内存储，仅限单实例
+)
+
+// Limit 限流规则
+// Period内最多Requests次请求，允许一次性突发Requests次
+type Limit struct {
+	Requests int           // 请求数量
+	Period   time.Duration // 时间窗口
+}
+
+// emissionInterval 两次请求之间的平均间隔
+func (l Limit) emissionInterval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result 限流判定结果
+type Result struct {
+	Allowed    bool          // 是否放行
+	Limit      int           // 窗口内请求上限
+	Remaining  int           // 剩余可用请求数
+	RetryAfter time.Duration // 被拒绝时需要等待的时间
+	ResetAfter time.Duration // 配额完全恢复需要的时间
+}
+
+// Store 限流存储接口
+type Store interface {
+	// Allow 对限流键消耗一次配额并返回判定结果
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+
+	// Backend 存储后端名称
+	Backend() string
+
+	// Close 释放存储资源
+	Close() error
+}
+
+// NewStore 根据配置创建限流存储
+// 配置为Redis但连接失败时降级为进程内存储，此时配额按网关实例分别计算
+func NewStore(cfg *types.Config, logger *logrus.Logger) Store {
+	if cfg.RateLimit.Backend == BackendMemory {
+		logger.Info("初始化内存限流存储...")
+		return NewMemoryStore(cfg.RateLimit.KeyPrefix, logger)
+	}
+
+	store, err := NewRedisStore(&cfg.Redis, cfg.RateLimit.KeyPrefix, logger)
+	if err != nil {
+		logger.Warnf("⚠️ Redis限流存储不可用，降级为内存存储(多实例部署时配额按实例计算): %v", err)
+		return NewMemoryStore(cfg.RateLimit.KeyPrefix, logger)
+	}
+	return store
+}
+
+// gcra 根据当前理论到达时间计算限流结果
+// 返回放行后的新理论到达时间，被拒绝时理论到达时间不变
+func gcra(tat, now time.Time, limit Limit) (time.Time, *Result) {
+	interval := limit.emissionInterval()
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-limit.Period)
+	diff := now.Sub(allowAt)
+
+	if diff < 0 {
+		return tat, &Result{
+			Allowed:    false,
+			Limit:      limit.Requests,
+			Remaining:  0,
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}
+	}
+
+	return newTat, &Result{
+		Allowed:    true,
+		Limit:      limit.Requests,
+		Remaining:  int(diff / interval),
+		ResetAfter: newTat.Sub(now),
+	}
+}
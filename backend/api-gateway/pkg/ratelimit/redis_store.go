@@ -0,0 +1,108 @@
+// Package ratelimit Redis限流存储实现
+// GCRA判定在Lua脚本内原子完成，时间取自Redis服务器，多个网关实例之间不依赖本地时钟同步
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"defi-aggregator/api-gateway/internal/types"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// gcraScript GCRA限流脚本
+// KEYS[1]=限流键, ARGV=[窗口请求数, 窗口时长(微秒)]
+// 返回: [是否放行, 剩余请求数, 重试等待(微秒), 完全恢复时间(微秒)]
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local interval = period / limit
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local diff = now - (new_tat - period)
+if diff < 0 then
+	return {0, 0, math.ceil(-diff), math.ceil(tat - now)}
+end
+
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor(diff / interval), 0, math.ceil(new_tat - now)}
+`)
+
+// RedisStore Redis限流存储
+type RedisStore struct {
+	client *redis.Client  // Redis客户端
+	prefix string         // 限流键前缀
+	logger *logrus.Logger // 日志记录器
+}
+
+// NewRedisStore 创建Redis限流存储
+// 建立连接后立即执行PING，连接失败时返回错误
+func NewRedisStore(cfg *types.RedisConfig, prefix string, logger *logrus.Logger) (*RedisStore, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("未配置REDIS_HOST")
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接Redis失败: %w", err)
+	}
+
+	logger.Infof("Redis限流存储连接成功: %s:%d, db=%d", cfg.Host, cfg.Port, cfg.DB)
+
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+		logger: logger,
+	}, nil
+}
+
+// Allow 对限流键消耗一次配额
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	values, err := gcraScript.Run(ctx, s.client, []string{s.prefix + key},
+		limit.Requests, limit.Period.Microseconds()).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("执行限流脚本失败: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("限流脚本返回值异常: %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Requests,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
+
+// Backend 存储后端名称
+func (s *RedisStore) Backend() string {
+	return BackendRedis
+}
+
+// Close 关闭Redis连接
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -19,6 +19,7 @@ import (
 	"defi-aggregator/business-logic/pkg/config"
 	"defi-aggregator/business-logic/pkg/database"
 	"defi-aggregator/business-logic/pkg/middleware"
+	"defi-aggregator/business-logic/pkg/session"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -37,6 +38,11 @@ type Application struct {
 	Repositories *repository.Repositories // 数据访问层
 	Services     *services.Services       // 业务逻辑层
 	Controllers  *controllers.Controllers // 控制器层
+	Sessions     session.Store            // JWT会话存储
+
+	// 后台任务
+	Tracker     *services.TransactionTracker // 链上交易确认跟踪器（未启用时为nil）
+	StatsRollup *services.StatsRollupJob     // 统计汇总任务（未启用时为nil）
 }
 
 // main 主函数
@@ -94,9 +100,22 @@ func NewApplication() (*Application, error) {
 	logger.Info("初始化数据访问层...")
 	repos := repository.New(db.GetDB())
 
-	// 6. 初始化业务逻辑层
+	// 6. 初始化会话存储和业务逻辑层
+	logger.Info("初始化会话存储...")
+	sessions := session.NewStore(cfg, logger)
+
 	logger.Info("初始化业务逻辑层...")
-	srvs := services.New(repos, cfg, logger)
+	srvs := services.New(repos, sessions, cfg, logger)
+
+	var tracker *services.TransactionTracker
+	if cfg.Tracker.Enabled {
+		tracker = services.NewTransactionTracker(repos, srvs.Swap, cfg, logger)
+	}
+
+	var statsRollup *services.StatsRollupJob
+	if cfg.Stats.RollupEnabled {
+		statsRollup = services.NewStatsRollupJob(repos, cfg, logger)
+	}
 
 	// 7. 初始化控制器层
 	logger.Info("初始化控制器层...")
@@ -110,7 +129,7 @@ func NewApplication() (*Application, error) {
 	}
 
 	// 9. 创建HTTP路由器
-	router := setupRouter(cfg, ctrlrs, logger)
+	router := setupRouter(cfg, ctrlrs, sessions, logger)
 
 	// 10. 创建HTTP服务器
 	server := &http.Server{
@@ -131,6 +150,9 @@ func NewApplication() (*Application, error) {
 		Repositories: repos,
 		Services:     srvs,
 		Controllers:  ctrlrs,
+		Sessions:     sessions,
+		Tracker:      tracker,
+		StatsRollup:  statsRollup,
 	}, nil
 }
 
@@ -141,6 +163,16 @@ func (app *Application) Run() error {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	// 启动链上交易确认跟踪
+	if app.Tracker != nil {
+		app.Tracker.Start()
+	}
+
+	// 启动统计汇总任务
+	if app.StatsRollup != nil {
+		app.StatsRollup.Start()
+	}
+
 	// 在goroutine中启动HTTP服务器
 	go func() {
 		app.Logger.Infof("HTTP服务器启动，监听端口: %s", app.Server.Addr)
@@ -167,6 +199,14 @@ func (app *Application) Shutdown() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// 停止后台任务
+	if app.Tracker != nil {
+		app.Tracker.Stop()
+	}
+	if app.StatsRollup != nil {
+		app.StatsRollup.Stop()
+	}
+
 	app.Logger.Info("正在关闭HTTP服务器...")
 
 	// 关闭HTTP服务器
@@ -175,6 +215,11 @@ func (app *Application) Shutdown() error {
 		return err
 	}
 
+	// 关闭会话存储
+	if err := app.Sessions.Close(); err != nil {
+		app.Logger.Warnf("会话存储关闭失败: %v", err)
+	}
+
 	app.Logger.Info("正在关闭数据库连接...")
 
 	// 关闭数据库连接
@@ -232,7 +277,7 @@ func initLogger(cfg *config.Config) *logrus.Logger {
 
 // setupRouter 设置HTTP路由器
 // 配置中间件、路由和错误处理
-func setupRouter(cfg *config.Config, ctrlrs *controllers.Controllers, logger *logrus.Logger) *gin.Engine {
+func setupRouter(cfg *config.Config, ctrlrs *controllers.Controllers, sessions session.Store, logger *logrus.Logger) *gin.Engine {
 	// 创建Gin引擎
 	router := gin.New()
 
@@ -265,13 +310,15 @@ func setupRouter(cfg *config.Config, ctrlrs *controllers.Controllers, logger *lo
 
 		// 需要认证的路由
 		protected := v1.Group("")
-		protected.Use(middleware.JWT(cfg)) // JWT认证中间件
+		protected.Use(middleware.JWT(cfg, sessions)) // JWT认证中间件
 		{
 			// 认证用户操作
-			protected.POST("/auth/logout", ctrlrs.Auth.Logout) // 用户登出
+			protected.POST("/auth/logout", middleware.SessionOnly(), ctrlrs.Auth.Logout)        // 用户登出(当前会话)
+			protected.POST("/auth/logout-all", middleware.SessionOnly(), ctrlrs.Auth.LogoutAll) // 登出所有会话
 
 			// 用户相关路由
 			users := protected.Group("/users")
+			users.Use(middleware.SessionOnly())
 			{
 				users.GET("/profile", ctrlrs.User.GetProfile)                  // 获取用户资料
 				users.PUT("/profile", ctrlrs.User.UpdateProfile)               // 更新用户资料
@@ -281,11 +328,25 @@ func setupRouter(cfg *config.Config, ctrlrs *controllers.Controllers, logger *lo
 				users.GET("/stats", ctrlrs.User.GetStats)                      // 获取用户统计
 			}
 
+			// API Key管理路由
+			apiKeys := protected.Group("/api-keys")
+			apiKeys.Use(middleware.SessionOnly())
+			{
+				apiKeys.GET("", ctrlrs.APIKey.ListAPIKeys)              // 获取API Key列表
+				apiKeys.POST("", ctrlrs.APIKey.CreateAPIKey)            // 创建API Key
+				apiKeys.POST("/:id/rotate", ctrlrs.APIKey.RotateAPIKey) // 轮换API Key
+				apiKeys.DELETE("/:id", ctrlrs.APIKey.RevokeAPIKey)      // 撤销API Key
+
+				apiKeys.PUT("/:id/tier", middleware.Admin(), ctrlrs.APIKey.UpdateAPIKeyTier) // 调整配额等级(仅管理员)
+			}
+
 			// 交易历史路由
 			transactions := protected.Group("/transactions")
 			{
-				transactions.GET("", ctrlrs.Transaction.GetTransactions)    // 获取交易列表
-				transactions.GET("/:id", ctrlrs.Transaction.GetTransaction) // 获取交易详情
+				transactions.GET("", ctrlrs.Transaction.GetTransactions)               // 获取交易列表
+				transactions.GET("/:id", ctrlrs.Transaction.GetTransaction)            // 获取交易详情
+				transactions.POST("/:id/cancel", ctrlrs.Transaction.CancelTransaction) // 取消交易
+				transactions.POST("/:id/retry", ctrlrs.Transaction.RetryTransaction)   // 重试交易
 			}
 		}
 
@@ -309,27 +370,44 @@ func setupRouter(cfg *config.Config, ctrlrs *controllers.Controllers, logger *lo
 			// 报价相关路由
 			quotes := public.Group("/quotes")
 			{
-				quotes.POST("", ctrlrs.Quote.GetQuote)               // 获取报价
-				quotes.GET("/history", ctrlrs.Quote.GetQuoteHistory) // 报价历史
+				quotes.POST("", ctrlrs.Quote.GetQuote)                            // 获取报价
+				quotes.POST("/stream", ctrlrs.Quote.StreamQuote)                  // 流式获取报价
+				quotes.GET("/history", ctrlrs.Quote.GetQuoteHistory)              // 报价历史
+				quotes.POST("/price-impact", ctrlrs.Quote.GetPriceImpactAnalysis) // 价格冲击分析
+				quotes.GET("/:requestId/compare", ctrlrs.Quote.CompareQuotes)     // 聚合器报价对比
 			}
 
-			// 交易相关路由
+			// 交易相关路由（登录用户的交易归属到其账户）
 			swaps := public.Group("/swaps")
+			swaps.Use(middleware.OptionalJWT(cfg, sessions))
 			{
-				swaps.POST("", ctrlrs.Swap.CreateSwap)           // 创建交易
-				swaps.GET("/:txHash", ctrlrs.Swap.GetSwapStatus) // 查询交易状态
+				swaps.POST("", ctrlrs.Swap.CreateSwap)               // 创建交易
+				swaps.POST("/submit", ctrlrs.Swap.SubmitTransaction) // 提交交易哈希
+				swaps.GET("/:txHash", ctrlrs.Swap.GetSwapStatus)     // 查询交易状态
 			}
 
 			// 统计相关路由
 			stats := public.Group("/stats")
 			{
-				stats.GET("/system", ctrlrs.Stats.GetSystemStats)          // 系统统计
-				stats.GET("/aggregators", ctrlrs.Stats.GetAggregatorStats) // 聚合器统计
-				stats.GET("/token-pairs", ctrlrs.Stats.GetTokenPairStats)  // 代币对统计
+				stats.GET("/system", ctrlrs.Stats.GetSystemStats)                      // 系统统计
+				stats.GET("/volume", ctrlrs.Stats.GetTradingVolume)                    // 每日交易量
+				stats.GET("/aggregators", ctrlrs.Stats.GetAggregatorStats)             // 聚合器统计
+				stats.GET("/aggregators/rankings", ctrlrs.Stats.GetAggregatorRankings) // 聚合器排名
+				stats.GET("/token-pairs", ctrlrs.Stats.GetTokenPairStats)              // 代币对统计
+				stats.GET("/token-pairs/popular", ctrlrs.Stats.GetPopularTokenPairs)   // 热门代币对
 			}
 		}
 	}
 
+	// 内部路由（仅供API网关调用，未配置INTERNAL_SERVICE_TOKEN时不注册）
+	if cfg.Security.InternalServiceToken != "" {
+		internal := router.Group("/internal")
+		internal.Use(middleware.InternalAuth(cfg))
+		{
+			internal.POST("/api-keys/resolve", ctrlrs.APIKey.ResolveAPIKey) // 解析API Key
+		}
+	}
+
 	// 404处理
 	router.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, types.APIResponse{
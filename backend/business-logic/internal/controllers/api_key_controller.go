@@ -0,0 +1,309 @@
+// Package controllers API Key控制器实现
+// 处理登录用户创建、查看、轮换和撤销API Key的HTTP请求，
+// 管理员调整配额等级的接口，以及网关解析X-API-Key的内部接口
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"defi-aggregator/business-logic/internal/services"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// APIKeyController API Key控制器
+type APIKeyController struct {
+	apiKeyService services.APIKeyService // API Key业务服务
+	cfg           *config.Config         // 应用配置
+	logger        *logrus.Logger         // 日志记录器
+}
+
+// NewAPIKeyController 创建API Key控制器实例
+func NewAPIKeyController(apiKeyService services.APIKeyService, cfg *config.Config, logger *logrus.Logger) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// ========================================
+// 用户API Key管理接口
+// ========================================
+
+// CreateAPIKey 创建API Key
+// POST /api/v1/api-keys
+// 响应中的key字段为完整密钥，只返回这一次
+func (c *APIKeyController) CreateAPIKey(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	var req types.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warnf("[%s] 创建API Key请求参数无效: %v", requestID, err)
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "请求参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	key, err := c.apiKeyService.CreateAPIKey(userID, &req)
+	if err != nil {
+		c.handleServiceError(ctx, err, "创建API Key失败")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, types.APIResponse{
+		Success:   true,
+		Data:      key,
+		Message:   "API Key创建成功，请妥善保存，密钥不会再次显示",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ListAPIKeys 获取API Key列表
+// GET /api/v1/api-keys
+func (c *APIKeyController) ListAPIKeys(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	keys, err := c.apiKeyService.ListAPIKeys(userID)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取API Key列表失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      keys,
+		Message:   "获取API Key列表成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// RotateAPIKey 轮换API Key
+// POST /api/v1/api-keys/:id/rotate
+// 旧密钥立即失效(网关缓存过期前仍可能短暂可用)
+func (c *APIKeyController) RotateAPIKey(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	id, ok := c.parseAPIKeyID(ctx)
+	if !ok {
+		return
+	}
+
+	key, err := c.apiKeyService.RotateAPIKey(userID, id)
+	if err != nil {
+		c.handleServiceError(ctx, err, "轮换API Key失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      key,
+		Message:   "API Key轮换成功，请妥善保存，密钥不会再次显示",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// RevokeAPIKey 撤销API Key
+// DELETE /api/v1/api-keys/:id
+func (c *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	id, ok := c.parseAPIKeyID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.apiKeyService.RevokeAPIKey(userID, id); err != nil {
+		c.handleServiceError(ctx, err, "撤销API Key失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      gin.H{"message": "API Key已撤销"},
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 管理员接口
+// ========================================
+
+// UpdateAPIKeyTier 调整API Key配额等级
+// PUT /api/v1/api-keys/:id/tier
+// 仅管理员可用，可调整任意用户的密钥
+func (c *APIKeyController) UpdateAPIKeyTier(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	id, ok := c.parseAPIKeyID(ctx)
+	if !ok {
+		return
+	}
+
+	var req types.UpdateAPIKeyTierRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warnf("[%s] 调整API Key配额等级请求参数无效: %v", requestID, err)
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "请求参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	key, err := c.apiKeyService.UpdateAPIKeyTier(id, req.Tier)
+	if err != nil {
+		c.handleServiceError(ctx, err, "调整API Key配额等级失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      key,
+		Message:   "API Key配额等级已调整",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 内部接口
+// ========================================
+
+// ResolveAPIKey 解析API Key
+// POST /internal/api-keys/resolve
+// 供网关将X-API-Key解析为调用方身份、权限范围和配额等级，需通过InternalAuth认证
+func (c *APIKeyController) ResolveAPIKey(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	var req types.ResolveAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.Key == "" {
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "缺少API Key",
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	principal, err := c.apiKeyService.ResolveAPIKey(req.Key)
+	if err != nil {
+		c.handleServiceError(ctx, err, "解析API Key失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      principal,
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// parseAPIKeyID 解析路径中的API Key ID，无效时直接返回400
+func (c *APIKeyController) parseAPIKeyID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "无效的API Key ID",
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: ctx.GetString("request_id"),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleServiceError 处理业务服务错误
+func (c *APIKeyController) handleServiceError(ctx *gin.Context, err error, defaultMessage string) {
+	requestID := ctx.GetString("request_id")
+
+	// 检查是否为业务服务错误
+	if serviceErr, ok := err.(*services.ServiceError); ok {
+		// 根据错误代码确定HTTP状态码
+		var statusCode int
+		switch serviceErr.Code {
+		case types.ErrCodeValidation:
+			statusCode = http.StatusBadRequest
+		case types.ErrCodeUnauthorized:
+			statusCode = http.StatusUnauthorized
+		case types.ErrCodeForbidden:
+			statusCode = http.StatusForbidden
+		case types.ErrCodeNotFound:
+			statusCode = http.StatusNotFound
+		case types.ErrCodeConflict:
+			statusCode = http.StatusConflict
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+
+		ctx.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    serviceErr.Code,
+				Message: serviceErr.Message,
+				Details: serviceErr.Details,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		// 记录错误日志
+		if statusCode >= 500 {
+			c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+		} else {
+			c.logger.Warnf("[%s] %s: %v", requestID, defaultMessage, err)
+		}
+	} else {
+		// 未知错误，返回通用内部错误
+		ctx.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeInternal,
+				Message: defaultMessage,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+	}
+}
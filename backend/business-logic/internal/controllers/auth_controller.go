@@ -139,13 +139,14 @@ func (c *AuthController) Login(ctx *gin.Context) {
 
 // Logout 用户登出
 // POST /api/v1/auth/logout
-// 撤销用户的访问令牌
+// 撤销当前令牌所属的会话，访问令牌和刷新令牌立即失效
 func (c *AuthController) Logout(ctx *gin.Context) {
 	requestID := ctx.GetString("request_id")
 	userID := ctx.GetUint("user_id")
+	sessionID := ctx.GetString("session_id")
 
 	// 调用认证服务登出用户
-	if err := c.authService.LogoutUser(userID); err != nil {
+	if err := c.authService.LogoutUser(userID, sessionID); err != nil {
 		c.handleServiceError(ctx, err, "登出失败")
 		return
 	}
@@ -161,9 +162,31 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 	c.logger.Infof("[%s] 用户 %d 登出成功", requestID, userID)
 }
 
+// LogoutAll 登出所有会话
+// POST /api/v1/auth/logout-all
+// 撤销用户在所有设备上的会话，用于账户安全处理
+func (c *AuthController) LogoutAll(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	if err := c.authService.LogoutAllSessions(userID); err != nil {
+		c.handleServiceError(ctx, err, "登出所有会话失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      gin.H{"message": "已登出所有会话"},
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+
+	c.logger.Infof("[%s] 用户 %d 已登出所有会话", requestID, userID)
+}
+
 // RefreshToken 刷新访问令牌
 // POST /api/v1/auth/refresh
-// 使用刷新令牌获取新的访问令牌
+// 使用刷新令牌轮换出新的访问令牌和刷新令牌，旧刷新令牌随即失效
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
 	requestID := ctx.GetString("request_id")
 
@@ -189,19 +212,20 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 	}
 
 	// 调用认证服务刷新令牌
-	newAccessToken, err := c.authService.RefreshToken(req.RefreshToken)
+	accessToken, refreshToken, err := c.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
 		c.handleServiceError(ctx, err, "令牌刷新失败")
 		return
 	}
 
-	// 返回新的访问令牌
+	// 返回新的令牌对，客户端必须保存新的刷新令牌
 	ctx.JSON(http.StatusOK, types.APIResponse{
 		Success: true,
 		Data: gin.H{
-			"access_token": newAccessToken,
-			"expires_in":   int64(c.cfg.JWT.ExpiresIn.Seconds()),
-			"token_type":   "Bearer",
+			"access_token":  accessToken,
+			"refresh_token": refreshToken,
+			"expires_in":    int64(c.cfg.JWT.ExpiresIn.Seconds()),
+			"token_type":    "Bearer",
 		},
 		Message:   "令牌刷新成功",
 		Timestamp: time.Now().Unix(),
@@ -16,6 +16,7 @@ import (
 type Controllers struct {
 	Auth        *AuthController        // 认证控制器
 	User        *UserController        // 用户控制器
+	APIKey      *APIKeyController      // API Key控制器
 	Token       *TokenController       // 代币控制器
 	Chain       *ChainController       // 区块链控制器
 	Quote       *QuoteController       // 报价控制器
@@ -31,48 +32,22 @@ func New(srvs *services.Services, cfg *config.Config, logger *logrus.Logger) *Co
 	return &Controllers{
 		Auth:        NewAuthController(srvs.Auth, cfg, logger),
 		User:        NewUserController(srvs.User, cfg, logger),
+		APIKey:      NewAPIKeyController(srvs.APIKey, cfg, logger),
 		Token:       NewTokenController(srvs.Token, srvs.Chain, cfg, logger),
 		Chain:       NewChainController(srvs.Chain, cfg, logger),
 		Quote:       NewQuoteController(srvs.Quote, cfg, logger),
-		Swap:        &SwapController{},        // TODO: 实现
-		Transaction: &TransactionController{}, // TODO: 实现
-		Stats:       &StatsController{},       // TODO: 实现
-		Health:      &HealthController{},      // TODO: 实现
+		Swap:        NewSwapController(srvs.Swap, cfg, logger),
+		Transaction: NewTransactionController(srvs.Swap, cfg, logger),
+		Stats:       NewStatsController(srvs.Stats, cfg, logger),
+		Health:      &HealthController{}, // TODO: 实现
 	}
 }
 
 // 临时控制器结构体（待实现）
-type SwapController struct{}
-type TransactionController struct{}
-type StatsController struct{}
 type HealthController struct{}
 
 // 临时方法（待实现）
 
-func (c *SwapController) CreateSwap(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{"message": "Swap controller not implemented yet"})
-}
-func (c *SwapController) GetSwapStatus(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{"message": "Swap controller not implemented yet"})
-}
-
-func (c *TransactionController) GetTransactions(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{"message": "Transaction controller not implemented yet"})
-}
-func (c *TransactionController) GetTransaction(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{"message": "Transaction controller not implemented yet"})
-}
-
-func (c *StatsController) GetSystemStats(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{"message": "Stats controller not implemented yet"})
-}
-func (c *StatsController) GetAggregatorStats(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{"message": "Stats controller not implemented yet"})
-}
-func (c *StatsController) GetTokenPairStats(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{"message": "Stats controller not implemented yet"})
-}
-
 func (c *HealthController) HealthCheck(ctx *gin.Context) {
 	ctx.JSON(200, gin.H{
 		"status":    "healthy",
@@ -92,6 +92,74 @@ func (c *QuoteController) GetQuote(ctx *gin.Context) {
 		requestID, quote.BestAggregator, quote.AmountOut.String(), time.Since(startTime))
 }
 
+// StreamQuote 流式获取报价
+// POST /api/v1/quotes/stream
+// 以Server-Sent Events推送报价：每个聚合器报价到达时发送quote事件，
+// 最后发送包含最优报价的best事件；流开始后的失败以error事件通知
+func (c *QuoteController) StreamQuote(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	c.logger.Infof("[%s] 收到流式报价请求", requestID)
+
+	// 绑定报价请求参数
+	var req types.QuoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warnf("[%s] 流式报价请求参数无效: %v", requestID, err)
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "报价请求参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	// SSE响应头在第一个事件发送前才写入，
+	// 这样参数校验、代币查询等前置错误仍可按普通JSON错误返回
+	streaming := false
+	eventCount := 0
+	startTime := time.Now()
+	err := c.quoteService.StreamQuote(ctx.Request.Context(), &req, func(event string, data interface{}) error {
+		if !streaming {
+			ctx.Header("Content-Type", "text/event-stream")
+			ctx.Header("Cache-Control", "no-cache")
+			ctx.Header("Connection", "keep-alive")
+			ctx.Header("X-Accel-Buffering", "no")
+			ctx.Status(http.StatusOK)
+			streaming = true
+		}
+
+		ctx.SSEvent(event, data)
+		ctx.Writer.Flush()
+		eventCount++
+		return ctx.Request.Context().Err()
+	})
+
+	if err != nil {
+		if !streaming {
+			c.handleServiceError(ctx, err, "获取流式报价失败")
+			return
+		}
+
+		c.logger.Warnf("[%s] 流式报价中断: %v", requestID, err)
+		apiErr := &types.APIError{Code: types.ErrCodeInternal, Message: "获取流式报价失败"}
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			apiErr.Code = serviceErr.Code
+			apiErr.Message = serviceErr.Message
+		}
+		ctx.SSEvent(types.QuoteStreamEventError, apiErr)
+		ctx.Writer.Flush()
+		return
+	}
+
+	c.logger.Infof("[%s] 流式报价请求处理完成: events=%d, duration=%v",
+		requestID, eventCount, time.Since(startTime))
+}
+
 // GetQuoteHistory 获取报价历史
 // GET /api/v1/quotes/history
 // 返回用户的历史报价记录，支持分页和筛选
@@ -0,0 +1,271 @@
+// Package controllers 统计控制器实现
+// 处理系统概览、聚合器统计与排名、代币对统计和交易量查询请求
+// 统计接口公开访问，支持time_range参数(1h/24h/7d/30d/90d)筛选时间范围
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"defi-aggregator/business-logic/internal/services"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// StatsController 统计控制器
+// 处理统计相关的HTTP请求
+type StatsController struct {
+	statsService services.StatsService // 统计业务服务
+	cfg          *config.Config        // 应用配置
+	logger       *logrus.Logger        // 日志记录器
+}
+
+// NewStatsController 创建统计控制器实例
+func NewStatsController(statsService services.StatsService, cfg *config.Config, logger *logrus.Logger) *StatsController {
+	return &StatsController{
+		statsService: statsService,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// ========================================
+// 系统统计接口
+// ========================================
+
+// GetSystemStats 获取系统统计
+// GET /api/v1/stats/system
+func (c *StatsController) GetSystemStats(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	stats, err := c.statsService.GetSystemStats()
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取系统统计失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      stats,
+		Message:   "获取系统统计成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// GetTradingVolume 获取每日交易量
+// GET /api/v1/stats/volume?time_range=7d
+func (c *StatsController) GetTradingVolume(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	req, ok := c.bindStatsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	volumes, err := c.statsService.GetTradingVolume(req.TimeRange)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取交易量失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      volumes,
+		Message:   "获取交易量成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 聚合器统计接口
+// ========================================
+
+// GetAggregatorStats 获取聚合器统计
+// GET /api/v1/stats/aggregators?time_range=24h&aggregator_id=1
+// 指定aggregator_id时返回该聚合器的汇总和小时明细
+func (c *StatsController) GetAggregatorStats(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	req, ok := c.bindStatsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Debugf("[%s] 获取聚合器统计: timeRange=%s", requestID, req.TimeRange)
+
+	stats, err := c.statsService.GetAggregatorStats(req.AggregatorID, req.TimeRange)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取聚合器统计失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      stats,
+		Message:   "获取聚合器统计成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// GetAggregatorRankings 获取聚合器排名
+// GET /api/v1/stats/aggregators/rankings?time_range=24h
+func (c *StatsController) GetAggregatorRankings(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	req, ok := c.bindStatsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	rankings, err := c.statsService.GetAggregatorRankings(req.TimeRange)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取聚合器排名失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      rankings,
+		Message:   "获取聚合器排名成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 代币对统计接口
+// ========================================
+
+// GetTokenPairStats 获取代币对日统计
+// GET /api/v1/stats/token-pairs?time_range=7d&from_token_id=1&to_token_id=2
+func (c *StatsController) GetTokenPairStats(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	req, ok := c.bindStatsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Debugf("[%s] 获取代币对统计: from=%d, to=%d, timeRange=%s",
+		requestID, req.FromTokenID, req.ToTokenID, req.TimeRange)
+
+	stats, err := c.statsService.GetTokenPairStats(req.FromTokenID, req.ToTokenID, req.TimeRange)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取代币对统计失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      stats,
+		Message:   "获取代币对统计成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// GetPopularTokenPairs 获取热门代币对
+// GET /api/v1/stats/token-pairs/popular?time_range=7d&limit=10
+func (c *StatsController) GetPopularTokenPairs(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	req, ok := c.bindStatsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	pairs, err := c.statsService.GetPopularTokenPairs(req.Limit, req.TimeRange)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取热门代币对失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      pairs,
+		Message:   "获取热门代币对成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// bindStatsRequest 绑定统计查询参数，无效时直接返回400
+func (c *StatsController) bindStatsRequest(ctx *gin.Context) (*types.StatsRequest, bool) {
+	var req types.StatsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		requestID := ctx.GetString("request_id")
+		c.logger.Warnf("[%s] 统计查询参数无效: %v", requestID, err)
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "统计查询参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return nil, false
+	}
+	return &req, true
+}
+
+// handleServiceError 处理业务服务错误
+func (c *StatsController) handleServiceError(ctx *gin.Context, err error, defaultMessage string) {
+	requestID := ctx.GetString("request_id")
+
+	// 检查是否为业务服务错误
+	if serviceErr, ok := err.(*services.ServiceError); ok {
+		// 根据错误代码确定HTTP状态码
+		var statusCode int
+		switch serviceErr.Code {
+		case types.ErrCodeValidation:
+			statusCode = http.StatusBadRequest
+		case types.ErrCodeNotFound:
+			statusCode = http.StatusNotFound
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+
+		ctx.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    serviceErr.Code,
+				Message: serviceErr.Message,
+				Details: serviceErr.Details,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		// 记录错误日志
+		if statusCode >= 500 {
+			c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+		} else {
+			c.logger.Warnf("[%s] %s: %v", requestID, defaultMessage, err)
+		}
+	} else {
+		// 未知错误，返回通用内部错误
+		ctx.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeInternal,
+				Message: defaultMessage,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+	}
+}
@@ -0,0 +1,225 @@
+// Package controllers 交易控制器实现
+// 处理交易创建、交易哈希提交和交易状态查询的HTTP请求
+// 交易接口对匿名用户开放，登录用户的交易归属到其账户
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"defi-aggregator/business-logic/internal/services"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// SwapController 交易控制器
+// 处理交易相关的HTTP请求
+type SwapController struct {
+	swapService services.SwapService // 交易业务服务
+	cfg         *config.Config       // 应用配置
+	logger      *logrus.Logger       // 日志记录器
+}
+
+// NewSwapController 创建交易控制器实例
+func NewSwapController(swapService services.SwapService, cfg *config.Config, logger *logrus.Logger) *SwapController {
+	return &SwapController{
+		swapService: swapService,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// ========================================
+// 交易核心接口
+// ========================================
+
+// CreateSwap 创建交易
+// POST /api/v1/swaps
+// 基于报价请求构建可执行交易，返回calldata供钱包签名发送
+func (c *SwapController) CreateSwap(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	// 绑定交易请求参数
+	var req types.SwapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warnf("[%s] 交易请求参数无效: %v", requestID, err)
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "交易请求参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	c.logger.Infof("[%s] 收到交易请求: quoteRequestID=%s, user=%s", requestID, req.RequestID, req.UserAddress)
+
+	// 调用交易服务构建交易
+	swap, err := c.swapService.CreateSwap(ctx.Request.Context(), c.currentUserID(ctx), &req)
+	if err != nil {
+		c.handleServiceError(ctx, err, "创建交易失败")
+		return
+	}
+
+	// 返回可执行交易
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      swap,
+		Message:   "创建交易成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+
+	c.logger.Infof("[%s] 交易创建成功: transactionID=%d, provider=%s", requestID, swap.TransactionID, swap.Provider)
+}
+
+// SubmitTransaction 提交交易哈希
+// POST /api/v1/swaps/submit
+// 钱包广播交易后回传交易哈希，用于跟踪链上确认状态
+func (c *SwapController) SubmitTransaction(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+
+	// 绑定交易哈希提交请求
+	var req types.SubmitSwapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warnf("[%s] 交易哈希提交参数无效: %v", requestID, err)
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "交易哈希提交参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	c.logger.Infof("[%s] 收到交易哈希: transactionID=%d, txHash=%s", requestID, req.TransactionID, req.TxHash)
+
+	// 调用交易服务记录交易哈希
+	transaction, err := c.swapService.SubmitTransaction(c.currentUserID(ctx), &req)
+	if err != nil {
+		c.handleServiceError(ctx, err, "提交交易哈希失败")
+		return
+	}
+
+	// 返回更新后的交易信息
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      transaction,
+		Message:   "交易哈希提交成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// GetSwapStatus 查询交易状态
+// GET /api/v1/swaps/:txHash
+// 根据链上交易哈希查询交易状态
+func (c *SwapController) GetSwapStatus(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	txHash := ctx.Param("txHash")
+
+	c.logger.Debugf("[%s] 查询交易状态: txHash=%s", requestID, txHash)
+
+	// 调用交易服务查询状态
+	transaction, err := c.swapService.GetSwapStatus(txHash)
+	if err != nil {
+		c.handleServiceError(ctx, err, "查询交易状态失败")
+		return
+	}
+
+	// 返回交易状态
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      transaction,
+		Message:   "查询交易状态成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// currentUserID 获取当前登录用户ID，匿名请求返回nil
+func (c *SwapController) currentUserID(ctx *gin.Context) *uint {
+	if uid, exists := ctx.Get("user_id"); exists {
+		if id, ok := uid.(uint); ok {
+			return &id
+		}
+	}
+	return nil
+}
+
+// handleServiceError 处理业务服务错误
+func (c *SwapController) handleServiceError(ctx *gin.Context, err error, defaultMessage string) {
+	requestID := ctx.GetString("request_id")
+
+	// 检查是否为业务服务错误
+	if serviceErr, ok := err.(*services.ServiceError); ok {
+		// 根据错误代码确定HTTP状态码
+		var statusCode int
+		switch serviceErr.Code {
+		case types.ErrCodeValidation:
+			statusCode = http.StatusBadRequest
+		case types.ErrCodeUnauthorized:
+			statusCode = http.StatusUnauthorized
+		case types.ErrCodeForbidden:
+			statusCode = http.StatusForbidden
+		case types.ErrCodeNotFound:
+			statusCode = http.StatusNotFound
+		case types.ErrCodeConflict:
+			statusCode = http.StatusConflict
+		case services.ErrQuoteExpired.Code:
+			statusCode = http.StatusGone
+		case types.ErrCodeExternalAPI:
+			statusCode = http.StatusServiceUnavailable
+		case types.ErrCodeRateLimit:
+			statusCode = http.StatusTooManyRequests
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+
+		ctx.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    serviceErr.Code,
+				Message: serviceErr.Message,
+				Details: serviceErr.Details,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		// 记录错误日志
+		if statusCode >= 500 {
+			c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+		} else {
+			c.logger.Warnf("[%s] %s: %v", requestID, defaultMessage, err)
+		}
+	} else {
+		// 未知错误，返回通用内部错误
+		ctx.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeInternal,
+				Message: defaultMessage,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+	}
+}
@@ -0,0 +1,264 @@
+// Package controllers 交易历史控制器实现
+// 处理登录用户的交易列表、交易详情以及取消、重试等交易管理请求
+// 所有接口需要JWT认证，用户只能访问自己的交易
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"defi-aggregator/business-logic/internal/services"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// TransactionController 交易历史控制器
+// 处理交易历史相关的HTTP请求
+type TransactionController struct {
+	swapService services.SwapService // 交易业务服务
+	cfg         *config.Config       // 应用配置
+	logger      *logrus.Logger       // 日志记录器
+}
+
+// NewTransactionController 创建交易历史控制器实例
+func NewTransactionController(swapService services.SwapService, cfg *config.Config, logger *logrus.Logger) *TransactionController {
+	return &TransactionController{
+		swapService: swapService,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// ========================================
+// 交易查询接口
+// ========================================
+
+// GetTransactions 获取交易列表
+// GET /api/v1/transactions
+// 返回当前用户的交易记录，支持按状态、链和时间范围筛选及分页
+func (c *TransactionController) GetTransactions(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	// 绑定筛选和分页参数
+	var req types.TransactionListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warnf("[%s] 交易列表参数无效: %v", requestID, err)
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "交易列表参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	c.logger.Debugf("[%s] 获取交易列表: userID=%d, page=%d, pageSize=%d",
+		requestID, userID, req.Page, req.PageSize)
+
+	// 调用交易服务获取当前用户的交易
+	transactions, meta, err := c.swapService.GetTransactionHistory(&userID, &req)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取交易列表失败")
+		return
+	}
+
+	// 返回交易列表
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      transactions,
+		Meta:      meta,
+		Message:   "获取交易列表成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+
+	c.logger.Debugf("[%s] 交易列表获取成功: total=%d", requestID, meta.Total)
+}
+
+// GetTransaction 获取交易详情
+// GET /api/v1/transactions/:id
+func (c *TransactionController) GetTransaction(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	id, ok := c.parseTransactionID(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Debugf("[%s] 获取交易详情: id=%d, userID=%d", requestID, id, userID)
+
+	// 调用交易服务获取详情
+	transaction, err := c.swapService.GetTransactionDetails(id, userID)
+	if err != nil {
+		c.handleServiceError(ctx, err, "获取交易详情失败")
+		return
+	}
+
+	// 返回交易详情
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      transaction,
+		Message:   "获取交易详情成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// ========================================
+// 交易管理接口
+// ========================================
+
+// CancelTransaction 取消交易
+// POST /api/v1/transactions/:id/cancel
+// 只能取消尚未提交交易哈希的待处理交易
+func (c *TransactionController) CancelTransaction(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	id, ok := c.parseTransactionID(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Infof("[%s] 取消交易: id=%d, userID=%d", requestID, id, userID)
+
+	// 调用交易服务取消交易
+	if err := c.swapService.CancelTransaction(id, userID); err != nil {
+		c.handleServiceError(ctx, err, "取消交易失败")
+		return
+	}
+
+	// 返回成功响应
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      gin.H{"transaction_id": id, "status": types.TransactionStatusCancelled},
+		Message:   "交易已取消",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// RetryTransaction 重试交易
+// POST /api/v1/transactions/:id/retry
+// 对失败或已取消的交易重新构建一笔新交易
+func (c *TransactionController) RetryTransaction(ctx *gin.Context) {
+	requestID := ctx.GetString("request_id")
+	userID := ctx.GetUint("user_id")
+
+	id, ok := c.parseTransactionID(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Infof("[%s] 重试交易: id=%d, userID=%d", requestID, id, userID)
+
+	// 调用交易服务重试交易
+	swap, err := c.swapService.RetryTransaction(ctx.Request.Context(), id, userID)
+	if err != nil {
+		c.handleServiceError(ctx, err, "重试交易失败")
+		return
+	}
+
+	// 返回新的可执行交易
+	ctx.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      swap,
+		Message:   "交易重试成功",
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+
+	c.logger.Infof("[%s] 交易重试成功: id=%d -> %d", requestID, id, swap.TransactionID)
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// parseTransactionID 解析路径中的交易ID，无效时直接返回400
+func (c *TransactionController) parseTransactionID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeValidation,
+				Message: "无效的交易ID",
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: ctx.GetString("request_id"),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleServiceError 处理业务服务错误
+func (c *TransactionController) handleServiceError(ctx *gin.Context, err error, defaultMessage string) {
+	requestID := ctx.GetString("request_id")
+
+	// 检查是否为业务服务错误
+	if serviceErr, ok := err.(*services.ServiceError); ok {
+		// 根据错误代码确定HTTP状态码
+		var statusCode int
+		switch serviceErr.Code {
+		case types.ErrCodeValidation:
+			statusCode = http.StatusBadRequest
+		case types.ErrCodeUnauthorized:
+			statusCode = http.StatusUnauthorized
+		case types.ErrCodeForbidden:
+			statusCode = http.StatusForbidden
+		case types.ErrCodeNotFound:
+			statusCode = http.StatusNotFound
+		case types.ErrCodeConflict:
+			statusCode = http.StatusConflict
+		case types.ErrCodeExternalAPI:
+			statusCode = http.StatusServiceUnavailable
+		case types.ErrCodeRateLimit:
+			statusCode = http.StatusTooManyRequests
+		default:
+			statusCode = http.StatusInternalServerError
+		}
+
+		ctx.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    serviceErr.Code,
+				Message: serviceErr.Message,
+				Details: serviceErr.Details,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		// 记录错误日志
+		if statusCode >= 500 {
+			c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+		} else {
+			c.logger.Warnf("[%s] %s: %v", requestID, defaultMessage, err)
+		}
+	} else {
+		// 未知错误，返回通用内部错误
+		ctx.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeInternal,
+				Message: defaultMessage,
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+
+		c.logger.Errorf("[%s] %s: %v", requestID, defaultMessage, err)
+	}
+}
@@ -180,6 +180,26 @@ type UserPreferences struct {
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"` // 多对一：属于某个用户
 }
 
+// APIKey 用户API Key模型
+// 对应数据库表: api_keys
+// 供合作方以X-API-Key方式调用接口，只保存密钥的SHA-256哈希，明文不落库
+type APIKey struct {
+	BaseModel
+	UserID     uint       `gorm:"not null;index" json:"user_id"`                   // 所属用户ID
+	Name       string     `gorm:"size:100;not null" json:"name"`                   // 名称
+	KeyPrefix  string     `gorm:"size:16;not null" json:"key_prefix"`              // 明文前缀 (用于展示和识别)
+	KeyHash    string     `gorm:"size:64;uniqueIndex;not null" json:"-"`           // SHA-256哈希 (敏感信息不序列化)
+	Scopes     string     `gorm:"size:100;not null;default:'quote'" json:"scopes"` // 权限范围 (逗号分隔)
+	Tier       string     `gorm:"size:20;not null;default:'free'" json:"tier"`     // 配额等级
+	IsActive   bool       `gorm:"default:true" json:"is_active"`                   // 是否有效
+	LastUsedAt *time.Time `gorm:"null" json:"last_used_at"`                        // 最后使用时间
+	ExpiresAt  *time.Time `gorm:"null" json:"expires_at"`                          // 过期时间 (为空表示不过期)
+	RevokedAt  *time.Time `gorm:"null" json:"revoked_at"`                          // 撤销时间
+
+	// 关系定义
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"` // 多对一：属于某个用户
+}
+
 // ========================================
 // 报价相关模型
 // ========================================
@@ -233,6 +253,9 @@ type QuoteResponse struct {
 	Success         bool             `gorm:"not null;index" json:"success"`                  // 是否成功
 	AmountOut       *decimal.Decimal `gorm:"type:decimal(78,0);null" json:"amount_out"`      // 输出数量
 	GasEstimate     *uint64          `gorm:"null" json:"gas_estimate"`                       // Gas估算
+	GasPrice        *decimal.Decimal `gorm:"type:decimal(30,0);null" json:"gas_price"`       // 折算Gas费用使用的Gas价格(wei)
+	GasCost         *decimal.Decimal `gorm:"type:decimal(78,0);null" json:"gas_cost"`        // Gas费用折算的输出代币数量
+	NetAmountOut    *decimal.Decimal `gorm:"type:decimal(78,0);null" json:"net_amount_out"`  // 扣除Gas费用后的净输出数量
 	PriceImpact     *decimal.Decimal `gorm:"type:decimal(8,6);null" json:"price_impact"`     // 价格冲击
 	ConfidenceScore *decimal.Decimal `gorm:"type:decimal(3,2);null" json:"confidence_score"` // 置信度分数
 	PriceRank       *int             `gorm:"null" json:"price_rank"`                         // 价格排名
@@ -255,13 +278,13 @@ type QuoteResponse struct {
 // 记录用户执行的交易的完整信息，从创建到确认的全生命周期
 type Transaction struct {
 	BaseModel
-	UserID         *uint  `gorm:"null;index" json:"user_id"`                          // 用户ID
-	QuoteRequestID *uint  `gorm:"null" json:"quote_request_id"`                       // 关联的报价请求ID
-	TxHash         string `gorm:"size:66;uniqueIndex" json:"tx_hash"`                 // 交易哈希
-	ChainID        uint   `gorm:"not null;index" json:"chain_id"`                     // 链ID
-	FromTokenID    uint   `gorm:"not null;index:idx_token_pair" json:"from_token_id"` // 源代币ID
-	ToTokenID      uint   `gorm:"not null;index:idx_token_pair" json:"to_token_id"`   // 目标代币ID
-	AggregatorID   uint   `gorm:"not null;index" json:"aggregator_id"`                // 聚合器ID
+	UserID         *uint   `gorm:"null;index" json:"user_id"`                          // 用户ID
+	QuoteRequestID *uint   `gorm:"null" json:"quote_request_id"`                       // 关联的报价请求ID
+	TxHash         *string `gorm:"size:66;uniqueIndex;null" json:"tx_hash"`            // 交易哈希 (钱包提交前为空)
+	ChainID        uint    `gorm:"not null;index" json:"chain_id"`                     // 链ID
+	FromTokenID    uint    `gorm:"not null;index:idx_token_pair" json:"from_token_id"` // 源代币ID
+	ToTokenID      uint    `gorm:"not null;index:idx_token_pair" json:"to_token_id"`   // 目标代币ID
+	AggregatorID   uint    `gorm:"not null;index" json:"aggregator_id"`                // 聚合器ID
 
 	// 交易参数
 	AmountIn          decimal.Decimal  `gorm:"type:decimal(78,0);not null" json:"amount_in"`           // 实际输入数量
@@ -292,9 +315,10 @@ type Transaction struct {
 	ConfirmationCount int        `gorm:"default:0" json:"confirmation_count"`           // 确认数
 
 	// 元数据
-	RouteData   string     `gorm:"type:jsonb" json:"route_data"`  // 交易路径详情 (JSONB)
-	ErrorReason string     `gorm:"type:text" json:"error_reason"` // 失败原因
-	ConfirmedAt *time.Time `gorm:"null" json:"confirmed_at"`      // 确认时间
+	RouteData       string     `gorm:"type:jsonb" json:"route_data"`  // 交易路径详情 (JSONB)
+	ErrorReason     string     `gorm:"type:text" json:"error_reason"` // 失败原因
+	SubmitTokenHash string     `gorm:"size:64" json:"-"`              // 提交凭证SHA-256哈希 (敏感信息不序列化)
+	ConfirmedAt     *time.Time `gorm:"null" json:"confirmed_at"`      // 确认时间
 
 	// 关系定义
 	User         *User         `gorm:"foreignKey:UserID" json:"user,omitempty"`                  // 多对一：属于某个用户
@@ -404,6 +428,10 @@ func (UserPreferences) TableName() string {
 	return "user_preferences"
 }
 
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
 func (QuoteRequest) TableName() string {
 	return "quote_requests"
 }
@@ -458,8 +486,8 @@ func (t *Token) BeforeCreate(tx *gorm.DB) error {
 // BeforeCreate 交易创建前验证
 func (tr *Transaction) BeforeCreate(tx *gorm.DB) error {
 	// 验证交易哈希格式
-	if tr.TxHash != "" && (len(tr.TxHash) != 66 || tr.TxHash[:2] != "0x") {
-		return fmt.Errorf("无效的交易哈希格式: %s", tr.TxHash)
+	if tr.TxHash != nil && (len(*tr.TxHash) != 66 || (*tr.TxHash)[:2] != "0x") {
+		return fmt.Errorf("无效的交易哈希格式: %s", *tr.TxHash)
 	}
 
 	// 验证用户地址格式
@@ -0,0 +1,97 @@
+// Package repository API Key数据访问层实现
+// 实现APIKeyRepository接口，提供API Key的创建、查询和状态更新
+// 密钥只以SHA-256哈希形式存储，按哈希查找用于网关鉴权
+package repository
+
+import (
+	"time"
+
+	"defi-aggregator/business-logic/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyRepository API Key数据访问层实现
+type apiKeyRepository struct {
+	db *gorm.DB // 数据库连接实例
+}
+
+// NewAPIKeyRepository 创建API Key数据访问层实例
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create 创建API Key
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		return NewRepositoryError("Create", "APIKey", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取API Key
+// 记录不存在时返回的RepositoryError.Err为gorm.ErrRecordNotFound
+func (r *apiKeyRepository) GetByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, NewRepositoryError("GetByID", "APIKey", err)
+	}
+	return &key, nil
+}
+
+// GetByHash 根据密钥哈希获取API Key
+// 预加载所属用户，便于鉴权时校验用户状态
+func (r *apiKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.Preload("User").
+		Where("key_hash = ?", keyHash).
+		First(&key).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetByHash", "APIKey", err)
+	}
+	return &key, nil
+}
+
+// GetByUserID 获取用户的所有API Key(包括已撤销的)，按创建时间倒序
+func (r *apiKeyRepository) GetByUserID(userID uint) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetByUserID", "APIKey", err)
+	}
+	return keys, nil
+}
+
+// CountActiveByUserID 统计用户有效的API Key数量
+func (r *apiKeyRepository) CountActiveByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.APIKey{}).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Count(&count).Error
+	if err != nil {
+		return 0, NewRepositoryError("CountActiveByUserID", "APIKey", err)
+	}
+	return count, nil
+}
+
+// Update 更新API Key
+func (r *apiKeyRepository) Update(key *models.APIKey) error {
+	if err := r.db.Omit("User").Save(key).Error; err != nil {
+		return NewRepositoryError("Update", "APIKey", err)
+	}
+	return nil
+}
+
+// UpdateLastUsed 更新最后使用时间
+// 只更新last_used_at字段，避免鉴权高频调用时覆盖其他字段
+func (r *apiKeyRepository) UpdateLastUsed(id uint, usedAt time.Time) error {
+	err := r.db.Model(&models.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+	if err != nil {
+		return NewRepositoryError("UpdateLastUsed", "APIKey", err)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"defi-aggregator/business-logic/internal/types"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ========================================
@@ -40,11 +41,6 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
-// NewStatsRepository 创建统计Repository实例
-func NewStatsRepository(db *gorm.DB) StatsRepository {
-	return &statsRepository{db: db}
-}
-
 // ========================================
 // Repository实现结构体
 // ========================================
@@ -54,7 +50,6 @@ type chainRepository struct{ db *gorm.DB }
 type aggregatorRepository struct{ db *gorm.DB }
 type quoteRequestRepository struct{ db *gorm.DB }
 type transactionRepository struct{ db *gorm.DB }
-type statsRepository struct{ db *gorm.DB }
 
 // ========================================
 // TokenRepository接口实现
@@ -321,7 +316,7 @@ func (r *quoteRequestRepository) CreateResponse(response *models.QuoteResponse)
 }
 func (r *quoteRequestRepository) GetResponses(requestID uint) ([]*models.QuoteResponse, error) {
 	var responses []*models.QuoteResponse
-	err := r.db.Where("quote_request_id = ?", requestID).Find(&responses).Error
+	err := r.db.Preload("Aggregator").Where("quote_request_id = ?", requestID).Order("id ASC").Find(&responses).Error
 	return responses, err
 }
 func (r *quoteRequestRepository) GetTotalCount() (int64, error) {
@@ -359,16 +354,17 @@ func (r *transactionRepository) Create(transaction *models.Transaction) error {
 }
 func (r *transactionRepository) GetByID(id uint) (*models.Transaction, error) {
 	var tx models.Transaction
-	err := r.db.Preload("FromToken").Preload("ToToken").First(&tx, id).Error
+	err := r.withDetails(r.db).First(&tx, id).Error
 	return &tx, err
 }
 func (r *transactionRepository) GetByTxHash(txHash string) (*models.Transaction, error) {
 	var tx models.Transaction
-	err := r.db.Where("tx_hash = ?", txHash).First(&tx).Error
+	err := r.withDetails(r.db).Where("tx_hash = ?", txHash).First(&tx).Error
 	return &tx, err
 }
 func (r *transactionRepository) Update(transaction *models.Transaction) error {
-	return r.db.Save(transaction).Error
+	// 只更新交易本身，预加载的代币、聚合器等关联数据不回写
+	return r.db.Omit(clause.Associations).Save(transaction).Error
 }
 func (r *transactionRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Transaction{}, id).Error
@@ -378,12 +374,28 @@ func (r *transactionRepository) List(req *types.TransactionListRequest) ([]*mode
 	var total int64
 	query := r.db.Model(&models.Transaction{})
 
+	if req.UserID != nil {
+		query = query.Where("user_id = ?", *req.UserID)
+	}
+	if req.Status != nil {
+		query = query.Where("status = ?", string(*req.Status))
+	}
+	if req.ChainID != nil {
+		query = query.Where("chain_id = ?", *req.ChainID)
+	}
+	if req.FromDate != nil {
+		query = query.Where("created_at >= ?", *req.FromDate)
+	}
+	if req.ToDate != nil {
+		query = query.Where("created_at <= ?", *req.ToDate)
+	}
+
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	offset := (req.Page - 1) * req.PageSize
-	err := query.Offset(offset).Limit(req.PageSize).Find(&transactions).Error
+	err := r.withDetails(query).Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&transactions).Error
 	return transactions, total, err
 }
 func (r *transactionRepository) GetByUserID(userID uint, req *types.PaginationRequest) ([]*models.Transaction, int64, error) {
@@ -405,7 +417,14 @@ func (r *transactionRepository) GetByStatus(status string) ([]*models.Transactio
 	return transactions, err
 }
 func (r *transactionRepository) GetPendingTransactions() ([]*models.Transaction, error) {
-	return r.GetByStatus("pending")
+	var transactions []*models.Transaction
+	err := r.db.Preload("ToToken").Where("status = ?", "pending").Order("created_at ASC").Find(&transactions).Error
+	return transactions, err
+}
+func (r *transactionRepository) GetByQuoteRequestID(quoteRequestID uint) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.Where("quote_request_id = ?", quoteRequestID).Order("created_at DESC").Find(&transactions).Error
+	return transactions, err
 }
 func (r *transactionRepository) GetRecentTransactions(limit int) ([]*models.Transaction, error) {
 	var transactions []*models.Transaction
@@ -425,52 +444,12 @@ func (r *transactionRepository) GetTopTokenPairs(limit int) ([]map[string]interf
 func (r *transactionRepository) WithTx(tx *gorm.DB) interface{} {
 	return &transactionRepository{db: tx}
 }
-func (r *transactionRepository) HealthCheck() error {
-	var count int64
-	return r.db.Model(&models.Transaction{}).Limit(1).Count(&count).Error
-}
 
-// StatsRepository基础实现
-func (r *statsRepository) GetSystemStats() (*types.SystemStatsResponse, error) {
-	return &types.SystemStatsResponse{}, nil
-}
-func (r *statsRepository) GetDailyActiveUsers(date string) (int, error) { return 0, nil }
-func (r *statsRepository) GetTotalVolume() (string, error)              { return "0", nil }
-func (r *statsRepository) CreateAggregatorStats(stats *models.AggregatorStatsHourly) error {
-	return r.db.Create(stats).Error
-}
-func (r *statsRepository) GetAggregatorStats(aggregatorID uint, hours int) ([]*models.AggregatorStatsHourly, error) {
-	var stats []*models.AggregatorStatsHourly
-	err := r.db.Where("aggregator_id = ?", aggregatorID).Find(&stats).Error
-	return stats, err
-}
-func (r *statsRepository) GetAggregatorRankings() ([]map[string]interface{}, error) {
-	return nil, nil
-}
-func (r *statsRepository) CreateTokenPairStats(stats *models.TokenPairStatsDaily) error {
-	return r.db.Create(stats).Error
-}
-func (r *statsRepository) GetTokenPairStats(fromTokenID, toTokenID uint, days int) ([]*models.TokenPairStatsDaily, error) {
-	var stats []*models.TokenPairStatsDaily
-	err := r.db.Where("from_token_id = ? AND to_token_id = ?", fromTokenID, toTokenID).Find(&stats).Error
-	return stats, err
+// withDetails 预加载交易详情展示所需的关联数据
+func (r *transactionRepository) withDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("FromToken").Preload("ToToken").Preload("Aggregator").Preload("QuoteRequest")
 }
-func (r *statsRepository) GetPopularTokenPairs(limit int) ([]map[string]interface{}, error) {
-	return nil, nil
-}
-func (r *statsRepository) CreateSystemMetric(metric *models.SystemMetrics) error {
-	return r.db.Create(metric).Error
-}
-func (r *statsRepository) GetSystemMetrics(metricName string, hours int) ([]*models.SystemMetrics, error) {
-	var metrics []*models.SystemMetrics
-	err := r.db.Where("metric_name = ?", metricName).Find(&metrics).Error
-	return metrics, err
-}
-func (r *statsRepository) GetLatestMetrics() (map[string]interface{}, error) {
-	return nil, nil
-}
-func (r *statsRepository) WithTx(tx *gorm.DB) interface{} { return &statsRepository{db: tx} }
-func (r *statsRepository) HealthCheck() error {
+func (r *transactionRepository) HealthCheck() error {
 	var count int64
-	return r.db.Model(&models.SystemMetrics{}).Limit(1).Count(&count).Error
+	return r.db.Model(&models.Transaction{}).Limit(1).Count(&count).Error
 }
@@ -5,10 +5,12 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"defi-aggregator/business-logic/internal/models"
 	"defi-aggregator/business-logic/internal/types"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +24,7 @@ type Repositories struct {
 	QuoteRequest QuoteRequestRepository // 报价请求数据访问
 	Transaction  TransactionRepository  // 交易数据访问
 	Stats        StatsRepository        // 统计数据访问
+	APIKey       APIKeyRepository       // API Key数据访问
 }
 
 // New 创建新的数据访问层实例
@@ -35,6 +38,7 @@ func New(db *gorm.DB) *Repositories {
 		QuoteRequest: NewQuoteRequestRepository(db),
 		Transaction:  NewTransactionRepository(db),
 		Stats:        NewStatsRepository(db),
+		APIKey:       NewAPIKeyRepository(db),
 	}
 }
 
@@ -66,6 +70,17 @@ type UserRepository interface {
 	UpdateNonce(userID uint, nonce string) error // 更新登录随机数
 }
 
+// APIKeyRepository API Key数据访问接口
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error                   // 创建API Key
+	GetByID(id uint) (*models.APIKey, error)           // 根据ID获取API Key
+	GetByHash(keyHash string) (*models.APIKey, error)  // 根据密钥哈希获取API Key(预加载用户)
+	GetByUserID(userID uint) ([]*models.APIKey, error) // 获取用户的所有API Key
+	CountActiveByUserID(userID uint) (int64, error)    // 统计用户有效的API Key数量
+	Update(key *models.APIKey) error                   // 更新API Key
+	UpdateLastUsed(id uint, usedAt time.Time) error    // 更新最后使用时间
+}
+
 // ========================================
 // 代币相关数据访问接口
 // ========================================
@@ -182,6 +197,7 @@ type TransactionRepository interface {
 	GetByUserID(userID uint, req *types.PaginationRequest) ([]*models.Transaction, int64, error) // 获取用户交易
 	GetByStatus(status string) ([]*models.Transaction, error)                                    // 根据状态获取交易
 	GetPendingTransactions() ([]*models.Transaction, error)                                      // 获取待处理交易
+	GetByQuoteRequestID(quoteRequestID uint) ([]*models.Transaction, error)                      // 获取报价请求关联的交易
 	GetRecentTransactions(limit int) ([]*models.Transaction, error)                              // 获取最近交易
 
 	// 统计操作
@@ -199,17 +215,29 @@ type StatsRepository interface {
 	// 系统统计
 	GetSystemStats() (*types.SystemStatsResponse, error) // 获取系统统计
 	GetDailyActiveUsers(date string) (int, error)        // 获取日活跃用户数
-	GetTotalVolume() (string, error)                     // 获取总交易量
+	GetActiveUsers(from, to time.Time) (int, error)      // 获取时间范围内活跃地址数
+	GetTotalVolume() (decimal.Decimal, error)            // 获取总交易量USD
+
+	// 统计汇总
+	RollupAggregatorStats(from, to time.Time) (int64, error) // 汇总聚合器小时统计
+	RollupTokenPairStats(from, to time.Time) (int64, error)  // 汇总代币对日统计
 
 	// 聚合器统计
-	CreateAggregatorStats(stats *models.AggregatorStatsHourly) error                          // 创建聚合器统计
-	GetAggregatorStats(aggregatorID uint, hours int) ([]*models.AggregatorStatsHourly, error) // 获取聚合器统计
-	GetAggregatorRankings() ([]map[string]interface{}, error)                                 // 获取聚合器排名
+	CreateAggregatorStats(stats *models.AggregatorStatsHourly) error                                   // 创建聚合器统计
+	GetAggregatorStats(aggregatorID uint, from, to time.Time) ([]*models.AggregatorStatsHourly, error) // 获取聚合器小时统计
+	GetAggregatorSummaries(aggregatorID *uint, from, to time.Time) ([]*types.AggregatorStats, error)   // 按聚合器汇总统计
+	GetLatencyStats(from, to time.Time) ([]*types.LatencyStats, error)                                 // 获取聚合器延迟分布
 
 	// 代币对统计
-	CreateTokenPairStats(stats *models.TokenPairStatsDaily) error                                   // 创建代币对统计
-	GetTokenPairStats(fromTokenID, toTokenID uint, days int) ([]*models.TokenPairStatsDaily, error) // 获取代币对统计
-	GetPopularTokenPairs(limit int) ([]map[string]interface{}, error)                               // 获取热门代币对
+	CreateTokenPairStats(stats *models.TokenPairStatsDaily) error                                       // 创建代币对统计
+	GetTokenPairStats(fromTokenID, toTokenID uint, from, to time.Time) ([]*types.TokenPairStats, error) // 获取代币对日统计
+	GetPopularTokenPairs(limit int, from, to time.Time) ([]*types.PopularTokenPair, error)              // 获取热门代币对
+	GetDailyVolume(from, to time.Time) ([]*types.VolumeData, error)                                     // 获取每日交易量
+
+	// 用户和性能统计
+	GetUserAnalytics(from, to time.Time) (*types.UserAnalytics, error)           // 获取用户统计
+	GetUserTrends(from, to time.Time) ([]*types.UserTrend, error)                // 获取每日用户趋势
+	GetPerformanceMetrics(from, to time.Time) (*types.PerformanceMetrics, error) // 获取性能指标
 
 	// 系统指标
 	CreateSystemMetric(metric *models.SystemMetrics) error                          // 创建系统指标
@@ -0,0 +1,557 @@
+// Package repository 统计数据访问层实现
+// 实现StatsRepository接口：将quote_requests/quote_responses/transactions原始数据
+// 汇总到aggregator_stats_hourly(按小时)和token_pair_stats_daily(按日)统计表，
+// 并基于统计表和原始表提供系统、聚合器、代币对、用户和性能统计查询
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"defi-aggregator/business-logic/internal/models"
+	"defi-aggregator/business-logic/internal/types"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// statsRepository 统计数据访问层实现
+type statsRepository struct {
+	db *gorm.DB // 数据库连接实例
+}
+
+// NewStatsRepository 创建统计Repository实例
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// ========================================
+// 统计汇总
+// ========================================
+
+// aggregatorRollupSQL 按小时汇总聚合器统计
+// 请求数和响应时间来自quote_responses，最优报价次数来自quote_requests.best_aggregator_id，
+// 成交量来自已确认交易；每次重新计算整小时数据并覆盖，重复执行结果一致
+const aggregatorRollupSQL = `
+WITH responses AS (
+	SELECT aggregator_id, date_trunc('hour', created_at) AS hour_timestamp,
+		COUNT(*) AS total_requests,
+		COUNT(*) FILTER (WHERE success) AS successful_requests,
+		COUNT(*) FILTER (WHERE NOT success) AS failed_requests,
+		ROUND(AVG(response_time_ms))::int AS avg_response_time,
+		MIN(response_time_ms) AS min_response_time,
+		MAX(response_time_ms) AS max_response_time
+	FROM quote_responses
+	WHERE created_at >= @from AND created_at < @to
+	GROUP BY 1, 2
+), best AS (
+	SELECT best_aggregator_id AS aggregator_id, date_trunc('hour', created_at) AS hour_timestamp,
+		COUNT(*) AS best_quotes_count
+	FROM quote_requests
+	WHERE best_aggregator_id IS NOT NULL AND created_at >= @from AND created_at < @to
+	GROUP BY 1, 2
+), volume AS (
+	SELECT aggregator_id, date_trunc('hour', COALESCE(confirmed_at, created_at)) AS hour_timestamp,
+		SUM(COALESCE(amount_in_usd, 0)) AS total_volume_usd
+	FROM transactions
+	WHERE status = 'confirmed' AND COALESCE(confirmed_at, created_at) >= @from AND COALESCE(confirmed_at, created_at) < @to
+	GROUP BY 1, 2
+), hours AS (
+	SELECT aggregator_id, hour_timestamp FROM responses
+	UNION SELECT aggregator_id, hour_timestamp FROM best
+	UNION SELECT aggregator_id, hour_timestamp FROM volume
+)
+INSERT INTO aggregator_stats_hourly (
+	aggregator_id, hour_timestamp, total_requests, successful_requests, failed_requests,
+	avg_response_time, min_response_time, max_response_time, total_volume_usd, best_quotes_count
+)
+SELECT h.aggregator_id, h.hour_timestamp,
+	COALESCE(r.total_requests, 0), COALESCE(r.successful_requests, 0), COALESCE(r.failed_requests, 0),
+	COALESCE(r.avg_response_time, 0), COALESCE(r.min_response_time, 0), COALESCE(r.max_response_time, 0),
+	COALESCE(v.total_volume_usd, 0), COALESCE(b.best_quotes_count, 0)
+FROM hours h
+LEFT JOIN responses r ON r.aggregator_id = h.aggregator_id AND r.hour_timestamp = h.hour_timestamp
+LEFT JOIN best b ON b.aggregator_id = h.aggregator_id AND b.hour_timestamp = h.hour_timestamp
+LEFT JOIN volume v ON v.aggregator_id = h.aggregator_id AND v.hour_timestamp = h.hour_timestamp
+ON CONFLICT (aggregator_id, hour_timestamp) DO UPDATE SET
+	total_requests = EXCLUDED.total_requests,
+	successful_requests = EXCLUDED.successful_requests,
+	failed_requests = EXCLUDED.failed_requests,
+	avg_response_time = EXCLUDED.avg_response_time,
+	min_response_time = EXCLUDED.min_response_time,
+	max_response_time = EXCLUDED.max_response_time,
+	total_volume_usd = EXCLUDED.total_volume_usd,
+	best_quotes_count = EXCLUDED.best_quotes_count`
+
+// tokenPairRollupSQL 按日汇总已确认交易的代币对统计
+// 实际输出缺失时使用预期输出，每次重新计算整日数据并覆盖
+const tokenPairRollupSQL = `
+INSERT INTO token_pair_stats_daily (
+	from_token_id, to_token_id, chain_id, date, transaction_count,
+	total_volume_from, total_volume_to, total_volume_usd, avg_price_impact, avg_gas_fee_usd, unique_users
+)
+SELECT from_token_id, to_token_id, chain_id, DATE(COALESCE(confirmed_at, created_at)),
+	COUNT(*),
+	SUM(amount_in),
+	SUM(COALESCE(amount_out_actual, amount_out_expected)),
+	SUM(COALESCE(amount_in_usd, 0)),
+	ROUND(AVG(price_impact), 6),
+	ROUND(AVG(gas_fee_usd), 2),
+	COUNT(DISTINCT LOWER(user_address))
+FROM transactions
+WHERE status = 'confirmed' AND COALESCE(confirmed_at, created_at) >= @from AND COALESCE(confirmed_at, created_at) < @to
+GROUP BY 1, 2, 3, 4
+ON CONFLICT (from_token_id, to_token_id, chain_id, date) DO UPDATE SET
+	transaction_count = EXCLUDED.transaction_count,
+	total_volume_from = EXCLUDED.total_volume_from,
+	total_volume_to = EXCLUDED.total_volume_to,
+	total_volume_usd = EXCLUDED.total_volume_usd,
+	avg_price_impact = EXCLUDED.avg_price_impact,
+	avg_gas_fee_usd = EXCLUDED.avg_gas_fee_usd,
+	unique_users = EXCLUDED.unique_users`
+
+// RollupAggregatorStats 汇总[from, to)内的聚合器小时统计
+// from应对齐到整点，否则起始小时只会汇总部分数据
+func (r *statsRepository) RollupAggregatorStats(from, to time.Time) (int64, error) {
+	result := r.db.Exec(aggregatorRollupSQL, map[string]interface{}{"from": from, "to": to})
+	if result.Error != nil {
+		return 0, NewRepositoryError("RollupAggregatorStats", "AggregatorStatsHourly", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// RollupTokenPairStats 汇总[from, to)内的代币对日统计
+// from应对齐到零点，否则起始日期只会汇总部分数据
+func (r *statsRepository) RollupTokenPairStats(from, to time.Time) (int64, error) {
+	result := r.db.Exec(tokenPairRollupSQL, map[string]interface{}{"from": from, "to": to})
+	if result.Error != nil {
+		return 0, NewRepositoryError("RollupTokenPairStats", "TokenPairStatsDaily", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ========================================
+// 系统统计
+// ========================================
+
+// GetSystemStats 获取系统统计
+func (r *statsRepository) GetSystemStats() (*types.SystemStatsResponse, error) {
+	var totalUsers, totalTransactions, supportedTokens, supportedChains int64
+
+	if err := r.db.Model(&models.User{}).Count(&totalUsers).Error; err != nil {
+		return nil, NewRepositoryError("GetSystemStats", "User", err)
+	}
+	if err := r.db.Model(&models.Transaction{}).Where("status = ?", "confirmed").Count(&totalTransactions).Error; err != nil {
+		return nil, NewRepositoryError("GetSystemStats", "Transaction", err)
+	}
+	if err := r.db.Model(&models.Token{}).Where("is_active = ?", true).Count(&supportedTokens).Error; err != nil {
+		return nil, NewRepositoryError("GetSystemStats", "Token", err)
+	}
+	if err := r.db.Model(&models.Chain{}).Where("is_active = ?", true).Count(&supportedChains).Error; err != nil {
+		return nil, NewRepositoryError("GetSystemStats", "Chain", err)
+	}
+
+	now := time.Now()
+	activeUsers, err := r.GetActiveUsers(now.Add(-24*time.Hour), now)
+	if err != nil {
+		return nil, err
+	}
+	totalVolume, err := r.GetTotalVolume()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SystemStatsResponse{
+		TotalUsers:        int(totalUsers),
+		ActiveUsers24h:    activeUsers,
+		TotalTransactions: int(totalTransactions),
+		TotalVolumeUSD:    totalVolume,
+		SupportedTokens:   int(supportedTokens),
+		SupportedChains:   int(supportedChains),
+	}, nil
+}
+
+// GetDailyActiveUsers 获取指定日期(YYYY-MM-DD)的活跃地址数
+func (r *statsRepository) GetDailyActiveUsers(date string) (int, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return 0, NewRepositoryError("GetDailyActiveUsers", "Stats", err)
+	}
+	return r.GetActiveUsers(day, day.AddDate(0, 0, 1))
+}
+
+// activeAddressesSQL 时间范围内发起过报价或交易的地址
+const activeAddressesSQL = `
+	SELECT LOWER(user_address) AS address, created_at FROM quote_requests
+	WHERE user_address <> '' AND created_at >= @from AND created_at < @to
+	UNION ALL
+	SELECT LOWER(user_address) AS address, created_at FROM transactions
+	WHERE created_at >= @from AND created_at < @to`
+
+// GetActiveUsers 获取[from, to)内发起过报价或交易的独立地址数
+func (r *statsRepository) GetActiveUsers(from, to time.Time) (int, error) {
+	var count int
+	err := r.db.Raw("SELECT COUNT(DISTINCT address) FROM ("+activeAddressesSQL+") a",
+		map[string]interface{}{"from": from, "to": to}).Scan(&count).Error
+	if err != nil {
+		return 0, NewRepositoryError("GetActiveUsers", "Stats", err)
+	}
+	return count, nil
+}
+
+// GetTotalVolume 获取已确认交易的总交易量USD
+func (r *statsRepository) GetTotalVolume() (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := r.db.Model(&models.Transaction{}).
+		Select("COALESCE(SUM(amount_in_usd), 0)").
+		Where("status = ?", "confirmed").
+		Scan(&total).Error
+	if err != nil {
+		return decimal.Zero, NewRepositoryError("GetTotalVolume", "Transaction", err)
+	}
+	return total, nil
+}
+
+// ========================================
+// 聚合器统计
+// ========================================
+
+// CreateAggregatorStats 创建聚合器统计
+func (r *statsRepository) CreateAggregatorStats(stats *models.AggregatorStatsHourly) error {
+	if err := r.db.Create(stats).Error; err != nil {
+		return NewRepositoryError("CreateAggregatorStats", "AggregatorStatsHourly", err)
+	}
+	return nil
+}
+
+// GetAggregatorStats 获取聚合器[from, to)内的小时统计，按时间升序
+func (r *statsRepository) GetAggregatorStats(aggregatorID uint, from, to time.Time) ([]*models.AggregatorStatsHourly, error) {
+	var stats []*models.AggregatorStatsHourly
+	err := r.db.Where("aggregator_id = ? AND hour_timestamp >= ? AND hour_timestamp < ?", aggregatorID, from, to).
+		Order("hour_timestamp ASC").
+		Find(&stats).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetAggregatorStats", "AggregatorStatsHourly", err)
+	}
+	return stats, nil
+}
+
+// GetAggregatorSummaries 按聚合器汇总[from, to)内的小时统计
+// aggregatorID非空时只返回该聚合器，结果按最优报价次数降序
+func (r *statsRepository) GetAggregatorSummaries(aggregatorID *uint, from, to time.Time) ([]*types.AggregatorStats, error) {
+	query := r.db.Table("aggregator_stats_hourly AS s").
+		Select(`s.aggregator_id, a.name AS aggregator_name, a.display_name,
+			SUM(s.total_requests) AS total_requests,
+			SUM(s.successful_requests) AS successful_requests,
+			SUM(s.failed_requests) AS failed_requests,
+			COALESCE(ROUND(SUM(s.avg_response_time * s.total_requests)::numeric / NULLIF(SUM(s.total_requests), 0)), 0)::int AS avg_response_time,
+			COALESCE(MIN(s.min_response_time) FILTER (WHERE s.total_requests > 0), 0) AS min_response_time,
+			COALESCE(MAX(s.max_response_time), 0) AS max_response_time,
+			SUM(s.total_volume_usd) AS total_volume_usd,
+			SUM(s.best_quotes_count) AS best_quotes_count`).
+		Joins("JOIN aggregators a ON a.id = s.aggregator_id").
+		Where("s.hour_timestamp >= ? AND s.hour_timestamp < ?", from, to)
+	if aggregatorID != nil {
+		query = query.Where("s.aggregator_id = ?", *aggregatorID)
+	}
+
+	var stats []*types.AggregatorStats
+	err := query.Group("s.aggregator_id, a.name, a.display_name").
+		Order("best_quotes_count DESC, total_requests DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetAggregatorSummaries", "AggregatorStatsHourly", err)
+	}
+
+	for _, s := range stats {
+		s.SuccessRate = ratio(s.SuccessfulRequests, s.TotalRequests)
+	}
+	return stats, nil
+}
+
+// GetLatencyStats 统计[from, to)内各聚合器成功响应的延迟分布
+// 分位数需要原始样本，直接查询quote_responses
+func (r *statsRepository) GetLatencyStats(from, to time.Time) ([]*types.LatencyStats, error) {
+	var stats []*types.LatencyStats
+	err := r.db.Table("quote_responses AS q").
+		Select(`q.aggregator_id, a.name AS aggregator_name,
+			COUNT(*) AS sample_count,
+			ROUND(AVG(q.response_time_ms))::int AS avg_ms,
+			ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY q.response_time_ms))::int AS p50_ms,
+			ROUND(percentile_cont(0.95) WITHIN GROUP (ORDER BY q.response_time_ms))::int AS p95_ms,
+			MAX(q.response_time_ms) AS max_ms`).
+		Joins("JOIN aggregators a ON a.id = q.aggregator_id").
+		Where("q.success = ? AND q.created_at >= ? AND q.created_at < ?", true, from, to).
+		Group("q.aggregator_id, a.name").
+		Order("avg_ms ASC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetLatencyStats", "QuoteResponse", err)
+	}
+	return stats, nil
+}
+
+// ========================================
+// 代币对统计
+// ========================================
+
+// CreateTokenPairStats 创建代币对统计
+func (r *statsRepository) CreateTokenPairStats(stats *models.TokenPairStatsDaily) error {
+	if err := r.db.Create(stats).Error; err != nil {
+		return NewRepositoryError("CreateTokenPairStats", "TokenPairStatsDaily", err)
+	}
+	return nil
+}
+
+// GetTokenPairStats 获取[from, to)内的代币对日统计
+// 代币ID为0时不过滤，结果按日期降序、交易量降序
+func (r *statsRepository) GetTokenPairStats(fromTokenID, toTokenID uint, from, to time.Time) ([]*types.TokenPairStats, error) {
+	query := r.db.Table("token_pair_stats_daily AS s").
+		Select(`s.date, s.from_token_id, ft.symbol AS from_token_symbol, s.to_token_id, tt.symbol AS to_token_symbol,
+			s.chain_id, s.transaction_count, s.total_volume_from, s.total_volume_to, s.total_volume_usd,
+			s.avg_price_impact, s.avg_gas_fee_usd, s.unique_users`).
+		Joins("JOIN tokens ft ON ft.id = s.from_token_id").
+		Joins("JOIN tokens tt ON tt.id = s.to_token_id").
+		Where("s.date >= ? AND s.date < ?", from, to)
+	if fromTokenID > 0 {
+		query = query.Where("s.from_token_id = ?", fromTokenID)
+	}
+	if toTokenID > 0 {
+		query = query.Where("s.to_token_id = ?", toTokenID)
+	}
+
+	var stats []*types.TokenPairStats
+	if err := query.Order("s.date DESC, s.total_volume_usd DESC").Scan(&stats).Error; err != nil {
+		return nil, NewRepositoryError("GetTokenPairStats", "TokenPairStatsDaily", err)
+	}
+	return stats, nil
+}
+
+// GetPopularTokenPairs 按交易量获取[from, to)内的热门代币对
+func (r *statsRepository) GetPopularTokenPairs(limit int, from, to time.Time) ([]*types.PopularTokenPair, error) {
+	var pairs []*types.PopularTokenPair
+	err := r.db.Table("token_pair_stats_daily AS s").
+		Select(`s.from_token_id, ft.symbol AS from_token_symbol, s.to_token_id, tt.symbol AS to_token_symbol, s.chain_id,
+			SUM(s.transaction_count) AS transaction_count,
+			SUM(s.total_volume_usd) AS total_volume_usd`).
+		Joins("JOIN tokens ft ON ft.id = s.from_token_id").
+		Joins("JOIN tokens tt ON tt.id = s.to_token_id").
+		Where("s.date >= ? AND s.date < ?", from, to).
+		Group("s.from_token_id, ft.symbol, s.to_token_id, tt.symbol, s.chain_id").
+		Order("total_volume_usd DESC, transaction_count DESC").
+		Limit(limit).
+		Scan(&pairs).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetPopularTokenPairs", "TokenPairStatsDaily", err)
+	}
+	return pairs, nil
+}
+
+// GetDailyVolume 获取[from, to)内的每日交易量，按日期升序
+func (r *statsRepository) GetDailyVolume(from, to time.Time) ([]*types.VolumeData, error) {
+	var volumes []*types.VolumeData
+	err := r.db.Table("token_pair_stats_daily").
+		Select("date, SUM(transaction_count) AS transaction_count, SUM(total_volume_usd) AS total_volume_usd").
+		Where("date >= ? AND date < ?", from, to).
+		Group("date").
+		Order("date ASC").
+		Scan(&volumes).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetDailyVolume", "TokenPairStatsDaily", err)
+	}
+	return volumes, nil
+}
+
+// ========================================
+// 用户和性能统计
+// ========================================
+
+// GetUserAnalytics 获取[from, to)内的用户统计
+func (r *statsRepository) GetUserAnalytics(from, to time.Time) (*types.UserAnalytics, error) {
+	var totalUsers, newUsers int64
+	if err := r.db.Model(&models.User{}).Count(&totalUsers).Error; err != nil {
+		return nil, NewRepositoryError("GetUserAnalytics", "User", err)
+	}
+	if err := r.db.Model(&models.User{}).Where("created_at >= ? AND created_at < ?", from, to).Count(&newUsers).Error; err != nil {
+		return nil, NewRepositoryError("GetUserAnalytics", "User", err)
+	}
+
+	activeUsers, err := r.GetActiveUsers(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var trading struct {
+		TradingUsers      int
+		TotalTransactions int
+	}
+	err = r.db.Model(&models.Transaction{}).
+		Select("COUNT(DISTINCT LOWER(user_address)) AS trading_users, COUNT(*) AS total_transactions").
+		Where("status = ? AND created_at >= ? AND created_at < ?", "confirmed", from, to).
+		Scan(&trading).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetUserAnalytics", "Transaction", err)
+	}
+
+	analytics := &types.UserAnalytics{
+		TotalUsers:             int(totalUsers),
+		NewUsers:               int(newUsers),
+		ActiveUsers:            activeUsers,
+		TradingUsers:           trading.TradingUsers,
+		TotalTransactions:      trading.TotalTransactions,
+		AvgTransactionsPerUser: decimal.Zero,
+	}
+	if trading.TradingUsers > 0 {
+		analytics.AvgTransactionsPerUser = decimal.NewFromInt(int64(trading.TotalTransactions)).
+			Div(decimal.NewFromInt(int64(trading.TradingUsers))).Round(2)
+	}
+	return analytics, nil
+}
+
+// GetUserTrends 获取[from, to)内每日活跃地址数和新注册用户数，按日期升序
+func (r *statsRepository) GetUserTrends(from, to time.Time) ([]*types.UserTrend, error) {
+	args := map[string]interface{}{"from": from, "to": to}
+
+	var active []*types.UserTrend
+	err := r.db.Raw(`SELECT DATE(created_at) AS date, COUNT(DISTINCT address) AS active_users
+		FROM (`+activeAddressesSQL+`) a GROUP BY 1 ORDER BY 1`, args).Scan(&active).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetUserTrends", "Stats", err)
+	}
+
+	var registered []*types.UserTrend
+	err = r.db.Model(&models.User{}).
+		Select("DATE(created_at) AS date, COUNT(*) AS new_users").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("DATE(created_at)").
+		Scan(&registered).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetUserTrends", "User", err)
+	}
+
+	// 按日期合并活跃和注册数据
+	byDate := make(map[string]*types.UserTrend, len(active))
+	for _, trend := range active {
+		byDate[trend.Date.Format("2006-01-02")] = trend
+	}
+	for _, reg := range registered {
+		if trend, ok := byDate[reg.Date.Format("2006-01-02")]; ok {
+			trend.NewUsers = reg.NewUsers
+			continue
+		}
+		active = append(active, reg)
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Date.Before(active[j].Date)
+	})
+	return active, nil
+}
+
+// GetPerformanceMetrics 获取[from, to)内的报价和交易性能指标
+func (r *statsRepository) GetPerformanceMetrics(from, to time.Time) (*types.PerformanceMetrics, error) {
+	var quotes struct {
+		Total     int
+		Completed int
+		CacheHits int
+		AvgMS     int
+	}
+	err := r.db.Model(&models.QuoteRequest{}).
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE cache_hit) AS cache_hits,
+			COALESCE(ROUND(AVG(total_duration_ms)), 0)::int AS avg_ms`).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&quotes).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetPerformanceMetrics", "QuoteRequest", err)
+	}
+
+	var transactions struct {
+		Total     int
+		Confirmed int
+		Failed    int
+	}
+	err = r.db.Model(&models.Transaction{}).
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'confirmed') AS confirmed,
+			COUNT(*) FILTER (WHERE status = 'failed') AS failed`).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&transactions).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetPerformanceMetrics", "Transaction", err)
+	}
+
+	return &types.PerformanceMetrics{
+		QuoteRequests:          quotes.Total,
+		QuoteSuccessRate:       ratio(quotes.Completed, quotes.Total),
+		AvgQuoteDurationMS:     quotes.AvgMS,
+		CacheHitRate:           ratio(quotes.CacheHits, quotes.Total),
+		Transactions:           transactions.Total,
+		ConfirmedTransactions:  transactions.Confirmed,
+		FailedTransactions:     transactions.Failed,
+		TransactionSuccessRate: ratio(transactions.Confirmed, transactions.Confirmed+transactions.Failed),
+	}, nil
+}
+
+// ========================================
+// 系统指标
+// ========================================
+
+// CreateSystemMetric 创建系统指标
+func (r *statsRepository) CreateSystemMetric(metric *models.SystemMetrics) error {
+	if err := r.db.Create(metric).Error; err != nil {
+		return NewRepositoryError("CreateSystemMetric", "SystemMetrics", err)
+	}
+	return nil
+}
+
+// GetSystemMetrics 获取最近hours小时内的指定指标，按时间升序
+func (r *statsRepository) GetSystemMetrics(metricName string, hours int) ([]*models.SystemMetrics, error) {
+	var metrics []*models.SystemMetrics
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	err := r.db.Where("metric_name = ? AND timestamp >= ?", metricName, since).
+		Order("timestamp ASC").
+		Find(&metrics).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetSystemMetrics", "SystemMetrics", err)
+	}
+	return metrics, nil
+}
+
+// GetLatestMetrics 获取每个指标的最新值
+func (r *statsRepository) GetLatestMetrics() (map[string]interface{}, error) {
+	var metrics []*models.SystemMetrics
+	err := r.db.Raw(`SELECT DISTINCT ON (metric_name) * FROM system_metrics
+		ORDER BY metric_name, timestamp DESC`).Scan(&metrics).Error
+	if err != nil {
+		return nil, NewRepositoryError("GetLatestMetrics", "SystemMetrics", err)
+	}
+
+	latest := make(map[string]interface{}, len(metrics))
+	for _, metric := range metrics {
+		latest[metric.MetricName] = metric.MetricValue
+	}
+	return latest, nil
+}
+
+// ========================================
+// 事务支持和辅助方法
+// ========================================
+
+// WithTx 返回使用指定事务的Repository实例
+func (r *statsRepository) WithTx(tx *gorm.DB) interface{} { return &statsRepository{db: tx} }
+
+// HealthCheck 检查统计表是否可访问
+func (r *statsRepository) HealthCheck() error {
+	var count int64
+	return r.db.Model(&models.SystemMetrics{}).Limit(1).Count(&count).Error
+}
+
+// ratio 计算比率(保留4位小数)，分母为0时返回0
+func ratio(numerator, denominator int) decimal.Decimal {
+	if denominator <= 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(int64(numerator)).Div(decimal.NewFromInt(int64(denominator))).Round(4)
+}
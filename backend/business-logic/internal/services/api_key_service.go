@@ -0,0 +1,385 @@
+// Package services API Key业务服务实现
+// 用户为合作方创建带权限范围(quote/swap/stats)和配额等级的API Key，
+// 密钥格式为 dfa_<64位十六进制>，数据库只保存SHA-256哈希和用于识别的前缀，
+// 网关通过内部接口将X-API-Key解析为调用方身份后再转发请求
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"defi-aggregator/business-logic/internal/models"
+	"defi-aggregator/business-logic/internal/repository"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyPrefix         = "dfa_"      // 密钥固定前缀，便于识别和密钥泄露扫描
+	apiKeySecretBytes    = 32          // 密钥随机部分字节数
+	apiKeyDisplayLength  = 12          // 保存和展示的明文前缀长度
+	apiKeyNameMaxLength  = 100         // 名称最大长度
+	apiKeyMaxExpiresDays = 3650        // 最长有效天数
+	apiKeyLastUsedWindow = time.Minute // 最后使用时间的更新粒度，避免每次鉴权都写库
+)
+
+// apiKeyScopeOrder 权限范围的规范顺序
+var apiKeyScopeOrder = []string{types.APIKeyScopeQuote, types.APIKeyScopeSwap, types.APIKeyScopeStats}
+
+// apiKeyTiers 支持的配额等级
+var apiKeyTiers = []string{types.APIKeyTierFree, types.APIKeyTierStandard, types.APIKeyTierPremium}
+
+// apiKeyService API Key业务服务实现
+type apiKeyService struct {
+	repos  *repository.Repositories // 数据访问层
+	cfg    *config.Config           // 应用配置
+	logger *logrus.Logger           // 日志记录器
+}
+
+// NewAPIKeyService 创建API Key服务实例
+func NewAPIKeyService(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) APIKeyService {
+	return &apiKeyService{
+		repos:  repos,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// ========================================
+// 用户API Key管理
+// ========================================
+
+// CreateAPIKey 创建API Key
+// 返回的密钥明文只出现在本次响应中
+func (s *apiKeyService) CreateAPIKey(userID uint, req *types.CreateAPIKeyRequest) (*types.APIKeySecretResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > apiKeyNameMaxLength {
+		return nil, NewServiceError(types.ErrCodeValidation, fmt.Sprintf("API Key名称不能为空且不超过%d个字符", apiKeyNameMaxLength), nil)
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > apiKeyMaxExpiresDays {
+		return nil, NewServiceError(types.ErrCodeValidation, fmt.Sprintf("有效天数必须在1-%d之间", apiKeyMaxExpiresDays), nil)
+	}
+
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 限制每个用户的有效密钥数量
+	count, err := s.repos.APIKey.CountActiveByUserID(userID)
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeDatabase, "查询API Key数量失败", err)
+	}
+	if count >= int64(s.cfg.Security.APIKeyMaxPerUser) {
+		serviceErr := NewServiceError(types.ErrCodeConflict, "有效API Key数量已达上限", nil)
+		serviceErr.Details["max_per_user"] = s.cfg.Security.APIKeyMaxPerUser
+		return nil, serviceErr
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeInternal, "生成API Key失败", err)
+	}
+
+	key := &models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		KeyPrefix: rawKey[:apiKeyDisplayLength],
+		KeyHash:   hashAPIKey(rawKey),
+		Scopes:    strings.Join(scopes, ","),
+		Tier:      s.cfg.Security.APIKeyDefaultTier,
+		IsActive:  true,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repos.APIKey.Create(key); err != nil {
+		return nil, NewServiceError(types.ErrCodeDatabase, "保存API Key失败", err)
+	}
+
+	s.logger.Infof("🔑 API Key创建成功: userID=%d, keyID=%d, prefix=%s, scopes=%s", userID, key.ID, key.KeyPrefix, key.Scopes)
+
+	return &types.APIKeySecretResponse{
+		APIKeyInfo: *s.convertToAPIKeyInfo(key),
+		Key:        rawKey,
+	}, nil
+}
+
+// ListAPIKeys 获取用户的API Key列表(包括已撤销的)
+func (s *apiKeyService) ListAPIKeys(userID uint) ([]*types.APIKeyInfo, error) {
+	keys, err := s.repos.APIKey.GetByUserID(userID)
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取API Key列表失败", err)
+	}
+
+	result := make([]*types.APIKeyInfo, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, s.convertToAPIKeyInfo(key))
+	}
+	return result, nil
+}
+
+// RotateAPIKey 轮换密钥
+// 保留名称、权限范围和配额等级，生成新的密钥，旧密钥立即失效
+func (s *apiKeyService) RotateAPIKey(userID, keyID uint) (*types.APIKeySecretResponse, error) {
+	key, err := s.getUserAPIKey(userID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !key.IsActive {
+		return nil, NewServiceError(types.ErrCodeConflict, "API Key已撤销，无法轮换", nil)
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeInternal, "生成API Key失败", err)
+	}
+
+	oldPrefix := key.KeyPrefix
+	key.KeyPrefix = rawKey[:apiKeyDisplayLength]
+	key.KeyHash = hashAPIKey(rawKey)
+	key.LastUsedAt = nil
+
+	if err := s.repos.APIKey.Update(key); err != nil {
+		return nil, NewServiceError(types.ErrCodeDatabase, "保存API Key失败", err)
+	}
+
+	s.logger.Infof("🔄 API Key轮换成功: userID=%d, keyID=%d, prefix=%s -> %s", userID, key.ID, oldPrefix, key.KeyPrefix)
+
+	return &types.APIKeySecretResponse{
+		APIKeyInfo: *s.convertToAPIKeyInfo(key),
+		Key:        rawKey,
+	}, nil
+}
+
+// RevokeAPIKey 撤销API Key
+// 重复撤销视为成功
+func (s *apiKeyService) RevokeAPIKey(userID, keyID uint) error {
+	key, err := s.getUserAPIKey(userID, keyID)
+	if err != nil {
+		return err
+	}
+	if !key.IsActive {
+		return nil
+	}
+
+	now := time.Now()
+	key.IsActive = false
+	key.RevokedAt = &now
+
+	if err := s.repos.APIKey.Update(key); err != nil {
+		return NewServiceError(types.ErrCodeDatabase, "撤销API Key失败", err)
+	}
+
+	s.logger.Infof("🚫 API Key已撤销: userID=%d, keyID=%d, prefix=%s", userID, key.ID, key.KeyPrefix)
+	return nil
+}
+
+// ========================================
+// 管理员操作
+// ========================================
+
+// UpdateAPIKeyTier 调整API Key配额等级
+// 可调整任意用户的密钥，调用方须已通过管理员权限校验；网关缓存过期后新配额生效
+func (s *apiKeyService) UpdateAPIKeyTier(keyID uint, tier string) (*types.APIKeyInfo, error) {
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if !isValidAPIKeyTier(tier) {
+		serviceErr := NewServiceError(types.ErrCodeValidation, fmt.Sprintf("不支持的API Key配额等级: %s", tier), nil)
+		serviceErr.Details["supported_tiers"] = apiKeyTiers
+		return nil, serviceErr
+	}
+
+	key, err := s.repos.APIKey.GetByID(keyID)
+	if err != nil {
+		if isRecordNotFound(err) {
+			return nil, NewServiceError(types.ErrCodeNotFound, "API Key不存在", err)
+		}
+		return nil, NewServiceError(types.ErrCodeDatabase, "查询API Key失败", err)
+	}
+	if !key.IsActive {
+		return nil, NewServiceError(types.ErrCodeConflict, "API Key已撤销，无法调整配额等级", nil)
+	}
+
+	oldTier := key.Tier
+	if oldTier == tier {
+		return s.convertToAPIKeyInfo(key), nil
+	}
+
+	key.Tier = tier
+	if err := s.repos.APIKey.Update(key); err != nil {
+		return nil, NewServiceError(types.ErrCodeDatabase, "保存API Key失败", err)
+	}
+
+	s.logger.Infof("🎚️ API Key配额等级已调整: userID=%d, keyID=%d, prefix=%s, tier=%s -> %s", key.UserID, key.ID, key.KeyPrefix, oldTier, tier)
+	return s.convertToAPIKeyInfo(key), nil
+}
+
+// ========================================
+// 网关鉴权
+// ========================================
+
+// ResolveAPIKey 解析密钥为调用方身份
+// 密钥不存在、已撤销、已过期或所属用户已停用时均返回未授权错误，网关据此区分密钥无效和服务故障
+func (s *apiKeyService) ResolveAPIKey(rawKey string) (*types.APIKeyPrincipal, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) || len(rawKey) != len(apiKeyPrefix)+apiKeySecretBytes*2 {
+		return nil, NewServiceError(types.ErrCodeUnauthorized, "无效的API Key", nil)
+	}
+
+	key, err := s.repos.APIKey.GetByHash(hashAPIKey(rawKey))
+	if err != nil {
+		if isRecordNotFound(err) {
+			return nil, NewServiceError(types.ErrCodeUnauthorized, "无效的API Key", nil)
+		}
+		return nil, NewServiceError(types.ErrCodeDatabase, "查询API Key失败", err)
+	}
+
+	now := time.Now()
+	if !key.IsActive {
+		return nil, NewServiceError(types.ErrCodeUnauthorized, "API Key已撤销", nil)
+	}
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return nil, NewServiceError(types.ErrCodeUnauthorized, "API Key已过期", nil)
+	}
+	if !key.User.IsActive {
+		return nil, NewServiceError(types.ErrCodeUnauthorized, "API Key所属账户已停用", nil)
+	}
+
+	// 按分钟粒度记录最后使用时间，写库失败不影响鉴权
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedWindow {
+		if err := s.repos.APIKey.UpdateLastUsed(key.ID, now); err != nil {
+			s.logger.Warnf("⚠️ 更新API Key最后使用时间失败: keyID=%d, error=%v", key.ID, err)
+		}
+	}
+
+	return &types.APIKeyPrincipal{
+		KeyID:         key.ID,
+		UserID:        key.UserID,
+		WalletAddress: key.User.WalletAddress,
+		Scopes:        splitAPIKeyScopes(key.Scopes),
+		Tier:          key.Tier,
+		ExpiresAt:     key.ExpiresAt,
+	}, nil
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// getUserAPIKey 获取属于指定用户的API Key
+// 不属于该用户的密钥按不存在处理，避免泄露其他用户的密钥ID
+func (s *apiKeyService) getUserAPIKey(userID, keyID uint) (*models.APIKey, error) {
+	key, err := s.repos.APIKey.GetByID(keyID)
+	if err != nil {
+		if isRecordNotFound(err) {
+			return nil, NewServiceError(types.ErrCodeNotFound, "API Key不存在", err)
+		}
+		return nil, NewServiceError(types.ErrCodeDatabase, "查询API Key失败", err)
+	}
+	if key.UserID != userID {
+		return nil, NewServiceError(types.ErrCodeNotFound, "API Key不存在", nil)
+	}
+	return key, nil
+}
+
+// convertToAPIKeyInfo 转换为API Key信息(不含密钥)
+func (s *apiKeyService) convertToAPIKeyInfo(key *models.APIKey) *types.APIKeyInfo {
+	return &types.APIKeyInfo{
+		ID:         key.ID,
+		Name:       key.Name,
+		KeyPrefix:  key.KeyPrefix,
+		Scopes:     splitAPIKeyScopes(key.Scopes),
+		Tier:       key.Tier,
+		IsActive:   key.IsActive,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// isValidAPIKeyTier 是否为支持的配额等级
+func isValidAPIKeyTier(tier string) bool {
+	for _, known := range apiKeyTiers {
+		if tier == known {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeAPIKeyScopes 校验并按规范顺序去重权限范围，为空时默认仅quote
+func normalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return []string{types.APIKeyScopeQuote}, nil
+	}
+
+	requested := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		valid := false
+		for _, known := range apiKeyScopeOrder {
+			if scope == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			serviceErr := NewServiceError(types.ErrCodeValidation, fmt.Sprintf("不支持的API Key权限范围: %s", scope), nil)
+			serviceErr.Details["supported_scopes"] = apiKeyScopeOrder
+			return nil, serviceErr
+		}
+		requested[scope] = true
+	}
+
+	result := make([]string, 0, len(requested))
+	for _, scope := range apiKeyScopeOrder {
+		if requested[scope] {
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+// splitAPIKeyScopes 拆分数据库中逗号分隔的权限范围
+func splitAPIKeyScopes(scopes string) []string {
+	result := make([]string, 0, len(apiKeyScopeOrder))
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+// generateAPIKey 生成新的密钥明文
+func generateAPIKey() (string, error) {
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	return apiKeyPrefix + hex.EncodeToString(secret), nil
+}
+
+// hashAPIKey 计算密钥的SHA-256哈希
+// 密钥本身是高熵随机数，无需加盐或慢哈希
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// isRecordNotFound 判断数据访问层错误是否为记录不存在
+func isRecordNotFound(err error) bool {
+	if repoErr, ok := err.(*repository.RepositoryError); ok {
+		return repoErr.Err == gorm.ErrRecordNotFound
+	}
+	return err == gorm.ErrRecordNotFound
+}
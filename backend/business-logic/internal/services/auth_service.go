@@ -4,32 +4,42 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"strings"
+	"time"
 
 	"defi-aggregator/business-logic/internal/models"
 	"defi-aggregator/business-logic/internal/repository"
 	"defi-aggregator/business-logic/internal/types"
 	"defi-aggregator/business-logic/pkg/config"
+	"defi-aggregator/business-logic/pkg/session"
 	"defi-aggregator/business-logic/pkg/utils"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// sessionStoreTimeout 单次会话存储操作超时时间
+const sessionStoreTimeout = 2 * time.Second
+
 // authService 认证服务实现
 // 负责处理用户认证、JWT令牌管理、会话控制等安全相关功能
 type authService struct {
-	repos  *repository.Repositories // 数据访问层
-	cfg    *config.Config           // 应用配置
-	logger *logrus.Logger           // 日志记录器
+	repos    *repository.Repositories // 数据访问层
+	sessions session.Store            // 会话存储
+	cfg      *config.Config           // 应用配置
+	logger   *logrus.Logger           // 日志记录器
 }
 
 // NewAuthService 创建认证服务实例
 // 注入必要的依赖，初始化认证服务
-func NewAuthService(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) AuthService {
+func NewAuthService(repos *repository.Repositories, sessions session.Store, cfg *config.Config, logger *logrus.Logger) AuthService {
 	return &authService{
-		repos:  repos,
-		cfg:    cfg,
-		logger: logger,
+		repos:    repos,
+		sessions: sessions,
+		cfg:      cfg,
+		logger:   logger,
 	}
 }
 
@@ -152,7 +162,7 @@ func (s *authService) VerifySignature(req *types.UserLoginRequest) (*types.UserL
 // ========================================
 
 // GenerateTokens 生成访问令牌和刷新令牌
-// 为认证用户创建一对JWT令牌
+// 为认证用户创建新会话并签发一对JWT令牌，两个令牌的jti记录在会话存储中
 // 参数:
 //   - userID: 用户ID
 //   - walletAddress: 钱包地址
@@ -162,104 +172,137 @@ func (s *authService) VerifySignature(req *types.UserLoginRequest) (*types.UserL
 //   - refreshToken: 刷新令牌
 //   - error: 生成错误
 func (s *authService) GenerateTokens(userID uint, walletAddress string) (accessToken, refreshToken string, err error) {
-	// 生成访问令牌
-	accessToken, err = utils.GenerateJWT(
-		userID,
-		walletAddress,
-		"user", // 默认角色
-		s.cfg.JWT.SecretKey,
-		s.cfg.JWT.ExpiresIn,
-		"access",
-	)
-	if err != nil {
-		s.logger.Errorf("生成访问令牌失败: %v", err)
-		return "", "", NewServiceError(types.ErrCodeInternal, "访问令牌生成失败", err)
+	sess := &session.Session{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		AccessJTI:  uuid.New().String(),
+		RefreshJTI: uuid.New().String(),
+		CreatedAt:  time.Now(),
 	}
 
-	// 生成刷新令牌
-	refreshToken, err = utils.GenerateJWT(
-		userID,
-		walletAddress,
-		"user",
-		s.cfg.JWT.SecretKey,
-		s.cfg.JWT.RefreshExpiresIn,
-		"refresh",
-	)
+	accessToken, refreshToken, err = s.signTokenPair(userID, walletAddress, sess.ID, sess.AccessJTI, sess.RefreshJTI)
 	if err != nil {
-		s.logger.Errorf("生成刷新令牌失败: %v", err)
-		return "", "", NewServiceError(types.ErrCodeInternal, "刷新令牌生成失败", err)
+		return "", "", err
 	}
 
-	s.logger.Debugf("为用户 %d 生成令牌对成功", userID)
+	ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+	defer cancel()
+
+	if err := s.sessions.Create(ctx, sess, s.cfg.JWT.RefreshExpiresIn); err != nil {
+		s.logger.Errorf("保存会话失败: %v", err)
+		return "", "", NewServiceError(types.ErrCodeInternal, "会话创建失败", err)
+	}
+
+	s.logger.Debugf("为用户 %d 生成令牌对成功: session=%s", userID, sess.ID)
 	return accessToken, refreshToken, nil
 }
 
-// RefreshToken 刷新访问令牌
-// 使用有效的刷新令牌生成新的访问令牌
+// RefreshToken 刷新令牌
+// 使用有效的刷新令牌轮换出新的访问令牌和刷新令牌，旧令牌随即失效；
+// 已被轮换过的刷新令牌再次使用视为令牌泄露，整个会话被撤销
 // 参数:
 //   - refreshTokenString: 刷新令牌字符串
 //
 // 返回:
-//   - newAccessToken: 新的访问令牌
+//   - accessToken: 新的访问令牌
+//   - refreshToken: 新的刷新令牌
 //   - error: 刷新过程中的错误
-func (s *authService) RefreshToken(refreshTokenString string) (newAccessToken string, err error) {
+func (s *authService) RefreshToken(refreshTokenString string) (accessToken, refreshToken string, err error) {
 	// 解析刷新令牌
 	claims, err := utils.ParseJWT(refreshTokenString, s.cfg.JWT.SecretKey)
 	if err != nil {
 		s.logger.Warnf("刷新令牌解析失败: %v", err)
-		return "", NewServiceError(types.ErrCodeUnauthorized, "无效的刷新令牌", err)
+		return "", "", NewServiceError(types.ErrCodeUnauthorized, "无效的刷新令牌", err)
 	}
 
 	// 验证令牌类型
 	if claims.TokenType != "refresh" {
 		s.logger.Warnf("令牌类型错误: %s", claims.TokenType)
-		return "", NewServiceError(types.ErrCodeUnauthorized, "令牌类型错误", nil)
+		return "", "", NewServiceError(types.ErrCodeUnauthorized, "令牌类型错误", nil)
+	}
+	if claims.SessionID == "" || claims.ID == "" {
+		return "", "", NewServiceError(types.ErrCodeUnauthorized, "刷新令牌缺少会话信息", nil)
 	}
 
 	// 验证用户是否仍然存在且活跃
 	user, err := s.repos.User.GetByID(claims.UserID)
 	if err != nil {
 		s.logger.Warnf("用户不存在: ID=%d", claims.UserID)
-		return "", NewServiceError(types.ErrCodeUnauthorized, "用户不存在", err)
+		return "", "", NewServiceError(types.ErrCodeUnauthorized, "用户不存在", err)
 	}
 
 	if !user.IsActive {
 		s.logger.Warnf("用户已停用: ID=%d", claims.UserID)
-		return "", NewServiceError(types.ErrCodeUnauthorized, "用户已停用", nil)
+		return "", "", NewServiceError(types.ErrCodeUnauthorized, "用户已停用", nil)
 	}
 
-	// 生成新的访问令牌
-	newAccessToken, err = utils.GenerateJWT(
-		user.ID,
-		user.WalletAddress,
-		"user",
-		s.cfg.JWT.SecretKey,
-		s.cfg.JWT.ExpiresIn,
-		"access",
-	)
+	ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+	defer cancel()
+
+	sess, err := s.sessions.Get(ctx, claims.SessionID)
+	if err != nil {
+		return "", "", s.sessionError(err)
+	}
+	if sess.UserID != user.ID {
+		return "", "", NewServiceError(types.ErrCodeUnauthorized, "会话与用户不匹配", nil)
+	}
+	if sess.RefreshJTI != claims.ID {
+		return "", "", s.revokeReusedSession(ctx, sess)
+	}
+
+	// 先签发新令牌再轮换，避免轮换成功但签发失败导致客户端持有的令牌被判定为重放
+	newAccessJTI := uuid.New().String()
+	newRefreshJTI := uuid.New().String()
+	accessToken, refreshToken, err = s.signTokenPair(user.ID, user.WalletAddress, sess.ID, newAccessJTI, newRefreshJTI)
+	if err != nil {
+		return "", "", err
+	}
+
+	rotated, err := s.sessions.Rotate(ctx, sess.ID, claims.ID, newAccessJTI, newRefreshJTI, s.cfg.JWT.RefreshExpiresIn)
 	if err != nil {
-		s.logger.Errorf("生成新访问令牌失败: %v", err)
-		return "", NewServiceError(types.ErrCodeInternal, "令牌生成失败", err)
+		return "", "", s.sessionError(err)
+	}
+	if !rotated {
+		// 与另一个请求同时使用了同一刷新令牌
+		return "", "", s.revokeReusedSession(ctx, sess)
 	}
 
-	s.logger.Infof("用户 %d 刷新令牌成功", user.ID)
-	return newAccessToken, nil
+	s.logger.Infof("用户 %d 刷新令牌成功: session=%s", user.ID, sess.ID)
+	return accessToken, refreshToken, nil
 }
 
-// RevokeToken 撤销用户令牌
-// 在用户登出或安全事件时撤销令牌
+// RevokeSession 撤销用户的单个会话
+// 会话下的访问令牌和刷新令牌立即失效，会话不存在时视为已撤销
 // 参数:
 //   - userID: 用户ID
-//   - tokenType: 令牌类型 ("access", "refresh", "all")
+//   - sessionID: 会话ID
 //
 // 返回:
 //   - error: 撤销过程中的错误
-func (s *authService) RevokeToken(userID uint, tokenType string) error {
-	// TODO: 实现令牌黑名单机制
-	// 可以使用Redis存储被撤销的令牌ID，直到其自然过期
-	// 或者在JWT中添加版本号，用户登出时递增版本号
+func (s *authService) RevokeSession(userID uint, sessionID string) error {
+	if sessionID == "" {
+		return NewServiceError(types.ErrCodeValidation, "会话ID不能为空", nil)
+	}
 
-	s.logger.Infof("撤销用户 %d 的 %s 令牌", userID, tokenType)
+	ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+	defer cancel()
+
+	sess, err := s.sessions.Get(ctx, sessionID)
+	if errors.Is(err, session.ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return s.sessionError(err)
+	}
+	if sess.UserID != userID {
+		return NewServiceError(types.ErrCodeForbidden, "无权撤销该会话", nil)
+	}
+
+	if err := s.sessions.Revoke(ctx, sessionID); err != nil {
+		return s.sessionError(err)
+	}
+
+	s.logger.Infof("撤销用户 %d 的会话: session=%s", userID, sessionID)
 	return nil
 }
 
@@ -288,15 +331,15 @@ func (s *authService) ValidateSession(userID uint) error {
 }
 
 // LogoutUser 用户登出
-// 撤销用户的访问令牌，结束会话
+// 撤销当前令牌所属的会话，其他设备上的会话不受影响
 // 参数:
 //   - userID: 用户ID
+//   - sessionID: 当前会话ID
 //
 // 返回:
 //   - error: 登出过程中的错误
-func (s *authService) LogoutUser(userID uint) error {
-	// 撤销访问令牌
-	if err := s.RevokeToken(userID, "access"); err != nil {
+func (s *authService) LogoutUser(userID uint, sessionID string) error {
+	if err := s.RevokeSession(userID, sessionID); err != nil {
 		return err
 	}
 
@@ -312,12 +355,15 @@ func (s *authService) LogoutUser(userID uint) error {
 // 返回:
 //   - error: 登出过程中的错误
 func (s *authService) LogoutAllSessions(userID uint) error {
-	// 撤销所有令牌
-	if err := s.RevokeToken(userID, "all"); err != nil {
-		return err
+	ctx, cancel := context.WithTimeout(context.Background(), sessionStoreTimeout)
+	defer cancel()
+
+	count, err := s.sessions.RevokeAll(ctx, userID)
+	if err != nil {
+		return s.sessionError(err)
 	}
 
-	s.logger.Infof("用户 %d 的所有会话已登出", userID)
+	s.logger.Infof("用户 %d 的所有会话已登出: 共%d个会话", userID, count)
 	return nil
 }
 
@@ -325,6 +371,66 @@ func (s *authService) LogoutAllSessions(userID uint) error {
 // 辅助方法
 // ========================================
 
+// signTokenPair 签发同一会话下的访问令牌和刷新令牌
+func (s *authService) signTokenPair(userID uint, walletAddress, sessionID, accessJTI, refreshJTI string) (accessToken, refreshToken string, err error) {
+	// 生成访问令牌
+	accessToken, err = utils.GenerateJWT(
+		userID,
+		walletAddress,
+		"user", // 默认角色
+		sessionID,
+		accessJTI,
+		s.cfg.JWT.SecretKey,
+		s.cfg.JWT.ExpiresIn,
+		"access",
+	)
+	if err != nil {
+		s.logger.Errorf("生成访问令牌失败: %v", err)
+		return "", "", NewServiceError(types.ErrCodeInternal, "访问令牌生成失败", err)
+	}
+
+	// 生成刷新令牌
+	refreshToken, err = utils.GenerateJWT(
+		userID,
+		walletAddress,
+		"user",
+		sessionID,
+		refreshJTI,
+		s.cfg.JWT.SecretKey,
+		s.cfg.JWT.RefreshExpiresIn,
+		"refresh",
+	)
+	if err != nil {
+		s.logger.Errorf("生成刷新令牌失败: %v", err)
+		return "", "", NewServiceError(types.ErrCodeInternal, "刷新令牌生成失败", err)
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeReusedSession 刷新令牌被重复使用时撤销整个会话
+// 无法区分合法用户和攻击者，两边持有的令牌全部作废，需要重新登录
+func (s *authService) revokeReusedSession(ctx context.Context, sess *session.Session) error {
+	s.logger.Warnf("🚨 检测到刷新令牌重复使用，撤销会话: user=%d, session=%s", sess.UserID, sess.ID)
+
+	if err := s.sessions.Revoke(ctx, sess.ID); err != nil {
+		s.logger.Errorf("撤销会话失败: session=%s, 错误=%v", sess.ID, err)
+	}
+
+	serviceErr := NewServiceError(types.ErrCodeUnauthorized, "刷新令牌已被使用，会话已注销，请重新登录", nil)
+	serviceErr.Details["session_id"] = sess.ID
+	return serviceErr
+}
+
+// sessionError 将会话存储错误转换为业务错误
+func (s *authService) sessionError(err error) error {
+	if errors.Is(err, session.ErrSessionNotFound) {
+		return NewServiceError(types.ErrCodeUnauthorized, "会话已失效，请重新登录", err)
+	}
+	s.logger.Errorf("会话存储操作失败: %v", err)
+	return NewServiceError(types.ErrCodeInternal, "会话服务暂不可用", err)
+}
+
 // validateLoginRequest 验证登录请求参数
 // 检查请求参数的完整性和格式正确性
 func (s *authService) validateLoginRequest(req *types.UserLoginRequest) error {
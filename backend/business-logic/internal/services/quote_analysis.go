@@ -0,0 +1,377 @@
+// Package services 报价分析
+// 基于持久化的聚合器响应对比各聚合器报价与最佳报价的差额(含扣除Gas费用后的差额，
+// 优先使用智能路由报价时按实时Gas价格计算并随响应记录的Gas费用)，
+// 以及按多个交易规模通过智能路由采样报价，得到价格冲击曲线
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"defi-aggregator/business-logic/internal/models"
+	"defi-aggregator/business-logic/internal/types"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// priceImpactSampleMultipliers 价格冲击分析的采样倍数(相对请求数量，升序)
+	priceImpactSampleMultipliers = []decimal.Decimal{
+		decimal.RequireFromString("0.1"),
+		decimal.RequireFromString("0.25"),
+		decimal.RequireFromString("0.5"),
+		decimal.NewFromInt(1),
+		decimal.NewFromInt(2),
+		decimal.NewFromInt(5),
+		decimal.NewFromInt(10),
+	}
+
+	// priceImpactThreshold 可接受的价格冲击阈值(1%)
+	priceImpactThreshold = decimal.RequireFromString("0.01")
+
+	// bpsPerUnit 1 = 10000基点
+	bpsPerUnit = decimal.NewFromInt(10000)
+)
+
+// ========================================
+// 报价对比
+// ========================================
+
+// CompareQuotes 比较报价结果
+// 基于持久化的聚合器响应计算各成功报价相对最佳报价的差额，
+// Gas费用可用时同时计算扣除Gas费用后的差额
+// 参数:
+//   - requestID: 报价请求ID
+//
+// 返回:
+//   - *types.QuoteComparison: 报价对比结果
+//   - error: 查询错误
+func (s *quoteService) CompareQuotes(requestID string) (*types.QuoteComparison, error) {
+	quoteRequest, responses, err := s.loadQuoteResponses(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) == 0 {
+		return nil, NewServiceError(types.ErrCodeNotFound, "报价请求没有聚合器响应记录", nil)
+	}
+
+	comparison := &types.QuoteComparison{
+		RequestID:  requestID,
+		TotalCount: len(responses),
+		Deltas:     []*types.QuoteDelta{},
+		Responses:  responses,
+	}
+
+	var best *types.AggregatorQuoteResponse
+	for _, response := range responses {
+		if !response.Success || response.AmountOut == nil {
+			continue
+		}
+		comparison.SuccessCount++
+
+		// 响应已按排名排序，第一个成功报价即最佳报价
+		if best == nil {
+			best = response
+			comparison.BestAggregator = response.AggregatorName
+			comparison.BestAmountOut = response.AmountOut
+		}
+		if comparison.WorstAmountOut == nil || response.AmountOut.LessThan(*comparison.WorstAmountOut) {
+			comparison.WorstAmountOut = response.AmountOut
+		}
+	}
+	if best == nil {
+		return comparison, nil
+	}
+
+	spread := comparison.BestAmountOut.Sub(*comparison.WorstAmountOut)
+	comparison.Spread = &spread
+
+	// 最佳报价无法计算净输出时不计算扣除Gas费用后的差额
+	netAmount := s.selectGasCostSource(quoteRequest, best, comparison)
+	var bestNetAmountOut *decimal.Decimal
+	if netAmount != nil {
+		_, bestNetAmountOut = netAmount(best)
+	}
+	if bestNetAmountOut != nil {
+		comparison.NetOfGas = true
+	} else {
+		comparison.GasCostSource = ""
+		comparison.GasPrice = nil
+	}
+
+	for _, response := range responses {
+		if !response.Success || response.AmountOut == nil {
+			continue
+		}
+
+		delta := response.AmountOut.Sub(*best.AmountOut)
+		quoteDelta := &types.QuoteDelta{
+			AggregatorName: response.AggregatorName,
+			DisplayName:    response.DisplayName,
+			PriceRank:      response.PriceRank,
+			AmountOut:      *response.AmountOut,
+			DeltaAmountOut: delta,
+			DeltaBps:       toBps(delta, *best.AmountOut),
+			GasEstimate:    response.GasEstimate,
+		}
+
+		if bestNetAmountOut != nil {
+			if cost, net := netAmount(response); net != nil {
+				netDelta := net.Sub(*bestNetAmountOut)
+				netDeltaBps := toBps(netDelta, *bestNetAmountOut)
+				quoteDelta.GasCost = cost
+				quoteDelta.NetAmountOut = net
+				quoteDelta.NetDeltaAmountOut = &netDelta
+				quoteDelta.NetDeltaBps = &netDeltaBps
+			}
+		}
+
+		comparison.Deltas = append(comparison.Deltas, quoteDelta)
+	}
+
+	s.logger.Debugf("报价比较完成: requestID=%s, success=%d/%d, netOfGas=%v, gasCostSource=%s",
+		requestID, comparison.SuccessCount, comparison.TotalCount, comparison.NetOfGas, comparison.GasCostSource)
+	return comparison, nil
+}
+
+// toBps 差额相对基准的基点数，基准不为正时返回0
+func toBps(delta, base decimal.Decimal) decimal.Decimal {
+	if !base.IsPositive() {
+		return decimal.Zero
+	}
+	return delta.Div(base).Mul(bpsPerUnit).Round(2)
+}
+
+// netAmountFunc 返回报价的Gas费用(输出代币数量)和扣除Gas费用后的净输出，无法计算时返回nil
+type netAmountFunc func(response *types.AggregatorQuoteResponse) (gasCost, netAmountOut *decimal.Decimal)
+
+// selectGasCostSource 选择报价对比使用的Gas费用来源，并将来源和Gas价格记录到对比结果
+// 最佳报价记录了智能路由按实时Gas价格计算的Gas费用时使用路由结果(同一次报价的各聚合器共用同一Gas价格)，
+// 否则(旧记录或路由未配置行情数据源)回退到按链配置的建议Gas价格估算；两者都不可用时返回nil
+func (s *quoteService) selectGasCostSource(quoteRequest *models.QuoteRequest, best *types.AggregatorQuoteResponse, comparison *types.QuoteComparison) netAmountFunc {
+	if best.GasCost != nil && best.NetAmountOut != nil {
+		comparison.GasCostSource = types.GasCostSourceRouter
+		comparison.GasPrice = best.GasPrice
+		return func(response *types.AggregatorQuoteResponse) (*decimal.Decimal, *decimal.Decimal) {
+			if response.GasCost == nil || response.NetAmountOut == nil {
+				return nil, nil
+			}
+			return response.GasCost, response.NetAmountOut
+		}
+	}
+
+	converter := s.newGasCostConverter(quoteRequest)
+	if converter == nil {
+		return nil
+	}
+	gasPrice := decimal.NewFromInt(int64(converter.gasPriceGwei)).Shift(9)
+	comparison.GasCostSource = types.GasCostSourceChainConfig
+	comparison.GasPrice = &gasPrice
+	return func(response *types.AggregatorQuoteResponse) (*decimal.Decimal, *decimal.Decimal) {
+		if response.GasEstimate == nil {
+			return nil, nil
+		}
+		cost := converter.cost(*response.GasEstimate)
+		net := response.AmountOut.Sub(cost)
+		return &cost, &net
+	}
+}
+
+// gasCostConverter 将Gas估算折算为输出代币数量
+// Gas价格取链配置的建议Gas价格(整数Gwei，仅作为未记录路由Gas费用时的估算)，通过原生代币和输出代币的USD价格换算
+type gasCostConverter struct {
+	gasPriceGwei   uint            // Gas价格(Gwei)
+	nativeDecimals int32           // 原生代币小数位数
+	nativePriceUSD decimal.Decimal // 原生代币USD价格
+	toDecimals     int32           // 输出代币小数位数
+	toPriceUSD     decimal.Decimal // 输出代币USD价格
+	toIsNative     bool            // 输出代币是否为原生代币
+}
+
+// newGasCostConverter 创建报价请求的Gas费用折算器，所需价格数据不可用时返回nil
+func (s *quoteService) newGasCostConverter(quoteRequest *models.QuoteRequest) *gasCostConverter {
+	toToken, err := s.repos.Token.GetByID(quoteRequest.ToTokenID)
+	if err != nil {
+		s.logger.Debugf("获取目标代币失败，跳过Gas费用折算: tokenID=%d, error=%v", quoteRequest.ToTokenID, err)
+		return nil
+	}
+	chain, err := s.repos.Chain.GetByID(toToken.ChainID)
+	if err != nil || chain.GasPriceGwei == 0 {
+		s.logger.Debugf("链Gas价格不可用，跳过Gas费用折算: chainID=%d", toToken.ChainID)
+		return nil
+	}
+
+	converter := &gasCostConverter{
+		gasPriceGwei: chain.GasPriceGwei,
+		toDecimals:   int32(toToken.Decimals),
+		toIsNative:   toToken.IsNative,
+	}
+	if toToken.IsNative {
+		converter.nativeDecimals = int32(toToken.Decimals)
+		return converter
+	}
+
+	if toToken.PriceUSD == nil || !toToken.PriceUSD.IsPositive() {
+		s.logger.Debugf("目标代币USD价格不可用，跳过Gas费用折算: token=%s", toToken.Symbol)
+		return nil
+	}
+	converter.toPriceUSD = *toToken.PriceUSD
+
+	tokens, err := s.repos.Token.GetByChainID(chain.ID)
+	if err != nil {
+		s.logger.Debugf("获取链代币失败，跳过Gas费用折算: chainID=%d, error=%v", chain.ID, err)
+		return nil
+	}
+	for _, token := range tokens {
+		if token.IsNative && token.PriceUSD != nil && token.PriceUSD.IsPositive() {
+			converter.nativeDecimals = int32(token.Decimals)
+			converter.nativePriceUSD = *token.PriceUSD
+			return converter
+		}
+	}
+
+	s.logger.Debugf("原生代币USD价格不可用，跳过Gas费用折算: chain=%s", chain.Name)
+	return nil
+}
+
+// cost Gas费用折算的输出代币数量(最小单位，向下取整)
+func (c *gasCostConverter) cost(gasEstimate uint64) decimal.Decimal {
+	// Gas价格为Gwei，折算为原生代币最小单位(wei)
+	costNative := decimal.NewFromInt(int64(gasEstimate)).
+		Mul(decimal.NewFromInt(int64(c.gasPriceGwei))).
+		Shift(9)
+	if c.toIsNative {
+		return costNative
+	}
+
+	costUSD := costNative.Shift(-c.nativeDecimals).Mul(c.nativePriceUSD)
+	return costUSD.Div(c.toPriceUSD).Shift(c.toDecimals).Floor()
+}
+
+// ========================================
+// 价格冲击分析
+// ========================================
+
+// GetPriceImpactAnalysis 价格冲击分析
+// 按请求数量的若干倍数并发通过智能路由采样同一交易对的报价，返回价格冲击曲线，
+// 以及价格冲击不超过阈值的最大采样数量；采样报价不记录到报价历史
+// 参数:
+//   - req: 报价请求参数(AmountIn为基准数量)
+//
+// 返回:
+//   - *types.PriceImpactAnalysis: 价格冲击分析结果
+//   - error: 参数错误或所有采样均失败
+func (s *quoteService) GetPriceImpactAnalysis(req *types.QuoteRequest) (*types.PriceImpactAnalysis, error) {
+	if err := s.validateQuoteRequest(req); err != nil {
+		return nil, err
+	}
+	fromToken, toToken, chain, _, err := s.getTokenInfo(req.FromTokenID, req.ToTokenID, req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	analysisID := uuid.New().String()
+	s.logger.Infof("[%s] 开始价格冲击分析: %s->%s, amount=%s, samples=%d",
+		analysisID, fromToken.Symbol, toToken.Symbol, req.AmountIn.String(), len(priceImpactSampleMultipliers))
+
+	points := make([]*types.PriceImpactPoint, 0, len(priceImpactSampleMultipliers))
+	for _, multiplier := range priceImpactSampleMultipliers {
+		amountIn := req.AmountIn.Mul(multiplier).Floor()
+		if !amountIn.IsPositive() {
+			continue
+		}
+		amountInFormatted := amountIn.Shift(-int32(fromToken.Decimals))
+		points = append(points, &types.PriceImpactPoint{
+			Multiplier:        multiplier,
+			AmountIn:          amountIn,
+			AmountInFormatted: &amountInFormatted,
+		})
+	}
+	if len(points) == 0 {
+		return nil, NewServiceError(types.ErrCodeValidation, "输入数量过小，无法采样价格冲击", nil)
+	}
+
+	// 并发采样各交易规模的报价
+	var wg sync.WaitGroup
+	for i, point := range points {
+		wg.Add(1)
+		go func(i int, point *types.PriceImpactPoint) {
+			defer wg.Done()
+
+			sampleReq := *req
+			sampleReq.AmountIn = point.AmountIn
+			routerReq := s.newSmartRouterRequest(fmt.Sprintf("%s-%d", analysisID, i), &sampleReq, fromToken, toToken, chain)
+
+			routerResponse, err := s.callSmartRouter(routerReq)
+			if err != nil {
+				point.ErrorMessage = err.Error()
+				return
+			}
+
+			data := routerResponse.Data
+			amountOut := data.BestPrice
+			amountOutFormatted := amountOut.Shift(-int32(toToken.Decimals))
+			exchangeRate := data.ExchangeRate
+			priceImpact := data.PriceImpact
+			point.Success = true
+			point.AmountOut = &amountOut
+			point.AmountOutFormatted = &amountOutFormatted
+			point.BestAggregator = data.BestProvider
+			point.GasEstimate = data.BestGasEstimate
+			point.ExchangeRate = &exchangeRate
+			point.PriceImpact = &priceImpact
+		}(i, point)
+	}
+	wg.Wait()
+
+	analysis := &types.PriceImpactAnalysis{
+		FromToken:       *s.convertTokenToInfo(fromToken),
+		ToToken:         *s.convertTokenToInfo(toToken),
+		AmountIn:        req.AmountIn,
+		ImpactThreshold: priceImpactThreshold,
+		Points:          points,
+	}
+
+	// 以最小成功采样的汇率为基准计算汇率衰减
+	var baseRate decimal.Decimal
+	successCount := 0
+	for _, point := range points {
+		if !point.Success {
+			continue
+		}
+		successCount++
+
+		rate := point.AmountOut.Div(point.AmountIn)
+		if baseRate.IsZero() {
+			baseRate = rate
+		}
+		degradation := decimal.Zero
+		if baseRate.IsPositive() {
+			degradation = decimal.NewFromInt(1).Sub(rate.Div(baseRate))
+			if degradation.IsNegative() {
+				degradation = decimal.Zero
+			}
+		}
+		degradation = degradation.Round(6)
+		impact := decimal.Max(*point.PriceImpact, degradation)
+		point.RateDegradation = &degradation
+		point.Impact = &impact
+	}
+	if successCount == 0 {
+		return nil, NewServiceError(types.ErrCodeExternalAPI, fmt.Sprintf("价格冲击分析采样全部失败: %s", points[0].ErrorMessage), nil)
+	}
+
+	// 价格冲击随规模单调变差，遇到失败或超过阈值的采样即停止
+	for _, point := range points {
+		if !point.Success || point.Impact.GreaterThan(priceImpactThreshold) {
+			break
+		}
+		amountIn := point.AmountIn
+		analysis.MaxAmountInWithinThreshold = &amountIn
+	}
+
+	s.logger.Infof("[%s] 价格冲击分析完成: success=%d/%d, maxWithinThreshold=%v",
+		analysisID, successCount, len(points), analysis.MaxAmountInWithinThreshold)
+	return analysis, nil
+}
@@ -4,8 +4,12 @@
 package services
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"defi-aggregator/business-logic/internal/models"
@@ -38,6 +42,12 @@ type SmartRouterQuoteRequest struct {
 	ChainID     uint            `json:"chain_id"`               // 链ID
 	Slippage    decimal.Decimal `json:"slippage"`               // 滑点
 	UserAddress string          `json:"user_address,omitempty"` // 用户地址
+
+	// 代币元数据，智能路由据此换算可读数量和汇率
+	FromTokenDecimals *int32 `json:"from_token_decimals,omitempty"` // 源代币小数位数
+	ToTokenDecimals   *int32 `json:"to_token_decimals,omitempty"`   // 目标代币小数位数
+	FromTokenSymbol   string `json:"from_token_symbol,omitempty"`   // 源代币符号
+	ToTokenSymbol     string `json:"to_token_symbol,omitempty"`     // 目标代币符号
 }
 
 // SmartRouterQuoteResponse 智能路由服务响应格式
@@ -51,16 +61,46 @@ type SmartRouterQuoteResponse struct {
 
 // SmartRouterQuoteData 智能路由报价数据
 type SmartRouterQuoteData struct {
-	RequestID       string          `json:"request_id"`
-	BestProvider    string          `json:"best_provider"`
-	BestPrice       decimal.Decimal `json:"best_price"`
-	BestGasEstimate uint64          `json:"best_gas_estimate"`
-	PriceImpact     decimal.Decimal `json:"price_impact"`
-	ExchangeRate    decimal.Decimal `json:"exchange_rate"`
-	Route           []RouteStep     `json:"route,omitempty"`
-	ValidUntil      time.Time       `json:"valid_until"`
-	CacheHit        bool            `json:"cache_hit"`
-	Performance     Performance     `json:"performance"`
+	RequestID           string           `json:"request_id"`
+	AmountIn            decimal.Decimal  `json:"amount_in"`
+	AmountInFormatted   *decimal.Decimal `json:"amount_in_formatted,omitempty"`
+	BestProvider        string           `json:"best_provider"`
+	BestPrice           decimal.Decimal  `json:"best_price"`
+	BestPriceFormatted  *decimal.Decimal `json:"best_price_formatted,omitempty"`
+	BestGasEstimate     uint64           `json:"best_gas_estimate"`
+	PriceImpact         decimal.Decimal  `json:"price_impact"`
+	ExchangeRate        decimal.Decimal  `json:"exchange_rate"`
+	InverseExchangeRate decimal.Decimal  `json:"inverse_exchange_rate"`
+	Route               []RouteStep      `json:"route,omitempty"`
+	ValidUntil          time.Time        `json:"valid_until"`
+	CacheHit            bool             `json:"cache_hit"`
+	AllQuotes           []ProviderQuote  `json:"all_quotes,omitempty"`
+	Performance         Performance      `json:"performance"`
+}
+
+// ProviderQuote 智能路由返回的单个聚合器报价
+// 包含失败的聚合器，路径和原始响应原样保留，用于持久化到quote_responses表
+type ProviderQuote struct {
+	Provider     string           `json:"provider"`
+	Success      bool             `json:"success"`
+	AmountOut    decimal.Decimal  `json:"amount_out"`
+	GasEstimate  uint64           `json:"gas_estimate"`
+	PriceImpact  decimal.Decimal  `json:"price_impact"`
+	Route        json.RawMessage  `json:"route,omitempty"`
+	ResponseTime int64            `json:"response_time"` // 纳秒，对应Smart Router的time.Duration序列化
+	Confidence   decimal.Decimal  `json:"confidence"`
+	Rank         int              `json:"rank"` // 排名，未参与排名的报价为0
+	ErrorCode    string           `json:"error_code,omitempty"`
+	ErrorMessage string           `json:"error_message,omitempty"`
+	RawResponse  json.RawMessage  `json:"raw_response,omitempty"`
+	NetAmountOut *decimal.Decimal `json:"net_amount_out,omitempty"` // 扣除Gas费用后的净输出数量(智能路由行情数据可用时计算)
+	GasCost      *RouterGasCost   `json:"gas_cost,omitempty"`       // Gas费用明细
+}
+
+// RouterGasCost 智能路由计算的Gas费用明细(只解析报价对比所需字段)
+type RouterGasCost struct {
+	GasPrice          decimal.Decimal `json:"gas_price"`            // Gas价格(wei)
+	CostInOutputToken decimal.Decimal `json:"cost_in_output_token"` // Gas费用折算的输出代币数量(最小单位)
 }
 
 // RouteStep 交易路径步骤
@@ -108,6 +148,119 @@ func NewQuoteService(repos *repository.Repositories, cfg *config.Config, logger
 //   - *types.QuoteResponse: 最优报价响应
 //   - error: 获取过程中的错误
 func (s *quoteService) GetQuote(req *types.QuoteRequest) (*types.QuoteResponse, error) {
+	prepared, err := s.prepareQuote(req)
+	if err != nil {
+		return nil, err
+	}
+	requestID := prepared.routerReq.RequestID
+
+	// 4. 调用智能路由服务
+	routerResponse, err := s.callSmartRouter(prepared.routerReq)
+	if err != nil {
+		// 更新数据库记录为失败状态
+		if prepared.quoteRequest != nil {
+			s.updateQuoteRequestStatus(prepared.quoteRequest.ID, "failed", err.Error())
+		}
+		return nil, err
+	}
+
+	// 5. 转换为业务层响应格式
+	response := s.convertToQuoteResponse(routerResponse, prepared.fromToken, prepared.toToken, prepared.startTime)
+
+	// 6. 更新数据库记录为成功状态
+	if prepared.quoteRequest != nil {
+		s.updateQuoteRequestSuccess(prepared.quoteRequest, routerResponse)
+	}
+
+	s.logger.Infof("[%s] 报价请求处理完成: provider=%s, duration=%v",
+		requestID, response.BestAggregator, time.Since(prepared.startTime))
+
+	return response, nil
+}
+
+// StreamQuote 流式获取报价
+// 透传智能路由服务的SSE报价流：每个聚合器报价到达时转发quote事件，
+// 聚合完成后将最优结果转换为业务层格式并发送best事件
+// 参数:
+//   - ctx: 请求上下文，客户端断开时终止上游流
+//   - req: 报价请求参数
+//   - emit: 事件发送函数
+//
+// 返回:
+//   - error: 建立流或处理过程中的错误
+func (s *quoteService) StreamQuote(ctx context.Context, req *types.QuoteRequest, emit QuoteStreamEmitter) error {
+	prepared, err := s.prepareQuote(req)
+	if err != nil {
+		return err
+	}
+	requestID := prepared.routerReq.RequestID
+
+	completed := false
+	err = s.callSmartRouterStream(ctx, prepared.routerReq, func(event string, data []byte) error {
+		switch event {
+		case types.QuoteStreamEventQuote:
+			// 单个聚合器报价原样转发
+			return emit(types.QuoteStreamEventQuote, json.RawMessage(data))
+
+		case types.QuoteStreamEventBest:
+			var routerData SmartRouterQuoteData
+			if err := json.Unmarshal(data, &routerData); err != nil {
+				return NewServiceError(types.ErrCodeExternalAPI, "解析智能路由最优报价失败", err)
+			}
+			routerResponse := &SmartRouterQuoteResponse{
+				Success:   true,
+				Data:      &routerData,
+				Timestamp: time.Now().Unix(),
+				RequestID: requestID,
+			}
+
+			response := s.convertToQuoteResponse(routerResponse, prepared.fromToken, prepared.toToken, prepared.startTime)
+			if prepared.quoteRequest != nil {
+				s.updateQuoteRequestSuccess(prepared.quoteRequest, routerResponse)
+			}
+
+			completed = true
+			return emit(types.QuoteStreamEventBest, response)
+
+		case types.QuoteStreamEventError:
+			errorMsg := "智能路由服务返回错误"
+			var routerErr SmartRouterError
+			if err := json.Unmarshal(data, &routerErr); err == nil && routerErr.Message != "" {
+				errorMsg = routerErr.Message
+			}
+			return NewServiceError(types.ErrCodeExternalAPI, errorMsg, nil)
+		}
+
+		s.logger.Debugf("[%s] 忽略未知的流式事件: %s", requestID, event)
+		return nil
+	})
+
+	if err == nil && !completed {
+		err = NewServiceError(types.ErrCodeExternalAPI, "智能路由报价流意外结束", nil)
+	}
+	if err != nil {
+		if prepared.quoteRequest != nil {
+			s.updateQuoteRequestStatus(prepared.quoteRequest.ID, "failed", err.Error())
+		}
+		return err
+	}
+
+	s.logger.Infof("[%s] 流式报价处理完成: duration=%v", requestID, time.Since(prepared.startTime))
+	return nil
+}
+
+// preparedQuote 报价请求的预处理结果
+type preparedQuote struct {
+	startTime    time.Time                // 请求开始时间
+	fromToken    *models.Token            // 源代币
+	toToken      *models.Token            // 目标代币
+	quoteRequest *models.QuoteRequest     // 数据库报价记录(记录失败时为nil)
+	routerReq    *SmartRouterQuoteRequest // 智能路由服务请求
+}
+
+// prepareQuote 预处理报价请求
+// 验证参数、加载代币信息、记录报价请求并构建智能路由请求
+func (s *quoteService) prepareQuote(req *types.QuoteRequest) (*preparedQuote, error) {
 	requestID := uuid.New().String()
 	startTime := time.Now()
 
@@ -132,15 +285,30 @@ func (s *quoteService) GetQuote(req *types.QuoteRequest) (*types.QuoteResponse,
 		// 不影响主流程，继续处理
 	}
 
-	// 4. 调用智能路由服务
+	return &preparedQuote{
+		startTime:    startTime,
+		fromToken:    fromToken,
+		toToken:      toToken,
+		quoteRequest: quoteRequest,
+		routerReq:    s.newSmartRouterRequest(requestID, req, fromToken, toToken, fromTokenChain),
+	}, nil
+}
+
+// newSmartRouterRequest 构建智能路由服务请求
+func (s *quoteService) newSmartRouterRequest(requestID string, req *types.QuoteRequest, fromToken, toToken *models.Token, chain *models.Chain) *SmartRouterQuoteRequest {
 	// 使用数据库中的外部chain_id，这样智能路由服务可以正确识别网络
+	fromDecimals, toDecimals := int32(fromToken.Decimals), int32(toToken.Decimals)
 	smartRouterReq := &SmartRouterQuoteRequest{
-		RequestID: requestID,
-		FromToken: fromToken.ContractAddress,
-		ToToken:   toToken.ContractAddress,
-		AmountIn:  req.AmountIn,
-		ChainID:   fromTokenChain.ChainID, // 使用外部chain_id（真实的区块链ID）
-		Slippage:  req.Slippage,
+		RequestID:         requestID,
+		FromToken:         fromToken.ContractAddress,
+		ToToken:           toToken.ContractAddress,
+		AmountIn:          req.AmountIn,
+		ChainID:           chain.ChainID, // 使用外部chain_id（真实的区块链ID）
+		Slippage:          req.Slippage,
+		FromTokenDecimals: &fromDecimals,
+		ToTokenDecimals:   &toDecimals,
+		FromTokenSymbol:   fromToken.Symbol,
+		ToTokenSymbol:     toToken.Symbol,
 	}
 
 	// 设置用户地址（如果存在）
@@ -148,27 +316,7 @@ func (s *quoteService) GetQuote(req *types.QuoteRequest) (*types.QuoteResponse,
 		smartRouterReq.UserAddress = *req.UserAddress
 	}
 
-	routerResponse, err := s.callSmartRouter(smartRouterReq)
-	if err != nil {
-		// 更新数据库记录为失败状态
-		if quoteRequest != nil {
-			s.updateQuoteRequestStatus(quoteRequest.ID, "failed", err.Error())
-		}
-		return nil, err
-	}
-
-	// 5. 转换为业务层响应格式
-	response := s.convertToQuoteResponse(routerResponse, fromToken, toToken, startTime)
-
-	// 6. 更新数据库记录为成功状态
-	if quoteRequest != nil {
-		s.updateQuoteRequestSuccess(quoteRequest, routerResponse)
-	}
-
-	s.logger.Infof("[%s] 报价请求处理完成: provider=%s, duration=%v",
-		requestID, response.BestAggregator, time.Since(startTime))
-
-	return response, nil
+	return smartRouterReq
 }
 
 // GetQuoteHistory 获取报价历史
@@ -246,6 +394,47 @@ func (s *quoteService) GetQuoteDetails(requestID string) (*types.QuoteResponse,
 	return response, nil
 }
 
+// GetQuoteResponses 获取报价请求的所有聚合器响应
+// 按排名排序，未参与排名的成功报价其次，失败的聚合器排在最后
+// 参数:
+//   - requestID: 报价请求ID
+//
+// 返回:
+//   - []*types.AggregatorQuoteResponse: 聚合器响应列表
+//   - error: 查询错误
+func (s *quoteService) GetQuoteResponses(requestID string) ([]*types.AggregatorQuoteResponse, error) {
+	_, responses, err := s.loadQuoteResponses(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugf("获取聚合器响应成功: requestID=%s, count=%d", requestID, len(responses))
+	return responses, nil
+}
+
+// loadQuoteResponses 加载报价请求及其聚合器响应(已排序)
+func (s *quoteService) loadQuoteResponses(requestID string) (*models.QuoteRequest, []*types.AggregatorQuoteResponse, error) {
+	quoteRequest, err := s.repos.QuoteRequest.GetByRequestID(requestID)
+	if err != nil {
+		s.logger.Warnf("获取聚合器响应失败: requestID=%s, error=%v", requestID, err)
+		return nil, nil, NewServiceError(types.ErrCodeNotFound, "报价请求不存在", err)
+	}
+
+	responses, err := s.repos.QuoteRequest.GetResponses(quoteRequest.ID)
+	if err != nil {
+		return nil, nil, NewServiceError(types.ErrCodeInternal, "获取聚合器响应失败", err)
+	}
+
+	result := make([]*types.AggregatorQuoteResponse, 0, len(responses))
+	for _, response := range responses {
+		result = append(result, s.convertToAggregatorQuoteResponse(response))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return compareAggregatorResponses(result[i], result[j])
+	})
+	return quoteRequest, result, nil
+}
+
 // ========================================
 // 智能路由服务调用
 // ========================================
@@ -290,6 +479,62 @@ func (s *quoteService) callSmartRouter(req *SmartRouterQuoteRequest) (*SmartRout
 	return &response, nil
 }
 
+// callSmartRouterStream 调用智能路由流式报价接口
+// 逐条解析SSE事件并交给handler处理，handler返回错误时立即终止
+func (s *quoteService) callSmartRouterStream(ctx context.Context, req *SmartRouterQuoteRequest, handler func(event string, data []byte) error) error {
+	smartRouterURL := fmt.Sprintf("%s/api/v1/quote/stream", s.cfg.ExternalServices.SmartRouterURL)
+
+	s.logger.Debugf("[%s] 调用智能路由流式服务: %s", req.RequestID, smartRouterURL)
+
+	// 流式请求的总时长同样受外部服务超时限制
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.ExternalServices.Timeout)
+	defer cancel()
+
+	body, err := s.httpClient.PostStream(ctx, smartRouterURL, req, map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "text/event-stream",
+	})
+	if err != nil {
+		s.logger.Errorf("[%s] 智能路由流式服务调用失败: URL=%s, 错误=%v", req.RequestID, smartRouterURL, err)
+		return NewServiceError(types.ErrCodeExternalAPI, fmt.Sprintf("智能路由服务调用失败: %v", err), err)
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// 空行表示一个事件结束
+		if line == "" {
+			if len(data) > 0 {
+				if err := handler(event, []byte(strings.Join(data, "\n"))); err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return NewServiceError(types.ErrCodeExternalAPI, fmt.Sprintf("读取智能路由报价流失败: %v", err), err)
+	}
+	return nil
+}
+
 // ========================================
 // 数据库操作
 // ========================================
@@ -348,22 +593,139 @@ func (s *quoteService) updateQuoteRequestSuccess(quoteRequest *models.QuoteReque
 		now := time.Now()
 		quoteRequest.CompletedAt = &now
 
-		// 更新最佳结果信息
-		if routerResponse.Data != nil {
-			quoteRequest.BestAmountOut = &routerResponse.Data.BestPrice
-			quoteRequest.BestGasEstimate = &routerResponse.Data.BestGasEstimate
-			quoteRequest.BestPriceImpact = &routerResponse.Data.PriceImpact
-			quoteRequest.CacheHit = routerResponse.Data.CacheHit
+		data := routerResponse.Data
+		if data == nil {
+			if err := s.repos.QuoteRequest.Update(quoteRequest); err != nil {
+				s.logger.Warnf("更新报价请求成功状态失败: %v", err)
+			}
+			return
+		}
 
-			// TODO: 根据provider名称查找aggregator_id
+		// 更新最佳结果信息
+		quoteRequest.BestAmountOut = &data.BestPrice
+		quoteRequest.BestGasEstimate = &data.BestGasEstimate
+		quoteRequest.BestPriceImpact = &data.PriceImpact
+		quoteRequest.CacheHit = data.CacheHit
+		quoteRequest.AggregatorCount = data.Performance.ProvidersQueried
+		quoteRequest.SuccessCount = data.Performance.ProvidersSuccess
+		if len(data.AllQuotes) > 0 {
+			quoteRequest.AggregatorCount = len(data.AllQuotes)
+			quoteRequest.SuccessCount = 0
+			for _, quote := range data.AllQuotes {
+				if quote.Success {
+					quoteRequest.SuccessCount++
+				}
+			}
 		}
 
+		// 记录最佳聚合器，创建交易时由该聚合器构建calldata
+		aggregatorIDs := newAggregatorIDResolver(s.repos.Aggregator, s.logger)
+		quoteRequest.BestAggregatorID = aggregatorIDs.resolve(data.BestProvider)
+
 		if err := s.repos.QuoteRequest.Update(quoteRequest); err != nil {
 			s.logger.Warnf("更新报价请求成功状态失败: %v", err)
+			return
 		}
+
+		s.saveQuoteResponses(quoteRequest, data.AllQuotes, aggregatorIDs)
 	}()
 }
 
+// saveQuoteResponses 保存各聚合器的报价响应
+// 每个聚合器一条quote_responses记录(包括失败的聚合器)，用于报价溯源和对比
+func (s *quoteService) saveQuoteResponses(quoteRequest *models.QuoteRequest, quotes []ProviderQuote, aggregatorIDs *aggregatorIDResolver) {
+	saved := 0
+	for i := range quotes {
+		quote := &quotes[i]
+		aggregatorID := aggregatorIDs.resolve(quote.Provider)
+		if aggregatorID == nil {
+			continue
+		}
+
+		response := &models.QuoteResponse{
+			QuoteRequestID: quoteRequest.ID,
+			AggregatorID:   *aggregatorID,
+			ResponseTimeMS: int(time.Duration(quote.ResponseTime).Milliseconds()),
+			Success:        quote.Success,
+			RouteData:      jsonbValue(quote.Route),
+			RawResponse:    jsonbValue(quote.RawResponse),
+			ErrorCode:      quote.ErrorCode,
+			ErrorMessage:   quote.ErrorMessage,
+		}
+		if quote.Success {
+			amountOut := quote.AmountOut
+			gasEstimate := quote.GasEstimate
+			priceImpact := quote.PriceImpact.Round(6)
+			confidence := quote.Confidence.Round(2)
+			response.AmountOut = &amountOut
+			response.GasEstimate = &gasEstimate
+			response.PriceImpact = &priceImpact
+			response.ConfidenceScore = &confidence
+
+			// 随报价记录路由按实时Gas价格计算的Gas费用，报价对比不必再用链配置估算
+			if quote.GasCost != nil && quote.NetAmountOut != nil {
+				gasPrice := quote.GasCost.GasPrice
+				gasCost := quote.GasCost.CostInOutputToken
+				netAmountOut := *quote.NetAmountOut
+				response.GasPrice = &gasPrice
+				response.GasCost = &gasCost
+				response.NetAmountOut = &netAmountOut
+			}
+		}
+		if quote.Rank > 0 {
+			rank := quote.Rank
+			response.PriceRank = &rank
+		}
+
+		if err := s.repos.QuoteRequest.CreateResponse(response); err != nil {
+			s.logger.Warnf("[%s] 保存聚合器报价响应失败: provider=%s, 错误=%v", quoteRequest.RequestID, quote.Provider, err)
+			continue
+		}
+		saved++
+	}
+
+	if len(quotes) > 0 {
+		s.logger.Debugf("[%s] 保存聚合器报价响应: %d/%d", quoteRequest.RequestID, saved, len(quotes))
+	}
+}
+
+// aggregatorIDResolver 按聚合器名称查找聚合器ID
+// 同一报价的多次查找共享结果，未注册的聚合器只告警一次
+type aggregatorIDResolver struct {
+	repo   repository.AggregatorRepository
+	logger *logrus.Logger
+	ids    map[string]*uint
+}
+
+// newAggregatorIDResolver 创建聚合器ID查找器
+func newAggregatorIDResolver(repo repository.AggregatorRepository, logger *logrus.Logger) *aggregatorIDResolver {
+	return &aggregatorIDResolver{repo: repo, logger: logger, ids: make(map[string]*uint)}
+}
+
+// resolve 返回聚合器ID，聚合器不存在时返回nil
+func (r *aggregatorIDResolver) resolve(name string) *uint {
+	if id, ok := r.ids[name]; ok {
+		return id
+	}
+
+	var id *uint
+	if aggregator, err := r.repo.GetByName(name); err == nil {
+		id = &aggregator.ID
+	} else {
+		r.logger.Warnf("查找聚合器失败: provider=%s, 错误=%v", name, err)
+	}
+	r.ids[name] = id
+	return id
+}
+
+// jsonbValue 将JSON片段转换为JSONB列的值，空值存为JSON null
+func jsonbValue(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return "null"
+	}
+	return string(raw)
+}
+
 // ========================================
 // 数据转换方法
 // ========================================
@@ -382,19 +744,22 @@ func (s *quoteService) convertToQuoteResponse(routerResp *SmartRouterQuoteRespon
 	}
 
 	return &types.QuoteResponse{
-		RequestID:       data.RequestID,
-		FromToken:       *s.convertTokenToInfo(fromToken),
-		ToToken:         *s.convertTokenToInfo(toToken),
-		AmountIn:        data.BestPrice, // TODO: 使用原始输入数量
-		AmountOut:       data.BestPrice,
-		BestAggregator:  data.BestProvider,
-		GasEstimate:     data.BestGasEstimate,
-		PriceImpact:     data.PriceImpact,
-		ExchangeRate:    data.ExchangeRate,
-		Route:           route,
-		ValidUntil:      data.ValidUntil,
-		TotalDurationMS: int(time.Since(startTime).Milliseconds()),
-		CacheHit:        data.CacheHit,
+		RequestID:           data.RequestID,
+		FromToken:           *s.convertTokenToInfo(fromToken),
+		ToToken:             *s.convertTokenToInfo(toToken),
+		AmountIn:            data.AmountIn,
+		AmountOut:           data.BestPrice,
+		AmountInFormatted:   data.AmountInFormatted,
+		AmountOutFormatted:  data.BestPriceFormatted,
+		BestAggregator:      data.BestProvider,
+		GasEstimate:         data.BestGasEstimate,
+		PriceImpact:         data.PriceImpact,
+		ExchangeRate:        data.ExchangeRate,
+		InverseExchangeRate: data.InverseExchangeRate,
+		Route:               route,
+		ValidUntil:          data.ValidUntil,
+		TotalDurationMS:     int(time.Since(startTime).Milliseconds()),
+		CacheHit:            data.CacheHit,
 	}
 }
 
@@ -428,6 +793,63 @@ func (s *quoteService) convertQuoteRequestToResponse(quoteReq *models.QuoteReque
 	return response
 }
 
+// convertToAggregatorQuoteResponse 将聚合器响应记录转换为API响应
+func (s *quoteService) convertToAggregatorQuoteResponse(response *models.QuoteResponse) *types.AggregatorQuoteResponse {
+	result := &types.AggregatorQuoteResponse{
+		AggregatorID:    response.AggregatorID,
+		AggregatorName:  response.Aggregator.Name,
+		DisplayName:     response.Aggregator.DisplayName,
+		Success:         response.Success,
+		AmountOut:       response.AmountOut,
+		GasEstimate:     response.GasEstimate,
+		GasPrice:        response.GasPrice,
+		GasCost:         response.GasCost,
+		NetAmountOut:    response.NetAmountOut,
+		PriceImpact:     response.PriceImpact,
+		ConfidenceScore: response.ConfidenceScore,
+		PriceRank:       response.PriceRank,
+		ResponseTimeMS:  response.ResponseTimeMS,
+		ErrorCode:       response.ErrorCode,
+		ErrorMessage:    response.ErrorMessage,
+		CreatedAt:       response.CreatedAt,
+	}
+
+	if response.RouteData != "" && response.RouteData != "null" {
+		if err := json.Unmarshal([]byte(response.RouteData), &result.Route); err != nil {
+			s.logger.Debugf("解析聚合器交易路径失败: responseID=%d, error=%v", response.ID, err)
+		}
+	}
+	if response.RawResponse != "" && response.RawResponse != "null" {
+		result.RawResponse = json.RawMessage(response.RawResponse)
+	}
+
+	return result
+}
+
+// compareAggregatorResponses 聚合器响应排序规则
+// 有排名的按排名，未参与排名的成功报价其次，失败的最后；同组内响应快的优先
+func compareAggregatorResponses(a, b *types.AggregatorQuoteResponse) bool {
+	groupOf := func(response *types.AggregatorQuoteResponse) int {
+		switch {
+		case response.PriceRank != nil:
+			return 0
+		case response.Success:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	groupA, groupB := groupOf(a), groupOf(b)
+	if groupA != groupB {
+		return groupA < groupB
+	}
+	if groupA == 0 && *a.PriceRank != *b.PriceRank {
+		return *a.PriceRank < *b.PriceRank
+	}
+	return a.ResponseTimeMS < b.ResponseTimeMS
+}
+
 // convertTokenToInfo 将Token模型转换为TokenInfo
 func (s *quoteService) convertTokenToInfo(token *models.Token) *types.TokenInfo {
 	tokenInfo := &types.TokenInfo{
@@ -541,11 +963,6 @@ func (s *quoteService) getTokenInfo(fromTokenID, toTokenID uint, requestChainID
 // 临时实现的其他接口方法
 // ========================================
 
-func (s *quoteService) GetQuoteResponses(requestID string) ([]*types.AggregatorQuoteResponse, error) {
-	// TODO: 实现获取所有聚合器响应
-	return nil, nil
-}
-
 func (s *quoteService) InvalidateQuoteCache(fromTokenID, toTokenID uint) error {
 	// TODO: 实现缓存失效
 	return nil
@@ -555,13 +972,3 @@ func (s *quoteService) GetCacheStats() (map[string]interface{}, error) {
 	// TODO: 实现缓存统计
 	return nil, nil
 }
-
-func (s *quoteService) CompareQuotes(requestID string) (*types.QuoteComparison, error) {
-	// TODO: 实现报价比较
-	return &types.QuoteComparison{}, nil
-}
-
-func (s *quoteService) GetPriceImpactAnalysis(req *types.QuoteRequest) (*types.PriceImpactAnalysis, error) {
-	// TODO: 实现价格冲击分析
-	return &types.PriceImpactAnalysis{}, nil
-}
@@ -4,11 +4,13 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"defi-aggregator/business-logic/internal/repository"
 	"defi-aggregator/business-logic/internal/types"
 	"defi-aggregator/business-logic/pkg/config"
+	"defi-aggregator/business-logic/pkg/session"
 
 	"github.com/sirupsen/logrus"
 )
@@ -18,6 +20,7 @@ import (
 type Services struct {
 	User   UserService   // 用户业务服务
 	Auth   AuthService   // 认证业务服务
+	APIKey APIKeyService // API Key业务服务
 	Token  TokenService  // 代币业务服务
 	Chain  ChainService  // 区块链业务服务
 	Quote  QuoteService  // 报价业务服务
@@ -30,15 +33,17 @@ type Services struct {
 // 初始化所有业务服务，注入依赖的Repository和配置
 // 参数:
 //   - repos: 数据访问层实例
+//   - sessions: JWT会话存储
 //   - cfg: 应用配置
 //   - logger: 日志记录器
 //
 // 返回:
 //   - *Services: 完整的业务服务集合
-func New(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) *Services {
+func New(repos *repository.Repositories, sessions session.Store, cfg *config.Config, logger *logrus.Logger) *Services {
 	return &Services{
 		User:   NewUserService(repos, cfg, logger),
-		Auth:   NewAuthService(repos, cfg, logger),
+		Auth:   NewAuthService(repos, sessions, cfg, logger),
+		APIKey: NewAPIKeyService(repos, cfg, logger),
 		Token:  NewTokenService(repos, cfg, logger),
 		Chain:  NewChainService(repos, cfg, logger),
 		Quote:  NewQuoteService(repos, cfg, logger),
@@ -52,31 +57,11 @@ func New(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logg
 // 临时构造函数实现（待实现具体服务）
 // ========================================
 
-func NewSwapService(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) SwapService {
-	return &swapService{repos: repos, cfg: cfg, logger: logger}
-}
-
-func NewStatsService(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) StatsService {
-	return &statsService{repos: repos, cfg: cfg, logger: logger}
-}
-
 func NewHealthService(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) HealthService {
 	return &healthService{repos: repos, cfg: cfg, logger: logger}
 }
 
 // 临时服务实现结构体
-type swapService struct {
-	repos  *repository.Repositories
-	cfg    *config.Config
-	logger *logrus.Logger
-}
-
-type statsService struct {
-	repos  *repository.Repositories
-	cfg    *config.Config
-	logger *logrus.Logger
-}
-
 type healthService struct {
 	repos  *repository.Repositories
 	cfg    *config.Config
@@ -121,14 +106,34 @@ type AuthService interface {
 	VerifySignature(req *types.UserLoginRequest) (*types.UserLoginResponse, error) // 验证签名并登录
 
 	// JWT令牌管理
-	GenerateTokens(userID uint, walletAddress string) (accessToken, refreshToken string, err error) // 生成访问令牌
-	RefreshToken(refreshToken string) (newAccessToken string, err error)                            // 刷新访问令牌
-	RevokeToken(userID uint, tokenType string) error                                                // 撤销令牌
+	GenerateTokens(userID uint, walletAddress string) (accessToken, refreshToken string, err error) // 创建会话并生成令牌对
+	RefreshToken(refreshToken string) (accessToken, newRefreshToken string, err error)              // 轮换令牌对
 
 	// 会话管理
-	ValidateSession(userID uint) error   // 验证会话有效性
-	LogoutUser(userID uint) error        // 用户登出
-	LogoutAllSessions(userID uint) error // 登出所有会话
+	ValidateSession(userID uint) error                 // 验证会话有效性
+	RevokeSession(userID uint, sessionID string) error // 撤销单个会话
+	LogoutUser(userID uint, sessionID string) error    // 用户登出(撤销当前会话)
+	LogoutAllSessions(userID uint) error               // 登出所有会话
+}
+
+// ========================================
+// API Key业务服务接口
+// ========================================
+
+// APIKeyService API Key业务服务接口
+// 管理合作方以X-API-Key调用接口所用的密钥，密钥明文只在创建和轮换时返回一次
+type APIKeyService interface {
+	// 用户管理自己的API Key
+	CreateAPIKey(userID uint, req *types.CreateAPIKeyRequest) (*types.APIKeySecretResponse, error) // 创建API Key
+	ListAPIKeys(userID uint) ([]*types.APIKeyInfo, error)                                          // 获取用户的API Key列表
+	RotateAPIKey(userID, keyID uint) (*types.APIKeySecretResponse, error)                          // 轮换密钥(旧密钥立即失效)
+	RevokeAPIKey(userID, keyID uint) error                                                         // 撤销API Key
+
+	// 管理员操作
+	UpdateAPIKeyTier(keyID uint, tier string) (*types.APIKeyInfo, error) // 调整API Key配额等级
+
+	// 网关鉴权
+	ResolveAPIKey(rawKey string) (*types.APIKeyPrincipal, error) // 解析密钥为调用方身份
 }
 
 // ========================================
@@ -196,11 +201,16 @@ type ChainService interface {
 // 报价业务服务接口
 // ========================================
 
+// QuoteStreamEmitter 流式报价事件发送函数
+// event为事件类型(types.QuoteStreamEvent*)，返回错误时终止流式处理
+type QuoteStreamEmitter func(event string, data interface{}) error
+
 // QuoteService 报价业务服务接口
 // 处理报价请求、聚合器调用、最优价格选择等核心业务逻辑
 type QuoteService interface {
 	// 报价核心操作
 	GetQuote(req *types.QuoteRequest) (*types.QuoteResponse, error)                                          // 获取最优报价
+	StreamQuote(ctx context.Context, req *types.QuoteRequest, emit QuoteStreamEmitter) error                 // 流式获取报价
 	GetQuoteHistory(userID *uint, req *types.PaginationRequest) ([]*types.QuoteResponse, *types.Meta, error) // 获取报价历史
 
 	// 报价详情
@@ -222,23 +232,25 @@ type QuoteService interface {
 
 // SwapService 交易业务服务接口
 // 处理交易创建、状态跟踪、交易历史等功能
+// userID为空表示匿名用户，匿名创建的交易只能通过用户地址校验归属
 type SwapService interface {
 	// 交易核心操作
-	CreateSwap(req *types.SwapRequest) (*types.SwapResponse, error)                        // 创建交易
-	GetSwapStatus(txHash string) (*types.TransactionInfo, error)                           // 获取交易状态
-	UpdateSwapStatus(txHash string, status string, blockData map[string]interface{}) error // 更新交易状态
+	CreateSwap(ctx context.Context, userID *uint, req *types.SwapRequest) (*types.SwapResponse, error) // 创建交易
+	SubmitTransaction(userID *uint, req *types.SubmitSwapRequest) (*types.TransactionInfo, error)      // 记录钱包提交的交易哈希
+	GetSwapStatus(txHash string) (*types.TransactionInfo, error)                                       // 获取交易状态
+	UpdateSwapStatus(txHash string, status string, blockData map[string]interface{}) error             // 更新交易状态
 
 	// 交易历史
 	GetTransactionHistory(userID *uint, req *types.TransactionListRequest) ([]*types.TransactionInfo, *types.Meta, error) // 获取交易历史
-	GetTransactionDetails(id uint) (*types.TransactionInfo, error)                                                        // 获取交易详情
+	GetTransactionDetails(id uint, userID uint) (*types.TransactionInfo, error)                                           // 获取交易详情
 
 	// 交易分析
 	CalculateTransactionCost(req *types.SwapRequest) (*types.TransactionCost, error) // 计算交易成本
 	GetSlippageAnalysis(txHash string) (*types.SlippageAnalysis, error)              // 滑点分析
 
 	// 交易管理
-	CancelTransaction(id uint, userID uint) error                       // 取消交易
-	RetryTransaction(id uint, userID uint) (*types.SwapResponse, error) // 重试交易
+	CancelTransaction(id uint, userID uint) error                                            // 取消交易
+	RetryTransaction(ctx context.Context, id uint, userID uint) (*types.SwapResponse, error) // 重试交易
 }
 
 // 交易相关类型补充定义
@@ -272,12 +284,12 @@ type StatsService interface {
 
 	// 聚合器统计
 	GetAggregatorStats(aggregatorID *uint, timeRange string) ([]*types.AggregatorStats, error) // 获取聚合器统计
-	GetAggregatorRankings() ([]*types.AggregatorRanking, error)                                // 获取聚合器排名
-	GetAggregatorComparison() (*types.AggregatorComparison, error)                             // 聚合器对比分析
+	GetAggregatorRankings(timeRange string) ([]*types.AggregatorRanking, error)                // 获取聚合器排名
+	GetAggregatorComparison(timeRange string) (*types.AggregatorComparison, error)             // 聚合器对比分析
 
 	// 代币对统计
 	GetTokenPairStats(fromTokenID, toTokenID uint, timeRange string) ([]*types.TokenPairStats, error) // 获取代币对统计
-	GetPopularTokenPairs(limit int) ([]*types.PopularTokenPair, error)                                // 获取热门代币对
+	GetPopularTokenPairs(limit int, timeRange string) ([]*types.PopularTokenPair, error)              // 获取热门代币对
 	GetTradingVolume(timeRange string) ([]*types.VolumeData, error)                                   // 获取交易量数据
 
 	// 用户统计
@@ -289,29 +301,6 @@ type StatsService interface {
 	GetLatencyStats() ([]*types.LatencyStats, error)           // 获取延迟统计
 }
 
-// 统计相关类型补充定义
-type AggregatorStats struct {
-	AggregatorName  string  `json:"aggregator_name"`
-	TotalRequests   int     `json:"total_requests"`
-	SuccessRate     float64 `json:"success_rate"`
-	AvgResponseTime int     `json:"avg_response_time"`
-	TotalVolume     string  `json:"total_volume"`
-	BestQuoteCount  int     `json:"best_quote_count"`
-}
-
-type AggregatorRanking struct {
-	Rank           int                    `json:"rank"`
-	AggregatorName string                 `json:"aggregator_name"`
-	Score          float64                `json:"score"`
-	Metrics        map[string]interface{} `json:"metrics"`
-}
-
-type AggregatorComparison struct {
-	Period      string                 `json:"period"`
-	Aggregators []*AggregatorStats     `json:"aggregators"`
-	Summary     map[string]interface{} `json:"summary"`
-}
-
 // ========================================
 // 健康检查服务接口
 // ========================================
@@ -0,0 +1,96 @@
+// Package services 统计汇总任务
+// 后台定期将quote_requests/quote_responses/transactions原始数据汇总到
+// aggregator_stats_hourly(按小时)和token_pair_stats_daily(按日)统计表，
+// 每次从上次汇总所在的整点/零点开始重新计算并覆盖，重复执行结果一致
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"defi-aggregator/business-logic/internal/repository"
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatsRollupJob 统计汇总任务
+type StatsRollupJob struct {
+	repos  *repository.Repositories // 数据访问层
+	cfg    *config.Config           // 应用配置
+	logger *logrus.Logger           // 日志记录器
+
+	runMutex sync.Mutex    // 保证同一时间只有一次汇总
+	lastRun  time.Time     // 上次成功汇总的时间
+	stopChan chan struct{} // 停止信号
+	stopOnce sync.Once     // 保证只停止一次
+}
+
+// NewStatsRollupJob 创建统计汇总任务
+func NewStatsRollupJob(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) *StatsRollupJob {
+	return &StatsRollupJob{
+		repos:    repos,
+		cfg:      cfg,
+		logger:   logger,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start 启动后台汇总协程，启动时立即回补STATS_ROLLUP_BACKFILL内的数据
+func (j *StatsRollupJob) Start() {
+	j.logger.Infof("📊 统计汇总任务已启动: interval=%v, backfill=%v",
+		j.cfg.Stats.RollupInterval, j.cfg.Stats.RollupBackfill)
+	go j.loop()
+}
+
+// Stop 停止后台汇总
+func (j *StatsRollupJob) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.stopChan)
+	})
+}
+
+// RunOnce 执行一次统计汇总
+// 汇总失败时不推进汇总位置，下次执行会覆盖更大的时间范围
+func (j *StatsRollupJob) RunOnce() error {
+	j.runMutex.Lock()
+	defer j.runMutex.Unlock()
+
+	now := time.Now()
+	since := j.lastRun
+	if since.IsZero() {
+		since = now.Add(-j.cfg.Stats.RollupBackfill)
+	}
+
+	hours, err := j.repos.Stats.RollupAggregatorStats(startOfHour(since), now)
+	if err != nil {
+		return fmt.Errorf("汇总聚合器统计失败: %w", err)
+	}
+	pairs, err := j.repos.Stats.RollupTokenPairStats(startOfDay(since), now)
+	if err != nil {
+		return fmt.Errorf("汇总代币对统计失败: %w", err)
+	}
+
+	j.lastRun = now
+	j.logger.Debugf("📊 统计汇总完成: 聚合器小时统计=%d行, 代币对日统计=%d行", hours, pairs)
+	return nil
+}
+
+// loop 立即执行一次汇总，之后定期执行
+func (j *StatsRollupJob) loop() {
+	ticker := time.NewTicker(j.cfg.Stats.RollupInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(); err != nil {
+			j.logger.Warnf("⚠️ 统计汇总失败: %v", err)
+		}
+
+		select {
+		case <-j.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,402 @@
+// Package services 统计业务服务实现
+// 基于StatsRollupJob汇总的aggregator_stats_hourly和token_pair_stats_daily统计表，
+// 提供系统概览、聚合器表现与排名、代币对交易量、用户活跃度和性能指标查询，
+// 统计表数据最多滞后一个汇总间隔(STATS_ROLLUP_INTERVAL)
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"defi-aggregator/business-logic/internal/repository"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// statsService 统计业务服务实现
+type statsService struct {
+	repos  *repository.Repositories // 数据访问层
+	cfg    *config.Config           // 应用配置
+	logger *logrus.Logger           // 日志记录器
+}
+
+// NewStatsService 创建统计服务实例
+func NewStatsService(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) StatsService {
+	return &statsService{
+		repos:  repos,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// 统计时间范围
+const (
+	defaultStatsTimeRange   = "24h" // 默认时间范围
+	defaultPopularPairLimit = 10    // 热门代币对默认条数
+	userTrendDays           = 30    // 用户趋势统计天数
+)
+
+// statsTimeRanges 支持的统计时间范围
+var statsTimeRanges = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// 聚合器综合评分权重
+var (
+	rankingWeightBestQuote = decimal.NewFromFloat(0.5) // 最优报价占比
+	rankingWeightSuccess   = decimal.NewFromFloat(0.3) // 成功率
+	rankingWeightSpeed     = decimal.NewFromFloat(0.2) // 响应速度(最快平均响应/自身平均响应)
+)
+
+// ========================================
+// 系统统计
+// ========================================
+
+// GetSystemStats 获取系统统计
+func (s *statsService) GetSystemStats() (*types.SystemStatsResponse, error) {
+	stats, err := s.repos.Stats.GetSystemStats()
+	if err != nil {
+		s.logger.Errorf("获取系统统计失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取系统统计失败", err)
+	}
+	return stats, nil
+}
+
+// GetDashboardStats 获取仪表板统计
+// 汇总系统概览、24小时聚合器排名、7天热门代币对和交易量、24小时性能指标
+func (s *statsService) GetDashboardStats() (map[string]interface{}, error) {
+	system, err := s.GetSystemStats()
+	if err != nil {
+		return nil, err
+	}
+	rankings, err := s.GetAggregatorRankings("24h")
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := s.GetPopularTokenPairs(5, "7d")
+	if err != nil {
+		return nil, err
+	}
+	volume, err := s.GetTradingVolume("7d")
+	if err != nil {
+		return nil, err
+	}
+	performance, err := s.GetPerformanceMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"system":        system,
+		"aggregators":   rankings,
+		"popular_pairs": pairs,
+		"volume":        volume,
+		"performance":   performance,
+	}, nil
+}
+
+// ========================================
+// 聚合器统计
+// ========================================
+
+// GetAggregatorStats 获取聚合器统计
+// 返回时间范围内各聚合器的汇总数据，指定aggregatorID时附带小时明细
+func (s *statsService) GetAggregatorStats(aggregatorID *uint, timeRange string) ([]*types.AggregatorStats, error) {
+	_, from, to, err := parseStatsTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	from = startOfHour(from)
+
+	if aggregatorID != nil {
+		if _, err := s.repos.Aggregator.GetByID(*aggregatorID); err != nil {
+			return nil, NewServiceError(types.ErrCodeNotFound, "聚合器不存在", err)
+		}
+	}
+
+	stats, err := s.repos.Stats.GetAggregatorSummaries(aggregatorID, from, to)
+	if err != nil {
+		s.logger.Errorf("获取聚合器统计失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取聚合器统计失败", err)
+	}
+
+	if aggregatorID != nil && len(stats) > 0 {
+		hourly, err := s.repos.Stats.GetAggregatorStats(*aggregatorID, from, to)
+		if err != nil {
+			s.logger.Errorf("获取聚合器小时统计失败: %v", err)
+			return nil, NewServiceError(types.ErrCodeDatabase, "获取聚合器统计失败", err)
+		}
+		for _, h := range hourly {
+			stats[0].Hourly = append(stats[0].Hourly, &types.AggregatorHourlyStats{
+				HourTimestamp:      h.HourTimestamp,
+				TotalRequests:      h.TotalRequests,
+				SuccessfulRequests: h.SuccessfulRequests,
+				FailedRequests:     h.FailedRequests,
+				AvgResponseTime:    h.AvgResponseTime,
+				TotalVolumeUSD:     h.TotalVolumeUSD,
+				BestQuotesCount:    h.BestQuotesCount,
+			})
+		}
+	}
+
+	return stats, nil
+}
+
+// GetAggregatorRankings 获取聚合器排名
+// 综合评分 = 100 × (0.5×最优报价占比 + 0.3×成功率 + 0.2×响应速度)
+func (s *statsService) GetAggregatorRankings(timeRange string) ([]*types.AggregatorRanking, error) {
+	stats, err := s.GetAggregatorStats(nil, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	totalBest := 0
+	fastest := 0
+	for _, stat := range stats {
+		totalBest += stat.BestQuotesCount
+		if stat.AvgResponseTime > 0 && (fastest == 0 || stat.AvgResponseTime < fastest) {
+			fastest = stat.AvgResponseTime
+		}
+	}
+
+	rankings := make([]*types.AggregatorRanking, 0, len(stats))
+	for _, stat := range stats {
+		bestShare := decimal.Zero
+		if totalBest > 0 {
+			bestShare = decimal.NewFromInt(int64(stat.BestQuotesCount)).Div(decimal.NewFromInt(int64(totalBest)))
+		}
+		speed := decimal.Zero
+		if stat.AvgResponseTime > 0 {
+			speed = decimal.NewFromInt(int64(fastest)).Div(decimal.NewFromInt(int64(stat.AvgResponseTime)))
+		}
+
+		score := rankingWeightBestQuote.Mul(bestShare).
+			Add(rankingWeightSuccess.Mul(stat.SuccessRate)).
+			Add(rankingWeightSpeed.Mul(speed)).
+			Mul(decimal.NewFromInt(100)).Round(2)
+
+		rankings = append(rankings, &types.AggregatorRanking{
+			AggregatorID:    stat.AggregatorID,
+			AggregatorName:  stat.AggregatorName,
+			Score:           score,
+			BestQuoteShare:  bestShare.Round(4),
+			SuccessRate:     stat.SuccessRate,
+			AvgResponseTime: stat.AvgResponseTime,
+			TotalVolumeUSD:  stat.TotalVolumeUSD,
+		})
+	}
+
+	sort.SliceStable(rankings, func(i, j int) bool {
+		return rankings[i].Score.GreaterThan(rankings[j].Score)
+	})
+	for i, ranking := range rankings {
+		ranking.Rank = i + 1
+	}
+
+	return rankings, nil
+}
+
+// GetAggregatorComparison 聚合器对比分析
+func (s *statsService) GetAggregatorComparison(timeRange string) (*types.AggregatorComparison, error) {
+	label, from, to, err := parseStatsTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.GetAggregatorStats(nil, label)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &types.AggregatorComparison{
+		TimeRange:   label,
+		StartTime:   startOfHour(from),
+		EndTime:     to,
+		Aggregators: stats,
+	}
+
+	var fastest, mostReliable, bestPrice *types.AggregatorStats
+	for _, stat := range stats {
+		comparison.TotalRequests += stat.TotalRequests
+		comparison.TotalBestQuotes += stat.BestQuotesCount
+
+		if stat.AvgResponseTime > 0 && (fastest == nil || stat.AvgResponseTime < fastest.AvgResponseTime) {
+			fastest = stat
+		}
+		if stat.TotalRequests > 0 && (mostReliable == nil || stat.SuccessRate.GreaterThan(mostReliable.SuccessRate)) {
+			mostReliable = stat
+		}
+		if stat.BestQuotesCount > 0 && (bestPrice == nil || stat.BestQuotesCount > bestPrice.BestQuotesCount) {
+			bestPrice = stat
+		}
+	}
+	if fastest != nil {
+		comparison.Fastest = fastest.AggregatorName
+	}
+	if mostReliable != nil {
+		comparison.MostReliable = mostReliable.AggregatorName
+	}
+	if bestPrice != nil {
+		comparison.BestPriceLeader = bestPrice.AggregatorName
+	}
+
+	return comparison, nil
+}
+
+// ========================================
+// 代币对统计
+// ========================================
+
+// GetTokenPairStats 获取代币对日统计
+// 代币ID为0时不按该方向过滤
+func (s *statsService) GetTokenPairStats(fromTokenID, toTokenID uint, timeRange string) ([]*types.TokenPairStats, error) {
+	_, from, to, err := parseStatsTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repos.Stats.GetTokenPairStats(fromTokenID, toTokenID, startOfDay(from), to)
+	if err != nil {
+		s.logger.Errorf("获取代币对统计失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取代币对统计失败", err)
+	}
+	return stats, nil
+}
+
+// GetPopularTokenPairs 获取热门代币对
+func (s *statsService) GetPopularTokenPairs(limit int, timeRange string) ([]*types.PopularTokenPair, error) {
+	_, from, to, err := parseStatsTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = defaultPopularPairLimit
+	}
+	if limit > s.cfg.Business.MaxPageSize {
+		limit = s.cfg.Business.MaxPageSize
+	}
+
+	pairs, err := s.repos.Stats.GetPopularTokenPairs(limit, startOfDay(from), to)
+	if err != nil {
+		s.logger.Errorf("获取热门代币对失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取热门代币对失败", err)
+	}
+	return pairs, nil
+}
+
+// GetTradingVolume 获取每日交易量
+func (s *statsService) GetTradingVolume(timeRange string) ([]*types.VolumeData, error) {
+	_, from, to, err := parseStatsTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := s.repos.Stats.GetDailyVolume(startOfDay(from), to)
+	if err != nil {
+		s.logger.Errorf("获取交易量失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取交易量失败", err)
+	}
+	return volumes, nil
+}
+
+// ========================================
+// 用户统计
+// ========================================
+
+// GetUserAnalytics 获取用户分析
+func (s *statsService) GetUserAnalytics(timeRange string) (*types.UserAnalytics, error) {
+	label, from, to, err := parseStatsTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := s.repos.Stats.GetUserAnalytics(from, to)
+	if err != nil {
+		s.logger.Errorf("获取用户分析失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取用户分析失败", err)
+	}
+	analytics.TimeRange = label
+	return analytics, nil
+}
+
+// GetActiveUserTrends 获取最近30天每日用户活跃趋势
+func (s *statsService) GetActiveUserTrends() ([]*types.UserTrend, error) {
+	now := time.Now()
+	from := startOfDay(now).AddDate(0, 0, -(userTrendDays - 1))
+
+	trends, err := s.repos.Stats.GetUserTrends(from, now)
+	if err != nil {
+		s.logger.Errorf("获取用户活跃趋势失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取用户活跃趋势失败", err)
+	}
+	return trends, nil
+}
+
+// ========================================
+// 性能统计
+// ========================================
+
+// GetPerformanceMetrics 获取最近24小时的性能指标
+func (s *statsService) GetPerformanceMetrics() (*types.PerformanceMetrics, error) {
+	label, from, to, _ := parseStatsTimeRange(defaultStatsTimeRange)
+
+	metrics, err := s.repos.Stats.GetPerformanceMetrics(from, to)
+	if err != nil {
+		s.logger.Errorf("获取性能指标失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取性能指标失败", err)
+	}
+	metrics.TimeRange = label
+	return metrics, nil
+}
+
+// GetLatencyStats 获取最近24小时各聚合器的延迟分布
+func (s *statsService) GetLatencyStats() ([]*types.LatencyStats, error) {
+	_, from, to, _ := parseStatsTimeRange(defaultStatsTimeRange)
+
+	stats, err := s.repos.Stats.GetLatencyStats(from, to)
+	if err != nil {
+		s.logger.Errorf("获取延迟统计失败: %v", err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "获取延迟统计失败", err)
+	}
+	return stats, nil
+}
+
+// ========================================
+// 辅助函数
+// ========================================
+
+// parseStatsTimeRange 解析统计时间范围，为空时使用24h
+// 返回规范化的时间范围标识和[from, to)时间区间
+func parseStatsTimeRange(timeRange string) (string, time.Time, time.Time, error) {
+	if timeRange == "" {
+		timeRange = defaultStatsTimeRange
+	}
+
+	duration, ok := statsTimeRanges[timeRange]
+	if !ok {
+		err := NewServiceError(types.ErrCodeValidation, fmt.Sprintf("无效的时间范围: %s", timeRange), nil)
+		err.Details["allowed"] = []string{"1h", "24h", "7d", "30d", "90d"}
+		return "", time.Time{}, time.Time{}, err
+	}
+
+	to := time.Now()
+	return timeRange, to.Add(-duration), to, nil
+}
+
+// startOfHour 对齐到整点
+func startOfHour(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// startOfDay 对齐到当天零点
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,927 @@
+// Package services 交易业务服务实现
+// 基于已完成的报价请求调用智能路由服务构建可执行交易(calldata)，供钱包签名发送，
+// 并记录交易从创建、钱包提交交易哈希到链上确认的完整生命周期:
+// pending -> confirmed/failed/cancelled
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"defi-aggregator/business-logic/internal/models"
+	"defi-aggregator/business-logic/internal/repository"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+	"defi-aggregator/business-logic/pkg/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// swapService 交易业务服务实现
+// 负责调用智能路由构建交易，管理交易记录的状态流转
+type swapService struct {
+	repos      *repository.Repositories // 数据访问层
+	cfg        *config.Config           // 应用配置
+	logger     *logrus.Logger           // 日志记录器
+	httpClient utils.HTTPClient         // 调用智能路由交易构建接口
+}
+
+// NewSwapService 创建交易服务实例
+func NewSwapService(repos *repository.Repositories, cfg *config.Config, logger *logrus.Logger) SwapService {
+	// 创建HTTP客户端用于调用智能路由服务
+	httpClient := utils.NewHTTPClient(30*time.Second, 2, logger)
+
+	return &swapService{
+		repos:      repos,
+		cfg:        cfg,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+// SmartRouterSwapRequest 智能路由交易构建请求格式
+// 在报价请求基础上指定聚合器，UserAddress必填
+type SmartRouterSwapRequest struct {
+	SmartRouterQuoteRequest
+	Provider string `json:"provider,omitempty"` // 指定聚合器(为空时由智能路由选择最优聚合器)
+}
+
+// SmartRouterSwapResponse 智能路由交易构建响应格式
+type SmartRouterSwapResponse struct {
+	Success   bool                 `json:"success"`
+	Data      *SmartRouterSwapData `json:"data"`
+	Error     *SmartRouterError    `json:"error,omitempty"`
+	Timestamp int64                `json:"timestamp"`
+	RequestID string               `json:"request_id"`
+}
+
+// SmartRouterSwapData 智能路由构建的交易数据
+// Kind为order时(CoW Protocol)To/Data为空，需对Order签名后提交到OrderURL
+type SmartRouterSwapData struct {
+	RequestID       string                 `json:"request_id"`
+	Provider        string                 `json:"provider"`
+	Kind            string                 `json:"kind"`
+	ChainID         uint                   `json:"chain_id"`
+	From            string                 `json:"from"`
+	To              string                 `json:"to"`
+	Data            string                 `json:"data"`
+	Value           string                 `json:"value"`
+	Gas             uint64                 `json:"gas"`
+	GasPrice        string                 `json:"gas_price"`
+	AllowanceTarget string                 `json:"allowance_target"`
+	AmountIn        decimal.Decimal        `json:"amount_in"`
+	AmountOut       decimal.Decimal        `json:"amount_out"`
+	MinAmountOut    decimal.Decimal        `json:"min_amount_out"`
+	Order           map[string]interface{} `json:"order,omitempty"`
+	OrderURL        string                 `json:"order_url,omitempty"`
+	Timestamp       time.Time              `json:"timestamp"`
+}
+
+// 智能路由交易构建结果类型
+const (
+	SwapKindTransaction = "transaction" // 链上交易
+	SwapKindOrder       = "order"       // 链下订单(CoW Protocol)
+)
+
+// 区块数据字段(UpdateSwapStatus的blockData键名)
+// 数值字段支持整数、十进制字符串和0x开头的十六进制字符串
+const (
+	BlockDataBlockNumber       = "block_number"        // 区块号
+	BlockDataBlockTimestamp    = "block_timestamp"     // 区块时间(time.Time或Unix秒)
+	BlockDataConfirmations     = "confirmation_count"  // 确认数
+	BlockDataGasUsed           = "gas_used"            // 实际使用Gas
+	BlockDataEffectiveGasPrice = "effective_gas_price" // 实际Gas价格(wei)
+	BlockDataAmountOutActual   = "amount_out_actual"   // 实际输出数量
+	BlockDataErrorReason       = "error_reason"        // 失败原因
+)
+
+// weiPerEther 1 ETH = 10^18 wei
+var weiPerEther = decimal.New(1, 18)
+
+// submitTokenBytes 交易提交凭证的随机字节数
+const submitTokenBytes = 32
+
+// ========================================
+// 交易核心功能实现
+// ========================================
+
+// CreateSwap 创建交易
+// 基于已完成且仍在有效期内的报价请求，由报价时的最佳聚合器构建calldata，
+// 并记录一笔pending状态的交易，等待钱包签名发送后回传交易哈希
+// 参数:
+//   - ctx: 请求上下文（客户端断开时取消交易构建）
+//   - userID: 当前用户ID（匿名时为空）
+//   - req: 交易请求参数
+//
+// 返回:
+//   - *types.SwapResponse: 可执行交易数据
+//   - error: 创建过程中的错误
+func (s *swapService) CreateSwap(ctx context.Context, userID *uint, req *types.SwapRequest) (*types.SwapResponse, error) {
+	s.logger.Infof("[%s] 开始创建交易: user=%s, slippage=%s", req.RequestID, req.UserAddress, req.Slippage.String())
+
+	// 1. 验证请求参数
+	if err := s.validateSwapRequest(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 加载报价请求，检查有效期和归属
+	quoteRequest, err := s.repos.QuoteRequest.GetByRequestID(req.RequestID)
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeNotFound, "报价请求不存在", err)
+	}
+	if err := s.checkQuoteUsable(quoteRequest); err != nil {
+		return nil, err
+	}
+	if err := s.checkQuoteOwnership(quoteRequest, userID, req.UserAddress); err != nil {
+		return nil, err
+	}
+
+	// 3. 同一报价只能对应一笔进行中或已确认的交易
+	if err := s.checkNoActiveTransaction(quoteRequest.ID); err != nil {
+		return nil, err
+	}
+
+	// 4. 优先使用报价时的最佳聚合器，保证成交价格与用户看到的报价一致
+	provider := ""
+	if quoteRequest.BestAggregatorID != nil {
+		if aggregator, err := s.repos.Aggregator.GetByID(*quoteRequest.BestAggregatorID); err == nil {
+			provider = aggregator.Name
+		}
+	}
+
+	// 5. 构建交易并记录
+	// 交易只归属到已认证的调用方；钱包地址是公开信息，匿名交易保持匿名，由提交凭证保护
+	transaction, swapData, submitToken, err := s.buildAndRecordSwap(ctx, quoteRequest, userID, req.UserAddress, req.Slippage, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("[%s] ✅ 交易创建成功: transactionID=%d, provider=%s, kind=%s",
+		req.RequestID, transaction.ID, swapData.Provider, swapData.Kind)
+
+	return s.convertToSwapResponse(transaction, swapData, submitToken), nil
+}
+
+// SubmitTransaction 记录钱包提交的交易哈希
+// 钱包签名并广播交易后调用，交易哈希写入后不可更改；重复提交相同哈希视为成功。
+// 钱包地址是公开信息，匿名交易还需校验创建交易时返回的提交凭证；
+// 交易哈希的链上发送方由交易跟踪器在拿到回执后校验
+// 参数:
+//   - userID: 当前用户ID（匿名时为空）
+//   - req: 交易哈希提交请求
+//
+// 返回:
+//   - *types.TransactionInfo: 更新后的交易信息
+//   - error: 提交过程中的错误
+func (s *swapService) SubmitTransaction(userID *uint, req *types.SubmitSwapRequest) (*types.TransactionInfo, error) {
+	if req.TransactionID == 0 {
+		return nil, NewServiceError(types.ErrCodeValidation, "交易ID不能为0", nil)
+	}
+	if !utils.IsValidTransactionHash(req.TxHash) {
+		return nil, NewServiceError(types.ErrCodeValidation, "无效的交易哈希格式", nil)
+	}
+	if !utils.IsValidEthereumAddress(req.UserAddress) {
+		return nil, NewServiceError(types.ErrCodeValidation, "无效的用户地址格式", nil)
+	}
+	txHash := strings.ToLower(req.TxHash)
+
+	transaction, err := s.repos.Transaction.GetByID(req.TransactionID)
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeNotFound, "交易不存在", err)
+	}
+	if err := s.checkTransactionOwnership(transaction, userID, req.UserAddress); err != nil {
+		return nil, err
+	}
+	if transaction.UserID == nil && !verifySubmitToken(transaction.SubmitTokenHash, req.SubmitToken) {
+		return nil, NewServiceError(types.ErrCodeForbidden, "提交凭证无效", nil)
+	}
+
+	// 重复提交相同哈希直接返回
+	if transaction.TxHash != nil {
+		if *transaction.TxHash == txHash {
+			return s.convertTransactionToInfo(transaction), nil
+		}
+		return nil, NewServiceError(types.ErrCodeConflict, "交易已提交其他交易哈希", nil)
+	}
+	if transaction.Status != string(types.TransactionStatusPending) {
+		return nil, NewServiceError(types.ErrCodeConflict,
+			fmt.Sprintf("交易状态为%s，无法提交交易哈希", transaction.Status), nil)
+	}
+
+	// 交易哈希全局唯一
+	if existing, err := s.repos.Transaction.GetByTxHash(txHash); err == nil && existing.ID != transaction.ID {
+		return nil, NewServiceError(types.ErrCodeConflict, "交易哈希已被其他交易使用", nil)
+	}
+
+	transaction.TxHash = &txHash
+	if err := s.repos.Transaction.Update(transaction); err != nil {
+		s.logger.Errorf("记录交易哈希失败: transactionID=%d, 错误=%v", transaction.ID, err)
+		return nil, NewServiceError(types.ErrCodeDatabase, "记录交易哈希失败", err)
+	}
+
+	s.logger.Infof("📝 交易哈希已记录: transactionID=%d, txHash=%s", transaction.ID, txHash)
+	return s.convertTransactionToInfo(transaction), nil
+}
+
+// GetSwapStatus 获取交易状态
+// 根据链上交易哈希查询交易记录
+func (s *swapService) GetSwapStatus(txHash string) (*types.TransactionInfo, error) {
+	if !utils.IsValidTransactionHash(txHash) {
+		return nil, NewServiceError(types.ErrCodeValidation, "无效的交易哈希格式", nil)
+	}
+
+	transaction, err := s.repos.Transaction.GetByTxHash(strings.ToLower(txHash))
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeNotFound, "交易不存在", err)
+	}
+
+	return s.convertTransactionToInfo(transaction), nil
+}
+
+// UpdateSwapStatus 更新交易状态
+// 只允许pending状态流转到confirmed/failed/cancelled；状态不变时仅更新区块数据(如确认数)
+// 参数:
+//   - txHash: 链上交易哈希
+//   - status: 目标状态
+//   - blockData: 区块数据，键名见BlockData*常量
+//
+// 返回:
+//   - error: 状态不允许变更或更新失败
+func (s *swapService) UpdateSwapStatus(txHash string, status string, blockData map[string]interface{}) error {
+	newStatus := types.TransactionStatus(status)
+	if !isValidTransactionStatus(newStatus) {
+		return NewServiceError(types.ErrCodeValidation, fmt.Sprintf("无效的交易状态: %s", status), nil)
+	}
+
+	transaction, err := s.repos.Transaction.GetByTxHash(strings.ToLower(txHash))
+	if err != nil {
+		return NewServiceError(types.ErrCodeNotFound, "交易不存在", err)
+	}
+
+	currentStatus := types.TransactionStatus(transaction.Status)
+	if currentStatus != newStatus && !canTransitionTransaction(currentStatus, newStatus) {
+		return NewServiceError(types.ErrCodeConflict,
+			fmt.Sprintf("交易状态不允许从%s变更为%s", currentStatus, newStatus), nil)
+	}
+
+	s.applyBlockData(transaction, blockData)
+	transaction.Status = string(newStatus)
+	if newStatus == types.TransactionStatusConfirmed && transaction.ConfirmedAt == nil {
+		now := time.Now()
+		transaction.ConfirmedAt = &now
+	}
+
+	if err := s.repos.Transaction.Update(transaction); err != nil {
+		s.logger.Errorf("更新交易状态失败: txHash=%s, 错误=%v", txHash, err)
+		return NewServiceError(types.ErrCodeDatabase, "更新交易状态失败", err)
+	}
+
+	if currentStatus != newStatus {
+		s.logger.Infof("🔄 交易状态更新: txHash=%s, %s -> %s", txHash, currentStatus, newStatus)
+	}
+	return nil
+}
+
+// ========================================
+// 交易历史
+// ========================================
+
+// GetTransactionHistory 获取交易历史
+// 指定userID时只返回该用户的交易，按创建时间倒序分页
+// 参数:
+//   - userID: 用户ID（为空时按请求中的过滤条件查询所有交易）
+//   - req: 交易列表请求参数
+//
+// 返回:
+//   - []*types.TransactionInfo: 交易列表
+//   - *types.Meta: 分页元数据
+//   - error: 查询错误
+func (s *swapService) GetTransactionHistory(userID *uint, req *types.TransactionListRequest) ([]*types.TransactionInfo, *types.Meta, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > s.cfg.Business.MaxPageSize {
+		req.PageSize = s.cfg.Business.DefaultPageSize
+	}
+	if req.Status != nil && !isValidTransactionStatus(*req.Status) {
+		return nil, nil, NewServiceError(types.ErrCodeValidation, fmt.Sprintf("无效的交易状态: %s", *req.Status), nil)
+	}
+
+	// 普通用户只能查询自己的交易
+	if userID != nil {
+		req.UserID = userID
+	}
+
+	s.logger.Debugf("获取交易历史: userID=%v, page=%d", req.UserID, req.Page)
+
+	transactions, total, err := s.repos.Transaction.List(req)
+	if err != nil {
+		s.logger.Errorf("获取交易历史失败: %v", err)
+		return nil, nil, NewServiceError(types.ErrCodeDatabase, "获取交易历史失败", err)
+	}
+
+	infos := make([]*types.TransactionInfo, 0, len(transactions))
+	for _, transaction := range transactions {
+		infos = append(infos, s.convertTransactionToInfo(transaction))
+	}
+
+	// 计算分页信息
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	meta := &types.Meta{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	s.logger.Debugf("获取交易历史成功: total=%d", total)
+	return infos, meta, nil
+}
+
+// GetTransactionDetails 获取交易详情
+// 只允许交易所属用户查看
+func (s *swapService) GetTransactionDetails(id uint, userID uint) (*types.TransactionInfo, error) {
+	transaction, err := s.repos.Transaction.GetByID(id)
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeNotFound, "交易不存在", err)
+	}
+	if err := s.checkTransactionOwnership(transaction, &userID, ""); err != nil {
+		return nil, err
+	}
+
+	return s.convertTransactionToInfo(transaction), nil
+}
+
+// ========================================
+// 交易管理
+// ========================================
+
+// CancelTransaction 取消交易
+// 只有尚未提交交易哈希的pending交易可以取消，已上链的交易只能等待链上结果
+func (s *swapService) CancelTransaction(id uint, userID uint) error {
+	transaction, err := s.repos.Transaction.GetByID(id)
+	if err != nil {
+		return NewServiceError(types.ErrCodeNotFound, "交易不存在", err)
+	}
+	if err := s.checkTransactionOwnership(transaction, &userID, ""); err != nil {
+		return err
+	}
+
+	if transaction.Status != string(types.TransactionStatusPending) {
+		return NewServiceError(types.ErrCodeConflict, "只有待处理的交易可以取消", nil)
+	}
+	if transaction.TxHash != nil {
+		return NewServiceError(types.ErrCodeConflict, "交易已提交上链，无法取消", nil)
+	}
+
+	transaction.Status = string(types.TransactionStatusCancelled)
+	if err := s.repos.Transaction.Update(transaction); err != nil {
+		s.logger.Errorf("取消交易失败: transactionID=%d, 错误=%v", id, err)
+		return NewServiceError(types.ErrCodeDatabase, "取消交易失败", err)
+	}
+
+	s.logger.Infof("🚫 交易已取消: transactionID=%d, userID=%d", id, userID)
+	return nil
+}
+
+// RetryTransaction 重试交易
+// 对失败或已取消的交易按原参数重新构建一笔新交易，关联同一报价请求；
+// 原报价可能已过期，因此不限定聚合器，由智能路由按当前行情选择最优聚合器
+func (s *swapService) RetryTransaction(ctx context.Context, id uint, userID uint) (*types.SwapResponse, error) {
+	transaction, err := s.repos.Transaction.GetByID(id)
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeNotFound, "交易不存在", err)
+	}
+	if err := s.checkTransactionOwnership(transaction, &userID, ""); err != nil {
+		return nil, err
+	}
+
+	status := types.TransactionStatus(transaction.Status)
+	if status != types.TransactionStatusFailed && status != types.TransactionStatusCancelled {
+		return nil, NewServiceError(types.ErrCodeConflict, "只有失败或已取消的交易可以重试", nil)
+	}
+	if transaction.QuoteRequestID == nil {
+		return nil, NewServiceError(types.ErrCodeValidation, "交易缺少关联的报价请求，无法重试", nil)
+	}
+
+	quoteRequest, err := s.repos.QuoteRequest.GetByID(*transaction.QuoteRequestID)
+	if err != nil {
+		return nil, NewServiceError(types.ErrCodeNotFound, "报价请求不存在", err)
+	}
+	if err := s.checkNoActiveTransaction(quoteRequest.ID); err != nil {
+		return nil, err
+	}
+
+	retried, swapData, submitToken, err := s.buildAndRecordSwap(ctx, quoteRequest, transaction.UserID, transaction.UserAddress, transaction.SlippageSet, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("🔁 交易重试成功: transactionID=%d -> %d, provider=%s", id, retried.ID, swapData.Provider)
+	return s.convertToSwapResponse(retried, swapData, submitToken), nil
+}
+
+// ========================================
+// 交易分析（待实现）
+// ========================================
+
+// CalculateTransactionCost 计算交易成本
+func (s *swapService) CalculateTransactionCost(req *types.SwapRequest) (*types.TransactionCost, error) {
+	// TODO: 实现交易成本计算
+	return &types.TransactionCost{}, nil
+}
+
+// GetSlippageAnalysis 滑点分析
+func (s *swapService) GetSlippageAnalysis(txHash string) (*types.SlippageAnalysis, error) {
+	// TODO: 实现滑点分析
+	return &types.SlippageAnalysis{}, nil
+}
+
+// ========================================
+// 智能路由服务调用
+// ========================================
+
+// callSmartRouterSwap 调用智能路由交易构建接口
+// 发送HTTP请求到智能路由服务，由指定聚合器(或最优聚合器)返回可执行交易
+func (s *swapService) callSmartRouterSwap(ctx context.Context, req *SmartRouterSwapRequest) (*SmartRouterSwapData, error) {
+	smartRouterURL := fmt.Sprintf("%s/api/v1/swap", s.cfg.ExternalServices.SmartRouterURL)
+
+	s.logger.Debugf("[%s] 调用智能路由交易构建: %s, provider=%s", req.RequestID, smartRouterURL, req.Provider)
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.ExternalServices.Timeout)
+	defer cancel()
+
+	var response SmartRouterSwapResponse
+	if err := s.httpClient.PostJSON(ctx, smartRouterURL, req, &response); err != nil {
+		s.logger.Errorf("[%s] 智能路由交易构建调用失败: URL=%s, 错误=%v", req.RequestID, smartRouterURL, err)
+		return nil, NewServiceError(types.ErrCodeExternalAPI, fmt.Sprintf("智能路由交易构建失败: %v", err), err)
+	}
+
+	if !response.Success {
+		errorMsg := "智能路由交易构建返回错误"
+		if response.Error != nil {
+			errorMsg = response.Error.Message
+		}
+		return nil, NewServiceError(types.ErrCodeExternalAPI, errorMsg, nil)
+	}
+
+	if response.Data == nil {
+		return nil, NewServiceError(types.ErrCodeExternalAPI, "智能路由交易构建返回空数据", nil)
+	}
+
+	s.logger.Infof("[%s] 智能路由交易构建成功: provider=%s, kind=%s",
+		req.RequestID, response.Data.Provider, response.Data.Kind)
+
+	return response.Data, nil
+}
+
+// ========================================
+// 内部方法
+// ========================================
+
+// buildAndRecordSwap 调用智能路由构建交易并记录pending交易
+// provider为空时由智能路由选择最优聚合器；返回的提交凭证明文不落库，只保存其哈希
+func (s *swapService) buildAndRecordSwap(ctx context.Context, quoteRequest *models.QuoteRequest, userID *uint, userAddress string, slippage decimal.Decimal, provider string) (*models.Transaction, *SmartRouterSwapData, string, error) {
+	// 加载代币和链信息，智能路由使用真实链ID
+	fromToken, err := s.repos.Token.GetByID(quoteRequest.FromTokenID)
+	if err != nil {
+		return nil, nil, "", NewServiceError(types.ErrCodeNotFound, "源代币不存在", err)
+	}
+	toToken, err := s.repos.Token.GetByID(quoteRequest.ToTokenID)
+	if err != nil {
+		return nil, nil, "", NewServiceError(types.ErrCodeNotFound, "目标代币不存在", err)
+	}
+	chain, err := s.repos.Chain.GetByID(fromToken.ChainID)
+	if err != nil {
+		return nil, nil, "", NewServiceError(types.ErrCodeNotFound, "获取代币链信息失败", err)
+	}
+
+	userAddress = strings.ToLower(userAddress)
+	swapData, err := s.callSmartRouterSwap(ctx, &SmartRouterSwapRequest{
+		SmartRouterQuoteRequest: SmartRouterQuoteRequest{
+			RequestID:   quoteRequest.RequestID,
+			FromToken:   fromToken.ContractAddress,
+			ToToken:     toToken.ContractAddress,
+			AmountIn:    quoteRequest.AmountIn,
+			ChainID:     chain.ChainID,
+			Slippage:    slippage,
+			UserAddress: userAddress,
+		},
+		Provider: provider,
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	aggregator, err := s.repos.Aggregator.GetByName(swapData.Provider)
+	if err != nil {
+		return nil, nil, "", NewServiceError(types.ErrCodeInternal, fmt.Sprintf("未知的聚合器: %s", swapData.Provider), err)
+	}
+
+	// 完整的构建结果写入route_data，便于审计和排查
+	routeData, err := json.Marshal(swapData)
+	if err != nil {
+		return nil, nil, "", NewServiceError(types.ErrCodeInternal, "序列化交易数据失败", err)
+	}
+
+	quoteRequestID := quoteRequest.ID
+	transaction := &models.Transaction{
+		UserID:            userID,
+		QuoteRequestID:    &quoteRequestID,
+		ChainID:           fromToken.ChainID,
+		FromTokenID:       fromToken.ID,
+		ToTokenID:         toToken.ID,
+		AggregatorID:      aggregator.ID,
+		AmountIn:          quoteRequest.AmountIn,
+		AmountOutExpected: swapData.AmountOut,
+		SlippageSet:       slippage,
+		Status:            string(types.TransactionStatusPending),
+		UserAddress:       userAddress,
+		ToAddress:         swapData.To,
+		RouteData:         string(routeData),
+	}
+	if swapData.Gas > 0 {
+		gasLimit := swapData.Gas
+		transaction.GasLimit = &gasLimit
+	}
+	if gasPrice, err := decimal.NewFromString(swapData.GasPrice); err == nil && gasPrice.IsPositive() {
+		transaction.GasPrice = &gasPrice
+	}
+	if quoteRequest.BestPriceImpact != nil {
+		transaction.PriceImpact = *quoteRequest.BestPriceImpact
+	}
+
+	submitToken, err := generateSubmitToken()
+	if err != nil {
+		return nil, nil, "", NewServiceError(types.ErrCodeInternal, "生成提交凭证失败", err)
+	}
+	transaction.SubmitTokenHash = hashSubmitToken(submitToken)
+
+	if err := s.repos.Transaction.Create(transaction); err != nil {
+		s.logger.Errorf("[%s] 记录交易失败: %v", quoteRequest.RequestID, err)
+		return nil, nil, "", NewServiceError(types.ErrCodeDatabase, "记录交易失败", err)
+	}
+
+	return transaction, swapData, submitToken, nil
+}
+
+// generateSubmitToken 生成交易提交凭证明文
+func generateSubmitToken() (string, error) {
+	secret := make([]byte, submitTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// hashSubmitToken 计算提交凭证的SHA-256哈希
+func hashSubmitToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifySubmitToken 校验提交凭证，交易没有凭证记录(如迁移前创建的交易)时一律拒绝
+func verifySubmitToken(tokenHash, token string) bool {
+	if tokenHash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(tokenHash), []byte(hashSubmitToken(token))) == 1
+}
+
+// validateSwapRequest 验证交易请求
+func (s *swapService) validateSwapRequest(req *types.SwapRequest) error {
+	if req.RequestID == "" {
+		return NewServiceError(types.ErrCodeValidation, "报价请求ID不能为空", nil)
+	}
+
+	if !utils.IsValidEthereumAddress(req.UserAddress) {
+		return NewServiceError(types.ErrCodeValidation, "无效的用户地址格式", nil)
+	}
+
+	if req.Slippage.IsNegative() || req.Slippage.GreaterThan(decimal.NewFromFloat(0.5)) {
+		return NewServiceError(types.ErrCodeValidation, "滑点必须在0-50%之间", nil)
+	}
+
+	if req.Deadline != nil && req.Deadline.Before(time.Now()) {
+		return NewServiceError(types.ErrCodeValidation, "交易截止时间已过", nil)
+	}
+
+	return nil
+}
+
+// checkQuoteUsable 检查报价请求是否可用于创建交易
+// 报价必须已成功完成，且完成时间在QUOTE_VALIDITY有效期内
+func (s *swapService) checkQuoteUsable(quoteRequest *models.QuoteRequest) error {
+	if quoteRequest.Status != "completed" {
+		return NewServiceError(types.ErrCodeValidation, "报价请求未成功完成，无法创建交易", nil)
+	}
+
+	quotedAt := quoteRequest.CreatedAt
+	if quoteRequest.CompletedAt != nil {
+		quotedAt = *quoteRequest.CompletedAt
+	}
+	if time.Since(quotedAt) > s.cfg.Business.QuoteValidity {
+		return ErrQuoteExpired
+	}
+
+	return nil
+}
+
+// checkQuoteOwnership 检查报价请求归属
+// 登录用户的报价只能由本人使用；报价记录了用户地址时交易地址必须一致
+func (s *swapService) checkQuoteOwnership(quoteRequest *models.QuoteRequest, userID *uint, userAddress string) error {
+	if quoteRequest.UserID != nil && (userID == nil || *userID != *quoteRequest.UserID) {
+		return NewServiceError(types.ErrCodeForbidden, "无权使用该报价请求", nil)
+	}
+
+	if quoteRequest.UserAddress != "" && !strings.EqualFold(quoteRequest.UserAddress, userAddress) {
+		return NewServiceError(types.ErrCodeForbidden, "交易地址与报价请求的用户地址不一致", nil)
+	}
+
+	return nil
+}
+
+// checkNoActiveTransaction 检查报价请求是否已有进行中或已确认的交易
+func (s *swapService) checkNoActiveTransaction(quoteRequestID uint) error {
+	transactions, err := s.repos.Transaction.GetByQuoteRequestID(quoteRequestID)
+	if err != nil {
+		return NewServiceError(types.ErrCodeDatabase, "查询报价关联交易失败", err)
+	}
+
+	for _, transaction := range transactions {
+		status := types.TransactionStatus(transaction.Status)
+		if status == types.TransactionStatusPending || status == types.TransactionStatusConfirmed {
+			serviceErr := NewServiceError(types.ErrCodeConflict, "该报价已创建交易", nil)
+			serviceErr.Details["transaction_id"] = transaction.ID
+			return serviceErr
+		}
+	}
+
+	return nil
+}
+
+// checkTransactionOwnership 检查交易归属
+// 关联用户的交易只能由该用户操作；userAddress非空时还需与交易地址一致
+func (s *swapService) checkTransactionOwnership(transaction *models.Transaction, userID *uint, userAddress string) error {
+	if transaction.UserID != nil && (userID == nil || *userID != *transaction.UserID) {
+		return NewServiceError(types.ErrCodeForbidden, "无权操作该交易", nil)
+	}
+
+	// 匿名交易只能通过用户地址校验归属
+	if transaction.UserID == nil && userAddress == "" {
+		return NewServiceError(types.ErrCodeForbidden, "无权操作该交易", nil)
+	}
+
+	if userAddress != "" && !strings.EqualFold(transaction.UserAddress, userAddress) {
+		return NewServiceError(types.ErrCodeForbidden, "用户地址与交易地址不一致", nil)
+	}
+
+	return nil
+}
+
+// applyBlockData 将区块数据写入交易记录
+// 同时根据实际Gas和实际输出计算Gas费用和实际滑点
+func (s *swapService) applyBlockData(transaction *models.Transaction, blockData map[string]interface{}) {
+	if len(blockData) == 0 {
+		return
+	}
+
+	if blockNumber, ok := uint64Value(blockData[BlockDataBlockNumber]); ok {
+		transaction.BlockNumber = &blockNumber
+	}
+	if blockTime, ok := timeValue(blockData[BlockDataBlockTimestamp]); ok {
+		transaction.BlockTimestamp = &blockTime
+	}
+	if confirmations, ok := uint64Value(blockData[BlockDataConfirmations]); ok {
+		transaction.ConfirmationCount = int(confirmations)
+	}
+	if gasUsed, ok := uint64Value(blockData[BlockDataGasUsed]); ok {
+		transaction.GasUsed = &gasUsed
+	}
+	if gasPrice, ok := decimalValue(blockData[BlockDataEffectiveGasPrice]); ok {
+		transaction.GasPrice = &gasPrice
+	}
+	if transaction.GasUsed != nil && transaction.GasPrice != nil {
+		gasUsed := decimal.NewFromBigInt(new(big.Int).SetUint64(*transaction.GasUsed), 0)
+		gasFee := gasUsed.Mul(*transaction.GasPrice).Div(weiPerEther).Round(9)
+		transaction.GasFeeETH = &gasFee
+	}
+
+	if amountOut, ok := decimalValue(blockData[BlockDataAmountOutActual]); ok {
+		transaction.AmountOutActual = &amountOut
+		// 实际滑点 = (预期输出 - 实际输出) / 预期输出，负值表示成交优于预期
+		if transaction.AmountOutExpected.IsPositive() {
+			slippage := transaction.AmountOutExpected.Sub(amountOut).Div(transaction.AmountOutExpected).Round(6)
+			transaction.SlippageActual = &slippage
+		}
+	}
+
+	if reason, ok := blockData[BlockDataErrorReason].(string); ok && reason != "" {
+		transaction.ErrorReason = reason
+	}
+}
+
+// convertToSwapResponse 转换交易记录和构建结果为API响应
+func (s *swapService) convertToSwapResponse(transaction *models.Transaction, swapData *SmartRouterSwapData, submitToken string) *types.SwapResponse {
+	response := &types.SwapResponse{
+		TransactionID:   transaction.ID,
+		Provider:        swapData.Provider,
+		Kind:            swapData.Kind,
+		To:              swapData.To,
+		Data:            swapData.Data,
+		Value:           swapData.Value,
+		GasPrice:        swapData.GasPrice,
+		AllowanceTarget: swapData.AllowanceTarget,
+		AmountIn:        swapData.AmountIn,
+		AmountOut:       swapData.AmountOut,
+		MinAmountOut:    swapData.MinAmountOut,
+		Order:           swapData.Order,
+		OrderURL:        swapData.OrderURL,
+		Status:          types.TransactionStatus(transaction.Status),
+		SubmitToken:     submitToken,
+	}
+
+	if swapData.Gas > 0 {
+		response.GasLimit = strconv.FormatUint(swapData.Gas, 10)
+	}
+
+	return response
+}
+
+// convertTransactionToInfo 将Transaction模型转换为TransactionInfo
+func (s *swapService) convertTransactionToInfo(transaction *models.Transaction) *types.TransactionInfo {
+	info := &types.TransactionInfo{
+		ID:                transaction.ID,
+		TxHash:            transaction.TxHash,
+		ChainID:           transaction.ChainID,
+		Aggregator:        transaction.Aggregator.Name,
+		AmountIn:          transaction.AmountIn,
+		AmountOutExpected: transaction.AmountOutExpected,
+		AmountOutActual:   transaction.AmountOutActual,
+		SlippageSet:       transaction.SlippageSet,
+		SlippageActual:    transaction.SlippageActual,
+		GasLimit:          transaction.GasLimit,
+		GasUsed:           transaction.GasUsed,
+		GasPrice:          transaction.GasPrice,
+		GasFeeETH:         transaction.GasFeeETH,
+		GasFeeUSD:         transaction.GasFeeUSD,
+		PriceImpact:       transaction.PriceImpact,
+		Status:            types.TransactionStatus(transaction.Status),
+		UserAddress:       transaction.UserAddress,
+		BlockNumber:       transaction.BlockNumber,
+		BlockTimestamp:    transaction.BlockTimestamp,
+		ErrorReason:       transaction.ErrorReason,
+		CreatedAt:         transaction.CreatedAt,
+		ConfirmedAt:       transaction.ConfirmedAt,
+	}
+
+	if transaction.UserID != nil {
+		info.UserID = *transaction.UserID
+	}
+	if transaction.QuoteRequest != nil {
+		info.QuoteRequestID = transaction.QuoteRequest.RequestID
+	}
+
+	// 设置代币信息（需要预加载）
+	if transaction.FromToken.ID > 0 {
+		info.FromToken = *s.convertTokenToInfo(&transaction.FromToken)
+	}
+	if transaction.ToToken.ID > 0 {
+		info.ToToken = *s.convertTokenToInfo(&transaction.ToToken)
+	}
+
+	return info
+}
+
+// convertTokenToInfo 将Token模型转换为TokenInfo
+func (s *swapService) convertTokenToInfo(token *models.Token) *types.TokenInfo {
+	return &types.TokenInfo{
+		ID:              token.ID,
+		ChainID:         token.ChainID,
+		ContractAddress: token.ContractAddress,
+		Symbol:          token.Symbol,
+		Name:            token.Name,
+		Decimals:        token.Decimals,
+		LogoURL:         token.LogoURL,
+		IsNative:        token.IsNative,
+		IsStable:        token.IsStable,
+		IsVerified:      token.IsVerified,
+		IsActive:        token.IsActive,
+		PriceUSD:        token.PriceUSD,
+	}
+}
+
+// isValidTransactionStatus 检查是否为已知的交易状态
+func isValidTransactionStatus(status types.TransactionStatus) bool {
+	switch status {
+	case types.TransactionStatusPending, types.TransactionStatusConfirmed,
+		types.TransactionStatusFailed, types.TransactionStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// canTransitionTransaction 检查交易状态流转是否合法
+// confirmed/failed/cancelled均为终态，只有pending可以流转
+func canTransitionTransaction(from, to types.TransactionStatus) bool {
+	if from != types.TransactionStatusPending {
+		return false
+	}
+	return to == types.TransactionStatusConfirmed ||
+		to == types.TransactionStatusFailed ||
+		to == types.TransactionStatusCancelled
+}
+
+// uint64Value 将区块数据字段转换为uint64
+func uint64Value(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case uint:
+		return uint64(v), true
+	case int:
+		return uint64(v), v >= 0
+	case int64:
+		return uint64(v), v >= 0
+	case float64:
+		return uint64(v), v >= 0
+	case json.Number:
+		n, err := strconv.ParseUint(v.String(), 10, 64)
+		return n, err == nil
+	case string:
+		// base=0支持0x开头的十六进制(JSON-RPC返回格式)
+		n, err := strconv.ParseUint(v, 0, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// decimalValue 将区块数据字段转换为decimal
+func decimalValue(value interface{}) (decimal.Decimal, bool) {
+	switch v := value.(type) {
+	case decimal.Decimal:
+		return v, true
+	case *decimal.Decimal:
+		if v == nil {
+			return decimal.Zero, false
+		}
+		return *v, true
+	case *big.Int:
+		if v == nil {
+			return decimal.Zero, false
+		}
+		return decimal.NewFromBigInt(v, 0), true
+	case float64:
+		return decimal.NewFromFloat(v), true
+	case int64:
+		return decimal.NewFromInt(v), true
+	case uint64:
+		return decimal.NewFromBigInt(new(big.Int).SetUint64(v), 0), true
+	case json.Number:
+		d, err := decimal.NewFromString(v.String())
+		return d, err == nil
+	case string:
+		if strings.HasPrefix(v, "0x") {
+			n, ok := new(big.Int).SetString(v[2:], 16)
+			if !ok {
+				return decimal.Zero, false
+			}
+			return decimal.NewFromBigInt(n, 0), true
+		}
+		d, err := decimal.NewFromString(v)
+		return d, err == nil
+	}
+	return decimal.Zero, false
+}
+
+// timeValue 将区块数据字段转换为时间，数值按Unix秒处理
+func timeValue(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	}
+
+	if seconds, ok := uint64Value(value); ok {
+		return time.Unix(int64(seconds), 0), true
+	}
+	return time.Time{}, false
+}
@@ -6,69 +6,6 @@ import (
 	"defi-aggregator/business-logic/internal/types"
 )
 
-// ========================================
-// SwapService临时实现
-// ========================================
-
-func (s *swapService) CreateSwap(req *types.SwapRequest) (*types.SwapResponse, error) {
-	return &types.SwapResponse{}, nil
-}
-func (s *swapService) GetSwapStatus(txHash string) (*types.TransactionInfo, error) {
-	return &types.TransactionInfo{}, nil
-}
-func (s *swapService) UpdateSwapStatus(txHash string, status string, blockData map[string]interface{}) error {
-	return nil
-}
-func (s *swapService) GetTransactionHistory(userID *uint, req *types.TransactionListRequest) ([]*types.TransactionInfo, *types.Meta, error) {
-	return nil, &types.Meta{}, nil
-}
-func (s *swapService) GetTransactionDetails(id uint) (*types.TransactionInfo, error) {
-	return &types.TransactionInfo{}, nil
-}
-func (s *swapService) CalculateTransactionCost(req *types.SwapRequest) (*types.TransactionCost, error) {
-	return &types.TransactionCost{}, nil
-}
-func (s *swapService) GetSlippageAnalysis(txHash string) (*types.SlippageAnalysis, error) {
-	return &types.SlippageAnalysis{}, nil
-}
-func (s *swapService) CancelTransaction(id uint, userID uint) error { return nil }
-func (s *swapService) RetryTransaction(id uint, userID uint) (*types.SwapResponse, error) {
-	return &types.SwapResponse{}, nil
-}
-
-// ========================================
-// StatsService临时实现
-// ========================================
-
-func (s *statsService) GetSystemStats() (*types.SystemStatsResponse, error) {
-	return &types.SystemStatsResponse{}, nil
-}
-func (s *statsService) GetDashboardStats() (map[string]interface{}, error) { return nil, nil }
-func (s *statsService) GetAggregatorStats(aggregatorID *uint, timeRange string) ([]*types.AggregatorStats, error) {
-	return nil, nil
-}
-func (s *statsService) GetAggregatorRankings() ([]*types.AggregatorRanking, error) { return nil, nil }
-func (s *statsService) GetAggregatorComparison() (*types.AggregatorComparison, error) {
-	return &types.AggregatorComparison{}, nil
-}
-func (s *statsService) GetTokenPairStats(fromTokenID, toTokenID uint, timeRange string) ([]*types.TokenPairStats, error) {
-	return nil, nil
-}
-func (s *statsService) GetPopularTokenPairs(limit int) ([]*types.PopularTokenPair, error) {
-	return nil, nil
-}
-func (s *statsService) GetTradingVolume(timeRange string) ([]*types.VolumeData, error) {
-	return nil, nil
-}
-func (s *statsService) GetUserAnalytics(timeRange string) (*types.UserAnalytics, error) {
-	return &types.UserAnalytics{}, nil
-}
-func (s *statsService) GetActiveUserTrends() ([]*types.UserTrend, error) { return nil, nil }
-func (s *statsService) GetPerformanceMetrics() (*types.PerformanceMetrics, error) {
-	return &types.PerformanceMetrics{}, nil
-}
-func (s *statsService) GetLatencyStats() ([]*types.LatencyStats, error) { return nil, nil }
-
 // ========================================
 // HealthService临时实现
 // ========================================
@@ -0,0 +1,251 @@
+// Package services 链上交易确认跟踪
+// 后台定期轮询已提交交易哈希的待处理交易，通过所在链的JSON-RPC节点查询回执和最新区块，
+// 达到确认数后标记为confirmed并解码Transfer事件记录实际输出和实际滑点，
+// 回执发送方与用户地址不一致、回执显示revert或长时间未上链的交易标记为failed
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"defi-aggregator/business-logic/internal/models"
+	"defi-aggregator/business-logic/internal/repository"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+	"defi-aggregator/business-logic/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RPCClientFactory 根据RPC节点地址创建JSON-RPC客户端
+type RPCClientFactory func(rpcURL string) utils.EthRPCClient
+
+// TransactionTracker 链上交易确认跟踪器
+type TransactionTracker struct {
+	repos       *repository.Repositories // 数据访问层
+	swapService SwapService              // 通过交易服务更新状态，复用状态流转校验
+	cfg         *config.Config           // 应用配置
+	logger      *logrus.Logger           // 日志记录器
+	newClient   RPCClientFactory         // RPC客户端工厂
+
+	clientsMutex sync.Mutex                    // 保护clients
+	clients      map[string]utils.EthRPCClient // 按RPC地址缓存的客户端
+	pollMutex    sync.Mutex                    // 保证同一时间只有一轮轮询
+	stopChan     chan struct{}                 // 停止信号
+	stopOnce     sync.Once                     // 保证只停止一次
+}
+
+// NewTransactionTracker 创建链上交易确认跟踪器
+func NewTransactionTracker(repos *repository.Repositories, swapService SwapService, cfg *config.Config, logger *logrus.Logger) *TransactionTracker {
+	httpClient := utils.NewHTTPClient(cfg.Tracker.RPCTimeout, 1, logger)
+
+	return &TransactionTracker{
+		repos:       repos,
+		swapService: swapService,
+		cfg:         cfg,
+		logger:      logger,
+		newClient: func(rpcURL string) utils.EthRPCClient {
+			return utils.NewEthRPCClient(rpcURL, httpClient)
+		},
+		clients:  make(map[string]utils.EthRPCClient),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// SetRPCClientFactory 替换RPC客户端工厂，用于接入自定义节点客户端
+func (t *TransactionTracker) SetRPCClientFactory(factory RPCClientFactory) {
+	t.clientsMutex.Lock()
+	defer t.clientsMutex.Unlock()
+
+	t.newClient = factory
+	t.clients = make(map[string]utils.EthRPCClient)
+}
+
+// Start 启动后台轮询协程
+func (t *TransactionTracker) Start() {
+	t.logger.Infof("⛓️ 链上交易跟踪已启动: interval=%v, confirmations=%d",
+		t.cfg.Tracker.Interval, t.cfg.Tracker.RequiredConfirmations)
+	go t.loop()
+}
+
+// Stop 停止后台轮询
+func (t *TransactionTracker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stopChan)
+	})
+}
+
+// loop 定期轮询待处理交易
+func (t *TransactionTracker) loop() {
+	ticker := time.NewTicker(t.cfg.Tracker.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stopChan:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), t.cfg.Tracker.Interval)
+			if err := t.PollOnce(ctx); err != nil {
+				t.logger.Warnf("⚠️ 链上交易跟踪轮询失败: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// ========================================
+// 轮询处理
+// ========================================
+
+// chainState 单轮轮询内缓存的链信息
+type chainState struct {
+	client      utils.EthRPCClient // RPC客户端
+	latestBlock uint64             // 最新区块号
+	err         error              // 链信息获取失败原因
+}
+
+// PollOnce 执行一轮待处理交易检查
+// 单笔交易处理失败只记录日志，不影响同轮其他交易
+func (t *TransactionTracker) PollOnce(ctx context.Context) error {
+	t.pollMutex.Lock()
+	defer t.pollMutex.Unlock()
+
+	transactions, err := t.repos.Transaction.GetPendingTransactions()
+	if err != nil {
+		return fmt.Errorf("获取待处理交易失败: %w", err)
+	}
+
+	chains := make(map[uint]*chainState)
+	for _, transaction := range transactions {
+		// 尚未提交交易哈希的交易等待钱包回传
+		if transaction.TxHash == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		state, ok := chains[transaction.ChainID]
+		if !ok {
+			state = t.loadChainState(ctx, transaction.ChainID)
+			chains[transaction.ChainID] = state
+		}
+		if state.err != nil {
+			t.logger.Debugf("跳过交易跟踪: txHash=%s, 原因=%v", *transaction.TxHash, state.err)
+			continue
+		}
+
+		if err := t.trackTransaction(ctx, state, transaction); err != nil {
+			t.logger.Warnf("⚠️ 交易跟踪失败: txHash=%s, 错误=%v", *transaction.TxHash, err)
+		}
+	}
+
+	return nil
+}
+
+// loadChainState 获取链的RPC客户端和最新区块号
+func (t *TransactionTracker) loadChainState(ctx context.Context, chainID uint) *chainState {
+	chain, err := t.repos.Chain.GetByID(chainID)
+	if err != nil {
+		return &chainState{err: fmt.Errorf("获取链信息失败: %w", err)}
+	}
+	if chain.RPCURL == "" {
+		return &chainState{err: fmt.Errorf("链未配置RPC节点: %s", chain.Name)}
+	}
+
+	client := t.clientFor(chain.RPCURL)
+	latestBlock, err := client.BlockNumber(ctx)
+	if err != nil {
+		return &chainState{err: fmt.Errorf("获取最新区块失败: %w", err)}
+	}
+
+	return &chainState{client: client, latestBlock: latestBlock}
+}
+
+// clientFor 获取RPC地址对应的客户端，不存在时创建
+func (t *TransactionTracker) clientFor(rpcURL string) utils.EthRPCClient {
+	t.clientsMutex.Lock()
+	defer t.clientsMutex.Unlock()
+
+	client, ok := t.clients[rpcURL]
+	if !ok {
+		client = t.newClient(rpcURL)
+		t.clients[rpcURL] = client
+	}
+	return client
+}
+
+// trackTransaction 检查单笔交易的链上状态并更新记录
+func (t *TransactionTracker) trackTransaction(ctx context.Context, state *chainState, transaction *models.Transaction) error {
+	txHash := *transaction.TxHash
+
+	receipt, err := state.client.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	// 尚未上链: 超过等待时间视为被丢弃(替换或gas过低)
+	if receipt == nil {
+		if t.cfg.Tracker.DropTimeout > 0 && time.Since(transaction.UpdatedAt) > t.cfg.Tracker.DropTimeout {
+			t.logger.Warnf("⚠️ 交易长时间未上链，标记为失败: txHash=%s", txHash)
+			return t.swapService.UpdateSwapStatus(txHash, string(types.TransactionStatusFailed), map[string]interface{}{
+				BlockDataErrorReason: fmt.Sprintf("交易超过%v未上链", t.cfg.Tracker.DropTimeout),
+			})
+		}
+		return nil
+	}
+
+	// 交易哈希由客户端回传，发送方不是交易用户时说明关联了他人的交易，
+	// 不采用该回执的区块数据，直接标记为失败
+	if !strings.EqualFold(receipt.From, transaction.UserAddress) {
+		t.logger.Warnf("⚠️ 交易发送方与用户地址不一致，标记为失败: txHash=%s, from=%s, user=%s",
+			txHash, receipt.From, transaction.UserAddress)
+		return t.swapService.UpdateSwapStatus(txHash, string(types.TransactionStatusFailed), map[string]interface{}{
+			BlockDataErrorReason: fmt.Sprintf("交易发送方%s与用户地址不一致", receipt.From),
+		})
+	}
+
+	blockData := map[string]interface{}{
+		BlockDataBlockNumber: receipt.BlockNumber,
+		BlockDataGasUsed:     receipt.GasUsed,
+	}
+	if receipt.EffectiveGasPrice != nil {
+		blockData[BlockDataEffectiveGasPrice] = receipt.EffectiveGasPrice
+	}
+
+	// 执行失败无需等待确认数
+	if !receipt.Succeeded() {
+		blockData[BlockDataErrorReason] = "交易执行失败(reverted)"
+		return t.swapService.UpdateSwapStatus(txHash, string(types.TransactionStatusFailed), blockData)
+	}
+
+	var confirmations uint64
+	if state.latestBlock >= receipt.BlockNumber {
+		confirmations = state.latestBlock - receipt.BlockNumber + 1
+	}
+	blockData[BlockDataConfirmations] = confirmations
+
+	// 确认数不足时只更新区块信息，保持pending
+	if confirmations < uint64(t.cfg.Tracker.RequiredConfirmations) {
+		return t.swapService.UpdateSwapStatus(txHash, string(types.TransactionStatusPending), blockData)
+	}
+
+	if blockTime, err := state.client.GetBlockTimestamp(ctx, receipt.BlockNumber); err == nil {
+		blockData[BlockDataBlockTimestamp] = blockTime
+	} else {
+		t.logger.Debugf("获取区块时间失败: block=%d, 错误=%v", receipt.BlockNumber, err)
+	}
+
+	// 原生代币输出不产生Transfer事件，无法从日志获得实际输出
+	if !transaction.ToToken.IsNative && transaction.ToToken.ContractAddress != "" {
+		if amountOut := receipt.TransferredTo(transaction.ToToken.ContractAddress, transaction.UserAddress); amountOut != nil {
+			blockData[BlockDataAmountOutActual] = amountOut
+		}
+	}
+
+	return t.swapService.UpdateSwapStatus(txHash, string(types.TransactionStatusConfirmed), blockData)
+}
@@ -0,0 +1,580 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"defi-aggregator/business-logic/internal/models"
+	"defi-aggregator/business-logic/internal/repository"
+	"defi-aggregator/business-logic/internal/services"
+	"defi-aggregator/business-logic/internal/types"
+	"defi-aggregator/business-logic/pkg/config"
+	"defi-aggregator/business-logic/pkg/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// 测试用的链、代币和地址
+const (
+	testChainID        uint = 1
+	testRPCURL              = "https://rpc.example.invalid"
+	testUserAddress         = "0x1111111111111111111111111111111111111111"
+	testOtherAddress        = "0x2222222222222222222222222222222222222222"
+	testRouterAddress       = "0x3333333333333333333333333333333333333333"
+	testTokenAddress        = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+	testOtherToken          = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	testTxHash              = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testBlockTimestamp      = 1700000000
+)
+
+// ========================================
+// 假链JSON-RPC节点
+// ========================================
+
+// fakeEthNode 假链JSON-RPC节点，响应eth_blockNumber、eth_getTransactionReceipt和eth_getBlockByNumber
+type fakeEthNode struct {
+	server *httptest.Server
+
+	mutex       sync.Mutex
+	latestBlock uint64
+	receipts    map[string]map[string]interface{} // 按小写交易哈希索引，缺失表示未上链
+	methods     []string                          // 收到的调用方法
+}
+
+// newFakeEthNode 启动假节点，服务器在测试结束时自动关闭
+func newFakeEthNode(t *testing.T, latestBlock uint64) *fakeEthNode {
+	t.Helper()
+
+	node := &fakeEthNode{latestBlock: latestBlock, receipts: make(map[string]map[string]interface{})}
+	node.server = httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+// setReceipt 设置交易回执
+func (n *fakeEthNode) setReceipt(txHash string, receipt map[string]interface{}) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.receipts[strings.ToLower(txHash)] = receipt
+}
+
+// calls 返回收到的调用方法
+func (n *fakeEthNode) calls() []string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]string(nil), n.methods...)
+}
+
+// serveHTTP 处理单个JSON-RPC请求
+func (n *fakeEthNode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	var request struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mutex.Lock()
+	n.methods = append(n.methods, request.Method)
+	var result interface{}
+	switch request.Method {
+	case "eth_blockNumber":
+		result = hexUint(n.latestBlock)
+	case "eth_getTransactionReceipt":
+		var txHash string
+		_ = json.Unmarshal(request.Params[0], &txHash)
+		if receipt, ok := n.receipts[strings.ToLower(txHash)]; ok {
+			result = receipt
+		}
+	case "eth_getBlockByNumber":
+		result = map[string]interface{}{"timestamp": hexUint(testBlockTimestamp)}
+	}
+	n.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result":  result,
+	})
+}
+
+// newReceipt 构造eth_getTransactionReceipt结果
+func newReceipt(from string, blockNumber uint64, succeeded bool, logs ...map[string]interface{}) map[string]interface{} {
+	status := "0x0"
+	if succeeded {
+		status = "0x1"
+	}
+	if logs == nil {
+		logs = []map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"transactionHash":   testTxHash,
+		"blockNumber":       hexUint(blockNumber),
+		"status":            status,
+		"gasUsed":           hexUint(150000),
+		"effectiveGasPrice": hexUint(20000000000),
+		"from":              from,
+		"to":                testRouterAddress,
+		"logs":              logs,
+	}
+}
+
+// transferLog 构造ERC20 Transfer事件日志
+func transferLog(token, from, to string, amount uint64) map[string]interface{} {
+	return map[string]interface{}{
+		"address": token,
+		"topics":  []string{utils.TransferEventTopic, addressTopic(from), addressTopic(to)},
+		"data":    fmt.Sprintf("0x%064x", amount),
+	}
+}
+
+// addressTopic 地址左侧补零为32字节主题
+func addressTopic(address string) string {
+	return "0x" + strings.Repeat("0", 24) + strings.TrimPrefix(address, "0x")
+}
+
+// hexUint 十六进制数值
+func hexUint(value uint64) string {
+	return fmt.Sprintf("0x%x", value)
+}
+
+// ========================================
+// 内存数据访问层
+// ========================================
+
+// memoryTransactionRepository 内存交易数据访问，只实现跟踪器和状态更新用到的方法
+type memoryTransactionRepository struct {
+	repository.TransactionRepository
+
+	mutex        sync.Mutex
+	transactions map[string]models.Transaction // 按小写交易哈希索引
+}
+
+func (r *memoryTransactionRepository) GetPendingTransactions() ([]*models.Transaction, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var pending []*models.Transaction
+	for _, transaction := range r.transactions {
+		if transaction.Status == string(types.TransactionStatusPending) {
+			copied := transaction
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memoryTransactionRepository) GetByTxHash(txHash string) (*models.Transaction, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	transaction, ok := r.transactions[txHash]
+	if !ok {
+		return nil, fmt.Errorf("交易不存在: %s", txHash)
+	}
+	return &transaction, nil
+}
+
+func (r *memoryTransactionRepository) Update(transaction *models.Transaction) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.transactions[strings.ToLower(*transaction.TxHash)] = *transaction
+	return nil
+}
+
+// get 获取交易当前记录
+func (r *memoryTransactionRepository) get(t *testing.T, txHash string) models.Transaction {
+	t.Helper()
+
+	transaction, err := r.GetByTxHash(strings.ToLower(txHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *transaction
+}
+
+// memoryChainRepository 内存区块链数据访问
+type memoryChainRepository struct {
+	repository.ChainRepository
+
+	chains map[uint]*models.Chain
+}
+
+func (r *memoryChainRepository) GetByID(id uint) (*models.Chain, error) {
+	chain, ok := r.chains[id]
+	if !ok {
+		return nil, fmt.Errorf("区块链不存在: %d", id)
+	}
+	return chain, nil
+}
+
+// ========================================
+// 测试环境
+// ========================================
+
+// trackerFixture 跟踪器测试环境
+type trackerFixture struct {
+	node         *fakeEthNode
+	transactions *memoryTransactionRepository
+	tracker      *services.TransactionTracker
+	rpcURLs      []string // 客户端工厂收到的RPC地址
+}
+
+// newTrackerFixture 创建跟踪器，交易的链RPC节点通过客户端工厂指向假节点
+func newTrackerFixture(t *testing.T, latestBlock uint64, transaction models.Transaction) *trackerFixture {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := &config.Config{
+		Tracker: config.TrackerConfig{
+			Enabled:               true,
+			Interval:              time.Second,
+			RequiredConfirmations: 3,
+			DropTimeout:           30 * time.Minute,
+			RPCTimeout:            2 * time.Second,
+		},
+	}
+
+	transactions := &memoryTransactionRepository{
+		transactions: map[string]models.Transaction{strings.ToLower(*transaction.TxHash): transaction},
+	}
+	repos := &repository.Repositories{
+		Transaction: transactions,
+		Chain: &memoryChainRepository{chains: map[uint]*models.Chain{
+			testChainID: {BaseModel: models.BaseModel{ID: testChainID}, Name: "Ethereum", RPCURL: testRPCURL},
+		}},
+	}
+
+	f := &trackerFixture{
+		node:         newFakeEthNode(t, latestBlock),
+		transactions: transactions,
+	}
+
+	f.tracker = services.NewTransactionTracker(repos, services.NewSwapService(repos, cfg, logger), cfg, logger)
+	httpClient := utils.NewHTTPClient(cfg.Tracker.RPCTimeout, 1, logger)
+	f.tracker.SetRPCClientFactory(func(rpcURL string) utils.EthRPCClient {
+		f.rpcURLs = append(f.rpcURLs, rpcURL)
+		return utils.NewEthRPCClient(f.node.server.URL, httpClient)
+	})
+	return f
+}
+
+// poll 执行一轮跟踪
+func (f *trackerFixture) poll(t *testing.T) {
+	t.Helper()
+
+	if err := f.tracker.PollOnce(context.Background()); err != nil {
+		t.Fatalf("PollOnce失败: %v", err)
+	}
+}
+
+// newPendingTransaction 构造已提交交易哈希的待处理交易，预期输出1000000
+func newPendingTransaction() models.Transaction {
+	txHash := testTxHash
+	return models.Transaction{
+		BaseModel:         models.BaseModel{ID: 1, UpdatedAt: time.Now()},
+		TxHash:            &txHash,
+		ChainID:           testChainID,
+		AmountIn:          decimal.NewFromInt(500000),
+		AmountOutExpected: decimal.NewFromInt(1000000),
+		Status:            string(types.TransactionStatusPending),
+		UserAddress:       testUserAddress,
+		ToToken:           models.Token{ContractAddress: testTokenAddress},
+	}
+}
+
+// ========================================
+// 测试用例
+// ========================================
+
+func TestTrackerConfirmsTransactionWithActualOutput(t *testing.T) {
+	t.Parallel()
+
+	f := newTrackerFixture(t, 102, newPendingTransaction())
+	f.node.setReceipt(testTxHash, newReceipt(testUserAddress, 100, true,
+		transferLog(testTokenAddress, testRouterAddress, testUserAddress, 990000),
+	))
+
+	f.poll(t)
+
+	transaction := f.transactions.get(t, testTxHash)
+	if transaction.Status != string(types.TransactionStatusConfirmed) {
+		t.Fatalf("status = %s, want confirmed", transaction.Status)
+	}
+	if transaction.ConfirmedAt == nil {
+		t.Error("ConfirmedAt未设置")
+	}
+	if transaction.ConfirmationCount != 3 {
+		t.Errorf("ConfirmationCount = %d, want 3", transaction.ConfirmationCount)
+	}
+	if transaction.BlockNumber == nil || *transaction.BlockNumber != 100 {
+		t.Errorf("BlockNumber = %v, want 100", transaction.BlockNumber)
+	}
+	if transaction.BlockTimestamp == nil || transaction.BlockTimestamp.Unix() != testBlockTimestamp {
+		t.Errorf("BlockTimestamp = %v, want %d", transaction.BlockTimestamp, testBlockTimestamp)
+	}
+	if transaction.GasUsed == nil || *transaction.GasUsed != 150000 {
+		t.Errorf("GasUsed = %v, want 150000", transaction.GasUsed)
+	}
+	if transaction.GasFeeETH == nil || !transaction.GasFeeETH.Equal(decimal.RequireFromString("0.003")) {
+		t.Errorf("GasFeeETH = %v, want 0.003", transaction.GasFeeETH)
+	}
+	if transaction.AmountOutActual == nil || !transaction.AmountOutActual.Equal(decimal.NewFromInt(990000)) {
+		t.Errorf("AmountOutActual = %v, want 990000", transaction.AmountOutActual)
+	}
+	if transaction.SlippageActual == nil || !transaction.SlippageActual.Equal(decimal.RequireFromString("0.01")) {
+		t.Errorf("SlippageActual = %v, want 0.01", transaction.SlippageActual)
+	}
+	if len(f.rpcURLs) != 1 || f.rpcURLs[0] != testRPCURL {
+		t.Errorf("客户端工厂收到的RPC地址 = %v, want [%s]", f.rpcURLs, testRPCURL)
+	}
+}
+
+func TestTrackerDecodesTransferLogs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		logs         []map[string]interface{}
+		wantAmount   string // 为空表示不记录实际输出
+		wantSlippage string
+	}{
+		{
+			name: "只统计输出代币转入用户的转账",
+			logs: []map[string]interface{}{
+				transferLog(testOtherToken, testUserAddress, testRouterAddress, 500000),
+				transferLog(testTokenAddress, testRouterAddress, testOtherAddress, 3000),
+				transferLog(testTokenAddress, testRouterAddress, testUserAddress, 980000),
+			},
+			wantAmount:   "980000",
+			wantSlippage: "0.02",
+		},
+		{
+			name: "多笔转入累加",
+			logs: []map[string]interface{}{
+				transferLog(testTokenAddress, testRouterAddress, testUserAddress, 600000),
+				transferLog(testTokenAddress, testOtherAddress, testUserAddress, 395000),
+			},
+			wantAmount:   "995000",
+			wantSlippage: "0.005",
+		},
+		{
+			name: "成交优于预期时滑点为负",
+			logs: []map[string]interface{}{
+				transferLog(testTokenAddress, testRouterAddress, testUserAddress, 1002500),
+			},
+			wantAmount:   "1002500",
+			wantSlippage: "-0.0025",
+		},
+		{
+			name: "没有转入用户的转账",
+			logs: []map[string]interface{}{
+				transferLog(testTokenAddress, testRouterAddress, testOtherAddress, 990000),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newTrackerFixture(t, 110, newPendingTransaction())
+			f.node.setReceipt(testTxHash, newReceipt(testUserAddress, 100, true, tt.logs...))
+
+			f.poll(t)
+
+			transaction := f.transactions.get(t, testTxHash)
+			if transaction.Status != string(types.TransactionStatusConfirmed) {
+				t.Fatalf("status = %s, want confirmed", transaction.Status)
+			}
+			if tt.wantAmount == "" {
+				if transaction.AmountOutActual != nil || transaction.SlippageActual != nil {
+					t.Errorf("AmountOutActual = %v, SlippageActual = %v, want nil",
+						transaction.AmountOutActual, transaction.SlippageActual)
+				}
+				return
+			}
+			if transaction.AmountOutActual == nil || !transaction.AmountOutActual.Equal(decimal.RequireFromString(tt.wantAmount)) {
+				t.Errorf("AmountOutActual = %v, want %s", transaction.AmountOutActual, tt.wantAmount)
+			}
+			if transaction.SlippageActual == nil || !transaction.SlippageActual.Equal(decimal.RequireFromString(tt.wantSlippage)) {
+				t.Errorf("SlippageActual = %v, want %s", transaction.SlippageActual, tt.wantSlippage)
+			}
+		})
+	}
+}
+
+func TestTrackerCountsConfirmations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		latestBlock       uint64
+		wantStatus        types.TransactionStatus
+		wantConfirmations int
+	}{
+		{name: "刚上链", latestBlock: 100, wantStatus: types.TransactionStatusPending, wantConfirmations: 1},
+		{name: "确认数不足", latestBlock: 101, wantStatus: types.TransactionStatusPending, wantConfirmations: 2},
+		{name: "恰好达到确认数", latestBlock: 102, wantStatus: types.TransactionStatusConfirmed, wantConfirmations: 3},
+		{name: "节点落后于回执区块", latestBlock: 99, wantStatus: types.TransactionStatusPending, wantConfirmations: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newTrackerFixture(t, tt.latestBlock, newPendingTransaction())
+			f.node.setReceipt(testTxHash, newReceipt(testUserAddress, 100, true,
+				transferLog(testTokenAddress, testRouterAddress, testUserAddress, 990000),
+			))
+
+			f.poll(t)
+
+			transaction := f.transactions.get(t, testTxHash)
+			if transaction.Status != string(tt.wantStatus) {
+				t.Errorf("status = %s, want %s", transaction.Status, tt.wantStatus)
+			}
+			if transaction.ConfirmationCount != tt.wantConfirmations {
+				t.Errorf("ConfirmationCount = %d, want %d", transaction.ConfirmationCount, tt.wantConfirmations)
+			}
+			if transaction.BlockNumber == nil || *transaction.BlockNumber != 100 {
+				t.Errorf("BlockNumber = %v, want 100", transaction.BlockNumber)
+			}
+			// 确认前不解码Transfer事件
+			if tt.wantStatus == types.TransactionStatusPending && transaction.AmountOutActual != nil {
+				t.Errorf("AmountOutActual = %v, want nil", transaction.AmountOutActual)
+			}
+		})
+	}
+}
+
+func TestTrackerMarksRevertedTransactionFailed(t *testing.T) {
+	t.Parallel()
+
+	// 执行失败无需等待确认数
+	f := newTrackerFixture(t, 100, newPendingTransaction())
+	f.node.setReceipt(testTxHash, newReceipt(testUserAddress, 100, false))
+
+	f.poll(t)
+
+	transaction := f.transactions.get(t, testTxHash)
+	if transaction.Status != string(types.TransactionStatusFailed) {
+		t.Fatalf("status = %s, want failed", transaction.Status)
+	}
+	if transaction.ErrorReason == "" {
+		t.Error("ErrorReason未设置")
+	}
+	if transaction.BlockNumber == nil || *transaction.BlockNumber != 100 {
+		t.Errorf("BlockNumber = %v, want 100", transaction.BlockNumber)
+	}
+	if transaction.GasUsed == nil || *transaction.GasUsed != 150000 {
+		t.Errorf("GasUsed = %v, want 150000", transaction.GasUsed)
+	}
+	if transaction.ConfirmedAt != nil {
+		t.Errorf("ConfirmedAt = %v, want nil", transaction.ConfirmedAt)
+	}
+}
+
+func TestTrackerHandlesUnminedTransaction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		updatedAgo time.Duration
+		wantStatus types.TransactionStatus
+	}{
+		{name: "等待上链", updatedAgo: time.Minute, wantStatus: types.TransactionStatusPending},
+		{name: "超时视为丢弃", updatedAgo: time.Hour, wantStatus: types.TransactionStatusFailed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pending := newPendingTransaction()
+			pending.UpdatedAt = time.Now().Add(-tt.updatedAgo)
+			f := newTrackerFixture(t, 100, pending)
+
+			f.poll(t)
+
+			transaction := f.transactions.get(t, testTxHash)
+			if transaction.Status != string(tt.wantStatus) {
+				t.Fatalf("status = %s, want %s", transaction.Status, tt.wantStatus)
+			}
+			if tt.wantStatus == types.TransactionStatusFailed && transaction.ErrorReason == "" {
+				t.Error("ErrorReason未设置")
+			}
+			if transaction.BlockNumber != nil {
+				t.Errorf("BlockNumber = %v, want nil", *transaction.BlockNumber)
+			}
+		})
+	}
+}
+
+func TestTrackerRejectsReceiptFromOtherSender(t *testing.T) {
+	t.Parallel()
+
+	f := newTrackerFixture(t, 110, newPendingTransaction())
+	f.node.setReceipt(testTxHash, newReceipt(testOtherAddress, 100, true,
+		transferLog(testTokenAddress, testRouterAddress, testUserAddress, 990000),
+	))
+
+	f.poll(t)
+
+	transaction := f.transactions.get(t, testTxHash)
+	if transaction.Status != string(types.TransactionStatusFailed) {
+		t.Fatalf("status = %s, want failed", transaction.Status)
+	}
+	if !strings.Contains(transaction.ErrorReason, testOtherAddress) {
+		t.Errorf("ErrorReason = %q, want包含发送方地址", transaction.ErrorReason)
+	}
+	// 他人交易的区块数据不写入记录
+	if transaction.BlockNumber != nil || transaction.GasUsed != nil || transaction.ConfirmationCount != 0 {
+		t.Errorf("写入了区块数据: block=%v, gasUsed=%v, confirmations=%d",
+			transaction.BlockNumber, transaction.GasUsed, transaction.ConfirmationCount)
+	}
+	if transaction.AmountOutActual != nil || transaction.ConfirmedAt != nil {
+		t.Errorf("AmountOutActual = %v, ConfirmedAt = %v, want nil", transaction.AmountOutActual, transaction.ConfirmedAt)
+	}
+	for _, method := range f.node.calls() {
+		if method == "eth_getBlockByNumber" {
+			t.Error("发送方不一致时不应查询区块时间")
+		}
+	}
+}
+
+func TestTrackerAcceptsSenderCaseInsensitively(t *testing.T) {
+	t.Parallel()
+
+	pending := newPendingTransaction()
+	pending.UserAddress = "0xAbCdEf0123456789aBcDeF0123456789AbCdEf01"
+	f := newTrackerFixture(t, 110, pending)
+	f.node.setReceipt(testTxHash, newReceipt(strings.ToLower(pending.UserAddress), 100, true))
+
+	f.poll(t)
+
+	if transaction := f.transactions.get(t, testTxHash); transaction.Status != string(types.TransactionStatusConfirmed) {
+		t.Errorf("status = %s, want confirmed", transaction.Status)
+	}
+}
@@ -4,6 +4,7 @@
 package types
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -103,6 +104,59 @@ type UpdateUserRequest struct {
 	Timezone      *string `json:"timezone" validate:"omitempty"`                 // 时区
 }
 
+// ========================================
+// API Key相关类型
+// ========================================
+
+// CreateAPIKeyRequest 创建API Key请求
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`                        // 名称
+	Scopes        []string `json:"scopes" validate:"omitempty,dive,oneof=quote swap stats"` // 权限范围(为空时仅quote)
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,gte=1,lte=3650"`     // 有效天数(为空表示不过期)
+}
+
+// APIKeyInfo API Key信息(不含密钥)
+type APIKeyInfo struct {
+	ID         uint       `json:"id"`                     // API Key ID
+	Name       string     `json:"name"`                   // 名称
+	KeyPrefix  string     `json:"key_prefix"`             // 密钥前缀(用于识别)
+	Scopes     []string   `json:"scopes"`                 // 权限范围
+	Tier       string     `json:"tier"`                   // 配额等级
+	IsActive   bool       `json:"is_active"`              // 是否有效
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // 最后使用时间
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`   // 过期时间
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`   // 撤销时间
+	CreatedAt  time.Time  `json:"created_at"`             // 创建时间
+}
+
+// APIKeySecretResponse 创建或轮换API Key的响应
+// Key为完整密钥明文，仅在此响应中返回一次，服务端只保存哈希
+type APIKeySecretResponse struct {
+	APIKeyInfo
+	Key string `json:"key"` // 完整密钥
+}
+
+// UpdateAPIKeyTierRequest 调整API Key配额等级请求(仅管理员)
+type UpdateAPIKeyTierRequest struct {
+	Tier string `json:"tier" validate:"required,oneof=free standard premium"` // 配额等级
+}
+
+// ResolveAPIKeyRequest 解析API Key请求(网关内部调用)
+type ResolveAPIKeyRequest struct {
+	Key string `json:"key" validate:"required"` // 完整密钥
+}
+
+// APIKeyPrincipal API Key解析结果
+// 网关据此设置调用方身份、权限范围和限流配额等级
+type APIKeyPrincipal struct {
+	KeyID         uint       `json:"key_id"`               // API Key ID
+	UserID        uint       `json:"user_id"`              // 所属用户ID
+	WalletAddress string     `json:"wallet_address"`       // 所属用户钱包地址
+	Scopes        []string   `json:"scopes"`               // 权限范围
+	Tier          string     `json:"tier"`                 // 配额等级
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"` // 过期时间
+}
+
 // ========================================
 // 代币相关类型
 // ========================================
@@ -158,19 +212,22 @@ type QuoteRequest struct {
 
 // QuoteResponse 报价响应
 type QuoteResponse struct {
-	RequestID       string          `json:"request_id"`        // 请求ID
-	FromToken       TokenInfo       `json:"from_token"`        // 源代币信息
-	ToToken         TokenInfo       `json:"to_token"`          // 目标代币信息
-	AmountIn        decimal.Decimal `json:"amount_in"`         // 输入数量
-	AmountOut       decimal.Decimal `json:"amount_out"`        // 输出数量
-	BestAggregator  string          `json:"best_aggregator"`   // 最佳聚合器
-	GasEstimate     uint64          `json:"gas_estimate"`      // Gas估算
-	PriceImpact     decimal.Decimal `json:"price_impact"`      // 价格冲击
-	ExchangeRate    decimal.Decimal `json:"exchange_rate"`     // 汇率
-	Route           []RouteInfo     `json:"route,omitempty"`   // 交易路径
-	ValidUntil      time.Time       `json:"valid_until"`       // 有效期
-	TotalDurationMS int             `json:"total_duration_ms"` // 总耗时
-	CacheHit        bool            `json:"cache_hit"`         // 是否命中缓存
+	RequestID           string           `json:"request_id"`                     // 请求ID
+	FromToken           TokenInfo        `json:"from_token"`                     // 源代币信息
+	ToToken             TokenInfo        `json:"to_token"`                       // 目标代币信息
+	AmountIn            decimal.Decimal  `json:"amount_in"`                      // 输入数量(wei)
+	AmountOut           decimal.Decimal  `json:"amount_out"`                     // 输出数量(wei)
+	AmountInFormatted   *decimal.Decimal `json:"amount_in_formatted,omitempty"`  // 输入数量(按小数位数换算)
+	AmountOutFormatted  *decimal.Decimal `json:"amount_out_formatted,omitempty"` // 输出数量(按小数位数换算)
+	BestAggregator      string           `json:"best_aggregator"`                // 最佳聚合器
+	GasEstimate         uint64           `json:"gas_estimate"`                   // Gas估算
+	PriceImpact         decimal.Decimal  `json:"price_impact"`                   // 价格冲击
+	ExchangeRate        decimal.Decimal  `json:"exchange_rate"`                  // 汇率(每1个源代币可换得的目标代币数量)
+	InverseExchangeRate decimal.Decimal  `json:"inverse_exchange_rate"`          // 反向汇率(每1个目标代币对应的源代币数量)
+	Route               []RouteInfo      `json:"route,omitempty"`                // 交易路径
+	ValidUntil          time.Time        `json:"valid_until"`                    // 有效期
+	TotalDurationMS     int              `json:"total_duration_ms"`              // 总耗时
+	CacheHit            bool             `json:"cache_hit"`                      // 是否命中缓存
 }
 
 // RouteInfo 交易路径信息
@@ -179,6 +236,94 @@ type RouteInfo struct {
 	Percentage decimal.Decimal `json:"percentage"` // 比例
 }
 
+// AggregatorQuoteResponse 单个聚合器对报价请求的响应记录
+// 来自quote_responses表，失败的聚合器也会记录，用于报价溯源和审计
+type AggregatorQuoteResponse struct {
+	AggregatorID    uint             `json:"aggregator_id"`              // 聚合器ID
+	AggregatorName  string           `json:"aggregator_name"`            // 聚合器名称
+	DisplayName     string           `json:"display_name"`               // 聚合器显示名称
+	Success         bool             `json:"success"`                    // 是否成功
+	AmountOut       *decimal.Decimal `json:"amount_out,omitempty"`       // 输出数量(wei)
+	GasEstimate     *uint64          `json:"gas_estimate,omitempty"`     // Gas估算
+	GasPrice        *decimal.Decimal `json:"gas_price,omitempty"`        // 智能路由折算Gas费用使用的Gas价格(wei)
+	GasCost         *decimal.Decimal `json:"gas_cost,omitempty"`         // 智能路由计算的Gas费用折算输出代币数量(wei)
+	NetAmountOut    *decimal.Decimal `json:"net_amount_out,omitempty"`   // 智能路由计算的扣除Gas费用后净输出数量(wei)
+	PriceImpact     *decimal.Decimal `json:"price_impact,omitempty"`     // 价格冲击
+	ConfidenceScore *decimal.Decimal `json:"confidence_score,omitempty"` // 置信度分数
+	PriceRank       *int             `json:"price_rank,omitempty"`       // 排名(仅成功报价)
+	Route           []RouteInfo      `json:"route,omitempty"`            // 交易路径
+	RawResponse     json.RawMessage  `json:"raw_response,omitempty"`     // 聚合器原始响应
+	ResponseTimeMS  int              `json:"response_time_ms"`           // 响应时间毫秒
+	ErrorCode       string           `json:"error_code,omitempty"`       // 错误代码
+	ErrorMessage    string           `json:"error_message,omitempty"`    // 错误信息
+	CreatedAt       time.Time        `json:"created_at"`                 // 记录时间
+}
+
+// QuoteComparison 报价对比结果
+// 按排名列出所有聚合器的响应，失败的聚合器排在最后；
+// Deltas为各成功报价相对最佳报价的差额，Gas费用可用时同时给出扣除Gas费用后的差额，
+// GasCostSource说明Gas费用取自智能路由报价时的计算结果还是链配置的估算
+type QuoteComparison struct {
+	RequestID      string                     `json:"request_id"`                 // 报价请求ID
+	BestAggregator string                     `json:"best_aggregator,omitempty"`  // 最佳聚合器
+	BestAmountOut  *decimal.Decimal           `json:"best_amount_out,omitempty"`  // 最佳输出数量
+	WorstAmountOut *decimal.Decimal           `json:"worst_amount_out,omitempty"` // 最差成功报价的输出数量
+	Spread         *decimal.Decimal           `json:"spread,omitempty"`           // 最佳与最差成功报价的输出差额
+	TotalCount     int                        `json:"total_count"`                // 响应的聚合器数量
+	SuccessCount   int                        `json:"success_count"`              // 成功报价数量
+	NetOfGas       bool                       `json:"net_of_gas"`                 // 是否计算了扣除Gas费用后的差额
+	GasCostSource  string                     `json:"gas_cost_source,omitempty"`  // Gas费用来源(router/chain_config)
+	GasPrice       *decimal.Decimal           `json:"gas_price,omitempty"`        // 折算Gas费用使用的Gas价格(wei)
+	Deltas         []*QuoteDelta              `json:"deltas"`                     // 各成功报价相对最佳报价的差额
+	Responses      []*AggregatorQuoteResponse `json:"responses"`                  // 各聚合器响应
+}
+
+// QuoteDelta 单个聚合器报价相对最佳报价的差额
+// 差额为该报价减最佳报价，负数表示少于最佳报价；扣除Gas费用后的差额同样相对最佳报价的净输出计算，
+// 为正数时说明该报价扣除Gas费用后优于最佳报价
+type QuoteDelta struct {
+	AggregatorName    string           `json:"aggregator_name"`                // 聚合器名称
+	DisplayName       string           `json:"display_name"`                   // 聚合器显示名称
+	PriceRank         *int             `json:"price_rank,omitempty"`           // 排名
+	AmountOut         decimal.Decimal  `json:"amount_out"`                     // 输出数量(wei)
+	DeltaAmountOut    decimal.Decimal  `json:"delta_amount_out"`               // 与最佳报价的输出差额(wei)
+	DeltaBps          decimal.Decimal  `json:"delta_bps"`                      // 与最佳报价的输出差额(基点)
+	GasEstimate       *uint64          `json:"gas_estimate,omitempty"`         // Gas估算
+	GasCost           *decimal.Decimal `json:"gas_cost,omitempty"`             // Gas费用折算的输出代币数量(wei)
+	NetAmountOut      *decimal.Decimal `json:"net_amount_out,omitempty"`       // 扣除Gas费用后的净输出数量(wei)
+	NetDeltaAmountOut *decimal.Decimal `json:"net_delta_amount_out,omitempty"` // 与最佳报价净输出的差额(wei)
+	NetDeltaBps       *decimal.Decimal `json:"net_delta_bps,omitempty"`        // 与最佳报价净输出的差额(基点)
+}
+
+// PriceImpactAnalysis 价格冲击分析结果
+// 按请求数量的若干倍数对同一交易对采样报价，得到价格冲击随交易规模变化的曲线
+type PriceImpactAnalysis struct {
+	FromToken                  TokenInfo           `json:"from_token"`                               // 源代币信息
+	ToToken                    TokenInfo           `json:"to_token"`                                 // 目标代币信息
+	AmountIn                   decimal.Decimal     `json:"amount_in"`                                // 请求的输入数量(wei)
+	ImpactThreshold            decimal.Decimal     `json:"impact_threshold"`                         // 可接受的价格冲击阈值
+	MaxAmountInWithinThreshold *decimal.Decimal    `json:"max_amount_in_within_threshold,omitempty"` // 价格冲击不超过阈值的最大采样输入数量(wei)
+	Points                     []*PriceImpactPoint `json:"points"`                                   // 采样点(按输入数量升序)
+}
+
+// PriceImpactPoint 价格冲击曲线上的采样点
+// Impact取智能路由价格冲击(相对参考价格)与汇率衰减(相对最小采样数量的汇率)中较大者
+type PriceImpactPoint struct {
+	Multiplier         decimal.Decimal  `json:"multiplier"`                     // 相对请求数量的倍数
+	AmountIn           decimal.Decimal  `json:"amount_in"`                      // 输入数量(wei)
+	AmountInFormatted  *decimal.Decimal `json:"amount_in_formatted,omitempty"`  // 输入数量(按小数位数换算)
+	Success            bool             `json:"success"`                        // 是否获取到报价
+	AmountOut          *decimal.Decimal `json:"amount_out,omitempty"`           // 输出数量(wei)
+	AmountOutFormatted *decimal.Decimal `json:"amount_out_formatted,omitempty"` // 输出数量(按小数位数换算)
+	BestAggregator     string           `json:"best_aggregator,omitempty"`      // 最佳聚合器
+	GasEstimate        uint64           `json:"gas_estimate,omitempty"`         // Gas估算
+	ExchangeRate       *decimal.Decimal `json:"exchange_rate,omitempty"`        // 汇率(每1个源代币可换得的目标代币数量)
+	PriceImpact        *decimal.Decimal `json:"price_impact,omitempty"`         // 智能路由计算的价格冲击
+	RateDegradation    *decimal.Decimal `json:"rate_degradation,omitempty"`     // 相对最小采样数量汇率的下降比例
+	Impact             *decimal.Decimal `json:"impact,omitempty"`               // 用于阈值判断的价格冲击
+	ErrorMessage       string           `json:"error_message,omitempty"`        // 错误信息
+}
+
 // ========================================
 // 交易相关类型
 // ========================================
@@ -192,14 +337,35 @@ type SwapRequest struct {
 }
 
 // SwapResponse 交易响应
+// Kind为order时(CoW Protocol)To/Data为空，钱包需对Order签名后提交到OrderURL
 type SwapResponse struct {
-	TransactionID uint    `json:"transaction_id"`  // 交易ID
-	To            string  `json:"to"`              // 目标合约地址
-	Data          string  `json:"data"`            // 交易数据(calldata)
-	Value         string  `json:"value"`           // 交易价值
-	GasLimit      string  `json:"gas_limit"`       // Gas限制
-	GasPrice      string  `json:"gas_price"`       // Gas价格
-	Nonce         *uint64 `json:"nonce,omitempty"` // 交易nonce
+	TransactionID   uint                   `json:"transaction_id"`             // 交易ID
+	Provider        string                 `json:"provider"`                   // 构建交易的聚合器
+	Kind            string                 `json:"kind"`                       // 结果类型: transaction/order
+	To              string                 `json:"to"`                         // 目标合约地址
+	Data            string                 `json:"data"`                       // 交易数据(calldata)
+	Value           string                 `json:"value"`                      // 交易价值
+	GasLimit        string                 `json:"gas_limit"`                  // Gas限制
+	GasPrice        string                 `json:"gas_price"`                  // Gas价格
+	Nonce           *uint64                `json:"nonce,omitempty"`            // 交易nonce
+	AllowanceTarget string                 `json:"allowance_target,omitempty"` // 需要授权的合约地址
+	AmountIn        decimal.Decimal        `json:"amount_in"`                  // 输入数量
+	AmountOut       decimal.Decimal        `json:"amount_out"`                 // 预期输出数量
+	MinAmountOut    decimal.Decimal        `json:"min_amount_out"`             // 滑点保护后的最小输出数量
+	Order           map[string]interface{} `json:"order,omitempty"`            // 待签名订单(Kind为order时)
+	OrderURL        string                 `json:"order_url,omitempty"`        // 订单提交地址(Kind为order时)
+	Status          TransactionStatus      `json:"status"`                     // 交易状态
+	SubmitToken     string                 `json:"submit_token"`               // 提交凭证(只返回一次，匿名交易提交交易哈希时必须携带)
+}
+
+// SubmitSwapRequest 提交交易哈希请求
+// 钱包签名并广播交易后，回传交易哈希以便跟踪确认状态；
+// 匿名交易需携带创建交易时返回的提交凭证，证明提交者就是交易创建者
+type SubmitSwapRequest struct {
+	TransactionID uint   `json:"transaction_id" validate:"required"`        // 交易ID
+	TxHash        string `json:"tx_hash" validate:"required"`               // 链上交易哈希
+	UserAddress   string `json:"user_address" validate:"required,eth_addr"` // 用户地址
+	SubmitToken   string `json:"submit_token,omitempty"`                    // 提交凭证(匿名交易必填)
 }
 
 // TransactionInfo 交易信息
@@ -210,6 +376,7 @@ type TransactionInfo struct {
 	ChainID           uint              `json:"chain_id"`                    // 链ID
 	FromToken         TokenInfo         `json:"from_token"`                  // 源代币
 	ToToken           TokenInfo         `json:"to_token"`                    // 目标代币
+	QuoteRequestID    string            `json:"quote_request_id,omitempty"`  // 关联的报价请求ID
 	Aggregator        string            `json:"aggregator"`                  // 使用的聚合器
 	AmountIn          decimal.Decimal   `json:"amount_in"`                   // 输入数量
 	AmountOutExpected decimal.Decimal   `json:"amount_out_expected"`         // 预期输出
@@ -226,6 +393,7 @@ type TransactionInfo struct {
 	UserAddress       string            `json:"user_address"`                // 用户地址
 	BlockNumber       *uint64           `json:"block_number,omitempty"`      // 区块号
 	BlockTimestamp    *time.Time        `json:"block_timestamp,omitempty"`   // 区块时间
+	ErrorReason       string            `json:"error_reason,omitempty"`      // 失败原因
 	CreatedAt         time.Time         `json:"created_at"`                  // 创建时间
 	ConfirmedAt       *time.Time        `json:"confirmed_at,omitempty"`      // 确认时间
 }
@@ -274,6 +442,145 @@ type SystemStatsResponse struct {
 	SupportedChains   int             `json:"supported_chains"`   // 支持链数
 }
 
+// StatsRequest 统计查询请求
+type StatsRequest struct {
+	TimeRange    string `form:"time_range"`    // 时间范围: 1h/24h/7d/30d/90d，默认24h
+	AggregatorID *uint  `form:"aggregator_id"` // 聚合器ID过滤
+	FromTokenID  uint   `form:"from_token_id"` // 源代币ID过滤
+	ToTokenID    uint   `form:"to_token_id"`   // 目标代币ID过滤
+	Limit        int    `form:"limit"`         // 返回条数
+}
+
+// AggregatorStats 聚合器统计(时间范围内汇总)
+type AggregatorStats struct {
+	AggregatorID       uint                     `json:"aggregator_id"`       // 聚合器ID
+	AggregatorName     string                   `json:"aggregator_name"`     // 聚合器名称
+	DisplayName        string                   `json:"display_name"`        // 显示名称
+	TotalRequests      int                      `json:"total_requests"`      // 总请求数
+	SuccessfulRequests int                      `json:"successful_requests"` // 成功请求数
+	FailedRequests     int                      `json:"failed_requests"`     // 失败请求数
+	SuccessRate        decimal.Decimal          `json:"success_rate"`        // 成功率
+	AvgResponseTime    int                      `json:"avg_response_time"`   // 平均响应时间ms
+	MinResponseTime    int                      `json:"min_response_time"`   // 最小响应时间ms
+	MaxResponseTime    int                      `json:"max_response_time"`   // 最大响应时间ms
+	TotalVolumeUSD     decimal.Decimal          `json:"total_volume_usd"`    // 成交量USD
+	BestQuotesCount    int                      `json:"best_quotes_count"`   // 最优报价次数
+	Hourly             []*AggregatorHourlyStats `json:"hourly,omitempty"`    // 小时明细(指定聚合器时返回)
+}
+
+// AggregatorHourlyStats 聚合器小时统计
+type AggregatorHourlyStats struct {
+	HourTimestamp      time.Time       `json:"hour_timestamp"`      // 小时时间戳
+	TotalRequests      int             `json:"total_requests"`      // 总请求数
+	SuccessfulRequests int             `json:"successful_requests"` // 成功请求数
+	FailedRequests     int             `json:"failed_requests"`     // 失败请求数
+	AvgResponseTime    int             `json:"avg_response_time"`   // 平均响应时间ms
+	TotalVolumeUSD     decimal.Decimal `json:"total_volume_usd"`    // 成交量USD
+	BestQuotesCount    int             `json:"best_quotes_count"`   // 最优报价次数
+}
+
+// AggregatorRanking 聚合器排名
+type AggregatorRanking struct {
+	Rank            int             `json:"rank"`              // 排名
+	AggregatorID    uint            `json:"aggregator_id"`     // 聚合器ID
+	AggregatorName  string          `json:"aggregator_name"`   // 聚合器名称
+	Score           decimal.Decimal `json:"score"`             // 综合评分(0-100)
+	BestQuoteShare  decimal.Decimal `json:"best_quote_share"`  // 最优报价占比
+	SuccessRate     decimal.Decimal `json:"success_rate"`      // 成功率
+	AvgResponseTime int             `json:"avg_response_time"` // 平均响应时间ms
+	TotalVolumeUSD  decimal.Decimal `json:"total_volume_usd"`  // 成交量USD
+}
+
+// AggregatorComparison 聚合器对比分析
+type AggregatorComparison struct {
+	TimeRange       string             `json:"time_range"`        // 时间范围
+	StartTime       time.Time          `json:"start_time"`        // 开始时间
+	EndTime         time.Time          `json:"end_time"`          // 结束时间
+	Aggregators     []*AggregatorStats `json:"aggregators"`       // 各聚合器统计
+	TotalRequests   int                `json:"total_requests"`    // 总请求数
+	TotalBestQuotes int                `json:"total_best_quotes"` // 总最优报价次数
+	BestPriceLeader string             `json:"best_price_leader"` // 最优报价次数最多的聚合器
+	Fastest         string             `json:"fastest"`           // 平均响应最快的聚合器
+	MostReliable    string             `json:"most_reliable"`     // 成功率最高的聚合器
+}
+
+// TokenPairStats 代币对日统计
+type TokenPairStats struct {
+	Date             time.Time        `json:"date"`              // 日期
+	FromTokenID      uint             `json:"from_token_id"`     // 源代币ID
+	FromTokenSymbol  string           `json:"from_token_symbol"` // 源代币符号
+	ToTokenID        uint             `json:"to_token_id"`       // 目标代币ID
+	ToTokenSymbol    string           `json:"to_token_symbol"`   // 目标代币符号
+	ChainID          uint             `json:"chain_id"`          // 链ID
+	TransactionCount int              `json:"transaction_count"` // 交易次数
+	TotalVolumeFrom  decimal.Decimal  `json:"total_volume_from"` // 总输入量(wei)
+	TotalVolumeTo    decimal.Decimal  `json:"total_volume_to"`   // 总输出量(wei)
+	TotalVolumeUSD   decimal.Decimal  `json:"total_volume_usd"`  // 总交易量USD
+	AvgPriceImpact   *decimal.Decimal `json:"avg_price_impact"`  // 平均价格冲击
+	AvgGasFeeUSD     *decimal.Decimal `json:"avg_gas_fee_usd"`   // 平均Gas费用USD
+	UniqueUsers      int              `json:"unique_users"`      // 独立用户数
+}
+
+// PopularTokenPair 热门代币对(时间范围内汇总)
+type PopularTokenPair struct {
+	FromTokenID      uint            `json:"from_token_id"`     // 源代币ID
+	FromTokenSymbol  string          `json:"from_token_symbol"` // 源代币符号
+	ToTokenID        uint            `json:"to_token_id"`       // 目标代币ID
+	ToTokenSymbol    string          `json:"to_token_symbol"`   // 目标代币符号
+	ChainID          uint            `json:"chain_id"`          // 链ID
+	TransactionCount int             `json:"transaction_count"` // 交易次数
+	TotalVolumeUSD   decimal.Decimal `json:"total_volume_usd"`  // 总交易量USD
+}
+
+// VolumeData 每日交易量
+type VolumeData struct {
+	Date             time.Time       `json:"date"`              // 日期
+	TransactionCount int             `json:"transaction_count"` // 交易次数
+	TotalVolumeUSD   decimal.Decimal `json:"total_volume_usd"`  // 总交易量USD
+}
+
+// UserAnalytics 用户分析
+type UserAnalytics struct {
+	TimeRange              string          `json:"time_range"`                // 时间范围
+	TotalUsers             int             `json:"total_users"`               // 注册用户总数
+	NewUsers               int             `json:"new_users"`                 // 新注册用户数
+	ActiveUsers            int             `json:"active_users"`              // 活跃地址数(报价或交易)
+	TradingUsers           int             `json:"trading_users"`             // 交易地址数
+	TotalTransactions      int             `json:"total_transactions"`        // 交易数
+	AvgTransactionsPerUser decimal.Decimal `json:"avg_transactions_per_user"` // 人均交易数
+}
+
+// UserTrend 每日用户活跃趋势
+type UserTrend struct {
+	Date        time.Time `json:"date"`         // 日期
+	ActiveUsers int       `json:"active_users"` // 活跃地址数
+	NewUsers    int       `json:"new_users"`    // 新注册用户数
+}
+
+// PerformanceMetrics 系统性能指标
+type PerformanceMetrics struct {
+	TimeRange              string          `json:"time_range"`               // 时间范围
+	QuoteRequests          int             `json:"quote_requests"`           // 报价请求数
+	QuoteSuccessRate       decimal.Decimal `json:"quote_success_rate"`       // 报价成功率
+	AvgQuoteDurationMS     int             `json:"avg_quote_duration_ms"`    // 平均报价耗时ms
+	CacheHitRate           decimal.Decimal `json:"cache_hit_rate"`           // 缓存命中率
+	Transactions           int             `json:"transactions"`             // 交易数
+	ConfirmedTransactions  int             `json:"confirmed_transactions"`   // 已确认交易数
+	FailedTransactions     int             `json:"failed_transactions"`      // 失败交易数
+	TransactionSuccessRate decimal.Decimal `json:"transaction_success_rate"` // 交易成功率(已确认/已结束)
+}
+
+// LatencyStats 聚合器响应延迟统计
+type LatencyStats struct {
+	AggregatorID   uint   `json:"aggregator_id"`   // 聚合器ID
+	AggregatorName string `json:"aggregator_name"` // 聚合器名称
+	SampleCount    int    `json:"sample_count"`    // 样本数
+	AvgMS          int    `json:"avg_ms"`          // 平均延迟ms
+	P50MS          int    `json:"p50_ms"`          // P50延迟ms
+	P95MS          int    `json:"p95_ms"`          // P95延迟ms
+	MaxMS          int    `json:"max_ms"`          // 最大延迟ms
+}
+
 // ========================================
 // 健康检查类型
 // ========================================
@@ -314,6 +621,12 @@ const (
 	ErrCodeRateLimit    = "RATE_LIMIT_EXCEEDED" // 频率限制
 )
 
+// 报价对比的Gas费用来源
+const (
+	GasCostSourceRouter      = "router"       // 智能路由报价时按实时Gas价格计算
+	GasCostSourceChainConfig = "chain_config" // 按chains表建议Gas价格估算(报价响应未记录路由计算结果时回退)
+)
+
 // Gas速度枚举
 const (
 	GasSpeedSlow     = "slow"     // 慢速
@@ -342,14 +655,47 @@ const (
 	JWTClaimUserID     = "user_id"     // 用户ID
 	JWTClaimWalletAddr = "wallet_addr" // 钱包地址
 	JWTClaimRole       = "role"        // 用户角色
+	JWTClaimTokenType  = "token_type"  // 令牌类型
+	JWTClaimSessionID  = "sid"         // 会话ID
+	JWTClaimTokenID    = "jti"         // 令牌ID
+)
+
+// API Key权限范围
+const (
+	APIKeyScopeQuote = "quote" // 报价
+	APIKeyScopeSwap  = "swap"  // 交易构建和交易记录
+	APIKeyScopeStats = "stats" // 统计数据
+)
+
+// API Key配额等级(网关按等级选择限流配额)
+const (
+	APIKeyTierFree     = "free"     // 免费
+	APIKeyTierStandard = "standard" // 标准
+	APIKeyTierPremium  = "premium"  // 高级
 )
 
 // 请求头键名
 const (
-	HeaderRequestID    = "X-Request-ID"    // 请求ID头
-	HeaderUserAgent    = "User-Agent"      // 用户代理头
-	HeaderRealIP       = "X-Real-IP"       // 真实IP头
-	HeaderForwardedFor = "X-Forwarded-For" // 转发IP头
+	HeaderRequestID    = "X-Request-ID"     // 请求ID头
+	HeaderUserAgent    = "User-Agent"       // 用户代理头
+	HeaderRealIP       = "X-Real-IP"        // 真实IP头
+	HeaderForwardedFor = "X-Forwarded-For"  // 转发IP头
+	HeaderInternalAuth = "X-Internal-Token" // 服务间调用认证头
+)
+
+// 网关转发API Key调用方身份的内部请求头，只有同时携带有效X-Internal-Token时才被采信
+const (
+	HeaderPrincipalUserID = "X-Principal-User-ID"        // 调用方用户ID
+	HeaderPrincipalKeyID  = "X-Principal-API-Key-ID"     // API Key ID
+	HeaderPrincipalScopes = "X-Principal-Scopes"         // 权限范围(逗号分隔)
+	HeaderPrincipalWallet = "X-Principal-Wallet-Address" // 调用方钱包地址
+)
+
+// 流式报价事件类型(SSE event字段，与智能路由服务保持一致)
+const (
+	QuoteStreamEventQuote = "quote" // 单个聚合器报价
+	QuoteStreamEventBest  = "best"  // 最终最优报价
+	QuoteStreamEventError = "error" // 报价失败
 )
 
 // ========================================
@@ -370,18 +716,5 @@ type ChainInfo struct {
 }
 
 // 为services包需要的类型补充定义（临时）
-type AggregatorStats struct{}
-type AggregatorRanking struct{}
-type AggregatorComparison struct{}
-type TokenPairStats struct{}
-type PopularTokenPair struct{}
-type VolumeData struct{}
-type UserAnalytics struct{}
-type UserTrend struct{}
-type PerformanceMetrics struct{}
-type LatencyStats struct{}
 type TransactionCost struct{}
 type SlippageAnalysis struct{}
-type AggregatorQuoteResponse struct{}
-type QuoteComparison struct{}
-type PriceImpactAnalysis struct{}
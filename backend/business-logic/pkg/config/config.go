@@ -39,6 +39,12 @@ type Config struct {
 	// 业务配置
 	Business BusinessConfig `json:"business"`
 
+	// 交易确认跟踪配置
+	Tracker TrackerConfig `json:"tracker"`
+
+	// 统计汇总配置
+	Stats StatsConfig `json:"stats"`
+
 	// 监控配置
 	Monitoring MonitoringConfig `json:"monitoring"`
 }
@@ -84,6 +90,7 @@ type JWTConfig struct {
 	RefreshExpiresIn time.Duration `json:"refresh_expires_in"` // 刷新令牌过期时间
 	Issuer           string        `json:"issuer"`             // 令牌签发者
 	Algorithm        string        `json:"algorithm"`          // 签名算法
+	SessionBackend   string        `json:"session_backend"`    // 会话存储后端: redis, memory
 }
 
 // ExternalServicesConfig 外部服务配置
@@ -99,6 +106,11 @@ type SecurityConfig struct {
 	CORSAllowedHeaders []string      `json:"cors_allowed_headers"` // CORS允许的头部
 	RateLimitRequests  int           `json:"rate_limit_requests"`  // 限流请求数
 	RateLimitDuration  time.Duration `json:"rate_limit_duration"`  // 限流时间窗口
+
+	// API Key配置
+	InternalServiceToken string `json:"-"`                    // 服务间调用令牌(网关解析API Key)，为空时不开放内部接口
+	APIKeyMaxPerUser     int    `json:"api_key_max_per_user"` // 每个用户有效API Key数量上限
+	APIKeyDefaultTier    string `json:"api_key_default_tier"` // 新建API Key的配额等级
 }
 
 // BusinessConfig 业务相关配置
@@ -108,6 +120,25 @@ type BusinessConfig struct {
 	CacheTTLShort   time.Duration `json:"cache_ttl_short"`   // 短期缓存TTL
 	CacheTTLMedium  time.Duration `json:"cache_ttl_medium"`  // 中期缓存TTL
 	CacheTTLLong    time.Duration `json:"cache_ttl_long"`    // 长期缓存TTL
+	QuoteValidity   time.Duration `json:"quote_validity"`    // 报价可用于创建交易的有效期
+}
+
+// TrackerConfig 链上交易确认跟踪配置
+// 后台定期轮询已提交交易哈希的pending交易，通过链的RPC节点查询回执和确认数
+type TrackerConfig struct {
+	Enabled               bool          `json:"enabled"`                // 是否启用交易确认跟踪
+	Interval              time.Duration `json:"interval"`               // 轮询间隔
+	RequiredConfirmations int           `json:"required_confirmations"` // 判定为已确认所需的区块确认数
+	DropTimeout           time.Duration `json:"drop_timeout"`           // 提交后超过该时间仍无回执则判定为失败
+	RPCTimeout            time.Duration `json:"rpc_timeout"`            // 单次RPC调用超时时间
+}
+
+// StatsConfig 统计汇总配置
+// 后台定期将报价和交易数据汇总到aggregator_stats_hourly和token_pair_stats_daily
+type StatsConfig struct {
+	RollupEnabled  bool          `json:"rollup_enabled"`  // 是否启用统计汇总任务
+	RollupInterval time.Duration `json:"rollup_interval"` // 汇总间隔
+	RollupBackfill time.Duration `json:"rollup_backfill"` // 启动时回补的历史时长
 }
 
 // MonitoringConfig 监控配置
@@ -163,6 +194,7 @@ func Load() (*Config, error) {
 			RefreshExpiresIn: getEnvAsDuration("JWT_REFRESH_EXPIRES_IN", 168*time.Hour),
 			Issuer:           getEnv("JWT_ISSUER", "defi-aggregator"),
 			Algorithm:        getEnv("JWT_ALGORITHM", "HS256"),
+			SessionBackend:   getEnv("JWT_SESSION_BACKEND", "redis"),
 		},
 		ExternalServices: ExternalServicesConfig{
 			SmartRouterURL: getEnv("SMART_ROUTER_URL", ""), // 必填
@@ -174,6 +206,10 @@ func Load() (*Config, error) {
 			CORSAllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Requested-With"}),
 			RateLimitRequests:  getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
 			RateLimitDuration:  getEnvAsDuration("RATE_LIMIT_DURATION", time.Minute),
+
+			InternalServiceToken: getEnv("INTERNAL_SERVICE_TOKEN", ""),
+			APIKeyMaxPerUser:     getEnvAsInt("API_KEY_MAX_PER_USER", 10),
+			APIKeyDefaultTier:    getEnv("API_KEY_DEFAULT_TIER", "free"),
 		},
 		Business: BusinessConfig{
 			DefaultPageSize: getEnvAsInt("DEFAULT_PAGE_SIZE", 20),
@@ -181,6 +217,19 @@ func Load() (*Config, error) {
 			CacheTTLShort:   getEnvAsDuration("CACHE_TTL_SHORT", 60*time.Second),
 			CacheTTLMedium:  getEnvAsDuration("CACHE_TTL_MEDIUM", 300*time.Second),
 			CacheTTLLong:    getEnvAsDuration("CACHE_TTL_LONG", 3600*time.Second),
+			QuoteValidity:   getEnvAsDuration("QUOTE_VALIDITY", 5*time.Minute),
+		},
+		Tracker: TrackerConfig{
+			Enabled:               getEnvAsBool("TX_TRACKER_ENABLED", true),
+			Interval:              getEnvAsDuration("TX_TRACKER_INTERVAL", 15*time.Second),
+			RequiredConfirmations: getEnvAsInt("TX_REQUIRED_CONFIRMATIONS", 3),
+			DropTimeout:           getEnvAsDuration("TX_DROP_TIMEOUT", 30*time.Minute),
+			RPCTimeout:            getEnvAsDuration("TX_RPC_TIMEOUT", 10*time.Second),
+		},
+		Stats: StatsConfig{
+			RollupEnabled:  getEnvAsBool("STATS_ROLLUP_ENABLED", true),
+			RollupInterval: getEnvAsDuration("STATS_ROLLUP_INTERVAL", 5*time.Minute),
+			RollupBackfill: getEnvAsDuration("STATS_ROLLUP_BACKFILL", 7*24*time.Hour),
 		},
 		Monitoring: MonitoringConfig{
 			MetricsEnabled:  getEnvAsBool("METRICS_ENABLED", true),
@@ -270,12 +319,50 @@ func (c *Config) validate() error {
 	if len(c.JWT.SecretKey) < 32 {
 		return fmt.Errorf("JWT密钥长度必须至少32个字符，当前长度: %d", len(c.JWT.SecretKey))
 	}
+	switch c.JWT.SessionBackend {
+	case "redis", "memory":
+	default:
+		return fmt.Errorf("无效的会话存储后端: %s (可选: redis, memory)", c.JWT.SessionBackend)
+	}
 
 	// 验证必填的外部服务配置
 	if c.ExternalServices.SmartRouterURL == "" {
 		return fmt.Errorf("SMART_ROUTER_URL环境变量是必填项")
 	}
 
+	// 验证API Key配置
+	if c.Security.APIKeyMaxPerUser <= 0 {
+		return fmt.Errorf("API_KEY_MAX_PER_USER必须大于0，当前值: %d", c.Security.APIKeyMaxPerUser)
+	}
+	switch c.Security.APIKeyDefaultTier {
+	case "free", "standard", "premium":
+	default:
+		return fmt.Errorf("无效的API Key配额等级: %s (可选: free, standard, premium)", c.Security.APIKeyDefaultTier)
+	}
+	if c.Security.InternalServiceToken != "" && len(c.Security.InternalServiceToken) < 32 {
+		return fmt.Errorf("INTERNAL_SERVICE_TOKEN长度必须至少32个字符，当前长度: %d", len(c.Security.InternalServiceToken))
+	}
+
+	// 验证业务配置
+	if c.Business.QuoteValidity <= 0 {
+		return fmt.Errorf("QUOTE_VALIDITY必须大于0，当前值: %v", c.Business.QuoteValidity)
+	}
+
+	// 验证交易确认跟踪配置
+	if c.Tracker.Enabled {
+		if c.Tracker.Interval <= 0 {
+			return fmt.Errorf("TX_TRACKER_INTERVAL必须大于0，当前值: %v", c.Tracker.Interval)
+		}
+		if c.Tracker.RequiredConfirmations < 1 {
+			return fmt.Errorf("TX_REQUIRED_CONFIRMATIONS必须至少为1，当前值: %d", c.Tracker.RequiredConfirmations)
+		}
+	}
+
+	// 验证统计汇总配置
+	if c.Stats.RollupEnabled && c.Stats.RollupInterval <= 0 {
+		return fmt.Errorf("STATS_ROLLUP_INTERVAL必须大于0，当前值: %v", c.Stats.RollupInterval)
+	}
+
 	// 验证必填的安全配置
 	if len(c.Security.CORSAllowedOrigins) == 0 {
 		return fmt.Errorf("CORS_ALLOWED_ORIGINS环境变量是必填项")
@@ -5,6 +5,8 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,6 +14,7 @@ import (
 
 	"defi-aggregator/business-logic/internal/types"
 	"defi-aggregator/business-logic/pkg/config"
+	"defi-aggregator/business-logic/pkg/session"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -245,11 +248,23 @@ func Security() gin.HandlerFunc {
 	}
 }
 
+// sessionCheckTimeout 认证时查询会话存储的超时时间
+const sessionCheckTimeout = 2 * time.Second
+
 // JWT JWT认证中间件
 // 验证JWT令牌，提取用户信息
-// 支持Bearer Token格式，验证令牌有效性和过期时间
-func JWT(cfg *config.Config) gin.HandlerFunc {
+// 支持Bearer Token格式，验证令牌有效性和过期时间，
+// 并通过会话存储确认令牌未被登出、撤销或轮换；
+// 网关已认证的API Key调用方通过内部请求头转发身份，校验服务间调用令牌后按该用户处理
+func JWT(cfg *config.Config, sessions session.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if c.GetHeader(types.HeaderPrincipalUserID) != "" {
+			if setGatewayPrincipal(c, cfg) {
+				c.Next()
+			}
+			return
+		}
+
 		// 获取Authorization头
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -392,8 +407,47 @@ func JWT(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		// 只接受访问令牌，且必须是所属会话当前有效的访问令牌
+		if tokenType, _ := claims[types.JWTClaimTokenType].(string); tokenType != "access" {
+			abortUnauthorized(c, "令牌类型错误")
+			return
+		}
+		sessionID, _ := claims[types.JWTClaimSessionID].(string)
+		tokenID, _ := claims[types.JWTClaimTokenID].(string)
+		if sessionID == "" || tokenID == "" {
+			abortUnauthorized(c, "令牌缺少会话信息")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), sessionCheckTimeout)
+		sess, err := sessions.Get(ctx, sessionID)
+		cancel()
+		if errors.Is(err, session.ErrSessionNotFound) {
+			abortUnauthorized(c, "会话已失效，请重新登录")
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    types.ErrCodeInternal,
+					Message: "会话服务暂不可用",
+				},
+				Timestamp: time.Now().Unix(),
+				RequestID: c.GetString("request_id"),
+			})
+			c.Abort()
+			return
+		}
+		if sess.UserID != userID || sess.AccessJTI != tokenID {
+			abortUnauthorized(c, "认证令牌已被撤销")
+			return
+		}
+
 		// 将用户信息设置到上下文
 		c.Set("user_id", userID)
+		c.Set("session_id", sessionID)
+		c.Set("token_id", tokenID)
 		if walletAddr, exists := claims[types.JWTClaimWalletAddr]; exists {
 			c.Set("wallet_address", walletAddr)
 		}
@@ -408,8 +462,15 @@ func JWT(cfg *config.Config) gin.HandlerFunc {
 // Optional JWT 可选JWT认证中间件
 // 如果提供了JWT令牌则验证，否则继续处理
 // 适用于既支持认证用户又支持匿名用户的接口
-func OptionalJWT(cfg *config.Config) gin.HandlerFunc {
+func OptionalJWT(cfg *config.Config, sessions session.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if c.GetHeader(types.HeaderPrincipalUserID) != "" {
+			if setGatewayPrincipal(c, cfg) {
+				c.Next()
+			}
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 
 		// 如果没有认证头，继续处理（作为匿名用户）
@@ -419,11 +480,60 @@ func OptionalJWT(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		// 如果有认证头，则进行验证
-		jwtMiddleware := JWT(cfg)
+		jwtMiddleware := JWT(cfg, sessions)
 		jwtMiddleware(c)
 	}
 }
 
+// setGatewayPrincipal 校验网关转发的API Key调用方身份并设置到上下文
+// 服务间调用令牌无效或身份头格式错误时返回401，返回false表示请求已终止
+func setGatewayPrincipal(c *gin.Context, cfg *config.Config) bool {
+	expected := []byte(cfg.Security.InternalServiceToken)
+	token := []byte(c.GetHeader(types.HeaderInternalAuth))
+	if len(expected) == 0 || subtle.ConstantTimeCompare(token, expected) != 1 {
+		abortUnauthorized(c, "无效的网关身份凭证")
+		return false
+	}
+
+	userID, err := strconv.ParseUint(c.GetHeader(types.HeaderPrincipalUserID), 10, 32)
+	if err != nil || userID == 0 {
+		abortUnauthorized(c, "无效的用户ID格式")
+		return false
+	}
+	keyID, err := strconv.ParseUint(c.GetHeader(types.HeaderPrincipalKeyID), 10, 32)
+	if err != nil || keyID == 0 {
+		abortUnauthorized(c, "无效的API Key ID格式")
+		return false
+	}
+
+	var scopes []string
+	if header := c.GetHeader(types.HeaderPrincipalScopes); header != "" {
+		scopes = strings.Split(header, ",")
+	}
+
+	c.Set("user_id", uint(userID))
+	c.Set("api_key_id", uint(keyID))
+	c.Set("api_key_scopes", scopes)
+	if walletAddr := c.GetHeader(types.HeaderPrincipalWallet); walletAddr != "" {
+		c.Set("wallet_address", walletAddr)
+	}
+	return true
+}
+
+// abortUnauthorized 返回401并终止请求
+func abortUnauthorized(c *gin.Context, message string) {
+	c.JSON(http.StatusUnauthorized, types.APIResponse{
+		Success: false,
+		Error: &types.APIError{
+			Code:    types.ErrCodeUnauthorized,
+			Message: message,
+		},
+		Timestamp: time.Now().Unix(),
+		RequestID: c.GetString("request_id"),
+	})
+	c.Abort()
+}
+
 // Admin 管理员权限中间件
 // 验证用户是否具有管理员权限
 // 必须在JWT中间件之后使用
@@ -449,6 +559,54 @@ func Admin() gin.HandlerFunc {
 	}
 }
 
+// SessionOnly 仅限登录会话访问的中间件
+// 拒绝网关转发的API Key调用方，用于用户资料、登出和API Key管理等账户操作
+// 必须在JWT中间件之后使用
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := c.Get("api_key_id"); isAPIKey {
+			c.JSON(http.StatusForbidden, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    types.ErrCodeForbidden,
+					Message: "该接口不支持API Key访问",
+				},
+				Timestamp: time.Now().Unix(),
+				RequestID: c.GetString("request_id"),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// InternalAuth 服务间调用认证中间件
+// 校验X-Internal-Token与INTERNAL_SERVICE_TOKEN一致，用于仅供网关调用的内部接口
+func InternalAuth(cfg *config.Config) gin.HandlerFunc {
+	expected := []byte(cfg.Security.InternalServiceToken)
+
+	return func(c *gin.Context) {
+		token := []byte(c.GetHeader(types.HeaderInternalAuth))
+		if len(expected) == 0 || subtle.ConstantTimeCompare(token, expected) != 1 {
+			c.JSON(http.StatusForbidden, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    types.ErrCodeForbidden,
+					Message: "禁止访问内部接口",
+				},
+				Timestamp: time.Now().Unix(),
+				RequestID: c.GetString("request_id"),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // Timeout 请求超时中间件
 // 为请求设置超时时间，防止长时间占用资源
 func Timeout(timeout time.Duration) gin.HandlerFunc {
@@ -0,0 +1,140 @@
+// Package session 进程内会话存储实现
+// 用于本地开发、测试以及Redis不可用时的降级，会话不在实例间共享，重启后全部失效
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryStore 进程内会话存储
+// 过期会话在访问和创建时惰性清理
+type MemoryStore struct {
+	sessions map[string]*Session          // 会话ID -> 会话
+	users    map[uint]map[string]struct{} // 用户ID -> 会话ID集合
+	mutex    sync.Mutex                   // 保护sessions和users
+	logger   *logrus.Logger               // 日志记录器
+}
+
+// NewMemoryStore 创建进程内会话存储
+func NewMemoryStore(logger *logrus.Logger) *MemoryStore {
+	logger.Info("内存会话存储初始化完成")
+	return &MemoryStore{
+		sessions: make(map[string]*Session),
+		users:    make(map[uint]map[string]struct{}),
+		logger:   logger,
+	}
+}
+
+// Create 创建会话
+func (s *MemoryStore) Create(ctx context.Context, session *Session, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.purgeExpired(time.Now())
+
+	stored := *session
+	stored.ExpiresAt = time.Now().Add(ttl)
+	s.sessions[stored.ID] = &stored
+
+	if s.users[stored.UserID] == nil {
+		s.users[stored.UserID] = make(map[string]struct{})
+	}
+	s.users[stored.UserID][stored.ID] = struct{}{}
+	return nil
+}
+
+// Get 获取会话
+func (s *MemoryStore) Get(ctx context.Context, sessionID string) (*Session, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if time.Now().After(session.ExpiresAt) {
+		s.remove(session)
+		return nil, ErrSessionNotFound
+	}
+
+	result := *session
+	return &result, nil
+}
+
+// Rotate 轮换会话令牌
+func (s *MemoryStore) Rotate(ctx context.Context, sessionID, refreshJTI, newAccessJTI, newRefreshJTI string, ttl time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok || time.Now().After(session.ExpiresAt) {
+		return false, ErrSessionNotFound
+	}
+	if session.RefreshJTI != refreshJTI {
+		return false, nil
+	}
+
+	session.AccessJTI = newAccessJTI
+	session.RefreshJTI = newRefreshJTI
+	session.ExpiresAt = time.Now().Add(ttl)
+	return true, nil
+}
+
+// Revoke 撤销单个会话
+func (s *MemoryStore) Revoke(ctx context.Context, sessionID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if session, ok := s.sessions[sessionID]; ok {
+		s.remove(session)
+	}
+	return nil
+}
+
+// RevokeAll 撤销用户的所有会话
+func (s *MemoryStore) RevokeAll(ctx context.Context, userID uint) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for sessionID := range s.users[userID] {
+		delete(s.sessions, sessionID)
+		count++
+	}
+	delete(s.users, userID)
+	return count, nil
+}
+
+// Backend 存储后端名称
+func (s *MemoryStore) Backend() string {
+	return BackendMemory
+}
+
+// Close 释放存储资源
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// remove 删除会话及用户索引，调用方需持有锁
+func (s *MemoryStore) remove(session *Session) {
+	delete(s.sessions, session.ID)
+	if ids, ok := s.users[session.UserID]; ok {
+		delete(ids, session.ID)
+		if len(ids) == 0 {
+			delete(s.users, session.UserID)
+		}
+	}
+}
+
+// purgeExpired 清理已过期的会话，调用方需持有锁
+func (s *MemoryStore) purgeExpired(now time.Time) {
+	for _, session := range s.sessions {
+		if now.After(session.ExpiresAt) {
+			s.remove(session)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package session Redis会话存储实现
+// 会话保存为Hash(session:{id})，用户的会话ID集合保存为Set(session:user:{userID})，
+// 令牌轮换通过Lua脚本比较并替换刷新令牌ID，保证并发刷新时只有一个请求成功
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// redisKeyPrefix 会话键前缀
+const redisKeyPrefix = "session:"
+
+// rotateScript 比较刷新令牌ID并轮换
+// KEYS[1]=会话键, ARGV=[旧刷新令牌ID, 新访问令牌ID, 新刷新令牌ID, 过期秒数, 过期时间戳]
+// 返回: -1会话不存在, 0刷新令牌不匹配, 1轮换成功
+var rotateScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'refresh_jti')
+if not current then
+	return -1
+end
+if current ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'access_jti', ARGV[2], 'refresh_jti', ARGV[3], 'expires_at', ARGV[5])
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+return 1
+`)
+
+// RedisStore Redis会话存储
+type RedisStore struct {
+	client *redis.Client  // Redis客户端
+	logger *logrus.Logger // 日志记录器
+}
+
+// NewRedisStore 创建Redis会话存储
+// 建立连接后立即执行PING，连接失败时返回错误
+func NewRedisStore(cfg *config.RedisConfig, logger *logrus.Logger) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:       fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password:   cfg.Password,
+		DB:         cfg.DB,
+		MaxRetries: cfg.MaxRetries,
+		PoolSize:   cfg.PoolSize,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接Redis失败: %w", err)
+	}
+
+	logger.Infof("Redis会话存储连接成功: %s:%d, db=%d", cfg.Host, cfg.Port, cfg.DB)
+
+	return &RedisStore{
+		client: client,
+		logger: logger,
+	}, nil
+}
+
+// Create 创建会话
+func (s *RedisStore) Create(ctx context.Context, session *Session, ttl time.Duration) error {
+	key := sessionKey(session.ID)
+	userKey := userSessionsKey(session.UserID)
+	expiresAt := time.Now().Add(ttl)
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"user_id":     session.UserID,
+		"access_jti":  session.AccessJTI,
+		"refresh_jti": session.RefreshJTI,
+		"created_at":  session.CreatedAt.Unix(),
+		"expires_at":  expiresAt.Unix(),
+	})
+	pipe.Expire(ctx, key, ttl)
+	pipe.SAdd(ctx, userKey, session.ID)
+	// 用户索引的过期时间不短于最新会话，过期的会话ID在RevokeAll时一并清理
+	pipe.Expire(ctx, userKey, ttl)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("保存会话失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取会话
+func (s *RedisStore) Get(ctx context.Context, sessionID string) (*Session, error) {
+	values, err := s.client.HGetAll(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取会话失败: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	userID, _ := strconv.ParseUint(values["user_id"], 10, 64)
+	createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+	expiresAt, _ := strconv.ParseInt(values["expires_at"], 10, 64)
+
+	return &Session{
+		ID:         sessionID,
+		UserID:     uint(userID),
+		AccessJTI:  values["access_jti"],
+		RefreshJTI: values["refresh_jti"],
+		CreatedAt:  time.Unix(createdAt, 0),
+		ExpiresAt:  time.Unix(expiresAt, 0),
+	}, nil
+}
+
+// Rotate 轮换会话令牌
+func (s *RedisStore) Rotate(ctx context.Context, sessionID, refreshJTI, newAccessJTI, newRefreshJTI string, ttl time.Duration) (bool, error) {
+	result, err := rotateScript.Run(ctx, s.client, []string{sessionKey(sessionID)},
+		refreshJTI, newAccessJTI, newRefreshJTI, int64(ttl.Seconds()), time.Now().Add(ttl).Unix()).Int()
+	if err != nil {
+		return false, fmt.Errorf("轮换会话令牌失败: %w", err)
+	}
+
+	switch result {
+	case -1:
+		return false, ErrSessionNotFound
+	case 0:
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// Revoke 撤销单个会话
+func (s *RedisStore) Revoke(ctx context.Context, sessionID string) error {
+	key := sessionKey(sessionID)
+
+	userID, err := s.client.HGet(ctx, key, "user_id").Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("读取会话失败: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if id, parseErr := strconv.ParseUint(userID, 10, 64); parseErr == nil {
+		pipe.SRem(ctx, userSessionsKey(uint(id)), sessionID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("撤销会话失败: %w", err)
+	}
+	return nil
+}
+
+// RevokeAll 撤销用户的所有会话
+func (s *RedisStore) RevokeAll(ctx context.Context, userID uint) (int, error) {
+	userKey := userSessionsKey(userID)
+
+	sessionIDs, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("读取用户会话失败: %w", err)
+	}
+
+	keys := make([]string, 0, len(sessionIDs)+1)
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, sessionKey(sessionID))
+	}
+	keys = append(keys, userKey)
+
+	// 集合中可能包含已自然过期的会话ID，按实际删除的会话键计数
+	deleted, err := s.client.Del(ctx, keys...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("撤销用户会话失败: %w", err)
+	}
+	if deleted > 0 {
+		deleted-- // 扣除用户索引键
+	}
+	return int(deleted), nil
+}
+
+// Backend 存储后端名称
+func (s *RedisStore) Backend() string {
+	return BackendRedis
+}
+
+// Close 关闭Redis连接
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// sessionKey 会话键
+func sessionKey(sessionID string) string {
+	return redisKeyPrefix + sessionID
+}
+
+// userSessionsKey 用户会话索引键
+func userSessionsKey(userID uint) string {
+	return fmt.Sprintf("%suser:%d", redisKeyPrefix, userID)
+}
@@ -0,0 +1,76 @@
+// Package session JWT会话存储
+// 每次登录创建一个会话(刷新令牌家族)，记录当前有效的访问令牌和刷新令牌ID(jti)，
+// 认证中间件和刷新接口通过会话存储判断令牌是否已被撤销或轮换
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"defi-aggregator/business-logic/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 会话存储后端
+const (
+	BackendRedis  = "redis"  // Redis存储，多实例共享
+	BackendMemory = "memory" // 进程内存储，用于本地开发和测试
+)
+
+// ErrSessionNotFound 会话不存在(已撤销或已过期)
+var ErrSessionNotFound = errors.New("会话不存在或已失效")
+
+// Session 登录会话
+// 同一会话内每次刷新都会轮换访问令牌和刷新令牌，只有最新签发的令牌有效
+type Session struct {
+	ID         string    `json:"id"`          // 会话ID，写入令牌的sid声明
+	UserID     uint      `json:"user_id"`     // 用户ID
+	AccessJTI  string    `json:"access_jti"`  // 当前有效的访问令牌ID
+	RefreshJTI string    `json:"refresh_jti"` // 当前有效的刷新令牌ID
+	CreatedAt  time.Time `json:"created_at"`  // 登录时间
+	ExpiresAt  time.Time `json:"expires_at"`  // 会话过期时间(随刷新令牌续期)
+}
+
+// Store 会话存储接口
+type Store interface {
+	// Create 创建会话，ttl到期后自动失效
+	Create(ctx context.Context, session *Session, ttl time.Duration) error
+
+	// Get 获取会话，不存在时返回ErrSessionNotFound
+	Get(ctx context.Context, sessionID string) (*Session, error)
+
+	// Rotate 轮换会话令牌
+	// 仅当会话当前刷新令牌ID等于refreshJTI时替换为新的令牌ID并续期，返回是否替换成功；
+	// 返回false表示刷新令牌已被使用过(或并发刷新)，调用方应视为令牌重放
+	Rotate(ctx context.Context, sessionID, refreshJTI, newAccessJTI, newRefreshJTI string, ttl time.Duration) (bool, error)
+
+	// Revoke 撤销单个会话
+	Revoke(ctx context.Context, sessionID string) error
+
+	// RevokeAll 撤销用户的所有会话，返回撤销的会话数
+	RevokeAll(ctx context.Context, userID uint) (int, error)
+
+	// Backend 存储后端名称
+	Backend() string
+
+	// Close 释放存储资源
+	Close() error
+}
+
+// NewStore 根据配置创建会话存储
+// 配置为Redis但连接失败时降级为进程内存储，此时会话仅在当前实例内有效
+func NewStore(cfg *config.Config, logger *logrus.Logger) Store {
+	if cfg.JWT.SessionBackend == BackendMemory {
+		logger.Info("初始化内存会话存储...")
+		return NewMemoryStore(logger)
+	}
+
+	store, err := NewRedisStore(&cfg.Redis, logger)
+	if err != nil {
+		logger.Warnf("⚠️ Redis会话存储不可用，降级为内存存储(多实例部署时会话无法共享): %v", err)
+		return NewMemoryStore(logger)
+	}
+	return store
+}
@@ -37,6 +37,18 @@ func IsValidEthereumAddress(address string) bool {
 	return re.MatchString(address)
 }
 
+// IsValidTransactionHash 验证交易哈希格式
+// 检查哈希是否符合规范：66位十六进制字符，以0x开头
+// 参数:
+//   - txHash: 待验证的交易哈希
+//
+// 返回:
+//   - bool: 哈希是否有效
+func IsValidTransactionHash(txHash string) bool {
+	re := regexp.MustCompile("^0x[0-9a-fA-F]{64}$")
+	return re.MatchString(txHash)
+}
+
 // NormalizeEthereumAddress 标准化以太坊地址格式
 // 将地址转换为标准的小写格式，便于数据库存储和比较
 // 参数:
@@ -116,6 +128,7 @@ type JWTClaims struct {
 	WalletAddress        string `json:"wallet_address"` // 钱包地址
 	Role                 string `json:"role"`           // 用户角色
 	TokenType            string `json:"token_type"`     // 令牌类型: access, refresh
+	SessionID            string `json:"sid"`            // 会话ID，同一次登录签发的令牌共享
 	jwt.RegisteredClaims        // 标准JWT声明
 }
 
@@ -125,6 +138,8 @@ type JWTClaims struct {
 //   - userID: 用户ID
 //   - walletAddress: 钱包地址
 //   - role: 用户角色
+//   - sessionID: 会话ID
+//   - tokenID: 令牌ID(jti)，由会话存储记录用于撤销
 //   - secretKey: JWT密钥
 //   - expiresIn: 过期时间
 //   - tokenType: 令牌类型
@@ -132,7 +147,7 @@ type JWTClaims struct {
 // 返回:
 //   - string: JWT令牌字符串
 //   - error: 生成错误
-func GenerateJWT(userID uint, walletAddress, role, secretKey string, expiresIn time.Duration, tokenType string) (string, error) {
+func GenerateJWT(userID uint, walletAddress, role, sessionID, tokenID, secretKey string, expiresIn time.Duration, tokenType string) (string, error) {
 	now := time.Now()
 
 	// 创建JWT声明
@@ -141,6 +156,7 @@ func GenerateJWT(userID uint, walletAddress, role, secretKey string, expiresIn t
 		WalletAddress: strings.ToLower(walletAddress),
 		Role:          role,
 		TokenType:     tokenType,
+		SessionID:     sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "defi-aggregator",                      // 签发者
 			Subject:   fmt.Sprintf("user:%d", userID),         // 主题
@@ -148,7 +164,7 @@ func GenerateJWT(userID uint, walletAddress, role, secretKey string, expiresIn t
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)), // 过期时间
 			NotBefore: jwt.NewNumericDate(now),                // 生效时间
 			IssuedAt:  jwt.NewNumericDate(now),                // 签发时间
-			ID:        uuid.New().String(),                    // 令牌ID
+			ID:        tokenID,                                // 令牌ID
 		},
 	}
 
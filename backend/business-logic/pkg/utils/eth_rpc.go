@@ -0,0 +1,256 @@
+// Package utils 以太坊JSON-RPC客户端
+// 封装交易确认跟踪所需的eth_blockNumber、eth_getTransactionReceipt、eth_getBlockByNumber调用
+// 以及ERC20 Transfer事件日志解码，RPC节点地址取自chains表的rpc_url
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// TransferEventTopic ERC20 Transfer(address,address,uint256)事件签名哈希
+const TransferEventTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+// EthRPCClient 以太坊JSON-RPC客户端接口
+type EthRPCClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)                               // 获取最新区块号
+	GetTransactionReceipt(ctx context.Context, txHash string) (*EthReceipt, error) // 获取交易回执，交易未上链时返回nil
+	GetBlockTimestamp(ctx context.Context, blockNumber uint64) (time.Time, error)  // 获取区块时间
+}
+
+// EthReceipt 交易回执
+type EthReceipt struct {
+	TransactionHash   string   // 交易哈希
+	BlockNumber       uint64   // 所在区块号
+	Status            uint64   // 执行状态: 1成功, 0失败(revert)
+	GasUsed           uint64   // 实际使用Gas
+	EffectiveGasPrice *big.Int // 实际Gas价格(wei)，旧节点可能不返回
+	From              string   // 发送方地址
+	To                string   // 接收方地址
+	Logs              []EthLog // 事件日志
+}
+
+// EthLog 交易事件日志
+type EthLog struct {
+	Address string   `json:"address"` // 产生日志的合约地址
+	Topics  []string `json:"topics"`  // 索引主题
+	Data    string   `json:"data"`    // 非索引数据
+}
+
+// Succeeded 交易是否执行成功
+func (r *EthReceipt) Succeeded() bool {
+	return r.Status == 1
+}
+
+// TransferredTo 汇总回执中指定代币转入recipient的数量
+// 解码token合约发出的Transfer事件，返回nil表示没有匹配的转账
+func (r *EthReceipt) TransferredTo(token, recipient string) *big.Int {
+	var total *big.Int
+	for _, log := range r.Logs {
+		if !strings.EqualFold(log.Address, token) || len(log.Topics) != 3 {
+			continue
+		}
+		if !strings.EqualFold(log.Topics[0], TransferEventTopic) || !topicIsAddress(log.Topics[2], recipient) {
+			continue
+		}
+
+		amount, err := parseHexBig(log.Data)
+		if err != nil {
+			continue
+		}
+		if total == nil {
+			total = new(big.Int)
+		}
+		total.Add(total, amount)
+	}
+	return total
+}
+
+// EthRPCError JSON-RPC错误响应
+type EthRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *EthRPCError) Error() string {
+	return fmt.Sprintf("RPC错误[%d]: %s", e.Code, e.Message)
+}
+
+// ethRPCClient 基于HTTP的JSON-RPC客户端实现
+type ethRPCClient struct {
+	url        string     // RPC节点地址
+	httpClient HTTPClient // HTTP客户端
+	nextID     uint64     // 请求ID计数器
+}
+
+// NewEthRPCClient 创建以太坊JSON-RPC客户端
+func NewEthRPCClient(url string, httpClient HTTPClient) EthRPCClient {
+	return &ethRPCClient{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+// ethRPCRequest JSON-RPC请求格式
+type ethRPCRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// ethRPCResponse JSON-RPC响应格式
+type ethRPCResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *EthRPCError    `json:"error,omitempty"`
+}
+
+// rawEthReceipt 回执原始格式(数值字段为十六进制字符串)
+type rawEthReceipt struct {
+	TransactionHash   string   `json:"transactionHash"`
+	BlockNumber       string   `json:"blockNumber"`
+	Status            string   `json:"status"`
+	GasUsed           string   `json:"gasUsed"`
+	EffectiveGasPrice string   `json:"effectiveGasPrice"`
+	From              string   `json:"from"`
+	To                string   `json:"to"`
+	Logs              []EthLog `json:"logs"`
+}
+
+// BlockNumber 获取最新区块号
+func (c *ethRPCClient) BlockNumber(ctx context.Context) (uint64, error) {
+	var result string
+	if err := c.call(ctx, "eth_blockNumber", nil, &result); err != nil {
+		return 0, err
+	}
+	return parseHexUint64(result)
+}
+
+// GetTransactionReceipt 获取交易回执
+func (c *ethRPCClient) GetTransactionReceipt(ctx context.Context, txHash string) (*EthReceipt, error) {
+	var raw *rawEthReceipt
+	if err := c.call(ctx, "eth_getTransactionReceipt", []interface{}{txHash}, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	receipt := &EthReceipt{
+		TransactionHash: raw.TransactionHash,
+		From:            raw.From,
+		To:              raw.To,
+		Logs:            raw.Logs,
+	}
+
+	var err error
+	if receipt.BlockNumber, err = parseHexUint64(raw.BlockNumber); err != nil {
+		return nil, fmt.Errorf("解析回执区块号失败: %w", err)
+	}
+	if receipt.Status, err = parseHexUint64(raw.Status); err != nil {
+		return nil, fmt.Errorf("解析回执状态失败: %w", err)
+	}
+	if receipt.GasUsed, err = parseHexUint64(raw.GasUsed); err != nil {
+		return nil, fmt.Errorf("解析回执Gas失败: %w", err)
+	}
+	if raw.EffectiveGasPrice != "" {
+		if receipt.EffectiveGasPrice, err = parseHexBig(raw.EffectiveGasPrice); err != nil {
+			return nil, fmt.Errorf("解析回执Gas价格失败: %w", err)
+		}
+	}
+
+	return receipt, nil
+}
+
+// GetBlockTimestamp 获取区块时间
+func (c *ethRPCClient) GetBlockTimestamp(ctx context.Context, blockNumber uint64) (time.Time, error) {
+	var block *struct {
+		Timestamp string `json:"timestamp"`
+	}
+	params := []interface{}{fmt.Sprintf("0x%x", blockNumber), false}
+	if err := c.call(ctx, "eth_getBlockByNumber", params, &block); err != nil {
+		return time.Time{}, err
+	}
+	if block == nil {
+		return time.Time{}, fmt.Errorf("区块不存在: %d", blockNumber)
+	}
+
+	seconds, err := parseHexUint64(block.Timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("解析区块时间失败: %w", err)
+	}
+	return time.Unix(int64(seconds), 0), nil
+}
+
+// call 发送JSON-RPC请求并解析result
+func (c *ethRPCClient) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	request := &ethRPCRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&c.nextID, 1),
+		Method:  method,
+		Params:  params,
+	}
+
+	var response ethRPCResponse
+	if err := c.httpClient.PostJSON(ctx, c.url, request, &response); err != nil {
+		return fmt.Errorf("%s调用失败: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s调用失败: %w", method, response.Error)
+	}
+
+	// 部分节点对不存在的回执省略result字段，按null处理
+	if len(response.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("解析%s结果失败: %w", method, err)
+	}
+	return nil
+}
+
+// ========================================
+// 十六进制解析
+// ========================================
+
+// parseHexUint64 解析0x开头的十六进制数值
+func parseHexUint64(value string) (uint64, error) {
+	if !strings.HasPrefix(value, "0x") {
+		return 0, fmt.Errorf("无效的十六进制数值: %q", value)
+	}
+	return strconv.ParseUint(value[2:], 16, 64)
+}
+
+// parseHexBig 解析0x开头的十六进制大整数
+func parseHexBig(value string) (*big.Int, error) {
+	if !strings.HasPrefix(value, "0x") {
+		return nil, fmt.Errorf("无效的十六进制数值: %q", value)
+	}
+	if value == "0x" {
+		return new(big.Int), nil
+	}
+	n, ok := new(big.Int).SetString(value[2:], 16)
+	if !ok {
+		return nil, fmt.Errorf("无效的十六进制数值: %q", value)
+	}
+	return n, nil
+}
+
+// topicIsAddress 判断32字节主题是否为指定地址(左侧补零)
+func topicIsAddress(topic, address string) bool {
+	if len(topic) != 66 || len(address) != 42 {
+		return false
+	}
+	return strings.EqualFold(topic[26:], address[2:])
+}
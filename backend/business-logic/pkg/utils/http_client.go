@@ -27,6 +27,9 @@ type HTTPClient interface {
 	// JSON便捷方法
 	GetJSON(ctx context.Context, url string, result interface{}) error
 	PostJSON(ctx context.Context, url string, body interface{}, result interface{}) error
+
+	// 流式方法
+	PostStream(ctx context.Context, url string, body interface{}, headers map[string]string) (io.ReadCloser, error)
 }
 
 // DefaultHTTPClient 默认HTTP客户端实现
@@ -109,6 +112,50 @@ func (c *DefaultHTTPClient) PostJSON(ctx context.Context, url string, body inter
 	return json.Unmarshal(responseBody, result)
 }
 
+// ========================================
+// 流式方法实现
+// ========================================
+
+// PostStream 发送POST请求并返回未读取的响应体
+// 用于Server-Sent Events等长连接响应，调用方负责关闭返回的Body
+// 流式请求不做重试，也不受客户端整体超时限制，由ctx控制生命周期
+func (c *DefaultHTTPClient) PostStream(ctx context.Context, url string, body interface{}, headers map[string]string) (io.ReadCloser, error) {
+	var requestBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("序列化请求体失败: %w", err)
+		}
+		requestBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP请求失败: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "DeFi-Aggregator-Business-Logic/1.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	// 复用连接池，但去掉整体超时，避免长连接被提前截断
+	streamClient := &http.Client{Transport: c.client.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP请求失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTP流式请求失败: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	}
+
+	c.logger.Debugf("HTTP流式请求已建立: url=%s, status=%d", url, resp.StatusCode)
+	return resp.Body, nil
+}
+
 // ========================================
 // 核心请求方法
 // ========================================
@@ -24,12 +24,15 @@ import (
 
 // Application 智能路由应用程序
 type Application struct {
-	Config        *types.Config           // 应用配置
-	Cache         cache.CacheManager      // 缓存管理器
-	RouterService *services.RouterService // 路由服务
-	Handler       *handlers.RouterHandler // HTTP处理器
-	Server        *http.Server            // HTTP服务器
-	Logger        *logrus.Logger          // 日志记录器
+	Config        *types.Config                   // 应用配置
+	Cache         cache.CacheManager              // 缓存管理器
+	RouterService *services.RouterService         // 路由服务
+	Reloader      *services.ProviderReloader      // 聚合器配置热加载器(未启用时为nil)
+	ConfigManager *config.AggregatorConfigManager // 数据库聚合器配置管理器(未启用时为nil)
+	MarketData    *config.MarketDataManager       // 行情数据管理器(未启用net_of_gas排序时为nil)
+	Handler       *handlers.RouterHandler         // HTTP处理器
+	Server        *http.Server                    // HTTP服务器
+	Logger        *logrus.Logger                  // 日志记录器
 }
 
 // main 主函数
@@ -61,31 +64,42 @@ func NewApplication() (*Application, error) {
 	logger.Infof("启动DeFi聚合器智能路由服务 - 环境: %s", cfg.Server.Environment)
 
 	// 3. 初始化缓存管理器
-	logger.Info("初始化Redis缓存...")
-	cacheManager, err := cache.NewRedisCache(&cfg.Redis, cfg.Cache.PrefixKey, logger)
+	cacheManager, err := initCache(cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("缓存初始化失败: %w", err)
 	}
 
-	// 4. 初始化智能路由服务
+	// 4. 初始化行情数据源和智能路由服务
+	marketData := initMarketData(cfg, logger)
+
 	logger.Info("初始化智能路由服务...")
-	routerService := services.NewRouterService(cfg, cacheManager, logger)
+	var marketDataSource services.MarketDataSource
+	if marketData != nil {
+		marketDataSource = marketData
+	}
+	routerService, err := services.NewRouterService(cfg, cacheManager, marketDataSource, logger)
+	if err != nil {
+		return nil, fmt.Errorf("智能路由服务初始化失败: %w", err)
+	}
 
-	// 5. 初始化HTTP处理器
+	// 5. 初始化聚合器配置热加载
+	configManager, reloader := initProviderReloader(cfg, routerService, logger)
+
+	// 6. 初始化HTTP处理器
 	logger.Info("初始化HTTP处理器...")
-	routerHandler := handlers.NewRouterHandler(routerService, logger)
+	routerHandler := handlers.NewRouterHandler(routerService, reloader, logger)
 
-	// 6. 设置Gin模式
+	// 7. 设置Gin模式
 	if cfg.Server.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
 	}
 
-	// 7. 创建HTTP路由器
+	// 8. 创建HTTP路由器
 	router := setupRouter(cfg, routerHandler, logger)
 
-	// 8. 创建HTTP服务器
+	// 9. 创建HTTP服务器
 	server := &http.Server{
 		Addr:           fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:        router,
@@ -99,6 +113,9 @@ func NewApplication() (*Application, error) {
 		Config:        cfg,
 		Cache:         cacheManager,
 		RouterService: routerService,
+		Reloader:      reloader,
+		ConfigManager: configManager,
+		MarketData:    marketData,
 		Handler:       routerHandler,
 		Server:        server,
 		Logger:        logger,
@@ -111,14 +128,24 @@ func (app *Application) Run() error {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	// 启动聚合器配置热加载
+	if app.Reloader != nil {
+		app.Reloader.Start()
+	}
+
 	// 在goroutine中启动HTTP服务器
 	go func() {
 		app.Logger.Infof("智能路由服务启动，监听端口: %s", app.Server.Addr)
 		app.Logger.Infof("服务器地址: http://localhost%s", app.Server.Addr)
 		app.Logger.Info("API接口:")
 		app.Logger.Info("  报价聚合: POST http://localhost:5178/api/v1/quote")
+		app.Logger.Info("  流式报价: POST http://localhost:5178/api/v1/quote/stream")
+		app.Logger.Info("  交易构建: POST http://localhost:5178/api/v1/swap")
 		app.Logger.Info("  健康检查: GET  http://localhost:5178/health")
 		app.Logger.Info("  性能指标: GET  http://localhost:5178/api/v1/metrics")
+		if app.Config.Server.AdminToken != "" {
+			app.Logger.Info("  配置重载: POST http://localhost:5178/api/v1/admin/providers/reload")
+		}
 
 		if err := app.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			app.Logger.Fatalf("HTTP服务器启动失败: %v", err)
@@ -147,6 +174,22 @@ func (app *Application) Shutdown() error {
 		return err
 	}
 
+	// 停止聚合器配置热加载
+	if app.Reloader != nil {
+		app.Reloader.Stop()
+	}
+	if app.ConfigManager != nil {
+		if err := app.ConfigManager.Close(); err != nil {
+			app.Logger.Warnf("聚合器配置数据库连接关闭失败: %v", err)
+		}
+	}
+
+	if app.MarketData != nil {
+		if err := app.MarketData.Close(); err != nil {
+			app.Logger.Warnf("行情数据数据库连接关闭失败: %v", err)
+		}
+	}
+
 	app.Logger.Info("正在关闭缓存连接...")
 
 	// 关闭缓存连接
@@ -186,6 +229,49 @@ func initLogger(cfg *types.Config) *logrus.Logger {
 	return logger
 }
 
+// initCache 根据配置初始化缓存管理器
+// 生产环境使用Redis，本地开发和测试可使用进程内LRU缓存
+func initCache(cfg *types.Config, logger *logrus.Logger) (cache.CacheManager, error) {
+	if cfg.Cache.Backend == cache.BackendMemory {
+		logger.Info("初始化内存LRU缓存...")
+		return cache.NewMemoryCache(cfg.Cache.MaxEntries, cfg.Cache.CleanupInterval, cfg.Cache.PrefixKey, logger), nil
+	}
+
+	logger.Info("初始化Redis缓存...")
+	return cache.NewRedisCache(&cfg.Redis, cfg.Cache.PrefixKey, logger)
+}
+
+// initProviderReloader 初始化聚合器配置热加载
+// 未启用或数据库不可用时返回nil，服务继续使用启动时的聚合器配置
+func initProviderReloader(cfg *types.Config, routerService *services.RouterService, logger *logrus.Logger) (*config.AggregatorConfigManager, *services.ProviderReloader) {
+	if !cfg.ProviderReload.Enabled {
+		return nil, nil
+	}
+
+	logger.Info("初始化聚合器配置热加载...")
+	configManager, err := config.NewAggregatorConfigManager(config.DatabaseURL(), logger)
+	if err != nil {
+		logger.Warnf("创建聚合器配置管理器失败: %v，热加载未启用", err)
+		return nil, nil
+	}
+
+	return configManager, services.NewProviderReloader(configManager, routerService, cfg.ProviderReload.Interval, logger)
+}
+
+// initMarketData 初始化行情数据管理器
+// 供net_of_gas排序策略使用(请求可单独指定该策略，因此始终尝试初始化)；
+// 数据库不可用时返回nil，net_of_gas请求回退到weighted策略
+func initMarketData(cfg *types.Config, logger *logrus.Logger) *config.MarketDataManager {
+	logger.Info("初始化行情数据管理器...")
+	marketData, err := config.NewMarketDataManager(config.DatabaseURL(), &cfg.Ranking, logger)
+	if err != nil {
+		logger.Warnf("创建行情数据管理器失败: %v，net_of_gas排序策略不可用", err)
+		return nil
+	}
+
+	return marketData
+}
+
 // setupRouter 设置HTTP路由器
 func setupRouter(cfg *types.Config, handler *handlers.RouterHandler, logger *logrus.Logger) *gin.Engine {
 	router := gin.New()
@@ -216,12 +302,20 @@ func setupRouter(cfg *types.Config, handler *handlers.RouterHandler, logger *log
 	{
 		// 核心聚合接口
 		v1.POST("/quote", handler.GetQuote)
+		v1.POST("/quote/stream", handler.StreamQuote)
+		v1.POST("/swap", handler.BuildSwap)
 
 		// 监控接口
 		if cfg.Monitoring.MetricsEnabled {
 			v1.GET("/metrics", handler.GetMetrics)
 			v1.GET("/providers/status", handler.GetProviderStatus)
 		}
+
+		// 管理接口（需配置ADMIN_TOKEN）
+		if cfg.Server.AdminToken != "" {
+			admin := v1.Group("/admin", handler.AdminAuth(cfg.Server.AdminToken))
+			admin.POST("/providers/reload", handler.ReloadProviders)
+		}
 	}
 
 	// 404处理
@@ -0,0 +1,172 @@
+// Package adapters 合约调用ABI编解码
+// 直连DEX适配器只调用少量固定签名的合约只读方法，这里实现所需的最小ABI编解码：
+// address/uint256/bytes/address[]参数编码，以及uint256/address/uint256[]返回值解码
+package adapters
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// abiWordSize ABI编码的字长(字节)
+const abiWordSize = 32
+
+// abiArg ABI编码的调用参数
+// 静态类型直接写入head；动态类型在head中写入偏移量，内容写入tail
+type abiArg struct {
+	word    []byte // 静态参数编码(32字节)
+	tail    []byte // 动态参数内容编码
+	dynamic bool   // 是否为动态类型
+}
+
+// selector 将十六进制函数选择器转换为字节
+func selector(hexSelector string) []byte {
+	data, err := hex.DecodeString(hexSelector)
+	if err != nil || len(data) != 4 {
+		panic(fmt.Sprintf("无效的函数选择器: %s", hexSelector))
+	}
+	return data
+}
+
+// parseAddress 解析0x前缀的20字节地址
+func parseAddress(address string) ([]byte, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	data, err := hex.DecodeString(trimmed)
+	if err != nil || len(data) != 20 {
+		return nil, fmt.Errorf("无效的地址: %s", address)
+	}
+	return data, nil
+}
+
+// padWord 将数据左侧补零为32字节
+func padWord(data []byte) []byte {
+	word := make([]byte, abiWordSize)
+	copy(word[abiWordSize-len(data):], data)
+	return word
+}
+
+// abiUint 编码uint参数
+func abiUint(value *big.Int) abiArg {
+	return abiArg{word: padWord(value.Bytes())}
+}
+
+// abiAddress 编码address参数
+func abiAddress(address string) (abiArg, error) {
+	data, err := parseAddress(address)
+	if err != nil {
+		return abiArg{}, err
+	}
+	return abiArg{word: padWord(data)}, nil
+}
+
+// abiBytes 编码bytes参数(长度 + 右侧补零到整字的内容)
+func abiBytes(data []byte) abiArg {
+	padded := make([]byte, (len(data)+abiWordSize-1)/abiWordSize*abiWordSize)
+	copy(padded, data)
+	tail := append(padWord(big.NewInt(int64(len(data))).Bytes()), padded...)
+	return abiArg{tail: tail, dynamic: true}
+}
+
+// abiAddressArray 编码address[]参数(长度 + 各地址)
+func abiAddressArray(addresses []string) (abiArg, error) {
+	tail := padWord(big.NewInt(int64(len(addresses))).Bytes())
+	for _, address := range addresses {
+		data, err := parseAddress(address)
+		if err != nil {
+			return abiArg{}, err
+		}
+		tail = append(tail, padWord(data)...)
+	}
+	return abiArg{tail: tail, dynamic: true}, nil
+}
+
+// encodeCall 编码合约调用数据：函数选择器 + head(静态参数与动态参数偏移量) + tail(动态参数内容)
+func encodeCall(sel []byte, args ...abiArg) []byte {
+	head := make([]byte, 0, len(args)*abiWordSize)
+	var tail []byte
+	for _, arg := range args {
+		if !arg.dynamic {
+			head = append(head, arg.word...)
+			continue
+		}
+		offset := big.NewInt(int64(len(args)*abiWordSize + len(tail)))
+		head = append(head, padWord(offset.Bytes())...)
+		tail = append(tail, arg.tail...)
+	}
+
+	data := make([]byte, 0, len(sel)+len(head)+len(tail))
+	data = append(data, sel...)
+	data = append(data, head...)
+	return append(data, tail...)
+}
+
+// abiWord 读取返回数据中第index个字
+func abiWord(data []byte, index int) ([]byte, error) {
+	start := index * abiWordSize
+	if index < 0 || start+abiWordSize > len(data) {
+		return nil, fmt.Errorf("返回数据长度不足: %d字节, 读取第%d个字", len(data), index)
+	}
+	return data[start : start+abiWordSize], nil
+}
+
+// decodeUint 解码返回数据中第index个字为uint
+func decodeUint(data []byte, index int) (*big.Int, error) {
+	word, err := abiWord(data, index)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(word), nil
+}
+
+// decodeAddress 解码返回数据中第index个字为address(小写十六进制)
+func decodeAddress(data []byte, index int) (string, error) {
+	word, err := abiWord(data, index)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(word[abiWordSize-20:]), nil
+}
+
+// decodeUintArray 解码返回数据中第index个字(偏移量)指向的uint[]
+func decodeUintArray(data []byte, index int) ([]*big.Int, error) {
+	offset, err := decodeUint(data, index)
+	if err != nil {
+		return nil, err
+	}
+	if !offset.IsInt64() || offset.Int64()%abiWordSize != 0 {
+		return nil, fmt.Errorf("无效的数组偏移量: %s", offset.String())
+	}
+	start := int(offset.Int64() / abiWordSize)
+
+	length, err := decodeUint(data, start)
+	if err != nil {
+		return nil, err
+	}
+	if !length.IsInt64() || length.Int64() > int64(len(data)/abiWordSize) {
+		return nil, fmt.Errorf("无效的数组长度: %s", length.String())
+	}
+
+	values := make([]*big.Int, length.Int64())
+	for i := range values {
+		if values[i], err = decodeUint(data, start+1+i); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// isZeroAddress 判断地址是否为零地址(工厂合约对不存在的池子返回零地址)
+func isZeroAddress(address string) bool {
+	data, err := parseAddress(address)
+	if err != nil {
+		return true
+	}
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
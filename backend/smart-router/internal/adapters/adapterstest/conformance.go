@@ -0,0 +1,609 @@
+// Package adapterstest 适配器一致性测试套件
+// 验证适配器在假聚合器服务器上的行为：请求URL、参数和认证请求头、大额数量的无损传递、买入报价参数、原生代币地址转换、
+// 错误响应到失败报价(ProviderQuote)的映射、重试策略、超时控制以及链支持检查
+package adapterstest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"defi-aggregator/smart-router/internal/adapters"
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// 测试用的链和代币
+const (
+	SupportedChainID   uint = 1  // 适配器配置支持的链
+	UnsupportedChainID uint = 56 // 适配器配置不支持的链
+
+	WETHAddress = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2" // 以太坊WETH
+	DAIAddress  = "0x6B175474E89094C44Da98b954EedeAC495271d0F" // 以太坊DAI
+	UserAddress = "0x1111111111111111111111111111111111111111" // 测试用户地址
+)
+
+// slowTimeout 超时场景下的超时时间，slowDeadline 超时场景允许的最长耗时
+// slow场景的录制响应延迟远大于slowDeadline，适配器必须在超时后放弃等待
+const (
+	slowTimeout  = 200 * time.Millisecond
+	slowDeadline = 1500 * time.Millisecond
+)
+
+// ProviderSpec 被测适配器的描述
+type ProviderSpec struct {
+	Name       string                                                                             // 聚合器名称，同时是fixture目录名
+	NewAdapter func(config *types.ProviderConfig, logger *logrus.Logger) adapters.ProviderAdapter // 适配器构造函数
+	APIKey     string                                                                             // 配置的API密钥，只能出现在Headers列出的请求头中
+
+	// 报价请求期望
+	Method         string            // HTTP方法
+	Path           string            // 请求路径(SupportedChainID上)
+	FromTokenParam string            // 输入代币参数名
+	ToTokenParam   string            // 输出代币参数名
+	AmountParam    string            // 输入数量参数名
+	Params         map[string]string // 其他参数的期望值
+	Headers        map[string]string // 请求头的期望值(包括API密钥认证请求头)
+
+	// 买入(exact-output)报价请求期望，BuyAmountParam为空表示适配器不支持买入报价
+	BuyAmountParam string            // 期望输出数量参数名
+	BuyParams      map[string]string // 买入报价其他参数的期望值(覆盖Params中的同名参数)
+
+	// 卖出原生代币(types.NativeTokenAddress)报价请求期望，NativeSellToken为空表示原样传递规范地址
+	NativeSellToken      string            // 输入代币参数的期望值(如包装代币地址)，需同时在路径中返回包装步骤
+	NativeSellParams     map[string]string // 其他参数的期望值(覆盖Params中的同名参数)
+	NativeBuyUnsupported bool              // 以原生代币为输入的买入报价是否被CheckTokens排除
+
+	// 录制响应对应的期望结果
+	SuccessAmountIn    string // success场景的输入数量
+	SuccessAmountOut   string // success场景的输出数量
+	SuccessGasEstimate uint64 // success场景的Gas估算
+	ClientErrorMessage string // client_error场景的错误信息中应包含的内容
+}
+
+// RunConformance 运行适配器一致性测试
+func RunConformance(t *testing.T, spec ProviderSpec) {
+	t.Run("IsSupported", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, _ := newConformanceAdapter(t, spec, nil)
+
+		if !adapter.IsSupported(SupportedChainID) {
+			t.Errorf("IsSupported(%d) = false, 期望 true", SupportedChainID)
+		}
+		if adapter.IsSupported(UnsupportedChainID) {
+			t.Errorf("IsSupported(%d) = true, 期望 false", UnsupportedChainID)
+		}
+		if adapter.GetName() != spec.Name {
+			t.Errorf("GetName() = %q, 期望 %q", adapter.GetName(), spec.Name)
+		}
+	})
+
+	t.Run("UnsupportedChain", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		req := newQuoteRequest()
+		req.ChainID = UnsupportedChainID
+
+		quote, err := adapter.GetQuote(context.Background(), req)
+
+		// 不支持的链可以返回UNSUPPORTED_CHAIN错误，也可以返回同错误码的失败报价
+		var routerErr *types.RouterError
+		switch {
+		case err != nil:
+			if !errors.As(err, &routerErr) || routerErr.Code != types.ErrCodeUnsupportedChain {
+				t.Errorf("错误 = %v, 期望 %s", err, types.ErrCodeUnsupportedChain)
+			}
+		case quote == nil:
+			t.Fatal("报价和错误均为nil")
+		default:
+			if quote.Success || quote.ErrorCode != types.ErrCodeUnsupportedChain {
+				t.Errorf("报价 success=%t errorCode=%q, 期望失败且错误码为 %s",
+					quote.Success, quote.ErrorCode, types.ErrCodeUnsupportedChain)
+			}
+		}
+		if n := len(server.Requests()); n != 0 {
+			t.Errorf("不支持的链仍发送了 %d 个请求", n)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		req := newQuoteRequest()
+
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		if quote.Provider != spec.Name {
+			t.Errorf("Provider = %q, 期望 %q", quote.Provider, spec.Name)
+		}
+		if expected := decimal.RequireFromString(spec.SuccessAmountIn); !quote.AmountIn.Equal(expected) {
+			t.Errorf("AmountIn = %s, 期望 %s", quote.AmountIn, expected)
+		}
+		if expected := decimal.RequireFromString(spec.SuccessAmountOut); !quote.AmountOut.Equal(expected) {
+			t.Errorf("AmountOut = %s, 期望 %s", quote.AmountOut, expected)
+		}
+		if quote.MaxAmountIn != nil {
+			t.Errorf("卖出报价MaxAmountIn = %s, 期望为空", quote.MaxAmountIn)
+		}
+		if quote.GasEstimate != spec.SuccessGasEstimate {
+			t.Errorf("GasEstimate = %d, 期望 %d", quote.GasEstimate, spec.SuccessGasEstimate)
+		}
+		if !quote.Confidence.IsPositive() || quote.Confidence.GreaterThan(decimal.NewFromInt(1)) {
+			t.Errorf("Confidence = %s, 期望在(0, 1]范围内", quote.Confidence)
+		}
+		if !quote.PriceImpact.IsZero() {
+			t.Errorf("PriceImpact = %s, 适配器不应自行计算价格冲击", quote.PriceImpact)
+		}
+		if quote.RawResponse == nil {
+			t.Error("RawResponse为空, 成功报价应保留原始响应用于报价溯源")
+		}
+
+		requests := server.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("收到 %d 个请求, 期望 1 个", len(requests))
+		}
+		assertQuoteRequest(t, spec, &requests[0], req)
+		assertAuthHeaders(t, spec, &requests[0])
+	})
+
+	t.Run("LargeAmount", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		req := newQuoteRequest()
+		// 超出uint64范围的数量必须按十进制整数原样传递，不能出现精度丢失或科学计数法
+		req.AmountIn = decimal.RequireFromString("123456789012345678901234567890")
+
+		mustGetQuote(t, adapter, context.Background(), req)
+
+		requests := server.Requests()
+		if len(requests) == 0 {
+			t.Fatal("没有收到请求")
+		}
+		if got := requests[0].Param(spec.AmountParam); got != "123456789012345678901234567890" {
+			t.Errorf("%s = %q, 期望 %q", spec.AmountParam, got, "123456789012345678901234567890")
+		}
+	})
+
+	t.Run("ExactOutput", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		req := newQuoteRequest()
+		req.Side = types.QuoteSideBuy
+		req.AmountIn = decimal.Zero
+		req.AmountOut = decimal.RequireFromString(spec.SuccessAmountOut)
+
+		if spec.BuyAmountParam == "" {
+			if adapter.SupportsQuoteSide(types.QuoteSideBuy) {
+				t.Fatal("SupportsQuoteSide(buy) = true, 期望 false")
+			}
+			quote, err := adapter.GetQuote(context.Background(), req)
+			var routerErr *types.RouterError
+			if err == nil && (quote == nil || quote.Success || quote.ErrorCode != types.ErrCodeUnsupportedQuoteSide) {
+				t.Errorf("不支持的买入报价应返回 %s", types.ErrCodeUnsupportedQuoteSide)
+			} else if err != nil && (!errors.As(err, &routerErr) || routerErr.Code != types.ErrCodeUnsupportedQuoteSide) {
+				t.Errorf("错误 = %v, 期望 %s", err, types.ErrCodeUnsupportedQuoteSide)
+			}
+			if n := len(server.Requests()); n != 0 {
+				t.Errorf("不支持的买入报价仍发送了 %d 个请求", n)
+			}
+			return
+		}
+
+		if !adapter.SupportsQuoteSide(types.QuoteSideBuy) {
+			t.Fatal("SupportsQuoteSide(buy) = false, 期望 true")
+		}
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		amountIn := decimal.RequireFromString(spec.SuccessAmountIn)
+		if !quote.AmountIn.Equal(amountIn) {
+			t.Errorf("AmountIn = %s, 期望 %s", quote.AmountIn, amountIn)
+		}
+		// 最大输入数量 = 所需输入 × (1 + 滑点)，向上取整
+		expectedMax := amountIn.Mul(decimal.NewFromInt(1).Add(req.Slippage)).Ceil()
+		if quote.MaxAmountIn == nil || !quote.MaxAmountIn.Equal(expectedMax) {
+			t.Errorf("MaxAmountIn = %v, 期望 %s", quote.MaxAmountIn, expectedMax)
+		}
+
+		requests := server.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("收到 %d 个请求, 期望 1 个", len(requests))
+		}
+		recorded := &requests[0]
+		expected := map[string]string{
+			spec.FromTokenParam: req.FromToken,
+			spec.ToTokenParam:   req.ToToken,
+			spec.BuyAmountParam: req.AmountOut.String(),
+		}
+		for name, value := range spec.Params {
+			expected[name] = value
+		}
+		for name, value := range spec.BuyParams {
+			expected[name] = value
+		}
+		for name, value := range expected {
+			if got := recorded.Param(name); got != value {
+				t.Errorf("参数 %s = %q, 期望 %q", name, got, value)
+			}
+		}
+		if spec.AmountParam != spec.BuyAmountParam && recorded.Param(spec.AmountParam) != "" {
+			t.Errorf("买入报价不应发送输入数量参数 %s", spec.AmountParam)
+		}
+	})
+
+	t.Run("NativeToken", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		req := newQuoteRequest()
+		req.FromToken = types.NativeTokenAddress
+
+		if err := adapter.CheckTokens(req); err != nil {
+			t.Fatalf("CheckTokens(卖出原生代币) = %v, 期望支持", err)
+		}
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+
+		requests := server.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("收到 %d 个请求, 期望 1 个", len(requests))
+		}
+		sellToken := types.NativeTokenAddress
+		if spec.NativeSellToken != "" {
+			sellToken = spec.NativeSellToken
+			if len(quote.Route) == 0 || quote.Route[0].Protocol != types.RouteProtocolWrapNative {
+				t.Errorf("Route = %+v, 期望以 %s 步骤开始", quote.Route, types.RouteProtocolWrapNative)
+			}
+		}
+		expected := map[string]string{
+			spec.FromTokenParam: sellToken,
+			spec.ToTokenParam:   req.ToToken,
+			spec.AmountParam:    req.AmountIn.String(),
+		}
+		for name, value := range spec.Params {
+			expected[name] = value
+		}
+		for name, value := range spec.NativeSellParams {
+			expected[name] = value
+		}
+		for name, value := range expected {
+			if got := requests[0].Param(name); got != value {
+				t.Errorf("参数 %s = %q, 期望 %q", name, got, value)
+			}
+		}
+
+		if spec.BuyAmountParam == "" {
+			return
+		}
+		req.Side = types.QuoteSideBuy
+		req.AmountOut = decimal.RequireFromString(spec.SuccessAmountOut)
+		err := adapter.CheckTokens(req)
+		var routerErr *types.RouterError
+		switch {
+		case !spec.NativeBuyUnsupported && err != nil:
+			t.Errorf("CheckTokens(原生代币买入报价) = %v, 期望支持", err)
+		case spec.NativeBuyUnsupported && (!errors.As(err, &routerErr) || routerErr.Code != types.ErrCodeNativeTokenUnsupported):
+			t.Errorf("CheckTokens(原生代币买入报价) = %v, 期望 %s", err, types.ErrCodeNativeTokenUnsupported)
+		case spec.NativeBuyUnsupported:
+			if _, err := adapter.GetQuote(context.Background(), req); err == nil {
+				t.Error("不支持的原生代币报价GetQuote应返回错误")
+			}
+			if n := len(server.Requests()); n != 1 {
+				t.Errorf("不支持的原生代币报价仍发送了 %d 个请求", n-1)
+			}
+		}
+	})
+
+	t.Run("ClientError", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		server.Use(ScenarioClientError)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertFailedQuote(t, spec, quote)
+		if !strings.Contains(quote.ErrorMessage, spec.ClientErrorMessage) {
+			t.Errorf("ErrorMessage = %q, 期望包含 %q", quote.ErrorMessage, spec.ClientErrorMessage)
+		}
+		// 4xx属于请求本身的问题，不应重试
+		if n := len(server.Requests()); n != 1 {
+			t.Errorf("4xx响应后发送了 %d 个请求, 期望 1 个", n)
+		}
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		server.Use(ScenarioServerError)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertFailedQuote(t, spec, quote)
+		requests := server.Requests()
+		if expected := adapter.GetConfig().RetryCount + 1; len(requests) != expected {
+			t.Fatalf("5xx响应后发送了 %d 个请求, 期望 %d 个", len(requests), expected)
+		}
+		// 每次重试都必须发送完整的请求体
+		for i, recorded := range requests[1:] {
+			if string(recorded.Body) != string(requests[0].Body) {
+				t.Errorf("第 %d 次重试的请求体 = %q, 期望 %q", i+1, recorded.Body, requests[0].Body)
+			}
+		}
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		server.UseSequence(ScenarioRateLimited, ScenarioSuccess)
+
+		startTime := time.Now()
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+		elapsed := time.Since(startTime)
+
+		if !quote.Success {
+			t.Fatalf("限流后重试的报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		if n := len(server.Requests()); n != 2 {
+			t.Errorf("收到 %d 个请求, 期望 2 个", n)
+		}
+		// rate_limited场景的Retry-After为1秒
+		if elapsed < time.Second {
+			t.Errorf("限流后 %v 即重试, 期望遵守Retry-After等待1秒", elapsed)
+		}
+	})
+
+	t.Run("RetryAfterTooLong", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, func(config *types.ProviderConfig) {
+			config.Retry.MaxRetryAfter = 500 * time.Millisecond
+		})
+		server.UseSequence(ScenarioRateLimited, ScenarioSuccess)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertFailedQuote(t, spec, quote)
+		if n := len(server.Requests()); n != 1 {
+			t.Errorf("Retry-After超过上限时发送了 %d 个请求, 期望 1 个", n)
+		}
+	})
+
+	t.Run("RetryBudget", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, func(config *types.ProviderConfig) {
+			config.Retry.InitialBackoff = time.Second
+			config.Retry.MaxBackoff = time.Second
+			config.Retry.Jitter = 0
+		})
+		server.Use(ScenarioServerError)
+
+		ctx, cancel := context.WithTimeout(context.Background(), slowTimeout)
+		defer cancel()
+
+		startTime := time.Now()
+		quote := mustGetQuote(t, adapter, ctx, newQuoteRequest())
+		elapsed := time.Since(startTime)
+
+		assertFailedQuote(t, spec, quote)
+		// 退避等待超过剩余时间，不应等待也不应重试
+		if n := len(server.Requests()); n != 1 {
+			t.Errorf("发送了 %d 个请求, 期望 1 个", n)
+		}
+		if elapsed > slowTimeout {
+			t.Errorf("耗时 %v, 期望在上下文超时 %v 前返回", elapsed, slowTimeout)
+		}
+	})
+
+	t.Run("MalformedJSON", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		server.Use(ScenarioMalformed)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertFailedQuote(t, spec, quote)
+	})
+
+	t.Run("ContextTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, nil)
+		server.Use(ScenarioSlow)
+
+		ctx, cancel := context.WithTimeout(context.Background(), slowTimeout)
+		defer cancel()
+
+		startTime := time.Now()
+		quote := mustGetQuote(t, adapter, ctx, newQuoteRequest())
+		elapsed := time.Since(startTime)
+
+		assertFailedQuote(t, spec, quote)
+		if elapsed > slowDeadline {
+			t.Errorf("上下文超时 %v 后耗时 %v, 期望不超过 %v", slowTimeout, elapsed, slowDeadline)
+		}
+	})
+
+	t.Run("ConfigTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, server := newConformanceAdapter(t, spec, func(config *types.ProviderConfig) {
+			config.Timeout = slowTimeout
+			config.RetryCount = 0
+		})
+		server.Use(ScenarioSlow)
+
+		startTime := time.Now()
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+		elapsed := time.Since(startTime)
+
+		assertFailedQuote(t, spec, quote)
+		if elapsed > slowDeadline {
+			t.Errorf("配置超时 %v 后耗时 %v, 期望不超过 %v", slowTimeout, elapsed, slowDeadline)
+		}
+	})
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// newConformanceAdapter 创建指向假服务器的适配器
+// mutate可在创建前调整配置
+func newConformanceAdapter(t *testing.T, spec ProviderSpec, mutate func(*types.ProviderConfig)) (adapters.ProviderAdapter, *FakeProvider) {
+	t.Helper()
+
+	server := NewFakeProvider(t, spec.Name)
+	config := &types.ProviderConfig{
+		Name:            spec.Name,
+		DisplayName:     spec.Name,
+		BaseURL:         server.URL(),
+		APIKey:          spec.APIKey,
+		Timeout:         2 * time.Second,
+		RetryCount:      2,
+		Priority:        1,
+		Weight:          decimal.NewFromInt(1),
+		IsActive:        true,
+		SupportedChains: []uint{SupportedChainID, 137},
+		Retry: types.RetryPolicy{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     50 * time.Millisecond,
+			Multiplier:     2,
+			Jitter:         0.2,
+			MaxRetryAfter:  2 * time.Second,
+		},
+	}
+	if mutate != nil {
+		mutate(config)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return spec.NewAdapter(config, logger), server
+}
+
+// newQuoteRequest 创建标准测试报价请求：1 WETH -> DAI
+func newQuoteRequest() *types.QuoteRequest {
+	return &types.QuoteRequest{
+		RequestID:   "conformance",
+		FromToken:   WETHAddress,
+		ToToken:     DAIAddress,
+		AmountIn:    decimal.RequireFromString("1000000000000000000"),
+		ChainID:     SupportedChainID,
+		Slippage:    decimal.RequireFromString("0.005"),
+		UserAddress: UserAddress,
+	}
+}
+
+// mustGetQuote 获取报价，聚合器响应异常时适配器必须返回失败报价而不是错误
+func mustGetQuote(t *testing.T, adapter adapters.ProviderAdapter, ctx context.Context, req *types.QuoteRequest) *types.ProviderQuote {
+	t.Helper()
+
+	quote, err := adapter.GetQuote(ctx, req)
+	if err != nil {
+		t.Fatalf("GetQuote返回错误: %v, 期望返回失败报价", err)
+	}
+	if quote == nil {
+		t.Fatal("GetQuote返回nil报价")
+	}
+	return quote
+}
+
+// assertQuoteRequest 检查发送到聚合器的报价请求
+func assertQuoteRequest(t *testing.T, spec ProviderSpec, recorded *RecordedRequest, req *types.QuoteRequest) {
+	t.Helper()
+
+	if recorded.Method != spec.Method {
+		t.Errorf("Method = %s, 期望 %s", recorded.Method, spec.Method)
+	}
+	if recorded.Path != spec.Path {
+		t.Errorf("Path = %s, 期望 %s", recorded.Path, spec.Path)
+	}
+
+	expected := map[string]string{
+		spec.FromTokenParam: req.FromToken,
+		spec.ToTokenParam:   req.ToToken,
+		spec.AmountParam:    req.AmountIn.String(),
+	}
+	for name, value := range spec.Params {
+		expected[name] = value
+	}
+	for name, value := range expected {
+		if got := recorded.Param(name); got != value {
+			t.Errorf("参数 %s = %q, 期望 %q", name, got, value)
+		}
+	}
+
+	for name, value := range spec.Headers {
+		if got := recorded.Header.Get(name); got != value {
+			t.Errorf("请求头 %s = %q, 期望 %q", name, got, value)
+		}
+	}
+}
+
+// assertAuthHeaders 检查API密钥只出现在期望的认证请求头中
+func assertAuthHeaders(t *testing.T, spec ProviderSpec, recorded *RecordedRequest) {
+	t.Helper()
+
+	if spec.APIKey == "" {
+		return
+	}
+
+	expected := make(map[string]bool, len(spec.Headers))
+	for name := range spec.Headers {
+		expected[http.CanonicalHeaderKey(name)] = true
+	}
+	for name, values := range recorded.Header {
+		if expected[name] {
+			continue
+		}
+		for _, value := range values {
+			if strings.Contains(value, spec.APIKey) {
+				t.Errorf("API密钥出现在非预期的请求头 %s 中", name)
+			}
+		}
+	}
+}
+
+// assertFailedQuote 检查失败报价的公共字段
+func assertFailedQuote(t *testing.T, spec ProviderSpec, quote *types.ProviderQuote) {
+	t.Helper()
+
+	if quote.Success {
+		t.Fatalf("报价成功: amountOut=%s, 期望失败", quote.AmountOut)
+	}
+	if quote.Provider != spec.Name {
+		t.Errorf("Provider = %q, 期望 %q", quote.Provider, spec.Name)
+	}
+	if quote.ErrorCode == "" {
+		t.Error("失败报价缺少ErrorCode")
+	}
+	if quote.ErrorMessage == "" {
+		t.Error("失败报价缺少ErrorMessage")
+	}
+	if !quote.AmountOut.IsZero() {
+		t.Errorf("失败报价AmountOut = %s, 期望 0", quote.AmountOut)
+	}
+}
@@ -0,0 +1,432 @@
+// Package adapterstest 直连DEX适配器一致性测试套件
+// 验证直连DEX适配器在假链节点和假Uniswap合约上的行为：路径和手续费档位选择、经中间代币的两跳路径、
+// 买入报价、原生代币包装、无流动性和RPC节点错误到失败报价的映射以及链支持检查
+package adapterstest
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"defi-aggregator/smart-router/internal/adapters"
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// USDCAddress 以太坊USDC(6位小数)，用于经WETH的两跳路径
+const USDCAddress = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+// DEXSpec 被测直连DEX适配器的描述
+type DEXSpec struct {
+	Name       string                                                                             // 适配器名称
+	NewAdapter func(config *types.ProviderConfig, logger *logrus.Logger) adapters.ProviderAdapter // 适配器构造函数
+	Version    int                                                                                // 假Uniswap合约版本(2或3)
+	Protocol   string                                                                             // 路径步骤的协议名称
+	FeeTiers   bool                                                                               // 是否探索多个手续费档位
+}
+
+// RunDEXConformance 运行直连DEX适配器一致性测试
+func RunDEXConformance(t *testing.T, spec DEXSpec) {
+	t.Run("IsSupported", func(t *testing.T) {
+		t.Parallel()
+
+		// 只配置链1的RPC节点，BaseURL为空
+		fake := NewFakeUniswap(t, spec.Version)
+		adapter := newDEXAdapter(t, spec, fake, func(config *types.ProviderConfig) {
+			config.BaseURL = ""
+			config.DEX.RPCURLs = map[uint]string{SupportedChainID: fake.URL()}
+		})
+
+		if !adapter.IsSupported(SupportedChainID) {
+			t.Errorf("IsSupported(%d) = false, 期望 true", SupportedChainID)
+		}
+		if adapter.IsSupported(UnsupportedChainID) {
+			t.Errorf("IsSupported(%d) = true, 期望 false", UnsupportedChainID)
+		}
+		if adapter.IsSupported(137) {
+			t.Error("IsSupported(137) = true, 没有RPC节点的链期望 false")
+		}
+		if adapter.GetName() != spec.Name {
+			t.Errorf("GetName() = %q, 期望 %q", adapter.GetName(), spec.Name)
+		}
+		if err := adapter.HealthCheck(context.Background()); err != nil {
+			t.Errorf("HealthCheck() = %v, 期望 nil", err)
+		}
+	})
+
+	t.Run("UnsupportedChain", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		adapter := newDEXAdapter(t, spec, fake, nil)
+		req := newQuoteRequest()
+		req.ChainID = UnsupportedChainID
+
+		_, err := adapter.GetQuote(context.Background(), req)
+
+		var routerErr *types.RouterError
+		if !errors.As(err, &routerErr) || routerErr.Code != types.ErrCodeUnsupportedChain {
+			t.Errorf("错误 = %v, 期望 %s", err, types.ErrCodeUnsupportedChain)
+		}
+		if n := fake.RPC().Requests(); n != 0 {
+			t.Errorf("不支持的链仍发送了 %d 个请求", n)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		best := fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		if spec.FeeTiers {
+			// 手续费更低但价格更差的池子不应被选中
+			fake.AddPool(WETHAddress, DAIAddress, 500, "2990")
+		}
+		adapter := newDEXAdapter(t, spec, fake, nil)
+		req := newQuoteRequest()
+
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		if quote.Provider != spec.Name {
+			t.Errorf("Provider = %q, 期望 %q", quote.Provider, spec.Name)
+		}
+		if !quote.AmountIn.Equal(req.AmountIn) {
+			t.Errorf("AmountIn = %s, 期望 %s", quote.AmountIn, req.AmountIn)
+		}
+		expected := fake.AmountOut(req.AmountIn.BigInt(), []string{WETHAddress, DAIAddress}, []*FakePool{best})
+		assertAmount(t, "AmountOut", quote.AmountOut, expected)
+		if quote.MaxAmountIn != nil {
+			t.Errorf("卖出报价MaxAmountIn = %s, 期望为空", quote.MaxAmountIn)
+		}
+		if quote.GasEstimate == 0 {
+			t.Error("GasEstimate = 0, 期望大于0")
+		}
+		if quote.RawResponse == nil {
+			t.Error("RawResponse为空, 成功报价应保留原始数据用于报价溯源")
+		}
+		if !quote.Confidence.IsPositive() || quote.Confidence.GreaterThan(decimal.NewFromInt(1)) {
+			t.Errorf("Confidence = %s, 期望在(0, 1]范围内", quote.Confidence)
+		}
+		assertRoute(t, spec, quote.Route, best)
+
+		// 查询池子和报价各一个批量请求
+		if n := fake.RPC().Requests(); n != 2 {
+			t.Errorf("发送了 %d 个请求, 期望 2 个批量请求", n)
+		}
+	})
+
+	t.Run("MultiHop", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		first := fake.AddPool(USDCAddress, WETHAddress, 500, "1000000000000/3000")
+		second := fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		adapter := newDEXAdapter(t, spec, fake, nil)
+		req := newQuoteRequest()
+		req.FromToken = USDCAddress
+		req.AmountIn = decimal.RequireFromString("3000000000") // 3000 USDC
+
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		expected := fake.AmountOut(req.AmountIn.BigInt(), []string{USDCAddress, WETHAddress, DAIAddress}, []*FakePool{first, second})
+		assertAmount(t, "AmountOut", quote.AmountOut, expected)
+		assertRoute(t, spec, quote.Route, first, second)
+	})
+
+	t.Run("IntermediateTokens", func(t *testing.T) {
+		t.Parallel()
+
+		// 配置的中间代币为空时只探索直连路径
+		fake := NewFakeUniswap(t, spec.Version)
+		fake.AddPool(USDCAddress, WETHAddress, 500, "1000000000000/3000")
+		fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		adapter := newDEXAdapter(t, spec, fake, func(config *types.ProviderConfig) {
+			config.DEX.IntermediateTokens = map[uint][]string{SupportedChainID: {}}
+		})
+		req := newQuoteRequest()
+		req.FromToken = USDCAddress
+
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+
+		assertDEXFailedQuote(t, spec, quote, types.ErrCodeInsufficientLiquidity)
+	})
+
+	if spec.FeeTiers {
+		t.Run("FeeTiers", func(t *testing.T) {
+			t.Parallel()
+
+			fake := NewFakeUniswap(t, spec.Version)
+			fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+			configured := fake.AddPool(WETHAddress, DAIAddress, 500, "2990")
+			adapter := newDEXAdapter(t, spec, fake, func(config *types.ProviderConfig) {
+				config.DEX.FeeTiers = []uint32{500}
+			})
+
+			quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+			if !quote.Success {
+				t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+			}
+			assertRoute(t, spec, quote.Route, configured)
+		})
+	}
+
+	t.Run("ExactOutput", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		first := fake.AddPool(USDCAddress, WETHAddress, 500, "1000000000000/3000")
+		second := fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		adapter := newDEXAdapter(t, spec, fake, nil)
+		req := newQuoteRequest()
+		req.FromToken = USDCAddress
+		req.Side = types.QuoteSideBuy
+		req.AmountIn = decimal.Zero
+		req.AmountOut = decimal.RequireFromString("1000000000000000000000") // 1000 DAI
+
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		if !quote.AmountOut.Equal(req.AmountOut) {
+			t.Errorf("AmountOut = %s, 期望 %s", quote.AmountOut, req.AmountOut)
+		}
+		expected := fake.AmountIn(req.AmountOut.BigInt(), []string{USDCAddress, WETHAddress, DAIAddress}, []*FakePool{first, second})
+		assertAmount(t, "AmountIn", quote.AmountIn, expected)
+		if quote.MaxAmountIn == nil || quote.MaxAmountIn.LessThan(quote.AmountIn) {
+			t.Errorf("MaxAmountIn = %v, 期望不小于AmountIn %s", quote.MaxAmountIn, quote.AmountIn)
+		}
+		assertRoute(t, spec, quote.Route, first, second)
+	})
+
+	t.Run("NativeToken", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		pool := fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		adapter := newDEXAdapter(t, spec, fake, nil)
+
+		// 卖出原生代币：按包装代币报价，路径以包装步骤开始
+		req := newQuoteRequest()
+		req.FromToken = types.NativeTokenAddress
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		if len(quote.Route) != 2 || quote.Route[0].Protocol != types.RouteProtocolWrapNative ||
+			!strings.EqualFold(quote.Route[0].Pool, WETHAddress) || !strings.EqualFold(quote.Route[1].Pool, pool.Address) {
+			t.Errorf("Route = %+v, 期望 [%s(%s), %s(%s)]", quote.Route, types.RouteProtocolWrapNative, WETHAddress, spec.Protocol, pool.Address)
+		}
+		for _, call := range fake.RPC().Calls() {
+			if strings.Contains(hex.EncodeToString(call.Args), strings.ToLower(types.NativeTokenAddress[2:])) {
+				t.Errorf("合约调用 %s 中出现了原生代币地址，期望替换为包装代币", call.Selector)
+			}
+		}
+
+		// 买入原生代币：路径以解包步骤结束
+		req = newQuoteRequest()
+		req.FromToken, req.ToToken = DAIAddress, types.NativeTokenAddress
+		req.AmountIn = decimal.RequireFromString("3000000000000000000000")
+		quote = mustGetQuote(t, adapter, context.Background(), req)
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		if last := quote.Route[len(quote.Route)-1]; last.Protocol != types.RouteProtocolUnwrapNative {
+			t.Errorf("Route最后一步 = %s, 期望 %s", last.Protocol, types.RouteProtocolUnwrapNative)
+		}
+
+		// 原生代币与包装代币互换不经过DEX池子
+		req = newQuoteRequest()
+		req.FromToken, req.ToToken = types.NativeTokenAddress, WETHAddress
+		var routerErr *types.RouterError
+		if err := adapter.CheckTokens(req); !errors.As(err, &routerErr) || routerErr.Code != types.ErrCodeNativeTokenUnsupported {
+			t.Errorf("CheckTokens(原生代币->包装代币) = %v, 期望 %s", err, types.ErrCodeNativeTokenUnsupported)
+		}
+	})
+
+	t.Run("NoLiquidity", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		adapter := newDEXAdapter(t, spec, fake, nil)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertDEXFailedQuote(t, spec, quote, types.ErrCodeInsufficientLiquidity)
+		// 没有池子时不发送报价请求
+		if n := fake.RPC().Requests(); n != 1 {
+			t.Errorf("发送了 %d 个请求, 期望 1 个", n)
+		}
+	})
+
+	t.Run("QuoteReverted", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		fake.AddPool(WETHAddress, DAIAddress, 3000, "3000").Revert = true
+		adapter := newDEXAdapter(t, spec, fake, nil)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertDEXFailedQuote(t, spec, quote, types.ErrCodeInsufficientLiquidity)
+	})
+
+	t.Run("LargeAmount", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		pool := fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		adapter := newDEXAdapter(t, spec, fake, nil)
+		req := newQuoteRequest()
+		// 超出uint64范围的数量必须无损编码到合约调用中
+		req.AmountIn = decimal.RequireFromString("123456789012345678901234567890")
+
+		quote := mustGetQuote(t, adapter, context.Background(), req)
+
+		if !quote.Success {
+			t.Fatalf("报价失败: code=%s, message=%s", quote.ErrorCode, quote.ErrorMessage)
+		}
+		expected := fake.AmountOut(req.AmountIn.BigInt(), []string{WETHAddress, DAIAddress}, []*FakePool{pool})
+		assertAmount(t, "AmountOut", quote.AmountOut, expected)
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		fake.RPC().FailHTTP(http.StatusBadGateway)
+		adapter := newDEXAdapter(t, spec, fake, nil)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertDEXFailedQuote(t, spec, quote, types.ErrCodeProviderError)
+		if expected := adapter.GetConfig().RetryCount + 1; fake.RPC().Requests() != expected {
+			t.Errorf("5xx响应后发送了 %d 个请求, 期望 %d 个", fake.RPC().Requests(), expected)
+		}
+	})
+
+	t.Run("RPCError", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		fake.AddPool(WETHAddress, DAIAddress, 3000, "3000")
+		fake.RPC().FailRPC("daily request count exceeded")
+		adapter := newDEXAdapter(t, spec, fake, nil)
+
+		quote := mustGetQuote(t, adapter, context.Background(), newQuoteRequest())
+
+		assertDEXFailedQuote(t, spec, quote, types.ErrCodeProviderError)
+		if !strings.Contains(quote.ErrorMessage, "daily request count exceeded") {
+			t.Errorf("ErrorMessage = %q, 期望包含节点错误信息", quote.ErrorMessage)
+		}
+	})
+
+	t.Run("BuildSwap", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeUniswap(t, spec.Version)
+		adapter := newDEXAdapter(t, spec, fake, nil)
+
+		_, err := adapter.BuildSwap(context.Background(), &types.SwapRequest{QuoteRequest: *newQuoteRequest()})
+
+		var routerErr *types.RouterError
+		if !errors.As(err, &routerErr) || routerErr.Code != types.ErrCodeSwapNotSupported {
+			t.Errorf("BuildSwap() = %v, 期望 %s", err, types.ErrCodeSwapNotSupported)
+		}
+	})
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// newDEXAdapter 创建指向假链节点和假Uniswap合约的适配器
+// mutate可在创建前调整配置
+func newDEXAdapter(t *testing.T, spec DEXSpec, fake *FakeUniswap, mutate func(*types.ProviderConfig)) adapters.ProviderAdapter {
+	t.Helper()
+
+	config := &types.ProviderConfig{
+		Name:            spec.Name,
+		DisplayName:     spec.Name,
+		BaseURL:         fake.URL(),
+		Timeout:         2 * time.Second,
+		RetryCount:      1,
+		Priority:        1,
+		Weight:          decimal.NewFromInt(1),
+		IsActive:        true,
+		SupportedChains: []uint{SupportedChainID, 137},
+		Retry: types.RetryPolicy{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     50 * time.Millisecond,
+			Multiplier:     2,
+			Jitter:         0.2,
+			MaxRetryAfter:  2 * time.Second,
+		},
+		DEX: &types.DEXConfig{
+			Contracts: map[uint]types.DEXContracts{
+				SupportedChainID: {Factory: FakeFactoryAddress, Quoter: FakeQuoterAddress},
+				137:              {Factory: FakeFactoryAddress, Quoter: FakeQuoterAddress},
+			},
+		},
+	}
+	if mutate != nil {
+		mutate(config)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return spec.NewAdapter(config, logger)
+}
+
+// assertAmount 检查数量与假合约计算结果一致
+func assertAmount(t *testing.T, name string, got decimal.Decimal, expected *big.Int) {
+	t.Helper()
+
+	if !got.Equal(decimal.NewFromBigInt(expected, 0)) {
+		t.Errorf("%s = %s, 期望 %s", name, got, expected)
+	}
+}
+
+// assertRoute 检查路径的每一步依次经过期望的池子
+func assertRoute(t *testing.T, spec DEXSpec, route []types.RouteStep, pools ...*FakePool) {
+	t.Helper()
+
+	if len(route) != len(pools) {
+		t.Fatalf("Route = %+v, 期望 %d 步", route, len(pools))
+	}
+	for i, pool := range pools {
+		if route[i].Protocol != spec.Protocol || !strings.EqualFold(route[i].Pool, pool.Address) {
+			t.Errorf("Route[%d] = %s(%s), 期望 %s(%s)", i, route[i].Protocol, route[i].Pool, spec.Protocol, pool.Address)
+		}
+		if !route[i].Percentage.Equal(decimal.NewFromInt(1)) {
+			t.Errorf("Route[%d].Percentage = %s, 期望 1", i, route[i].Percentage)
+		}
+	}
+}
+
+// assertDEXFailedQuote 检查失败报价的错误码和公共字段
+func assertDEXFailedQuote(t *testing.T, spec DEXSpec, quote *types.ProviderQuote, errorCode string) {
+	t.Helper()
+
+	assertFailedQuote(t, ProviderSpec{Name: spec.Name}, quote)
+	if quote.ErrorCode != errorCode {
+		t.Errorf("ErrorCode = %q, 期望 %q", quote.ErrorCode, errorCode)
+	}
+}
@@ -0,0 +1,198 @@
+// Package adapterstest 聚合器适配器离线测试工具
+// 提供基于httptest的假聚合器服务器，按场景回放testdata/<聚合器名称>/<场景>.json中录制的响应，
+// 以及所有ProviderAdapter都必须通过的一致性测试套件(RunConformance)
+package adapterstest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 录制响应场景
+const (
+	ScenarioSuccess     = "success"      // 正常报价
+	ScenarioClientError = "client_error" // 4xx错误响应
+	ScenarioServerError = "server_error" // 5xx错误响应
+	ScenarioSlow        = "slow"         // 正常报价但响应缓慢
+	ScenarioMalformed   = "malformed"    // 200状态码但响应体不是合法JSON
+	ScenarioRateLimited = "rate_limited" // 429限流响应(携带Retry-After)
+)
+
+//go:embed testdata
+var fixtureFS embed.FS
+
+// Fixture 录制的聚合器响应
+type Fixture struct {
+	Status  int               `json:"status"`             // HTTP状态码
+	Delay   string            `json:"delay,omitempty"`    // 响应前等待时间(time.ParseDuration格式)
+	Headers map[string]string `json:"headers,omitempty"`  // 响应头
+	Body    json.RawMessage   `json:"body,omitempty"`     // JSON响应体
+	RawBody string            `json:"raw_body,omitempty"` // 原样返回的响应体(优先于Body，用于非JSON响应)
+
+	delay time.Duration // 解析后的Delay
+}
+
+// LoadFixture 加载指定聚合器和场景的录制响应
+func LoadFixture(provider, scenario string) (*Fixture, error) {
+	data, err := fixtureFS.ReadFile(path.Join("testdata", provider, scenario+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("读取fixture失败: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("解析fixture %s/%s 失败: %w", provider, scenario, err)
+	}
+	if fixture.Status == 0 {
+		fixture.Status = http.StatusOK
+	}
+	if fixture.Delay != "" {
+		if fixture.delay, err = time.ParseDuration(fixture.Delay); err != nil {
+			return nil, fmt.Errorf("fixture %s/%s 延迟格式无效: %w", provider, scenario, err)
+		}
+	}
+	return &fixture, nil
+}
+
+// RecordedRequest 假服务器收到的请求
+type RecordedRequest struct {
+	Method string      // HTTP方法
+	Path   string      // 请求路径
+	Query  url.Values  // 查询参数
+	Header http.Header // 请求头
+	Body   []byte      // 请求体
+}
+
+// Param 获取请求参数：优先取查询参数，否则取JSON请求体的顶层字段
+// 非字符串字段返回其JSON文本，参数不存在时返回空字符串
+func (r *RecordedRequest) Param(name string) string {
+	if values, ok := r.Query[name]; ok && len(values) > 0 {
+		return values[0]
+	}
+
+	var fields map[string]json.RawMessage
+	if len(r.Body) == 0 || json.Unmarshal(r.Body, &fields) != nil {
+		return ""
+	}
+	raw, ok := fields[name]
+	if !ok {
+		return ""
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+	return string(raw)
+}
+
+// FakeProvider 假聚合器服务器
+// 按场景序列依次回放录制响应(序列用完后重复最后一个场景)，并记录收到的请求供断言
+type FakeProvider struct {
+	t        testing.TB
+	provider string
+	server   *httptest.Server
+
+	mutex    sync.Mutex
+	fixtures []*Fixture
+	requests []RecordedRequest
+}
+
+// NewFakeProvider 启动指定聚合器的假服务器，默认场景为ScenarioSuccess
+// 服务器在测试结束时自动关闭
+func NewFakeProvider(t testing.TB, provider string) *FakeProvider {
+	t.Helper()
+
+	f := &FakeProvider{t: t, provider: provider}
+	f.Use(ScenarioSuccess)
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// URL 服务器基础URL，作为适配器配置的BaseURL
+func (f *FakeProvider) URL() string {
+	return f.server.URL
+}
+
+// Use 切换回放场景，之后的所有请求都回放该场景
+func (f *FakeProvider) Use(scenario string) {
+	f.t.Helper()
+	f.UseSequence(scenario)
+}
+
+// UseSequence 按顺序为之后的请求回放各场景，用于测试重试(如先限流后成功)
+func (f *FakeProvider) UseSequence(scenarios ...string) {
+	f.t.Helper()
+
+	fixtures := make([]*Fixture, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		fixture, err := LoadFixture(f.provider, scenario)
+		if err != nil {
+			f.t.Fatalf("加载fixture失败: %v", err)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.fixtures = fixtures
+}
+
+// Requests 返回已收到的请求
+func (f *FakeProvider) Requests() []RecordedRequest {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]RecordedRequest(nil), f.requests...)
+}
+
+// serveHTTP 记录请求并回放当前场景的录制响应
+// 延迟期间客户端断开(超时或取消)时立即返回
+func (f *FakeProvider) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mutex.Lock()
+	f.requests = append(f.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	fixture := f.fixtures[0]
+	if len(f.fixtures) > 1 {
+		f.fixtures = f.fixtures[1:]
+	}
+	f.mutex.Unlock()
+
+	if fixture.delay > 0 {
+		select {
+		case <-time.After(fixture.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	for key, value := range fixture.Headers {
+		w.Header().Set(key, value)
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(fixture.Status)
+
+	if fixture.RawBody != "" {
+		io.WriteString(w, fixture.RawBody)
+		return
+	}
+	w.Write([]byte(strings.TrimSpace(string(fixture.Body))))
+}
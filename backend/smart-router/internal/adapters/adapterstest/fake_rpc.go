@@ -0,0 +1,210 @@
+// Package adapterstest 假链JSON-RPC节点
+// 直连DEX适配器通过eth_call调用合约，假节点按(合约地址, 函数选择器)把eth_call分发给测试注册的处理函数，
+// 支持单个和批量JSON-RPC请求，并记录收到的调用供断言
+package adapterstest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// CallHandler eth_call处理函数，args为去掉函数选择器的ABI编码参数
+// 返回错误时节点返回execution reverted错误
+type CallHandler func(args []byte) ([]byte, error)
+
+// RecordedCall 假节点收到的eth_call调用
+type RecordedCall struct {
+	To       string // 合约地址(小写)
+	Selector string // 函数选择器(十六进制，不含0x)
+	Args     []byte // ABI编码参数
+}
+
+// FakeRPC 假链JSON-RPC节点
+type FakeRPC struct {
+	t      testing.TB
+	server *httptest.Server
+
+	mutex      sync.Mutex
+	handlers   map[string]CallHandler
+	httpStatus int    // 非0时所有请求返回该HTTP状态码
+	rpcError   string // 非空时对整个请求返回该JSON-RPC错误
+	requests   int
+	calls      []RecordedCall
+}
+
+// rpcRequest JSON-RPC请求
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// rpcResponse JSON-RPC响应
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  *string         `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError JSON-RPC错误对象
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewFakeRPC 启动假链JSON-RPC节点，服务器在测试结束时自动关闭
+func NewFakeRPC(t testing.TB) *FakeRPC {
+	t.Helper()
+
+	f := &FakeRPC{t: t, handlers: make(map[string]CallHandler)}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// URL 节点URL，作为适配器配置的BaseURL或DEX.RPCURLs
+func (f *FakeRPC) URL() string {
+	return f.server.URL
+}
+
+// Handle 注册合约方法的eth_call处理函数，selector为十六进制函数选择器(如"1698ee82")
+// 未注册的调用返回空数据，与调用不存在的合约一致
+func (f *FakeRPC) Handle(to, selector string, handler CallHandler) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.handlers[strings.ToLower(to)+":"+selector] = handler
+}
+
+// FailHTTP 之后的所有请求返回指定HTTP状态码
+func (f *FakeRPC) FailHTTP(status int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.httpStatus = status
+}
+
+// FailRPC 之后的所有请求返回单个JSON-RPC错误对象(如节点限流)
+func (f *FakeRPC) FailRPC(message string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.rpcError = message
+}
+
+// Requests 返回收到的HTTP请求数(一个批量请求计为一个)
+func (f *FakeRPC) Requests() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.requests
+}
+
+// Calls 返回收到的eth_call调用
+func (f *FakeRPC) Calls() []RecordedCall {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]RecordedCall(nil), f.calls...)
+}
+
+// serveHTTP 处理单个或批量JSON-RPC请求
+func (f *FakeRPC) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mutex.Lock()
+	f.requests++
+	httpStatus, rpcErrorMessage := f.httpStatus, f.rpcError
+	f.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if httpStatus != 0 {
+		w.WriteHeader(httpStatus)
+		io.WriteString(w, `{"error":"fake rpc failure"}`)
+		return
+	}
+	if rpcErrorMessage != "" {
+		json.NewEncoder(w).Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: -32005, Message: rpcErrorMessage}})
+		return
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var requests []rpcRequest
+		if err := json.Unmarshal(trimmed, &requests); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		responses := make([]rpcResponse, len(requests))
+		for i := range requests {
+			responses[i] = f.handle(&requests[i])
+		}
+		json.NewEncoder(w).Encode(responses)
+		return
+	}
+
+	var request rpcRequest
+	if err := json.Unmarshal(trimmed, &request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(f.handle(&request))
+}
+
+// handle 处理单个JSON-RPC请求
+func (f *FakeRPC) handle(request *rpcRequest) rpcResponse {
+	response := rpcResponse{JSONRPC: "2.0", ID: request.ID}
+	result := func(data []byte) rpcResponse {
+		value := "0x" + hex.EncodeToString(data)
+		response.Result = &value
+		return response
+	}
+
+	switch request.Method {
+	case "eth_blockNumber":
+		value := "0x1312d00"
+		response.Result = &value
+		return response
+	case "eth_call":
+	default:
+		response.Error = &rpcError{Code: -32601, Message: "method not found"}
+		return response
+	}
+
+	var call struct {
+		To   string `json:"to"`
+		Data string `json:"data"`
+	}
+	if len(request.Params) == 0 || json.Unmarshal(request.Params[0], &call) != nil {
+		response.Error = &rpcError{Code: -32602, Message: "invalid params"}
+		return response
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(call.Data, "0x"))
+	if err != nil || len(data) < 4 {
+		response.Error = &rpcError{Code: -32602, Message: "invalid call data"}
+		return response
+	}
+
+	recorded := RecordedCall{
+		To:       strings.ToLower(call.To),
+		Selector: hex.EncodeToString(data[:4]),
+		Args:     data[4:],
+	}
+	f.mutex.Lock()
+	f.calls = append(f.calls, recorded)
+	handler := f.handlers[recorded.To+":"+recorded.Selector]
+	f.mutex.Unlock()
+
+	if handler == nil {
+		return result(nil)
+	}
+	output, err := handler(recorded.Args)
+	if err != nil {
+		response.Error = &rpcError{Code: 3, Message: "execution reverted: " + err.Error()}
+		return response
+	}
+	return result(output)
+}
@@ -0,0 +1,356 @@
+// Package adapterstest 假Uniswap合约
+// 在假链JSON-RPC节点上模拟Uniswap V3(Factory.getPool、QuoterV2.quoteExactInput/quoteExactOutput)
+// 和Uniswap V2(Factory.getPair、Router02.getAmountsOut/getAmountsIn)：池子按固定价格和手续费计算数量
+package adapterstest
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// 假Uniswap合约地址，测试配置通过DEX.Contracts指定
+const (
+	FakeFactoryAddress = "0x000000000000000000000000000000000000f00d" // 工厂合约
+	FakeQuoterAddress  = "0x000000000000000000000000000000000000900d" // V3为QuoterV2，V2为Router02
+)
+
+// 合约方法选择器
+const (
+	SelectorV3GetPool          = "1698ee82" // getPool(address,address,uint24)
+	SelectorV3QuoteExactInput  = "cdca1753" // quoteExactInput(bytes,uint256)
+	SelectorV3QuoteExactOutput = "2f80bb1d" // quoteExactOutput(bytes,uint256)
+	SelectorV2GetPair          = "e6a43905" // getPair(address,address)
+	SelectorV2GetAmountsOut    = "d06ca61f" // getAmountsOut(uint256,address[])
+	SelectorV2GetAmountsIn     = "1f00ca74" // getAmountsIn(uint256,address[])
+)
+
+// fakeV3HopGas 假QuoterV2每跳返回的gasEstimate
+const fakeV3HopGas = 80000
+
+// uniswapV2Fee Uniswap V2交易对固定手续费(百万分之一)
+const uniswapV2Fee = 3000
+
+// FakePool 假池子
+type FakePool struct {
+	Address string   // 池子地址
+	TokenA  string   // 代币A(小写)
+	TokenB  string   // 代币B(小写)
+	Fee     uint32   // 手续费(百万分之一)，V2固定为0.3%
+	Price   *big.Rat // 1单位代币A可兑换的代币B数量(最小单位)
+	Revert  bool     // 报价时revert(模拟流动性不足)
+}
+
+// FakeUniswap 假Uniswap合约
+type FakeUniswap struct {
+	rpc     *FakeRPC
+	version int
+	pools   []*FakePool
+}
+
+// NewFakeUniswap 启动假链节点并部署指定版本(2或3)的假Uniswap合约
+func NewFakeUniswap(t testing.TB, version int) *FakeUniswap {
+	t.Helper()
+
+	u := &FakeUniswap{rpc: NewFakeRPC(t), version: version}
+	switch version {
+	case 3:
+		u.rpc.Handle(FakeFactoryAddress, SelectorV3GetPool, u.getPool)
+		u.rpc.Handle(FakeQuoterAddress, SelectorV3QuoteExactInput, u.quoteExactInput)
+		u.rpc.Handle(FakeQuoterAddress, SelectorV3QuoteExactOutput, u.quoteExactOutput)
+	case 2:
+		u.rpc.Handle(FakeFactoryAddress, SelectorV2GetPair, u.getPair)
+		u.rpc.Handle(FakeQuoterAddress, SelectorV2GetAmountsOut, u.getAmountsOut)
+		u.rpc.Handle(FakeQuoterAddress, SelectorV2GetAmountsIn, u.getAmountsIn)
+	default:
+		t.Fatalf("不支持的Uniswap版本: %d", version)
+	}
+	return u
+}
+
+// RPC 假链节点
+func (u *FakeUniswap) RPC() *FakeRPC {
+	return u.rpc
+}
+
+// URL 假链节点URL
+func (u *FakeUniswap) URL() string {
+	return u.rpc.URL()
+}
+
+// AddPool 添加池子并返回池子地址，price为1单位tokenA可兑换的tokenB数量(最小单位，如"3000"或"1000000000000/3000")
+// V2忽略fee，使用固定的0.3%手续费
+func (u *FakeUniswap) AddPool(tokenA, tokenB string, fee uint32, price string) *FakePool {
+	rate, ok := new(big.Rat).SetString(price)
+	if !ok {
+		panic(fmt.Sprintf("无效的价格: %s", price))
+	}
+	if u.version == 2 {
+		fee = uniswapV2Fee
+	}
+	pool := &FakePool{
+		Address: fmt.Sprintf("0x%040x", 0x1000+len(u.pools)),
+		TokenA:  strings.ToLower(tokenA),
+		TokenB:  strings.ToLower(tokenB),
+		Fee:     fee,
+		Price:   rate,
+	}
+	u.pools = append(u.pools, pool)
+	return pool
+}
+
+// AmountOut 按池子价格计算卖出amountIn沿tokens路径(经过pools)的输出数量
+func (u *FakeUniswap) AmountOut(amountIn *big.Int, tokens []string, pools []*FakePool) *big.Int {
+	amount := new(big.Int).Set(amountIn)
+	for i, pool := range pools {
+		amount = swapOut(pool, tokens[i], amount)
+	}
+	return amount
+}
+
+// AmountIn 按池子价格计算沿tokens路径(经过pools)买入amountOut所需的输入数量
+func (u *FakeUniswap) AmountIn(amountOut *big.Int, tokens []string, pools []*FakePool) *big.Int {
+	amount := new(big.Int).Set(amountOut)
+	for i := len(pools) - 1; i >= 0; i-- {
+		amount = swapIn(pools[i], tokens[i], amount)
+	}
+	return amount
+}
+
+// ========================================
+// 池子计算
+// ========================================
+
+// rate 从tokenIn卖出到池子另一代币的有效兑换率(已扣除手续费)
+func rate(pool *FakePool, tokenIn string) *big.Rat {
+	price := new(big.Rat).Set(pool.Price)
+	if strings.EqualFold(tokenIn, pool.TokenB) {
+		price.Inv(price)
+	}
+	return price.Mul(price, big.NewRat(int64(1000000-pool.Fee), 1000000))
+}
+
+// swapOut 卖出amountIn的输出数量(向下取整)
+func swapOut(pool *FakePool, tokenIn string, amountIn *big.Int) *big.Int {
+	out := new(big.Rat).Mul(new(big.Rat).SetInt(amountIn), rate(pool, tokenIn))
+	return new(big.Int).Quo(out.Num(), out.Denom())
+}
+
+// swapIn 买入amountOut所需的输入数量(向上取整)
+func swapIn(pool *FakePool, tokenIn string, amountOut *big.Int) *big.Int {
+	in := new(big.Rat).Quo(new(big.Rat).SetInt(amountOut), rate(pool, tokenIn))
+	quotient, remainder := new(big.Int).QuoRem(in.Num(), in.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
+
+// findPool 查找代币对池子，fee为0时不区分手续费
+func (u *FakeUniswap) findPool(tokenA, tokenB string, fee uint32) *FakePool {
+	for _, pool := range u.pools {
+		if fee != 0 && pool.Fee != fee {
+			continue
+		}
+		if (strings.EqualFold(pool.TokenA, tokenA) && strings.EqualFold(pool.TokenB, tokenB)) ||
+			(strings.EqualFold(pool.TokenA, tokenB) && strings.EqualFold(pool.TokenB, tokenA)) {
+			return pool
+		}
+	}
+	return nil
+}
+
+// hopPools 查找路径每一跳的池子，池子不存在或设置了revert时返回错误
+func (u *FakeUniswap) hopPools(tokens []string, fees []uint32) ([]*FakePool, error) {
+	pools := make([]*FakePool, 0, len(tokens)-1)
+	for i := 0; i+1 < len(tokens); i++ {
+		var fee uint32
+		if fees != nil {
+			fee = fees[i]
+		}
+		pool := u.findPool(tokens[i], tokens[i+1], fee)
+		if pool == nil {
+			return nil, errors.New("pool not found")
+		}
+		if pool.Revert {
+			return nil, errors.New("insufficient liquidity")
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// ========================================
+// Uniswap V3合约
+// ========================================
+
+// getPool Factory.getPool(tokenA, tokenB, fee)
+func (u *FakeUniswap) getPool(args []byte) ([]byte, error) {
+	fee := argUint(args, 2)
+	pool := u.findPool(argAddress(args, 0), argAddress(args, 1), uint32(fee.Uint64()))
+	if pool == nil {
+		return addressWord("0x0000000000000000000000000000000000000000"), nil
+	}
+	return addressWord(pool.Address), nil
+}
+
+// quoteExactInput QuoterV2.quoteExactInput(path, amountIn)
+func (u *FakeUniswap) quoteExactInput(args []byte) ([]byte, error) {
+	tokens, fees := decodeV3Path(argBytes(args, 0))
+	pools, err := u.hopPools(tokens, fees)
+	if err != nil {
+		return nil, err
+	}
+	amountOut := u.AmountOut(argUint(args, 1), tokens, pools)
+	return encodeV3QuoteResult(amountOut, len(pools)), nil
+}
+
+// quoteExactOutput QuoterV2.quoteExactOutput(path, amountOut)，路径为输出代币到输入代币
+func (u *FakeUniswap) quoteExactOutput(args []byte) ([]byte, error) {
+	reversedTokens, reversedFees := decodeV3Path(argBytes(args, 0))
+	tokens := make([]string, len(reversedTokens))
+	for i, token := range reversedTokens {
+		tokens[len(tokens)-1-i] = token
+	}
+	fees := make([]uint32, len(reversedFees))
+	for i, fee := range reversedFees {
+		fees[len(fees)-1-i] = fee
+	}
+
+	pools, err := u.hopPools(tokens, fees)
+	if err != nil {
+		return nil, err
+	}
+	amountIn := u.AmountIn(argUint(args, 1), tokens, pools)
+	return encodeV3QuoteResult(amountIn, len(pools)), nil
+}
+
+// decodeV3Path 解析Uniswap V3路径：token(20字节) + fee(3字节) + token + ...
+func decodeV3Path(path []byte) ([]string, []uint32) {
+	var tokens []string
+	var fees []uint32
+	for len(path) >= 20 {
+		tokens = append(tokens, "0x"+hex.EncodeToString(path[:20]))
+		path = path[20:]
+		if len(path) >= 3 {
+			fees = append(fees, uint32(path[0])<<16|uint32(path[1])<<8|uint32(path[2]))
+			path = path[3:]
+		}
+	}
+	return tokens, fees
+}
+
+// encodeV3QuoteResult 编码(amount, uint160[] sqrtPriceX96AfterList, uint32[] initializedTicksCrossedList, gasEstimate)
+func encodeV3QuoteResult(amount *big.Int, hops int) []byte {
+	zeros := make([]*big.Int, hops)
+	for i := range zeros {
+		zeros[i] = big.NewInt(0)
+	}
+	array := encodeUintArray(zeros)
+
+	data := word(amount)
+	data = append(data, word(big.NewInt(4*32))...)
+	data = append(data, word(big.NewInt(int64(4*32+len(array))))...)
+	data = append(data, word(big.NewInt(int64(fakeV3HopGas*hops)))...)
+	data = append(data, array...)
+	return append(data, array...)
+}
+
+// ========================================
+// Uniswap V2合约
+// ========================================
+
+// getPair Factory.getPair(tokenA, tokenB)
+func (u *FakeUniswap) getPair(args []byte) ([]byte, error) {
+	pool := u.findPool(argAddress(args, 0), argAddress(args, 1), 0)
+	if pool == nil {
+		return addressWord("0x0000000000000000000000000000000000000000"), nil
+	}
+	return addressWord(pool.Address), nil
+}
+
+// getAmountsOut Router02.getAmountsOut(amountIn, path)
+func (u *FakeUniswap) getAmountsOut(args []byte) ([]byte, error) {
+	tokens := argAddressArray(args, 1)
+	pools, err := u.hopPools(tokens, nil)
+	if err != nil {
+		return nil, err
+	}
+	amounts := []*big.Int{argUint(args, 0)}
+	for i, pool := range pools {
+		amounts = append(amounts, swapOut(pool, tokens[i], amounts[i]))
+	}
+	return append(word(big.NewInt(32)), encodeUintArray(amounts)...), nil
+}
+
+// getAmountsIn Router02.getAmountsIn(amountOut, path)
+func (u *FakeUniswap) getAmountsIn(args []byte) ([]byte, error) {
+	tokens := argAddressArray(args, 1)
+	pools, err := u.hopPools(tokens, nil)
+	if err != nil {
+		return nil, err
+	}
+	amounts := make([]*big.Int, len(tokens))
+	amounts[len(amounts)-1] = argUint(args, 0)
+	for i := len(pools) - 1; i >= 0; i-- {
+		amounts[i] = swapIn(pools[i], tokens[i], amounts[i+1])
+	}
+	return append(word(big.NewInt(32)), encodeUintArray(amounts)...), nil
+}
+
+// ========================================
+// ABI编解码辅助方法
+// ========================================
+
+// word 编码uint为32字节
+func word(value *big.Int) []byte {
+	data := make([]byte, 32)
+	value.FillBytes(data)
+	return data
+}
+
+// addressWord 编码地址为32字节
+func addressWord(address string) []byte {
+	data, _ := hex.DecodeString(strings.TrimPrefix(strings.ToLower(address), "0x"))
+	return append(make([]byte, 32-len(data)), data...)
+}
+
+// encodeUintArray 编码uint[]内容(长度 + 各元素)
+func encodeUintArray(values []*big.Int) []byte {
+	data := word(big.NewInt(int64(len(values))))
+	for _, value := range values {
+		data = append(data, word(value)...)
+	}
+	return data
+}
+
+// argUint 读取第index个参数为uint
+func argUint(args []byte, index int) *big.Int {
+	return new(big.Int).SetBytes(args[index*32 : index*32+32])
+}
+
+// argAddress 读取第index个参数为地址(小写)
+func argAddress(args []byte, index int) string {
+	return "0x" + hex.EncodeToString(args[index*32+12:index*32+32])
+}
+
+// argBytes 读取第index个参数(偏移量)指向的bytes
+func argBytes(args []byte, index int) []byte {
+	offset := int(argUint(args, index).Int64())
+	length := int(new(big.Int).SetBytes(args[offset : offset+32]).Int64())
+	return args[offset+32 : offset+32+length]
+}
+
+// argAddressArray 读取第index个参数(偏移量)指向的address[]
+func argAddressArray(args []byte, index int) []string {
+	offset := int(argUint(args, index).Int64())
+	length := int(new(big.Int).SetBytes(args[offset : offset+32]).Int64())
+	addresses := make([]string, length)
+	for i := range addresses {
+		addresses[i] = argAddress(args[offset+32:], i)
+	}
+	return addresses
+}
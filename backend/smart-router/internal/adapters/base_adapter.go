@@ -4,11 +4,17 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"defi-aggregator/smart-router/internal/types"
@@ -24,6 +30,9 @@ type BaseAdapter struct {
 	httpClient *http.Client          // HTTP客户端
 	logger     *logrus.Logger        // 日志记录器
 	metrics    *AdapterMetrics       // 性能指标
+
+	defaultAuthScheme string // 适配器默认的API密钥认证方式(配置未指定时使用)
+	defaultAuthHeader string // 默认认证方式为header时的请求头名称
 }
 
 // AdapterMetrics 适配器性能指标
@@ -62,7 +71,7 @@ func NewBaseAdapter(config *types.ProviderConfig, logger *logrus.Logger) *BaseAd
 // ========================================
 
 // makeHTTPRequest 发送HTTP请求
-// 统一的HTTP请求方法，包含重试、超时、错误处理等
+// 统一的HTTP请求方法，包含认证、重试、超时、错误处理等，重试规则见types.RetryPolicy
 // 参数:
 //   - ctx: 上下文，用于超时控制
 //   - method: HTTP方法
@@ -72,12 +81,66 @@ func NewBaseAdapter(config *types.ProviderConfig, logger *logrus.Logger) *BaseAd
 //
 // 返回:
 //   - []byte: 响应体
-//   - error: 请求错误
+//   - error: 请求错误，HTTP状态码>=400时为*HTTPStatusError
 func (b *BaseAdapter) makeHTTPRequest(ctx context.Context, method, url string, body io.Reader, headers map[string]string) ([]byte, error) {
 	startTime := time.Now()
+	config := b.config
+	policy := b.retryPolicy()
 
 	// 记录请求开始
-	b.logger.Debugf("[%s] 开始请求: %s %s", b.config.Name, method, url)
+	b.logger.Debugf("[%s] 开始请求: %s %s", config.Name, method, url)
+
+	// 请求体在每次重试时重新创建，预先完整读取
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("读取请求体失败: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		responseBody, err := b.doHTTPRequest(ctx, method, url, payload, headers)
+		if err == nil {
+			// 更新性能指标
+			duration := time.Since(startTime)
+			b.updateMetrics(true, duration)
+			b.logger.Debugf("[%s] 请求完成: duration=%v, attempts=%d", config.Name, duration, attempt+1)
+			return responseBody, nil
+		}
+		lastErr = err
+
+		if attempt >= config.RetryCount || !b.shouldRetry(ctx, err) {
+			break
+		}
+
+		wait, ok := b.retryWait(ctx, policy, attempt+1, err)
+		if !ok {
+			break
+		}
+		b.logger.Debugf("[%s] %v 后重试请求: attempt=%d, error=%v", config.Name, wait, attempt+1, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			b.updateMetrics(false, time.Since(startTime))
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	b.updateMetrics(false, time.Since(startTime))
+	return nil, lastErr
+}
+
+// doHTTPRequest 发送单次HTTP请求
+func (b *BaseAdapter) doHTTPRequest(ctx context.Context, method, url string, payload []byte, headers map[string]string) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
 
 	// 创建HTTP请求
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
@@ -88,70 +151,182 @@ func (b *BaseAdapter) makeHTTPRequest(ctx context.Context, method, url string, b
 	// 设置请求头
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "DeFi-Aggregator-Smart-Router/1.0")
-
-	// 添加API密钥（如果存在）
-	if b.config.APIKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.config.APIKey))
-	}
+	b.setAuthHeader(req)
 
 	// 添加自定义请求头
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
-	// 执行请求（带重试）
-	var resp *http.Response
-	var lastErr error
-
-	for attempt := 0; attempt <= b.config.RetryCount; attempt++ {
-		if attempt > 0 {
-			// 重试前等待
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
-			}
-			b.logger.Debugf("[%s] 重试请求: attempt=%d", b.config.Name, attempt)
-		}
-
-		resp, lastErr = b.httpClient.Do(req)
-		if lastErr == nil && resp.StatusCode < 500 {
-			// 请求成功或客户端错误（不重试）
-			break
-		}
-
-		if resp != nil {
-			resp.Body.Close()
-		}
-	}
-
-	if lastErr != nil {
-		b.updateMetrics(false, time.Since(startTime))
-		return nil, fmt.Errorf("HTTP请求失败: %w", lastErr)
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应体
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		b.updateMetrics(false, time.Since(startTime))
 		return nil, fmt.Errorf("读取响应体失败: %w", err)
 	}
 
 	// 检查HTTP状态码
 	if resp.StatusCode >= 400 {
-		b.updateMetrics(false, time.Since(startTime))
-		return nil, fmt.Errorf("HTTP错误: status=%d, body=%s", resp.StatusCode, string(responseBody))
+		return nil, &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(responseBody),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
-	// 更新性能指标
-	duration := time.Since(startTime)
-	b.updateMetrics(true, duration)
+	return responseBody, nil
+}
 
-	b.logger.Debugf("[%s] 请求完成: duration=%v, status=%d",
-		b.config.Name, duration, resp.StatusCode)
+// ========================================
+// 认证与重试策略
+// ========================================
 
-	return responseBody, nil
+// HTTPStatusError 聚合器返回的HTTP错误状态
+type HTTPStatusError struct {
+	StatusCode int           // HTTP状态码
+	Body       string        // 响应体
+	RetryAfter time.Duration // Retry-After响应头(未提供时为0)
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP错误: status=%d, body=%s", e.StatusCode, e.Body)
+}
+
+// withDefaultAuth 设置适配器默认的API密钥认证方式
+// 配置中的AuthScheme非空时优先使用配置
+func (b *BaseAdapter) withDefaultAuth(scheme, header string) *BaseAdapter {
+	b.defaultAuthScheme = scheme
+	b.defaultAuthHeader = header
+	return b
+}
+
+// setAuthHeader 按认证方式设置API密钥请求头
+func (b *BaseAdapter) setAuthHeader(req *http.Request) {
+	if b.config.APIKey == "" {
+		return
+	}
+
+	scheme, header := b.config.AuthScheme, b.config.AuthHeader
+	if scheme == "" {
+		scheme, header = b.defaultAuthScheme, b.defaultAuthHeader
+	}
+
+	switch scheme {
+	case types.AuthSchemeBearer:
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.config.APIKey))
+	case types.AuthSchemeHeader:
+		if header != "" {
+			req.Header.Set(header, b.config.APIKey)
+		}
+	}
+}
+
+// retryPolicy 获取重试策略，未配置或无效的参数使用默认值
+func (b *BaseAdapter) retryPolicy() types.RetryPolicy {
+	policy := b.config.Retry
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = types.DefaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = types.DefaultRetryMaxBackoff
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = types.DefaultRetryMultiplier
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		policy.Jitter = types.DefaultRetryJitter
+	}
+	if policy.MaxRetryAfter <= 0 {
+		policy.MaxRetryAfter = types.DefaultRetryAfterMax
+	}
+	return policy
+}
+
+// shouldRetry 判断请求错误是否可重试
+// 上下文已结束时不重试；HTTP错误仅重试429和5xx，其他4xx属于请求本身的问题
+func (b *BaseAdapter) shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return true
+}
+
+// httpErrorCode 将请求错误归类为报价错误代码
+// 超时、429和5xx/传输错误属于聚合器故障，计入熔断；其他4xx多为交易对无路由或参数问题，不计入熔断
+func httpErrorCode(err error) string {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusTooManyRequests:
+			return types.ErrCodeRateLimitExceeded
+		case statusErr.StatusCode >= 500:
+			return types.ErrCodeProviderError
+		default:
+			return types.ErrCodeProviderRejected
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return types.ErrCodeProviderTimeout
+	}
+	return types.ErrCodeProviderError
+}
+
+// retryWait 计算第retry次重试前的等待时间
+// Retry-After超过上限，或等待后上下文剩余时间不足时返回false
+func (b *BaseAdapter) retryWait(ctx context.Context, policy types.RetryPolicy, retry int, err error) (time.Duration, bool) {
+	var wait time.Duration
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		if statusErr.RetryAfter > policy.MaxRetryAfter {
+			b.logger.Warnf("[%s] Retry-After %v 超过上限 %v，放弃重试", b.config.Name, statusErr.RetryAfter, policy.MaxRetryAfter)
+			return 0, false
+		}
+		wait = statusErr.RetryAfter
+	} else {
+		backoff := float64(policy.InitialBackoff) * math.Pow(policy.Multiplier, float64(retry-1))
+		backoff = math.Min(backoff, float64(policy.MaxBackoff))
+		// 抖动范围 [1-Jitter, 1+Jitter]，避免多个实例同时重试
+		backoff *= 1 + policy.Jitter*(2*rand.Float64()-1)
+		wait = time.Duration(backoff)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+		b.logger.Debugf("[%s] 剩余时间不足以等待 %v 后重试，放弃重试", b.config.Name, wait)
+		return 0, false
+	}
+	return wait, true
+}
+
+// parseRetryAfter 解析Retry-After响应头(秒数或HTTP日期)，无效时返回0
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
 }
 
 // ========================================
@@ -185,25 +360,47 @@ func (b *BaseAdapter) standardizeAmount(amount interface{}) (decimal.Decimal, er
 	}
 }
 
-// calculatePriceImpact 计算价格冲击
-// 基于输入输出金额计算价格冲击百分比
-func (b *BaseAdapter) calculatePriceImpact(amountIn, amountOut, marketPrice decimal.Decimal) decimal.Decimal {
-	if marketPrice.IsZero() || amountIn.IsZero() {
-		return decimal.Zero
+// validateSwapRequest 校验交易构建请求的公共参数
+func (b *BaseAdapter) validateSwapRequest(req *types.SwapRequest) error {
+	if !b.IsSupported(req.ChainID) {
+		return &types.RouterError{
+			Code:     types.ErrCodeUnsupportedChain,
+			Message:  fmt.Sprintf("%s不支持链ID: %d", b.config.DisplayName, req.ChainID),
+			Provider: b.config.Name,
+		}
+	}
+	if req.UserAddress == "" {
+		return &types.RouterError{
+			Code:     types.ErrCodeInvalidRequest,
+			Message:  "构建交易需要用户钱包地址",
+			Provider: b.config.Name,
+		}
 	}
+	return nil
+}
 
-	// 计算实际汇率
-	actualRate := amountOut.Div(amountIn)
+// minAmountOut 按滑点计算最小输出数量（向下取整到wei）
+func (b *BaseAdapter) minAmountOut(amountOut, slippage decimal.Decimal) decimal.Decimal {
+	return amountOut.Mul(decimal.NewFromInt(1).Sub(slippage)).Floor()
+}
 
-	// 计算价格冲击 = (市场价格 - 实际汇率) / 市场价格
-	priceImpact := marketPrice.Sub(actualRate).Div(marketPrice)
+// maxAmountIn 按滑点计算买入报价的最大输入数量（向上取整到wei）
+func (b *BaseAdapter) maxAmountIn(amountIn, slippage decimal.Decimal) decimal.Decimal {
+	return amountIn.Mul(decimal.NewFromInt(1).Add(slippage)).Ceil()
+}
 
-	// 确保价格冲击为正数
-	if priceImpact.IsNegative() {
-		priceImpact = priceImpact.Neg()
+// setInputAmounts 设置报价的输入数量，买入报价同时按滑点计算最大输入数量
+// 聚合器未返回输入数量时，卖出报价使用请求的AmountIn
+func (b *BaseAdapter) setInputAmounts(quote *types.ProviderQuote, amountIn decimal.Decimal, req *types.QuoteRequest) {
+	if !amountIn.IsPositive() && !req.IsExactOutput() {
+		amountIn = req.AmountIn
 	}
+	quote.AmountIn = amountIn
 
-	return priceImpact
+	if req.IsExactOutput() {
+		maxAmountIn := b.maxAmountIn(amountIn, req.Slippage)
+		quote.MaxAmountIn = &maxAmountIn
+	}
 }
 
 // ========================================
@@ -248,17 +445,6 @@ func (b *BaseAdapter) ResetMetrics() {
 // 配置管理
 // ========================================
 
-// UpdateConfig 更新适配器配置
-func (b *BaseAdapter) UpdateConfig(config *types.ProviderConfig) error {
-	b.config = config
-
-	// 更新HTTP客户端超时
-	b.httpClient.Timeout = config.Timeout
-
-	b.logger.Infof("[%s] 配置已更新", config.Name)
-	return nil
-}
-
 // GetConfig 获取当前配置
 func (b *BaseAdapter) GetConfig() *types.ProviderConfig {
 	return b.config
@@ -283,3 +469,14 @@ func (b *BaseAdapter) IsSupported(chainID uint) bool {
 	}
 	return false
 }
+
+// SupportsQuoteSide 检查是否支持指定报价方向，默认同时支持卖出和买入
+func (b *BaseAdapter) SupportsQuoteSide(side string) bool {
+	return side == "" || side == types.QuoteSideSell || side == types.QuoteSideBuy
+}
+
+// CheckTokens 检查是否支持请求的代币
+// 默认聚合器按types.NativeTokenAddress约定直接接受原生代币，无需转换
+func (b *BaseAdapter) CheckTokens(req *types.QuoteRequest) error {
+	return nil
+}
@@ -0,0 +1,220 @@
+package adapters_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"defi-aggregator/smart-router/internal/adapters"
+	"defi-aggregator/smart-router/internal/adapters/adapterstest"
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestOneInchAdapterConformance(t *testing.T) {
+	t.Parallel()
+	adapterstest.RunConformance(t, adapterstest.ProviderSpec{
+		Name:           types.Provider1inch,
+		NewAdapter:     adapters.NewOneInchAdapter,
+		APIKey:         "test-1inch-api-key",
+		Method:         http.MethodGet,
+		Path:           "/1/quote",
+		FromTokenParam: "fromTokenAddress",
+		ToTokenParam:   "toTokenAddress",
+		AmountParam:    "amount",
+		Params: map[string]string{
+			"slippage":    "0.5",
+			"fromAddress": adapterstest.UserAddress,
+		},
+		Headers: map[string]string{
+			"Authorization": "Bearer test-1inch-api-key",
+		},
+		SuccessAmountIn:    "1000000000000000000",
+		SuccessAmountOut:   "3421567890123456789012",
+		SuccessGasEstimate: 182345,
+		ClientErrorMessage: "insufficient liquidity",
+	})
+}
+
+func TestParaSwapAdapterConformance(t *testing.T) {
+	t.Parallel()
+	adapterstest.RunConformance(t, adapterstest.ProviderSpec{
+		Name:           types.ProviderParaswap,
+		NewAdapter:     adapters.NewParaSwapAdapter,
+		APIKey:         "test-paraswap-api-key",
+		Method:         http.MethodGet,
+		Path:           "/prices",
+		FromTokenParam: "srcToken",
+		ToTokenParam:   "destToken",
+		AmountParam:    "amount",
+		Params: map[string]string{
+			"network":     "1",
+			"side":        "SELL",
+			"userAddress": adapterstest.UserAddress,
+		},
+		BuyAmountParam: "amount",
+		BuyParams: map[string]string{
+			"side": "BUY",
+		},
+		SuccessAmountIn:    "1000000000000000000",
+		SuccessAmountOut:   "3419876543210987654321",
+		SuccessGasEstimate: 198700,
+		ClientErrorMessage: "No routes found with enough liquidity",
+	})
+}
+
+func TestZRXAdapterConformance(t *testing.T) {
+	t.Parallel()
+	adapterstest.RunConformance(t, adapterstest.ProviderSpec{
+		Name:           types.Provider0x,
+		NewAdapter:     adapters.NewZRXAdapter,
+		APIKey:         "test-0x-api-key",
+		Method:         http.MethodGet,
+		Path:           "/swap/permit2/quote",
+		FromTokenParam: "sellToken",
+		ToTokenParam:   "buyToken",
+		AmountParam:    "sellAmount",
+		Params: map[string]string{
+			"chainId": "1",
+			"taker":   adapterstest.UserAddress,
+		},
+		Headers: map[string]string{
+			"0x-api-key": "test-0x-api-key",
+			"0x-version": "v2",
+		},
+		SuccessAmountIn:    "1000000000000000000",
+		SuccessAmountOut:   "3422987654321098765432",
+		SuccessGasEstimate: 204000,
+		ClientErrorMessage: "Validation Failed",
+	})
+}
+
+func TestCowAdapterConformance(t *testing.T) {
+	t.Parallel()
+	adapterstest.RunConformance(t, adapterstest.ProviderSpec{
+		Name:           types.ProviderCowswap,
+		NewAdapter:     adapters.NewCowAdapter,
+		APIKey:         "test-cow-api-key",
+		Method:         http.MethodPost,
+		Path:           "/quote",
+		FromTokenParam: "sellToken",
+		ToTokenParam:   "buyToken",
+		AmountParam:    "sellAmountBeforeFee",
+		Params: map[string]string{
+			"kind":     "sell",
+			"from":     adapterstest.UserAddress,
+			"receiver": adapterstest.UserAddress,
+		},
+		BuyAmountParam: "buyAmountAfterFee",
+		BuyParams: map[string]string{
+			"kind": "buy",
+		},
+		NativeSellToken: adapterstest.WETHAddress, // ETH流订单
+		NativeSellParams: map[string]string{
+			"onchainOrder":  "true",
+			"signingScheme": "eip1271",
+		},
+		NativeBuyUnsupported: true,
+		SuccessAmountIn:      "1000000000000000000", // sellAmount + feeAmount
+		SuccessAmountOut:     "3417234567890123456789",
+		SuccessGasEstimate:   150000,
+		ClientErrorMessage:   "no route found",
+	})
+}
+
+func TestUniswapV3AdapterConformance(t *testing.T) {
+	t.Parallel()
+	adapterstest.RunDEXConformance(t, adapterstest.DEXSpec{
+		Name:       types.ProviderUniswapV3,
+		NewAdapter: adapters.NewUniswapV3Adapter,
+		Version:    3,
+		Protocol:   "UNISWAP_V3",
+		FeeTiers:   true,
+	})
+}
+
+func TestUniswapV2AdapterConformance(t *testing.T) {
+	t.Parallel()
+	adapterstest.RunDEXConformance(t, adapterstest.DEXSpec{
+		Name:       types.ProviderUniswapV2,
+		NewAdapter: adapters.NewUniswapV2Adapter,
+		Version:    2,
+		Protocol:   "UNISWAP_V2",
+	})
+}
+
+func TestHTTPJSONAdapterConformance(t *testing.T) {
+	t.Parallel()
+	adapterstest.RunConformance(t, adapterstest.ProviderSpec{
+		Name: "example_dex",
+		NewAdapter: func(config *types.ProviderConfig, logger *logrus.Logger) adapters.ProviderAdapter {
+			config.Adapter = types.AdapterHTTPJSON
+			config.AuthScheme = types.AuthSchemeHeader
+			config.AuthHeader = "X-API-Key"
+			config.HTTPJSON = &types.HTTPJSONConfig{
+				URLTemplate: "{base_url}/v1/{chain_id}/quote",
+				Params: map[string]string{
+					"tokenIn":     "{from_token}",
+					"tokenOut":    "{to_token}",
+					"amountIn":    "{amount_in}",
+					"slippageBps": "{slippage_bps}",
+					"taker":       "{user_address}",
+				},
+				Headers:       map[string]string{"Accept": "application/json"},
+				AmountOutPath: "$.data.amountOut",
+				GasPath:       "$.data.gas",
+				ErrorPath:     "$.error.message",
+				DefaultGas:    200000,
+			}
+			adapter, err := adapters.New(config, logger)
+			if err != nil {
+				panic(err)
+			}
+			return adapter
+		},
+		APIKey:         "test-example-api-key",
+		Method:         http.MethodGet,
+		Path:           "/v1/1/quote",
+		FromTokenParam: "tokenIn",
+		ToTokenParam:   "tokenOut",
+		AmountParam:    "amountIn",
+		Params: map[string]string{
+			"slippageBps": "50",
+			"taker":       adapterstest.UserAddress,
+		},
+		Headers: map[string]string{
+			"X-API-Key": "test-example-api-key",
+			"Accept":    "application/json",
+		},
+		SuccessAmountIn:    "1000000000000000000",
+		SuccessAmountOut:   "3418765432109876543210",
+		SuccessGasEstimate: 176500,
+		ClientErrorMessage: "no route found for pair",
+	})
+}
+
+func TestAdapterRegistry(t *testing.T) {
+	t.Parallel()
+
+	registered := make(map[string]bool)
+	for _, adapterType := range adapters.Registered() {
+		registered[adapterType] = true
+	}
+	for _, adapterType := range []string{
+		types.Provider1inch, types.ProviderParaswap, types.Provider0x, types.ProviderCowswap,
+		types.ProviderUniswapV3, types.ProviderUniswapV2, types.AdapterHTTPJSON,
+	} {
+		if !registered[adapterType] {
+			t.Errorf("适配器类型 %s 未注册", adapterType)
+		}
+	}
+
+	if _, err := adapters.New(&types.ProviderConfig{Name: "unknown_dex"}, logrus.New()); !errors.Is(err, adapters.ErrUnknownAdapter) {
+		t.Errorf("未注册的适配器类型: 错误 = %v, 期望 %v", err, adapters.ErrUnknownAdapter)
+	}
+	// http_json适配器缺少声明式配置时创建失败
+	if _, err := adapters.New(&types.ProviderConfig{Name: "example_dex", Adapter: types.AdapterHTTPJSON}, logrus.New()); err == nil {
+		t.Error("缺少http_json配置时应返回错误")
+	}
+}
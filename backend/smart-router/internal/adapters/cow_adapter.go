@@ -25,13 +25,22 @@ type CowAdapter struct {
 	*BaseAdapter // 嵌入基础适配器
 }
 
+// cowEthFlowChains 部署了CoW ETH流(EthFlow)合约的链
+// CoW订单只能卖出ERC20代币，卖出原生代币时由EthFlow合约包装后在链上创建以包装代币计价的订单
+var cowEthFlowChains = map[uint]bool{1: true, 100: true, 8453: true, 42161: true, 11155111: true}
+
 // NewCowAdapter 创建CoW Protocol适配器实例
+// CoW Protocol公开接口不需要API密钥
 func NewCowAdapter(config *types.ProviderConfig, logger *logrus.Logger) ProviderAdapter {
 	return &CowAdapter{
-		BaseAdapter: NewBaseAdapter(config, logger),
+		BaseAdapter: NewBaseAdapter(config, logger).withDefaultAuth(types.AuthSchemeNone, ""),
 	}
 }
 
+func init() {
+	Register(types.ProviderCowswap, constructor(NewCowAdapter))
+}
+
 // ========================================
 // CoW Protocol API响应结构定义
 // ========================================
@@ -82,6 +91,9 @@ func (a *CowAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 			Message: fmt.Sprintf("CoW Protocol不支持链ID: %d", req.ChainID),
 		}
 	}
+	if err := a.CheckTokens(req); err != nil {
+		return nil, err
+	}
 
 	// 构建请求URL
 	apiURL, err := a.buildQuoteURL(req)
@@ -98,42 +110,17 @@ func (a *CowAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 		userAddress = req.UserAddress
 	}
 
-	// 直接使用用户选择的代币，不做任何转换
-	// 如果用户选择ETH就是ETH，选择WETH就是WETH，我们不应该私自替换
-	fromToken := req.FromToken
-	toToken := req.ToToken
-
-	a.logger.Infof("[CoW] 使用用户选择的代币: %s -> %s", fromToken, toToken)
-
-	// 构建符合API文档的请求体
-	// 动态计算appDataHash，不使用固定值
-	appData := "{\"version\":\"0.9.0\",\"metadata\":{}}"
-	appDataHash, err := a.calculateAppDataHash(appData)
-	if err != nil {
-		return nil, fmt.Errorf("计算appDataHash失败: %w", err)
+	// 卖出原生代币时改用包装代币作为卖出代币(ETH流订单)，其他代币原样使用
+	sellToken, ethFlow := a.sellToken(req)
+	if ethFlow {
+		a.logger.Infof("[CoW] 卖出原生代币，使用ETH流订单: %s -> %s", sellToken, req.ToToken)
+	} else {
+		a.logger.Infof("[CoW] 使用用户选择的代币: %s -> %s", sellToken, req.ToToken)
 	}
 
-	requestBody := map[string]interface{}{
-		"sellToken":           fromToken,             // 卖出代币合约地址（可能转换后的）
-		"buyToken":            toToken,               // 买入代币合约地址（可能转换后的）
-		"receiver":            userAddress,           // 接收地址
-		"appData":             appData,               // 应用元数据
-		"appDataHash":         appDataHash,           // 正确计算的应用数据哈希
-		"sellTokenBalance":    "erc20",               // 卖出代币余额类型
-		"buyTokenBalance":     "erc20",               // 买入代币余额类型
-		"from":                userAddress,           // 发起者地址
-		"priceQuality":        "verified",            // 价格质量要求
-		"signingScheme":       "eip712",              // 签名方案
-		"onchainOrder":        false,                 // 链下订单
-		"timeout":             0,                     // 超时时间
-		"kind":                "sell",                // 订单类型：卖出固定数量
-		"sellAmountBeforeFee": req.AmountIn.String(), // 卖出数量（手续费前）
-	}
-
-	// 序列化请求体为JSON
-	jsonBody, err := json.Marshal(requestBody)
+	jsonBody, err := a.buildQuoteBody(req, userAddress)
 	if err != nil {
-		return nil, fmt.Errorf("序列化请求体失败: %w", err)
+		return nil, err
 	}
 
 	a.logger.Debugf("[CoW] 请求URL: %s", apiURL)
@@ -146,7 +133,7 @@ func (a *CowAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 			Provider:     types.ProviderCowswap,
 			Success:      false,
 			ResponseTime: time.Since(startTime),
-			ErrorCode:    types.ErrCodeProviderError,
+			ErrorCode:    httpErrorCode(err),
 			ErrorMessage: err.Error(),
 		}, nil // 返回失败的报价，不返回error，让聚合器继续处理其他提供商
 	}
@@ -197,6 +184,97 @@ func (a *CowAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 	return quote, nil
 }
 
+// BuildSwap 构建CoW Protocol订单
+// CoW Protocol不通过链上交易成交：先以用户地址获取报价，再生成待签名的订单载荷，
+// 用户对订单进行EIP-712签名后提交到/orders，由solver批量结算
+func (a *CowAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	if err := a.validateSwapRequest(req); err != nil {
+		return nil, err
+	}
+	if err := a.CheckTokens(&req.QuoteRequest); err != nil {
+		return nil, err
+	}
+	// ETH流订单需用户携带原生代币调用EthFlow合约在链上创建，不能通过签名订单提交
+	if _, ethFlow := a.sellToken(&req.QuoteRequest); ethFlow {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeNativeTokenUnsupported,
+			Message:  "CoW ETH流订单需通过EthFlow合约在链上创建，暂不支持构建",
+			Provider: types.ProviderCowswap,
+		}
+	}
+
+	apiURL, err := a.buildQuoteURL(&req.QuoteRequest)
+	if err != nil {
+		return nil, fmt.Errorf("构建请求URL失败: %w", err)
+	}
+
+	jsonBody, err := a.buildQuoteBody(&req.QuoteRequest, req.UserAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := a.makeHTTPRequest(ctx, "POST", apiURL, bytes.NewReader(jsonBody), nil)
+	if err != nil {
+		return nil, fmt.Errorf("CoW报价请求失败: %w", err)
+	}
+
+	var cowResponse CowQuoteResponse
+	if err := a.parseJSONResponse(responseBody, &cowResponse); err != nil {
+		return nil, err
+	}
+
+	buyAmount, err := decimal.NewFromString(cowResponse.Quote.BuyAmount)
+	if err != nil {
+		return nil, fmt.Errorf("解析buyAmount失败: %w", err)
+	}
+	sellAmount, err := decimal.NewFromString(cowResponse.Quote.SellAmount)
+	if err != nil {
+		return nil, fmt.Errorf("解析sellAmount失败: %w", err)
+	}
+	feeAmount, err := decimal.NewFromString(cowResponse.Quote.FeeAmount)
+	if err != nil {
+		feeAmount = decimal.Zero
+	}
+
+	// 订单手续费已并入卖出数量，买入数量按滑点下调作为成交下限
+	minBuyAmount := a.minAmountOut(buyAmount, req.Slippage)
+	order := map[string]interface{}{
+		"sellToken":         cowResponse.Quote.SellToken,
+		"buyToken":          cowResponse.Quote.BuyToken,
+		"receiver":          req.UserAddress,
+		"sellAmount":        sellAmount.Add(feeAmount).String(),
+		"buyAmount":         minBuyAmount.String(),
+		"validTo":           cowResponse.Quote.ValidTo,
+		"appData":           cowResponse.Quote.AppData,
+		"feeAmount":         "0",
+		"kind":              cowResponse.Quote.Kind,
+		"partiallyFillable": cowResponse.Quote.PartiallyFillable,
+		"sellTokenBalance":  cowResponse.Quote.SellTokenBalance,
+		"buyTokenBalance":   cowResponse.Quote.BuyTokenBalance,
+		"signingScheme":     cowResponse.Quote.SigningScheme,
+		"from":              req.UserAddress,
+		"quoteId":           cowResponse.ID,
+	}
+
+	a.logger.Infof("[CoW] 订单构建成功: quoteId=%d, buyAmount=%s, minBuyAmount=%s",
+		cowResponse.ID, buyAmount.String(), minBuyAmount.String())
+
+	return &types.SwapTransaction{
+		RequestID:    req.RequestID,
+		Provider:     types.ProviderCowswap,
+		Kind:         types.SwapKindOrder,
+		ChainID:      req.ChainID,
+		From:         req.UserAddress,
+		Gas:          0, // 链下签名，Gas由solver承担
+		AmountIn:     req.AmountIn,
+		AmountOut:    buyAmount,
+		MinAmountOut: minBuyAmount,
+		Order:        order,
+		OrderURL:     fmt.Sprintf("%s/orders", strings.TrimSuffix(a.config.BaseURL, "/")),
+		Timestamp:    time.Now(),
+	}, nil
+}
+
 // ========================================
 // CoW Protocol URL构建和数据转换
 // ========================================
@@ -214,6 +292,54 @@ func (a *CowAdapter) buildQuoteURL(req *types.QuoteRequest) (string, error) {
 	return apiURL, nil
 }
 
+// buildQuoteBody 构建CoW Protocol报价请求体
+// 根据API文档构建，appDataHash动态计算而不使用固定值
+func (a *CowAdapter) buildQuoteBody(req *types.QuoteRequest, userAddress string) ([]byte, error) {
+	appData := "{\"version\":\"0.9.0\",\"metadata\":{}}"
+	appDataHash, err := a.calculateAppDataHash(appData)
+	if err != nil {
+		return nil, fmt.Errorf("计算appDataHash失败: %w", err)
+	}
+
+	requestBody := map[string]interface{}{
+		"sellToken":        req.FromToken, // 卖出代币合约地址
+		"buyToken":         req.ToToken,   // 买入代币合约地址
+		"receiver":         userAddress,   // 接收地址
+		"appData":          appData,       // 应用元数据
+		"appDataHash":      appDataHash,   // 正确计算的应用数据哈希
+		"sellTokenBalance": "erc20",       // 卖出代币余额类型
+		"buyTokenBalance":  "erc20",       // 买入代币余额类型
+		"from":             userAddress,   // 发起者地址
+		"priceQuality":     "verified",    // 价格质量要求
+		"signingScheme":    "eip712",      // 签名方案
+		"onchainOrder":     false,         // 链下订单
+		"timeout":          0,             // 超时时间
+	}
+
+	// 订单类型：sell卖出固定数量(手续费前)，buy买入固定数量(手续费后)
+	if req.IsExactOutput() {
+		requestBody["kind"] = "buy"
+		requestBody["buyAmountAfterFee"] = req.AmountOut.String()
+	} else {
+		requestBody["kind"] = "sell"
+		requestBody["sellAmountBeforeFee"] = req.AmountIn.String()
+	}
+
+	// ETH流订单以包装代币作为卖出代币，由EthFlow合约在链上创建，订单签名按EIP-1271由合约验证
+	if sellToken, ethFlow := a.sellToken(req); ethFlow {
+		requestBody["sellToken"] = sellToken
+		requestBody["onchainOrder"] = true
+		requestBody["signingScheme"] = "eip1271"
+	}
+
+	// 序列化请求体为JSON
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求体失败: %w", err)
+	}
+	return jsonBody, nil
+}
+
 // convertToStandardQuote 将CoW Protocol响应转换为标准报价格式
 func (a *CowAdapter) convertToStandardQuote(cowResp *CowQuoteResponse, req *types.QuoteRequest, startTime time.Time) (*types.ProviderQuote, error) {
 	// 解析买入数量（从嵌套的quote对象中获取）
@@ -229,17 +355,21 @@ func (a *CowAdapter) convertToStandardQuote(cowResp *CowQuoteResponse, req *type
 		feeAmount = decimal.Zero // 设为0继续处理
 	}
 
+	// 解析卖出数量，所需输入为卖出数量加手续费
+	sellAmount, err := decimal.NewFromString(cowResp.Quote.SellAmount)
+	if err != nil {
+		return nil, fmt.Errorf("解析sellAmount失败: %w", err)
+	}
+
 	// 验证响应数据的一致性
-	if cowResp.Quote.SellToken != req.FromToken {
-		a.logger.Warnf("[CoW] 卖出代币地址不匹配: 请求=%s, 响应=%s", req.FromToken, cowResp.Quote.SellToken)
+	sellToken, ethFlow := a.sellToken(req)
+	if !strings.EqualFold(cowResp.Quote.SellToken, sellToken) {
+		a.logger.Warnf("[CoW] 卖出代币地址不匹配: 请求=%s, 响应=%s", sellToken, cowResp.Quote.SellToken)
 	}
-	if cowResp.Quote.BuyToken != req.ToToken {
+	if !strings.EqualFold(cowResp.Quote.BuyToken, req.ToToken) {
 		a.logger.Warnf("[CoW] 买入代币地址不匹配: 请求=%s, 响应=%s", req.ToToken, cowResp.Quote.BuyToken)
 	}
 
-	// 计算价格影响
-	priceImpact := a.calculatePriceImpact(req.AmountIn, buyAmount)
-
 	// CoW Protocol通常有较低的Gas费用，因为它使用批处理
 	gasEstimate := uint64(150000)
 
@@ -252,35 +382,28 @@ func (a *CowAdapter) convertToStandardQuote(cowResp *CowQuoteResponse, req *type
 	a.logger.Infof("[CoW] 报价转换成功: buyAmount=%s, feeAmount=%s, verified=%t",
 		buyAmount.String(), feeAmount.String(), cowResp.Verified)
 
-	return &types.ProviderQuote{
+	// CoW Protocol使用批处理，不提供详细路由，只返回原生代币的包装/解包步骤
+	route := []types.RouteStep{}
+	wrapped, _ := types.WrappedNativeToken(req.ChainID)
+	if ethFlow {
+		route = append(route, types.RouteStep{Protocol: types.RouteProtocolWrapNative, Percentage: decimal.NewFromInt(1), Pool: wrapped})
+	}
+	if types.IsNativeToken(req.ChainID, req.ToToken) {
+		route = append(route, types.RouteStep{Protocol: types.RouteProtocolUnwrapNative, Percentage: decimal.NewFromInt(1), Pool: wrapped})
+	}
+
+	quote := &types.ProviderQuote{
 		Provider:     types.ProviderCowswap,
 		Success:      true,
 		AmountOut:    buyAmount,
 		GasEstimate:  gasEstimate,
-		PriceImpact:  priceImpact,
-		Route:        []types.RouteStep{}, // CoW Protocol使用批处理，不提供详细路由
+		Route:        route,
 		ResponseTime: time.Since(startTime),
 		Confidence:   confidence,
-	}, nil
-}
-
-// calculatePriceImpact 计算价格影响
-func (a *CowAdapter) calculatePriceImpact(amountIn, amountOut decimal.Decimal) decimal.Decimal {
-	if amountIn.IsZero() {
-		return decimal.Zero
-	}
-
-	// 简化的价格影响计算
-	// 实际应该基于市场价格，这里使用近似计算
-	expectedOut := amountIn // 假设1:1汇率作为基准
-	actualOut := amountOut
-
-	if expectedOut.IsZero() {
-		return decimal.Zero
+		RawResponse:  cowResp, // 保存原始响应用于调试和报价溯源
 	}
-
-	impact := expectedOut.Sub(actualOut).Div(expectedOut).Abs()
-	return impact
+	a.setInputAmounts(quote, sellAmount.Add(feeAmount), req)
+	return quote, nil
 }
 
 // ========================================
@@ -305,9 +428,9 @@ func (a *CowAdapter) HealthCheck(ctx context.Context) error {
 // 工具方法
 // ========================================
 
-// GetName 返回适配器名称
+// GetName 返回配置中的适配器名称，与聚合器配置的name一致以便注册校验和按名称查找
 func (a *CowAdapter) GetName() string {
-	return string(types.ProviderCowswap)
+	return a.config.Name
 }
 
 // GetDisplayName 返回显示名称
@@ -325,6 +448,43 @@ func (a *CowAdapter) IsSupported(chainID uint) bool {
 	return false
 }
 
+// CheckTokens 检查是否支持请求的代币
+// 卖出原生代币需通过ETH流下单：链上需部署EthFlow合约，且只支持卖出报价；
+// 买入原生代币时由结算合约解包后支付，无需额外处理
+func (a *CowAdapter) CheckTokens(req *types.QuoteRequest) error {
+	if !types.IsNativeToken(req.ChainID, req.FromToken) {
+		return nil
+	}
+
+	var reason string
+	wrapped, ok := types.WrappedNativeToken(req.ChainID)
+	switch {
+	case !ok || !cowEthFlowChains[req.ChainID]:
+		reason = fmt.Sprintf("CoW Protocol在链%d上没有ETH流合约，无法卖出原生代币", req.ChainID)
+	case req.IsExactOutput():
+		reason = "CoW ETH流订单只支持卖出报价，无法以原生代币作为买入报价的输入"
+	case strings.EqualFold(req.ToToken, wrapped):
+		reason = "原生代币与包装代币互换无需通过CoW Protocol"
+	default:
+		return nil
+	}
+	return &types.RouterError{
+		Code:     types.ErrCodeNativeTokenUnsupported,
+		Message:  reason,
+		Provider: types.ProviderCowswap,
+	}
+}
+
+// sellToken 返回CoW订单的卖出代币，卖出原生代币时为包装代币并返回true(ETH流订单)
+func (a *CowAdapter) sellToken(req *types.QuoteRequest) (string, bool) {
+	if types.IsNativeToken(req.ChainID, req.FromToken) {
+		if wrapped, ok := types.WrappedNativeToken(req.ChainID); ok {
+			return wrapped, true
+		}
+	}
+	return req.FromToken, false
+}
+
 // calculateAppDataHash 计算应用数据哈希
 // CoW Protocol要求appDataHash必须与appData内容的Keccak256哈希匹配
 func (a *CowAdapter) calculateAppDataHash(appData string) (string, error) {
@@ -339,45 +499,3 @@ func (a *CowAdapter) calculateAppDataHash(appData string) (string, error) {
 	a.logger.Debugf("[CoW] 计算appDataHash: appData=%s, hash=%s", appData, hashHex)
 	return hashHex, nil
 }
-
-// getWETHAddress 动态获取指定链的WETH代币地址
-// 从业务逻辑服务查询WETH地址，完全避免硬编码
-func (a *CowAdapter) getWETHAddress(chainID uint) (string, error) {
-	// 使用搜索API查询WETH代币，然后筛选指定链
-	businessLogicURL := "http://localhost:5177" // 可以从配置获取
-	queryURL := fmt.Sprintf("%s/api/v1/tokens/search?q=WETH", businessLogicURL)
-
-	// 发送GET请求查询WETH代币信息
-	resp, err := a.makeHTTPRequest(context.Background(), "GET", queryURL, nil, nil)
-	if err != nil {
-		return "", fmt.Errorf("查询WETH代币失败: %w", err)
-	}
-
-	// 解析响应获取WETH地址
-	var tokenResponse struct {
-		Success bool `json:"success"`
-		Data    []struct {
-			Symbol          string `json:"symbol"`
-			ContractAddress string `json:"contract_address"`
-			ChainID         uint   `json:"chain_id"`
-		} `json:"data"`
-	}
-
-	if err := a.parseJSONResponse(resp, &tokenResponse); err != nil {
-		return "", fmt.Errorf("解析WETH查询响应失败: %w", err)
-	}
-
-	if !tokenResponse.Success {
-		return "", fmt.Errorf("WETH查询失败")
-	}
-
-	// 查找指定链上的WETH代币
-	for _, token := range tokenResponse.Data {
-		if token.Symbol == "WETH" && token.ChainID == chainID {
-			a.logger.Debugf("[CoW] 从业务逻辑服务获取链%d的WETH地址: %s", chainID, token.ContractAddress)
-			return token.ContractAddress, nil
-		}
-	}
-
-	return "", fmt.Errorf("链%d上没有找到WETH代币", chainID)
-}
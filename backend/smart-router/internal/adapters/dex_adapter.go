@@ -0,0 +1,460 @@
+// Package adapters 直连DEX适配器公共实现
+// 直连DEX适配器不依赖第三方聚合器API，通过链JSON-RPC节点eth_call调用DEX合约获取报价：
+// 先批量查询候选路径(直连和经中间代币的两跳)上的池子，再批量报价存在流动性的路径，选出最优路径
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// dexAdapter 直连DEX适配器公共部分
+// 负责合约地址和RPC节点选择、候选路径生成、原生代币包装以及报价结果转换，具体协议的合约调用由各适配器实现
+type dexAdapter struct {
+	*BaseAdapter // 嵌入基础适配器
+
+	provider    string                      // 适配器名称(types.ProviderUniswapV3等)
+	protocol    string                      // 路径步骤的协议名称(UNISWAP_V3等)
+	logTag      string                      // 日志前缀
+	deployments map[uint]types.DEXContracts // 内置的官方部署地址
+}
+
+// dexPath 候选交易路径
+type dexPath struct {
+	tokens []string // 途经代币(原生代币已替换为包装代币)
+	fees   []uint32 // 每一跳的池子手续费档位(仅Uniswap V3)
+	pools  []string // 每一跳的池子地址
+}
+
+// dexHop 池子查询的单跳：代币对(按小写地址排序)和手续费档位(Uniswap V2为0)
+type dexHop struct {
+	tokenA string
+	tokenB string
+	fee    uint32
+}
+
+// dexProtocol 具体DEX协议的合约调用编解码
+type dexProtocol interface {
+	feeTiers() []uint32                                                                                 // 探索的手续费档位
+	encodePoolCall(hop dexHop) ([]byte, error)                                                          // 编码工厂合约的池子查询调用
+	encodeQuoteCall(req *types.QuoteRequest, path dexPath, amount *big.Int) ([]byte, error)             // 编码报价合约调用
+	decodeQuote(req *types.QuoteRequest, path dexPath, amount *big.Int, data []byte) (*dexQuote, error) // 解码报价结果
+}
+
+// dexQuote 候选路径的报价结果
+type dexQuote struct {
+	path        dexPath  // 交易路径
+	amountIn    *big.Int // 输入数量
+	amountOut   *big.Int // 输出数量
+	gasEstimate uint64   // Gas估算
+}
+
+// dexRawQuote 直连DEX报价的原始数据(作为ProviderQuote.RawResponse返回，用于报价溯源)
+type dexRawQuote struct {
+	Path        []string `json:"path"`           // 途经代币
+	Fees        []uint32 `json:"fees,omitempty"` // 每一跳的手续费档位
+	Pools       []string `json:"pools"`          // 每一跳的池子地址
+	AmountIn    string   `json:"amount_in"`      // 合约返回的输入数量
+	AmountOut   string   `json:"amount_out"`     // 合约返回的输出数量
+	GasEstimate uint64   `json:"gas_estimate"`   // Gas估算
+}
+
+// dexConfidence 直连DEX报价的置信度
+// 报价来自链上合约的确定性计算，但只覆盖单个DEX且未考虑报价到成交之间的状态变化
+var dexConfidence = decimal.NewFromFloat(0.9)
+
+// ========================================
+// 配置解析
+// ========================================
+
+// contracts 获取链上的DEX合约地址，配置优先于内置部署地址
+func (a *dexAdapter) contracts(chainID uint) (types.DEXContracts, bool) {
+	if a.config.DEX != nil {
+		if contracts, ok := a.config.DEX.Contracts[chainID]; ok && contracts.Factory != "" && contracts.Quoter != "" {
+			return contracts, true
+		}
+	}
+	contracts, ok := a.deployments[chainID]
+	return contracts, ok
+}
+
+// rpcURL 获取链的JSON-RPC节点URL，未单独配置的链使用BaseURL
+func (a *dexAdapter) rpcURL(chainID uint) string {
+	if a.config.DEX != nil {
+		if url := a.config.DEX.RPCURLs[chainID]; url != "" {
+			return url
+		}
+	}
+	return a.config.BaseURL
+}
+
+// intermediateTokens 获取两跳路径的中间代币，未配置时使用包装原生代币
+func (a *dexAdapter) intermediateTokens(chainID uint) []string {
+	if a.config.DEX != nil {
+		if tokens, ok := a.config.DEX.IntermediateTokens[chainID]; ok {
+			return tokens
+		}
+	}
+	if wrapped, ok := types.WrappedNativeToken(chainID); ok {
+		return []string{wrapped}
+	}
+	return nil
+}
+
+// configuredFeeTiers 获取配置的池子手续费档位，未配置时使用默认档位
+func (a *dexAdapter) configuredFeeTiers(defaults []uint32) []uint32 {
+	if a.config.DEX != nil && len(a.config.DEX.FeeTiers) > 0 {
+		return a.config.DEX.FeeTiers
+	}
+	return defaults
+}
+
+// ========================================
+// 代币和路径
+// ========================================
+
+// dexToken 将原生代币转换为DEX池子中的包装代币，其他代币原样返回
+func (a *dexAdapter) dexToken(chainID uint, token string) string {
+	if types.IsNativeToken(chainID, token) {
+		if wrapped, ok := types.WrappedNativeToken(chainID); ok {
+			return wrapped
+		}
+	}
+	return token
+}
+
+// tokenPaths 生成候选代币路径：直连路径和经每个中间代币的两跳路径
+func (a *dexAdapter) tokenPaths(req *types.QuoteRequest) [][]string {
+	fromToken := a.dexToken(req.ChainID, req.FromToken)
+	toToken := a.dexToken(req.ChainID, req.ToToken)
+
+	paths := [][]string{{fromToken, toToken}}
+	seen := map[string]bool{strings.ToLower(fromToken): true, strings.ToLower(toToken): true}
+	for _, token := range a.intermediateTokens(req.ChainID) {
+		if seen[strings.ToLower(token)] {
+			continue
+		}
+		seen[strings.ToLower(token)] = true
+		paths = append(paths, []string{fromToken, token, toToken})
+	}
+	return paths
+}
+
+// requestedAmount 获取报价的固定数量：卖出报价为输入数量，买入报价为期望输出数量
+func (a *dexAdapter) requestedAmount(req *types.QuoteRequest) *big.Int {
+	if req.IsExactOutput() {
+		return req.AmountOut.BigInt()
+	}
+	return req.AmountIn.BigInt()
+}
+
+// selectBest 选择最优路径：卖出报价输出最多，买入报价所需输入最少
+func (a *dexAdapter) selectBest(req *types.QuoteRequest, quotes []dexQuote) *dexQuote {
+	var best *dexQuote
+	for i := range quotes {
+		quote := &quotes[i]
+		switch {
+		case best == nil:
+			best = quote
+		case req.IsExactOutput() && quote.amountIn.Cmp(best.amountIn) < 0:
+			best = quote
+		case !req.IsExactOutput() && quote.amountOut.Cmp(best.amountOut) > 0:
+			best = quote
+		}
+	}
+	return best
+}
+
+// newDEXHop 创建单跳，代币对按小写地址排序以便去重
+func newDEXHop(tokenA, tokenB string, fee uint32) dexHop {
+	tokenA, tokenB = strings.ToLower(tokenA), strings.ToLower(tokenB)
+	if tokenB < tokenA {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	return dexHop{tokenA: tokenA, tokenB: tokenB, fee: fee}
+}
+
+// candidateHops 列出候选路径涉及的所有单跳(去重)
+func (a *dexAdapter) candidateHops(tokenPaths [][]string, fees []uint32) []dexHop {
+	var hops []dexHop
+	seen := make(map[dexHop]bool)
+	for _, tokens := range tokenPaths {
+		for i := 0; i+1 < len(tokens); i++ {
+			for _, fee := range fees {
+				hop := newDEXHop(tokens[i], tokens[i+1], fee)
+				if !seen[hop] {
+					seen[hop] = true
+					hops = append(hops, hop)
+				}
+			}
+		}
+	}
+	return hops
+}
+
+// expandPaths 按存在的池子展开候选路径：每一跳在每个存在池子的手续费档位上各生成一条路径
+func (a *dexAdapter) expandPaths(tokenPaths [][]string, fees []uint32, pools map[dexHop]string) []dexPath {
+	var paths []dexPath
+	for _, tokens := range tokenPaths {
+		partial := []dexPath{{tokens: tokens}}
+		for i := 0; i+1 < len(tokens); i++ {
+			var next []dexPath
+			for _, path := range partial {
+				for _, fee := range fees {
+					pool, ok := pools[newDEXHop(tokens[i], tokens[i+1], fee)]
+					if !ok {
+						continue
+					}
+					next = append(next, dexPath{
+						tokens: tokens,
+						fees:   append(append([]uint32{}, path.fees...), fee),
+						pools:  append(append([]string{}, path.pools...), pool),
+					})
+				}
+			}
+			partial = next
+		}
+		paths = append(paths, partial...)
+	}
+	return paths
+}
+
+// ========================================
+// 报价流程
+// ========================================
+
+// getQuote 直连DEX报价流程
+// 第一轮批量调用工厂合约查询候选路径各跳的池子，第二轮批量调用报价合约报价所有池子都存在的路径；
+// RPC请求失败返回PROVIDER_ERROR失败报价，没有池子或所有路径报价失败返回INSUFFICIENT_LIQUIDITY失败报价
+func (a *dexAdapter) getQuote(ctx context.Context, req *types.QuoteRequest, protocol dexProtocol) (*types.ProviderQuote, error) {
+	startTime := time.Now()
+
+	if err := a.checkQuoteRequest(req); err != nil {
+		return nil, err
+	}
+	contracts, _ := a.contracts(req.ChainID)
+	rpcURL := a.rpcURL(req.ChainID)
+	amount := a.requestedAmount(req)
+
+	// 第一轮：查询池子
+	tokenPaths := a.tokenPaths(req)
+	fees := protocol.feeTiers()
+	hops := a.candidateHops(tokenPaths, fees)
+
+	poolCalls := make([]ethCall, len(hops))
+	for i, hop := range hops {
+		data, err := protocol.encodePoolCall(hop)
+		if err != nil {
+			return nil, &types.RouterError{Code: types.ErrCodeInvalidRequest, Message: err.Error(), Provider: a.provider}
+		}
+		poolCalls[i] = ethCall{To: contracts.Factory, Data: data}
+	}
+
+	poolResults, err := a.batchEthCall(ctx, rpcURL, poolCalls)
+	if err != nil {
+		a.logger.Errorf("[%s] 查询池子失败: %v", a.logTag, err)
+		return a.failedQuote(startTime, types.ErrCodeProviderError, fmt.Sprintf("查询池子失败: %v", err)), nil
+	}
+
+	pools := make(map[dexHop]string)
+	for i, result := range poolResults {
+		if result.Err != nil {
+			a.logger.Debugf("[%s] 池子查询失败: %s/%s fee=%d, error=%v", a.logTag, hops[i].tokenA, hops[i].tokenB, hops[i].fee, result.Err)
+			continue
+		}
+		if pool, err := decodeAddress(result.Data, 0); err == nil && !isZeroAddress(pool) {
+			pools[hops[i]] = pool
+		}
+	}
+
+	paths := a.expandPaths(tokenPaths, fees, pools)
+	if len(paths) == 0 {
+		return a.failedQuote(startTime, types.ErrCodeInsufficientLiquidity,
+			fmt.Sprintf("%s没有%s -> %s的流动性池", a.config.DisplayName, req.FromToken, req.ToToken)), nil
+	}
+	a.logger.Debugf("[%s] 找到 %d 个池子, %d 条候选路径", a.logTag, len(pools), len(paths))
+
+	// 第二轮：报价候选路径
+	quoteCalls := make([]ethCall, len(paths))
+	for i, path := range paths {
+		data, err := protocol.encodeQuoteCall(req, path, amount)
+		if err != nil {
+			return nil, &types.RouterError{Code: types.ErrCodeInvalidRequest, Message: err.Error(), Provider: a.provider}
+		}
+		quoteCalls[i] = ethCall{To: contracts.Quoter, Data: data}
+	}
+
+	quoteResults, err := a.batchEthCall(ctx, rpcURL, quoteCalls)
+	if err != nil {
+		a.logger.Errorf("[%s] 路径报价失败: %v", a.logTag, err)
+		return a.failedQuote(startTime, types.ErrCodeProviderError, fmt.Sprintf("路径报价失败: %v", err)), nil
+	}
+
+	quotes := make([]dexQuote, 0, len(paths))
+	for i, result := range quoteResults {
+		if result.Err != nil {
+			// 池子流动性不足以完成交易时报价合约revert
+			a.logger.Debugf("[%s] 路径报价失败: %s, error=%v", a.logTag, strings.Join(paths[i].tokens, " -> "), result.Err)
+			continue
+		}
+		quote, err := protocol.decodeQuote(req, paths[i], amount, result.Data)
+		if err != nil {
+			a.logger.Warnf("[%s] 解析路径报价失败: %v", a.logTag, err)
+			continue
+		}
+		quotes = append(quotes, *quote)
+	}
+
+	best := a.selectBest(req, quotes)
+	if best == nil {
+		return a.failedQuote(startTime, types.ErrCodeInsufficientLiquidity,
+			fmt.Sprintf("%s所有候选路径报价失败，流动性不足", a.config.DisplayName)), nil
+	}
+	return a.convertToStandardQuote(best, req, startTime), nil
+}
+
+// ========================================
+// 报价结果转换
+// ========================================
+
+// failedQuote 构建失败报价
+func (a *dexAdapter) failedQuote(startTime time.Time, errorCode, message string) *types.ProviderQuote {
+	return &types.ProviderQuote{
+		Provider:     a.provider,
+		Success:      false,
+		ResponseTime: time.Since(startTime),
+		ErrorCode:    errorCode,
+		ErrorMessage: message,
+	}
+}
+
+// convertToStandardQuote 将最优路径报价转换为标准报价格式
+// 路径包含每一跳的池子地址，原生代币输入/输出时在首尾加入包装/解包步骤
+func (a *dexAdapter) convertToStandardQuote(best *dexQuote, req *types.QuoteRequest, startTime time.Time) *types.ProviderQuote {
+	wrapped, _ := types.WrappedNativeToken(req.ChainID)
+	one := decimal.NewFromInt(1)
+
+	route := make([]types.RouteStep, 0, len(best.path.pools)+2)
+	if types.IsNativeToken(req.ChainID, req.FromToken) {
+		route = append(route, types.RouteStep{Protocol: types.RouteProtocolWrapNative, Percentage: one, Pool: wrapped})
+	}
+	for _, pool := range best.path.pools {
+		route = append(route, types.RouteStep{Protocol: a.protocol, Percentage: one, Pool: pool})
+	}
+	if types.IsNativeToken(req.ChainID, req.ToToken) {
+		route = append(route, types.RouteStep{Protocol: types.RouteProtocolUnwrapNative, Percentage: one, Pool: wrapped})
+	}
+
+	a.logger.Infof("[%s] 最优路径: %s, amountIn=%s, amountOut=%s, gas=%d", a.logTag,
+		strings.Join(best.path.tokens, " -> "), best.amountIn.String(), best.amountOut.String(), best.gasEstimate)
+
+	quote := &types.ProviderQuote{
+		Provider:     a.provider,
+		Success:      true,
+		AmountOut:    decimal.NewFromBigInt(best.amountOut, 0),
+		GasEstimate:  best.gasEstimate,
+		Route:        route,
+		ResponseTime: time.Since(startTime),
+		Confidence:   dexConfidence,
+		RawResponse: &dexRawQuote{
+			Path:        best.path.tokens,
+			Fees:        best.path.fees,
+			Pools:       best.path.pools,
+			AmountIn:    best.amountIn.String(),
+			AmountOut:   best.amountOut.String(),
+			GasEstimate: best.gasEstimate,
+		},
+	}
+	a.setInputAmounts(quote, decimal.NewFromBigInt(best.amountIn, 0), req)
+	return quote
+}
+
+// ========================================
+// 适配器接口公共实现
+// ========================================
+
+// checkQuoteRequest 检查链和代币支持
+func (a *dexAdapter) checkQuoteRequest(req *types.QuoteRequest) error {
+	if !a.IsSupported(req.ChainID) {
+		return &types.RouterError{
+			Code:    types.ErrCodeUnsupportedChain,
+			Message: fmt.Sprintf("%s不支持链ID: %d", a.config.DisplayName, req.ChainID),
+		}
+	}
+	return a.CheckTokens(req)
+}
+
+// BuildSwap 直连DEX适配器暂只提供报价，不构建交易
+func (a *dexAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	return nil, &types.RouterError{
+		Code:     types.ErrCodeSwapNotSupported,
+		Message:  fmt.Sprintf("%s直连DEX适配器暂只提供报价，不支持构建交易", a.config.DisplayName),
+		Provider: a.provider,
+	}
+}
+
+// HealthCheck 检查各链JSON-RPC节点是否可用
+func (a *dexAdapter) HealthCheck(ctx context.Context) error {
+	checked := make(map[string]bool)
+	for _, chainID := range a.config.SupportedChains {
+		rpcURL := a.rpcURL(chainID)
+		if rpcURL == "" || checked[rpcURL] {
+			continue
+		}
+		checked[rpcURL] = true
+
+		if _, err := a.blockNumber(ctx, rpcURL); err != nil {
+			return fmt.Errorf("%s健康检查失败(链%d): %w", a.config.DisplayName, chainID, err)
+		}
+	}
+
+	a.logger.Debugf("[%s] 健康检查通过", a.logTag)
+	return nil
+}
+
+// GetName 返回适配器名称
+func (a *dexAdapter) GetName() string {
+	return a.provider
+}
+
+// IsSupported 检查是否支持指定链：链已配置、合约地址已知且有可用的RPC节点
+func (a *dexAdapter) IsSupported(chainID uint) bool {
+	if !a.BaseAdapter.IsSupported(chainID) {
+		return false
+	}
+	if _, ok := a.contracts(chainID); !ok {
+		return false
+	}
+	return a.rpcURL(chainID) != ""
+}
+
+// CheckTokens 检查是否支持请求的代币
+// 原生代币按包装代币在池子中报价，需已知链的包装代币；原生代币与包装代币互换不经过DEX池子
+func (a *dexAdapter) CheckTokens(req *types.QuoteRequest) error {
+	fromNative := types.IsNativeToken(req.ChainID, req.FromToken)
+	toNative := types.IsNativeToken(req.ChainID, req.ToToken)
+	if !fromNative && !toNative {
+		return nil
+	}
+
+	var reason string
+	if _, ok := types.WrappedNativeToken(req.ChainID); !ok {
+		reason = fmt.Sprintf("链%d的包装原生代币未知，%s无法报价原生代币", req.ChainID, a.config.DisplayName)
+	} else if strings.EqualFold(a.dexToken(req.ChainID, req.FromToken), a.dexToken(req.ChainID, req.ToToken)) {
+		reason = fmt.Sprintf("原生代币与包装代币互换无需通过%s", a.config.DisplayName)
+	} else {
+		return nil
+	}
+	return &types.RouterError{
+		Code:     types.ErrCodeNativeTokenUnsupported,
+		Message:  reason,
+		Provider: a.provider,
+	}
+}
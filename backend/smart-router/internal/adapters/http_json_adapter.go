@@ -0,0 +1,377 @@
+// Package adapters 声明式HTTP JSON适配器实现
+// 通过配置(types.HTTPJSONConfig)接入只需一次HTTP请求即可报价的简单聚合器：
+// 按URL/参数/请求头模板构建请求，按JSONPath从响应中提取输出数量和Gas估算，无需编写专用适配器代码
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// httpJSONConfidence 声明式适配器报价置信度(响应字段语义未经专用代码校验，低于专用适配器)
+var httpJSONConfidence = decimal.NewFromFloat(0.8)
+
+// httpJSONPlaceholder 模板占位符
+var httpJSONPlaceholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// 报价模板和健康检查模板支持的占位符
+var (
+	httpJSONQuotePlaceholders = map[string]bool{
+		"base_url": true, "chain_id": true, "from_token": true, "to_token": true, "amount_in": true,
+		"slippage": true, "slippage_percent": true, "slippage_bps": true, "user_address": true,
+	}
+	httpJSONHealthPlaceholders = map[string]bool{"base_url": true, "chain_id": true}
+)
+
+// HTTPJSONAdapter 声明式HTTP JSON适配器
+// 只支持卖出报价，不支持构建交易
+type HTTPJSONAdapter struct {
+	*BaseAdapter
+	spec *httpJSONSpec // 校验并解析后的声明式配置(创建后不再修改，配置变化时由路由服务重新创建适配器)
+}
+
+// httpJSONSpec 校验并解析后的声明式配置
+type httpJSONSpec struct {
+	config        *types.HTTPJSONConfig
+	method        string
+	amountOutPath *jsonPath
+	gasPath       *jsonPath // 可为nil
+	errorPath     *jsonPath // 可为nil
+}
+
+// NewHTTPJSONAdapter 创建声明式HTTP JSON适配器实例
+// 配置缺失、模板包含未知占位符或JSONPath无效时返回错误；
+// API密钥按AuthScheme/AuthHeader配置发送，未指定认证方式时不发送
+func NewHTTPJSONAdapter(config *types.ProviderConfig, logger *logrus.Logger) (ProviderAdapter, error) {
+	spec, err := compileHTTPJSONSpec(config.HTTPJSON)
+	if err != nil {
+		return nil, fmt.Errorf("聚合器 %s 的http_json配置无效: %w", config.Name, err)
+	}
+	return &HTTPJSONAdapter{
+		BaseAdapter: NewBaseAdapter(config, logger).withDefaultAuth(types.AuthSchemeNone, ""),
+		spec:        spec,
+	}, nil
+}
+
+func init() {
+	Register(types.AdapterHTTPJSON, NewHTTPJSONAdapter)
+}
+
+// compileHTTPJSONSpec 校验声明式配置并解析JSONPath
+func compileHTTPJSONSpec(config *types.HTTPJSONConfig) (*httpJSONSpec, error) {
+	if config == nil {
+		return nil, fmt.Errorf("缺少http_json配置")
+	}
+
+	method := strings.ToUpper(config.Method)
+	switch method {
+	case "":
+		method = http.MethodGet
+	case http.MethodGet, http.MethodPost:
+	default:
+		return nil, fmt.Errorf("不支持的HTTP方法: %s", config.Method)
+	}
+
+	if config.URLTemplate == "" {
+		return nil, fmt.Errorf("url_template不能为空")
+	}
+	if err := checkPlaceholders("url_template", config.URLTemplate, httpJSONQuotePlaceholders); err != nil {
+		return nil, err
+	}
+	for name, value := range config.Params {
+		if err := checkPlaceholders("params."+name, value, httpJSONQuotePlaceholders); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range config.Headers {
+		if err := checkPlaceholders("headers."+name, value, httpJSONQuotePlaceholders); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkPlaceholders("health_url", config.HealthURL, httpJSONHealthPlaceholders); err != nil {
+		return nil, err
+	}
+
+	spec := &httpJSONSpec{config: config.Clone(), method: method}
+	var err error
+	if config.AmountOutPath == "" {
+		return nil, fmt.Errorf("amount_out_path不能为空")
+	}
+	if spec.amountOutPath, err = parseJSONPath(config.AmountOutPath); err != nil {
+		return nil, err
+	}
+	if config.GasPath != "" {
+		if spec.gasPath, err = parseJSONPath(config.GasPath); err != nil {
+			return nil, err
+		}
+	}
+	if config.ErrorPath != "" {
+		if spec.errorPath, err = parseJSONPath(config.ErrorPath); err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+// checkPlaceholders 检查模板只使用支持的占位符
+func checkPlaceholders(field, template string, allowed map[string]bool) error {
+	for _, match := range httpJSONPlaceholder.FindAllStringSubmatch(template, -1) {
+		if !allowed[match[1]] {
+			return fmt.Errorf("%s 包含不支持的占位符 {%s}", field, match[1])
+		}
+	}
+	return nil
+}
+
+// ========================================
+// 接口实现
+// ========================================
+
+// GetQuote 获取声明式聚合器报价
+func (a *HTTPJSONAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error) {
+	startTime := time.Now()
+	config, spec := a.config, a.spec
+
+	// 检查链支持
+	if !a.IsSupported(req.ChainID) {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeUnsupportedChain,
+			Message:  fmt.Sprintf("%s不支持链ID: %d", config.DisplayName, req.ChainID),
+			Provider: config.Name,
+		}
+	}
+
+	// 检查报价方向支持
+	if !a.SupportsQuoteSide(req.Side) {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeUnsupportedQuoteSide,
+			Message:  fmt.Sprintf("%s为声明式适配器，只支持卖出固定数量，不支持买入报价", config.DisplayName),
+			Provider: config.Name,
+		}
+	}
+
+	// 按模板构建请求
+	values := a.quoteValues(req)
+	apiURL, body, headers, err := a.buildQuoteRequest(spec, values)
+	if err != nil {
+		return a.failedQuote(types.ErrCodeInvalidRequest, fmt.Sprintf("构建请求失败: %v", err), startTime), nil
+	}
+
+	a.logger.Debugf("[%s] 请求URL: %s", config.Name, apiURL)
+
+	// 发送HTTP请求
+	responseBody, err := a.makeHTTPRequest(ctx, spec.method, apiURL, body, headers)
+	if err != nil {
+		message := err.Error()
+		var statusErr *HTTPStatusError
+		if errors.As(err, &statusErr) {
+			if providerMessage, ok := a.extractError(spec, []byte(statusErr.Body)); ok {
+				message = fmt.Sprintf("HTTP %d: %s", statusErr.StatusCode, providerMessage)
+			}
+		}
+		return a.failedQuote(httpErrorCode(err), message, startTime), nil
+	}
+
+	// 解析响应
+	document, err := decodeJSONDocument(responseBody)
+	if err != nil {
+		a.logger.Errorf("[%s] %v, data=%s", config.Name, err, string(responseBody))
+		return a.failedQuote(types.ErrCodeProviderError, "响应解析失败", startTime), nil
+	}
+
+	providerQuote, err := a.convertToStandardQuote(spec, document, req, time.Since(startTime))
+	if err != nil {
+		message := fmt.Sprintf("数据转换失败: %v", err)
+		if providerMessage, ok := a.extractError(spec, responseBody); ok {
+			message = providerMessage
+		}
+		return a.failedQuote(types.ErrCodeProviderError, message, startTime), nil
+	}
+
+	a.logger.Debugf("[%s] 报价获取成功: amountOut=%s, gas=%d, duration=%v",
+		config.Name, providerQuote.AmountOut.String(), providerQuote.GasEstimate, time.Since(startTime))
+
+	return providerQuote, nil
+}
+
+// BuildSwap 声明式适配器只提供报价，不支持构建交易
+func (a *HTTPJSONAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	return nil, &types.RouterError{
+		Code:     types.ErrCodeSwapNotSupported,
+		Message:  fmt.Sprintf("%s为声明式适配器，不支持构建交易", a.config.DisplayName),
+		Provider: a.config.Name,
+	}
+}
+
+// HealthCheck 请求配置的健康检查URL，未配置时视为健康
+func (a *HTTPJSONAdapter) HealthCheck(ctx context.Context) error {
+	healthURL := a.spec.config.HealthURL
+	if healthURL == "" {
+		return nil
+	}
+	if len(a.config.SupportedChains) == 0 {
+		return fmt.Errorf("没有配置支持的链")
+	}
+
+	values := map[string]string{
+		"base_url": strings.TrimRight(a.config.BaseURL, "/"),
+		"chain_id": strconv.FormatUint(uint64(a.config.SupportedChains[0]), 10),
+	}
+	if _, err := a.makeHTTPRequest(ctx, http.MethodGet, expandTemplate(healthURL, values, true), nil, nil); err != nil {
+		return fmt.Errorf("%s健康检查失败: %w", a.config.DisplayName, err)
+	}
+
+	a.logger.Debugf("[%s] 健康检查通过", a.config.Name)
+	return nil
+}
+
+// SupportsQuoteSide 只支持卖出报价
+func (a *HTTPJSONAdapter) SupportsQuoteSide(side string) bool {
+	return side == "" || side == types.QuoteSideSell
+}
+
+// ========================================
+// 辅助方法
+// ========================================
+
+// quoteValues 报价请求对应的占位符取值
+func (a *HTTPJSONAdapter) quoteValues(req *types.QuoteRequest) map[string]string {
+	return map[string]string{
+		"base_url":         strings.TrimRight(a.config.BaseURL, "/"),
+		"chain_id":         strconv.FormatUint(uint64(req.ChainID), 10),
+		"from_token":       req.FromToken,
+		"to_token":         req.ToToken,
+		"amount_in":        req.AmountIn.String(),
+		"slippage":         req.Slippage.String(),
+		"slippage_percent": req.Slippage.Mul(decimal.NewFromInt(100)).String(),
+		"slippage_bps":     req.Slippage.Mul(decimal.NewFromInt(10000)).Round(0).String(),
+		"user_address":     req.UserAddress,
+	}
+}
+
+// buildQuoteRequest 按模板构建请求URL、请求体和请求头
+// GET请求的参数作为查询参数，POST请求的参数作为JSON请求体字段
+func (a *HTTPJSONAdapter) buildQuoteRequest(spec *httpJSONSpec, values map[string]string) (string, io.Reader, map[string]string, error) {
+	apiURL, err := url.Parse(expandTemplate(spec.config.URLTemplate, values, true))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("无效的请求URL: %w", err)
+	}
+
+	params := make(map[string]string, len(spec.config.Params))
+	for name, value := range spec.config.Params {
+		params[name] = expandTemplate(value, values, false)
+	}
+
+	var body io.Reader
+	if spec.method == http.MethodGet {
+		query := apiURL.Query()
+		for name, value := range params {
+			query.Set(name, value)
+		}
+		apiURL.RawQuery = query.Encode()
+	} else {
+		payload, err := json.Marshal(params)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("序列化请求体失败: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	headers := make(map[string]string, len(spec.config.Headers))
+	for name, value := range spec.config.Headers {
+		headers[name] = expandTemplate(value, values, false)
+	}
+
+	return apiURL.String(), body, headers, nil
+}
+
+// expandTemplate 替换模板中的占位符
+// inURL为true时除{base_url}外的取值按URL路径转义
+func expandTemplate(template string, values map[string]string, inURL bool) string {
+	return httpJSONPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		value := values[name]
+		if inURL && name != "base_url" {
+			return url.PathEscape(value)
+		}
+		return value
+	})
+}
+
+// extractError 按ErrorPath从响应中提取错误信息
+func (a *HTTPJSONAdapter) extractError(spec *httpJSONSpec, body []byte) (string, bool) {
+	if spec.errorPath == nil {
+		return "", false
+	}
+	document, err := decodeJSONDocument(body)
+	if err != nil {
+		return "", false
+	}
+	message, ok := spec.errorPath.lookupString(document)
+	return message, ok && message != ""
+}
+
+// convertToStandardQuote 按JSONPath从响应中提取报价
+func (a *HTTPJSONAdapter) convertToStandardQuote(spec *httpJSONSpec, document interface{}, req *types.QuoteRequest, responseTime time.Duration) (*types.ProviderQuote, error) {
+	// 解析输出数量(最小单位整数)
+	rawAmountOut, ok := spec.amountOutPath.lookupString(document)
+	if !ok {
+		return nil, fmt.Errorf("响应缺少输出数量字段 %s", spec.amountOutPath)
+	}
+	amountOut, err := decimal.NewFromString(rawAmountOut)
+	if err != nil || !amountOut.IsPositive() || !amountOut.Equal(amountOut.Truncate(0)) {
+		return nil, fmt.Errorf("无效的输出数量 %s=%q", spec.amountOutPath, rawAmountOut)
+	}
+
+	// 解析Gas估算，字段缺失时使用默认值
+	gasEstimate := spec.config.DefaultGas
+	if spec.gasPath != nil {
+		if rawGas, ok := spec.gasPath.lookupString(document); ok {
+			if gasEstimate, err = strconv.ParseUint(rawGas, 10, 64); err != nil {
+				return nil, fmt.Errorf("无效的Gas估算 %s=%q", spec.gasPath, rawGas)
+			}
+		}
+	}
+
+	quote := &types.ProviderQuote{
+		Provider:    a.config.Name,
+		Success:     true,
+		AmountOut:   amountOut,
+		GasEstimate: gasEstimate,
+		Route: []types.RouteStep{{
+			Protocol:   a.config.DisplayName,
+			Percentage: decimal.NewFromInt(1),
+		}},
+		ResponseTime: responseTime,
+		Confidence:   httpJSONConfidence,
+		RawResponse:  document, // 保存原始响应用于调试
+	}
+	a.setInputAmounts(quote, req.AmountIn, req)
+	return quote, nil
+}
+
+// failedQuote 创建失败报价
+func (a *HTTPJSONAdapter) failedQuote(code, message string, startTime time.Time) *types.ProviderQuote {
+	return &types.ProviderQuote{
+		Provider:     a.config.Name,
+		Success:      false,
+		ResponseTime: time.Since(startTime),
+		ErrorCode:    code,
+		ErrorMessage: message,
+	}
+}
@@ -12,15 +12,17 @@ import (
 // 定义所有第三方聚合器必须实现的标准接口
 type ProviderAdapter interface {
 	// 基础信息
-	GetName() string               // 获取聚合器名称
-	GetDisplayName() string        // 获取显示名称
-	IsSupported(chainID uint) bool // 检查是否支持指定链
+	GetName() string                           // 获取聚合器名称
+	GetDisplayName() string                    // 获取显示名称
+	IsSupported(chainID uint) bool             // 检查是否支持指定链
+	SupportsQuoteSide(side string) bool        // 检查是否支持指定报价方向(sell/buy)
+	CheckTokens(req *types.QuoteRequest) error // 检查是否支持请求的代币(如原生代币)，不支持时返回带原因的RouterError
 
 	// 核心功能
-	GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error) // 获取报价
-	HealthCheck(ctx context.Context) error                                               // 健康检查
+	GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error)   // 获取报价
+	BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) // 构建可执行交易
+	HealthCheck(ctx context.Context) error                                                 // 健康检查
 
-	// 配置管理
-	UpdateConfig(config *types.ProviderConfig) error // 更新配置
-	GetConfig() *types.ProviderConfig                // 获取当前配置
+	// 配置管理(适配器创建后配置不再修改，配置变化时由路由服务重新创建适配器)
+	GetConfig() *types.ProviderConfig // 获取当前配置
 }
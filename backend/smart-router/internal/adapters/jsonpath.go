@@ -0,0 +1,143 @@
+// Package adapters JSONPath字段提取
+// 声明式HTTP JSON适配器按配置的JSONPath从响应中提取字段，这里实现所需的最小子集：
+// $.a.b 字段访问、[0] 数组下标和 ['a-b'] 带特殊字符的字段名，不支持通配符和过滤表达式
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep JSONPath中的一步：字段名或数组下标
+type jsonPathStep struct {
+	field   string // 字段名
+	index   int    // 数组下标
+	isIndex bool   // 是否为数组下标
+}
+
+// jsonPath 解析后的JSONPath表达式
+type jsonPath struct {
+	expr  string
+	steps []jsonPathStep
+}
+
+// parseJSONPath 解析JSONPath表达式，省略$前缀时视为从根对象开始(如"data.amountOut")
+func parseJSONPath(expr string) (*jsonPath, error) {
+	rest := strings.TrimSpace(expr)
+	if rest == "" {
+		return nil, fmt.Errorf("JSONPath不能为空")
+	}
+	if strings.HasPrefix(rest, "$") {
+		rest = rest[1:]
+	} else if !strings.HasPrefix(rest, "[") {
+		rest = "." + rest
+	}
+
+	path := &jsonPath{expr: expr}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("无效的JSONPath %q: 字段名为空", expr)
+			}
+			path.steps = append(path.steps, jsonPathStep{field: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("无效的JSONPath %q: 缺少 ]", expr)
+			}
+			step, err := parseJSONPathBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("无效的JSONPath %q: %w", expr, err)
+			}
+			path.steps = append(path.steps, step)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("无效的JSONPath %q: 意外的字符 %q", expr, rest[0])
+		}
+	}
+	return path, nil
+}
+
+// parseJSONPathBracket 解析方括号中的数组下标或带引号的字段名
+func parseJSONPathBracket(content string) (jsonPathStep, error) {
+	if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+		return jsonPathStep{field: content[1 : len(content)-1]}, nil
+	}
+	index, err := strconv.Atoi(content)
+	if err != nil || index < 0 {
+		return jsonPathStep{}, fmt.Errorf("不支持的下标 [%s]", content)
+	}
+	return jsonPathStep{index: index, isIndex: true}, nil
+}
+
+// lookup 在解码后的JSON文档中查找字段，路径不存在或值为null时返回false
+func (p *jsonPath) lookup(document interface{}) (interface{}, bool) {
+	current := document
+	for _, step := range p.steps {
+		if step.isIndex {
+			array, ok := current.([]interface{})
+			if !ok || step.index >= len(array) {
+				return nil, false
+			}
+			current = array[step.index]
+			continue
+		}
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[step.field]; !ok {
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// lookupString 查找字段并转换为字符串：数字保留原始文本，避免大数精度丢失
+func (p *jsonPath) lookupString(document interface{}) (string, bool) {
+	value, ok := p.lookup(document)
+	if !ok {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+}
+
+// String 返回原始表达式
+func (p *jsonPath) String() string {
+	return p.expr
+}
+
+// decodeJSONDocument 解码JSON文档，数字解码为json.Number保留原始精度
+func decodeJSONDocument(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("JSON解析失败: %w", err)
+	}
+	return document, nil
+}
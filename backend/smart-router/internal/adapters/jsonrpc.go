@@ -0,0 +1,150 @@
+// Package adapters 链JSON-RPC客户端
+// 直连DEX适配器通过链RPC节点的eth_call调用合约只读方法获取报价，
+// 多个调用合并为一个JSON-RPC批量请求发送，HTTP层的认证、重试和超时复用BaseAdapter.makeHTTPRequest
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ethCall 单个eth_call调用
+type ethCall struct {
+	To   string // 合约地址
+	Data []byte // 调用数据(函数选择器+ABI编码参数)
+}
+
+// ethCallResult eth_call调用结果
+// 节点返回错误(如合约revert)时Err不为nil
+type ethCallResult struct {
+	Data []byte // 返回数据
+	Err  error  // 调用错误
+}
+
+// jsonRPCRequest JSON-RPC请求
+type jsonRPCRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// jsonRPCResponse JSON-RPC响应
+type jsonRPCResponse struct {
+	ID     int           `json:"id"`
+	Result string        `json:"result"`
+	Error  *jsonRPCError `json:"error"`
+}
+
+// jsonRPCError JSON-RPC错误对象
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *jsonRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC错误: code=%d, message=%s", e.Code, e.Message)
+}
+
+// callJSONRPC 发送JSON-RPC批量请求，按请求顺序返回响应
+// 节点未返回某个请求的响应时，对应位置为带错误的响应；整个批量请求被拒绝时返回错误
+func (b *BaseAdapter) callJSONRPC(ctx context.Context, rpcURL string, requests []jsonRPCRequest) ([]jsonRPCResponse, error) {
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, fmt.Errorf("序列化JSON-RPC请求失败: %w", err)
+	}
+
+	responseBody, err := b.makeHTTPRequest(ctx, http.MethodPost, rpcURL, bytes.NewReader(body), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []jsonRPCResponse
+	if err := json.Unmarshal(responseBody, &responses); err != nil {
+		// 节点对整个批量请求返回单个错误对象(如限流、不支持批量请求)
+		var single jsonRPCResponse
+		if json.Unmarshal(responseBody, &single) == nil && single.Error != nil {
+			return nil, single.Error
+		}
+		return nil, fmt.Errorf("解析JSON-RPC响应失败: %w", err)
+	}
+
+	// 批量响应的顺序不保证与请求一致，按ID对应
+	indexByID := make(map[int]int, len(requests))
+	ordered := make([]jsonRPCResponse, len(requests))
+	for i, request := range requests {
+		indexByID[request.ID] = i
+		ordered[i] = jsonRPCResponse{ID: request.ID, Error: &jsonRPCError{Message: "节点未返回该请求的响应"}}
+	}
+	for _, response := range responses {
+		if i, ok := indexByID[response.ID]; ok {
+			ordered[i] = response
+		}
+	}
+	return ordered, nil
+}
+
+// batchEthCall 以一个JSON-RPC批量请求执行多个eth_call(latest区块)
+// 返回结果与calls一一对应；请求本身失败时返回错误，单个调用失败记录在对应结果的Err中
+func (b *BaseAdapter) batchEthCall(ctx context.Context, rpcURL string, calls []ethCall) ([]ethCallResult, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	requests := make([]jsonRPCRequest, len(calls))
+	for i, call := range calls {
+		requests[i] = jsonRPCRequest{
+			JSONRPC: "2.0",
+			ID:      i + 1,
+			Method:  "eth_call",
+			Params: []interface{}{
+				map[string]string{"to": call.To, "data": "0x" + hex.EncodeToString(call.Data)},
+				"latest",
+			},
+		}
+	}
+
+	responses, err := b.callJSONRPC(ctx, rpcURL, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ethCallResult, len(calls))
+	for i, response := range responses {
+		if response.Error != nil {
+			results[i].Err = response.Error
+			continue
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(response.Result, "0x"))
+		if err != nil {
+			results[i].Err = fmt.Errorf("解析eth_call返回数据失败: %w", err)
+			continue
+		}
+		if len(data) == 0 {
+			results[i].Err = errors.New("eth_call返回空数据(合约不存在或方法不存在)")
+			continue
+		}
+		results[i].Data = data
+	}
+	return results, nil
+}
+
+// blockNumber 查询最新区块号，用于RPC节点健康检查
+func (b *BaseAdapter) blockNumber(ctx context.Context, rpcURL string) (string, error) {
+	responses, err := b.callJSONRPC(ctx, rpcURL, []jsonRPCRequest{
+		{JSONRPC: "2.0", ID: 1, Method: "eth_blockNumber", Params: []interface{}{}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if responses[0].Error != nil {
+		return "", responses[0].Error
+	}
+	return responses[0].Result, nil
+}
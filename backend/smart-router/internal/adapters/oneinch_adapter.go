@@ -23,12 +23,17 @@ type OneInchAdapter struct {
 }
 
 // NewOneInchAdapter 创建1inch适配器实例
+// 1inch使用Authorization: Bearer认证
 func NewOneInchAdapter(config *types.ProviderConfig, logger *logrus.Logger) ProviderAdapter {
 	return &OneInchAdapter{
-		BaseAdapter: NewBaseAdapter(config, logger),
+		BaseAdapter: NewBaseAdapter(config, logger).withDefaultAuth(types.AuthSchemeBearer, ""),
 	}
 }
 
+func init() {
+	Register(types.Provider1inch, constructor(NewOneInchAdapter))
+}
+
 // ========================================
 // 1inch API响应结构定义
 // ========================================
@@ -61,6 +66,22 @@ type OneInchQuoteResponse struct {
 	EstimatedGas int64 `json:"estimatedGas"` // Gas估算
 }
 
+// OneInchSwapResponse 1inch交易构建API响应
+// 对应1inch /swap接口的响应格式
+type OneInchSwapResponse struct {
+	ToTokenAmount   string `json:"toTokenAmount"`   // 输出数量
+	FromTokenAmount string `json:"fromTokenAmount"` // 输入数量
+
+	Tx struct {
+		From     string `json:"from"`     // 发送方地址
+		To       string `json:"to"`       // 1inch路由合约地址
+		Data     string `json:"data"`     // 交易数据
+		Value    string `json:"value"`    // 发送的ETH数量
+		Gas      uint64 `json:"gas"`      // Gas限制
+		GasPrice string `json:"gasPrice"` // Gas价格
+	} `json:"tx"`
+}
+
 // OneInchErrorResponse 1inch错误响应
 type OneInchErrorResponse struct {
 	StatusCode int    `json:"statusCode"`
@@ -92,6 +113,15 @@ func (a *OneInchAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest)
 		}
 	}
 
+	// 检查报价方向支持
+	if !a.SupportsQuoteSide(req.Side) {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeUnsupportedQuoteSide,
+			Message:  "1inch /quote接口只支持卖出固定数量，不支持买入报价",
+			Provider: types.Provider1inch,
+		}
+	}
+
 	// 构建请求URL
 	apiURL, err := a.buildQuoteURL(req)
 	if err != nil {
@@ -107,7 +137,7 @@ func (a *OneInchAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest)
 			Provider:     types.Provider1inch,
 			Success:      false,
 			ResponseTime: time.Since(startTime),
-			ErrorCode:    types.ErrCodeProviderError,
+			ErrorCode:    httpErrorCode(err),
 			ErrorMessage: err.Error(),
 		}, nil // 返回失败的报价，不返回error，让聚合器继续处理其他提供商
 	}
@@ -137,7 +167,7 @@ func (a *OneInchAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest)
 	}
 
 	// 转换为标准格式
-	providerQuote, err := a.convertToStandardQuote(&quoteResp, time.Since(startTime))
+	providerQuote, err := a.convertToStandardQuote(&quoteResp, req, time.Since(startTime))
 	if err != nil {
 		return &types.ProviderQuote{
 			Provider:     types.Provider1inch,
@@ -154,6 +184,56 @@ func (a *OneInchAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest)
 	return providerQuote, nil
 }
 
+// BuildSwap 构建1inch交易
+// 调用1inch /swap接口，返回可直接由用户钱包发送的交易
+func (a *OneInchAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	if err := a.validateSwapRequest(req); err != nil {
+		return nil, err
+	}
+
+	apiURL := a.buildSwapURL(req)
+	a.logger.Debugf("[1inch] 交易构建URL: %s", apiURL)
+
+	responseBody, err := a.makeHTTPRequest(ctx, "GET", apiURL, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("1inch交易构建请求失败: %w", err)
+	}
+
+	var swapResp OneInchSwapResponse
+	if err := a.parseJSONResponse(responseBody, &swapResp); err != nil {
+		return nil, err
+	}
+	if swapResp.Tx.To == "" || swapResp.Tx.Data == "" {
+		return nil, fmt.Errorf("1inch返回的交易数据不完整")
+	}
+
+	amountOut, err := a.standardizeAmount(swapResp.ToTokenAmount)
+	if err != nil {
+		return nil, fmt.Errorf("解析输出数量失败: %w", err)
+	}
+
+	a.logger.Infof("[1inch] 交易构建成功: to=%s, gas=%d, amountOut=%s",
+		swapResp.Tx.To, swapResp.Tx.Gas, amountOut.String())
+
+	return &types.SwapTransaction{
+		RequestID:       req.RequestID,
+		Provider:        types.Provider1inch,
+		Kind:            types.SwapKindTransaction,
+		ChainID:         req.ChainID,
+		From:            req.UserAddress,
+		To:              swapResp.Tx.To,
+		Data:            swapResp.Tx.Data,
+		Value:           swapResp.Tx.Value,
+		Gas:             swapResp.Tx.Gas,
+		GasPrice:        swapResp.Tx.GasPrice,
+		AllowanceTarget: swapResp.Tx.To, // 1inch由路由合约直接转入代币
+		AmountIn:        req.AmountIn,
+		AmountOut:       amountOut,
+		MinAmountOut:    a.minAmountOut(amountOut, req.Slippage),
+		Timestamp:       time.Now(),
+	}, nil
+}
+
 // HealthCheck 1inch健康检查
 // 检查1inch API的可用性和响应时间
 func (a *OneInchAdapter) HealthCheck(ctx context.Context) error {
@@ -175,6 +255,11 @@ func (a *OneInchAdapter) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// SupportsQuoteSide 1inch /quote接口没有买入数量参数，只支持卖出报价
+func (a *OneInchAdapter) SupportsQuoteSide(side string) bool {
+	return side == "" || side == types.QuoteSideSell
+}
+
 // ========================================
 // 辅助方法
 // ========================================
@@ -207,17 +292,32 @@ func (a *OneInchAdapter) buildQuoteURL(req *types.QuoteRequest) (string, error)
 	return fullURL, nil
 }
 
+// buildSwapURL 构建1inch交易构建请求URL
+// /swap接口要求fromAddress和slippage(百分比格式)
+func (a *OneInchAdapter) buildSwapURL(req *types.SwapRequest) string {
+	baseURL := fmt.Sprintf("%s/%d/swap", a.config.BaseURL, req.ChainID)
+
+	params := url.Values{}
+	params.Set("fromTokenAddress", req.FromToken)
+	params.Set("toTokenAddress", req.ToToken)
+	params.Set("amount", req.AmountIn.String())
+	params.Set("fromAddress", req.UserAddress)
+	params.Set("slippage", req.Slippage.Mul(decimal.NewFromInt(100)).String())
+
+	return baseURL + "?" + params.Encode()
+}
+
 // convertToStandardQuote 将1inch响应转换为标准格式
 // 统一不同聚合器的响应格式差异
-func (a *OneInchAdapter) convertToStandardQuote(resp *OneInchQuoteResponse, responseTime time.Duration) (*types.ProviderQuote, error) {
+func (a *OneInchAdapter) convertToStandardQuote(resp *OneInchQuoteResponse, req *types.QuoteRequest, responseTime time.Duration) (*types.ProviderQuote, error) {
 	// 解析输出数量
 	amountOut, err := a.standardizeAmount(resp.ToTokenAmount)
 	if err != nil {
 		return nil, fmt.Errorf("解析输出数量失败: %w", err)
 	}
 
-	// 解析输入数量（用于验证，但不在返回值中使用）
-	_, err = a.standardizeAmount(resp.FromTokenAmount)
+	// 解析输入数量
+	amountIn, err := a.standardizeAmount(resp.FromTokenAmount)
 	if err != nil {
 		return nil, fmt.Errorf("解析输入数量失败: %w", err)
 	}
@@ -236,20 +336,18 @@ func (a *OneInchAdapter) convertToStandardQuote(resp *OneInchQuoteResponse, resp
 	// 计算置信度（基于响应时间和数据完整性）
 	confidence := a.calculateConfidence(responseTime, resp.EstimatedGas > 0)
 
-	// 计算价格冲击（简化计算，实际应该基于市场价格）
-	priceImpact := decimal.NewFromFloat(0.001) // 默认0.1%
-
-	return &types.ProviderQuote{
+	quote := &types.ProviderQuote{
 		Provider:     types.Provider1inch,
 		Success:      true,
 		AmountOut:    amountOut,
 		GasEstimate:  uint64(resp.EstimatedGas),
-		PriceImpact:  priceImpact,
 		Route:        route,
 		ResponseTime: responseTime,
 		Confidence:   confidence,
 		RawResponse:  resp, // 保存原始响应用于调试
-	}, nil
+	}
+	a.setInputAmounts(quote, amountIn, req)
+	return quote, nil
 }
 
 // calculateConfidence 计算报价置信度
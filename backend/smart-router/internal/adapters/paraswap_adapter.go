@@ -4,7 +4,9 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -22,12 +24,17 @@ type ParaSwapAdapter struct {
 }
 
 // NewParaSwapAdapter 创建ParaSwap适配器实例
+// ParaSwap公开接口不需要API密钥
 func NewParaSwapAdapter(config *types.ProviderConfig, logger *logrus.Logger) ProviderAdapter {
 	return &ParaSwapAdapter{
-		BaseAdapter: NewBaseAdapter(config, logger),
+		BaseAdapter: NewBaseAdapter(config, logger).withDefaultAuth(types.AuthSchemeNone, ""),
 	}
 }
 
+func init() {
+	Register(types.ProviderParaswap, constructor(NewParaSwapAdapter))
+}
+
 // ========================================
 // ParaSwap API响应结构定义
 // ========================================
@@ -65,6 +72,18 @@ type RouteData struct {
 	DestAmount string `json:"destAmount"` // 目标数量
 }
 
+// ParaSwapTransactionResponse ParaSwap交易构建API响应
+// 对应ParaSwap /transactions/{network}接口的响应格式
+type ParaSwapTransactionResponse struct {
+	From     string `json:"from"`     // 发送方地址
+	To       string `json:"to"`       // ParaSwap增强路由合约地址
+	Value    string `json:"value"`    // 发送的原生代币数量
+	Data     string `json:"data"`     // 交易数据
+	GasPrice string `json:"gasPrice"` // Gas价格
+	Gas      string `json:"gas"`      // Gas限制(ignoreGasEstimate时可能为空)
+	ChainID  int    `json:"chainId"`  // 链ID
+}
+
 // ParaSwapErrorResponse ParaSwap错误响应
 type ParaSwapErrorResponse struct {
 	Error struct {
@@ -114,7 +133,7 @@ func (a *ParaSwapAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest)
 			Provider:     types.ProviderParaswap,
 			Success:      false,
 			ResponseTime: time.Since(startTime),
-			ErrorCode:    types.ErrCodeProviderError,
+			ErrorCode:    httpErrorCode(err),
 			ErrorMessage: err.Error(),
 		}, nil
 	}
@@ -144,7 +163,7 @@ func (a *ParaSwapAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest)
 	}
 
 	// 转换为标准格式
-	providerQuote, err := a.convertToStandardQuote(&priceResp, time.Since(startTime))
+	providerQuote, err := a.convertToStandardQuote(&priceResp, req, time.Since(startTime))
 	if err != nil {
 		return &types.ProviderQuote{
 			Provider:     types.ProviderParaswap,
@@ -161,6 +180,100 @@ func (a *ParaSwapAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest)
 	return providerQuote, nil
 }
 
+// BuildSwap 构建ParaSwap交易
+// ParaSwap需要两步：先调用/prices获取priceRoute，再将priceRoute原样提交到/transactions构建交易
+func (a *ParaSwapAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	if err := a.validateSwapRequest(req); err != nil {
+		return nil, err
+	}
+
+	// 1. 获取priceRoute
+	priceURL, err := a.buildPriceURL(&req.QuoteRequest)
+	if err != nil {
+		return nil, fmt.Errorf("构建请求URL失败: %w", err)
+	}
+	priceBody, err := a.makeHTTPRequest(ctx, "GET", priceURL, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ParaSwap价格请求失败: %w", err)
+	}
+
+	var priceResp ParaSwapPriceResponse
+	if err := a.parseJSONResponse(priceBody, &priceResp); err != nil {
+		return nil, err
+	}
+	// priceRoute需要原样回传，避免结构体丢失字段
+	var rawPrice struct {
+		PriceRoute json.RawMessage `json:"priceRoute"`
+	}
+	if err := a.parseJSONResponse(priceBody, &rawPrice); err != nil {
+		return nil, err
+	}
+
+	amountOut, err := a.standardizeAmount(priceResp.PriceRoute.DestAmount)
+	if err != nil {
+		return nil, fmt.Errorf("解析输出数量失败: %w", err)
+	}
+
+	// 2. 构建交易
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"srcToken":    req.FromToken,
+		"destToken":   req.ToToken,
+		"srcAmount":   req.AmountIn.String(),
+		"slippage":    req.Slippage.Mul(decimal.NewFromInt(10000)).IntPart(), // 基点
+		"priceRoute":  rawPrice.PriceRoute,
+		"userAddress": req.UserAddress,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	txURL := fmt.Sprintf("%s/transactions/%d?ignoreChecks=true", a.config.BaseURL, req.ChainID)
+	a.logger.Debugf("[ParaSwap] 交易构建URL: %s", txURL)
+
+	txBody, err := a.makeHTTPRequest(ctx, "POST", txURL, bytes.NewReader(requestBody), nil)
+	if err != nil {
+		return nil, fmt.Errorf("ParaSwap交易构建请求失败: %w", err)
+	}
+
+	var txResp ParaSwapTransactionResponse
+	if err := a.parseJSONResponse(txBody, &txResp); err != nil {
+		return nil, err
+	}
+	if txResp.To == "" || txResp.Data == "" {
+		return nil, fmt.Errorf("ParaSwap返回的交易数据不完整")
+	}
+
+	// Gas限制优先使用交易接口返回值，否则使用priceRoute中的估算
+	var gas uint64
+	if txResp.Gas != "" {
+		gas, _ = strconv.ParseUint(txResp.Gas, 10, 64)
+	}
+	if gas == 0 && priceResp.PriceRoute.GasCost != "" {
+		gas, _ = strconv.ParseUint(priceResp.PriceRoute.GasCost, 10, 64)
+	}
+
+	a.logger.Infof("[ParaSwap] 交易构建成功: to=%s, gas=%d, amountOut=%s",
+		txResp.To, gas, amountOut.String())
+
+	return &types.SwapTransaction{
+		RequestID:       req.RequestID,
+		Provider:        types.ProviderParaswap,
+		Kind:            types.SwapKindTransaction,
+		ChainID:         req.ChainID,
+		From:            req.UserAddress,
+		To:              txResp.To,
+		Data:            txResp.Data,
+		Value:           txResp.Value,
+		Gas:             gas,
+		GasPrice:        txResp.GasPrice,
+		AllowanceTarget: priceResp.PriceRoute.TokenTransferProxy,
+		AmountIn:        req.AmountIn,
+		AmountOut:       amountOut,
+		MinAmountOut:    a.minAmountOut(amountOut, req.Slippage),
+		Timestamp:       time.Now(),
+	}, nil
+}
+
 // HealthCheck ParaSwap健康检查
 func (a *ParaSwapAdapter) HealthCheck(ctx context.Context) error {
 	// ParaSwap健康检查：调用支持的代币列表接口
@@ -193,9 +306,16 @@ func (a *ParaSwapAdapter) buildPriceURL(req *types.QuoteRequest) (string, error)
 	params := url.Values{}
 	params.Set("srcToken", req.FromToken)
 	params.Set("destToken", req.ToToken)
-	params.Set("amount", req.AmountIn.String())
 	params.Set("network", strconv.Itoa(int(req.ChainID)))
-	params.Set("side", "SELL") // 固定为SELL模式
+
+	// SELL模式amount为输入数量，BUY模式amount为期望输出数量
+	if req.IsExactOutput() {
+		params.Set("amount", req.AmountOut.String())
+		params.Set("side", "BUY")
+	} else {
+		params.Set("amount", req.AmountIn.String())
+		params.Set("side", "SELL")
+	}
 
 	// 添加可选参数
 	if req.UserAddress != "" {
@@ -208,13 +328,19 @@ func (a *ParaSwapAdapter) buildPriceURL(req *types.QuoteRequest) (string, error)
 }
 
 // convertToStandardQuote 将ParaSwap响应转换为标准格式
-func (a *ParaSwapAdapter) convertToStandardQuote(resp *ParaSwapPriceResponse, responseTime time.Duration) (*types.ProviderQuote, error) {
+func (a *ParaSwapAdapter) convertToStandardQuote(resp *ParaSwapPriceResponse, req *types.QuoteRequest, responseTime time.Duration) (*types.ProviderQuote, error) {
 	// 解析输出数量
 	amountOut, err := a.standardizeAmount(resp.PriceRoute.DestAmount)
 	if err != nil {
 		return nil, fmt.Errorf("解析输出数量失败: %w", err)
 	}
 
+	// 解析输入数量(BUY模式下为所需输入)
+	amountIn, err := a.standardizeAmount(resp.PriceRoute.SrcAmount)
+	if err != nil {
+		return nil, fmt.Errorf("解析输入数量失败: %w", err)
+	}
+
 	// 转换交易路径
 	var route []types.RouteStep
 	for _, routeData := range resp.PriceRoute.BestRoute {
@@ -224,36 +350,29 @@ func (a *ParaSwapAdapter) convertToStandardQuote(resp *ParaSwapPriceResponse, re
 		})
 	}
 
-	// 计算Gas估算（ParaSwap返回的是Gas费用，需要转换为Gas数量）
+	// 解析Gas估算（priceRoute.gasCost为Gas数量，费用以gasCostUSD单独给出）
 	var gasEstimate uint64 = 180000 // 默认Gas估算
 	if resp.PriceRoute.GasCost != "" {
-		if gasCost, err := decimal.NewFromString(resp.PriceRoute.GasCost); err == nil {
-			// 假设Gas价格为20 Gwei，计算Gas数量
-			gasPrice := decimal.NewFromInt(20000000000) // 20 Gwei in wei
-			if !gasPrice.IsZero() {
-				gasAmount := gasCost.Div(gasPrice)
-				gasEstimate = uint64(gasAmount.IntPart())
-			}
+		if gas, err := strconv.ParseUint(resp.PriceRoute.GasCost, 10, 64); err == nil && gas > 0 {
+			gasEstimate = gas
 		}
 	}
 
 	// 计算置信度
 	confidence := a.calculateConfidence(responseTime, gasEstimate > 0)
 
-	// 计算价格冲击（简化）
-	priceImpact := decimal.NewFromFloat(0.002) // 默认0.2%
-
-	return &types.ProviderQuote{
+	quote := &types.ProviderQuote{
 		Provider:     types.ProviderParaswap,
 		Success:      true,
 		AmountOut:    amountOut,
 		GasEstimate:  gasEstimate,
-		PriceImpact:  priceImpact,
 		Route:        route,
 		ResponseTime: responseTime,
 		Confidence:   confidence,
 		RawResponse:  resp,
-	}, nil
+	}
+	a.setInputAmounts(quote, amountIn, req)
+	return quote, nil
 }
 
 // calculateConfidence 计算ParaSwap报价置信度
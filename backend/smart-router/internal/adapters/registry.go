@@ -0,0 +1,81 @@
+// Package adapters 适配器注册表
+// 各适配器在init中按适配器类型注册工厂函数，路由服务按配置的适配器类型(ProviderConfig.AdapterType)创建适配器，
+// 新增聚合器只需实现ProviderAdapter并注册，无需修改路由服务
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Factory 适配器工厂函数，配置无效时返回错误
+type Factory func(config *types.ProviderConfig, logger *logrus.Logger) (ProviderAdapter, error)
+
+// ErrUnknownAdapter 适配器类型未注册
+var ErrUnknownAdapter = errors.New("未注册的适配器类型")
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]Factory)
+)
+
+// Register 注册适配器工厂函数
+// 适配器类型为空、工厂函数为nil或重复注册属于编程错误，直接panic
+func Register(adapterType string, factory Factory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if adapterType == "" || factory == nil {
+		panic("adapters: 注册适配器需要类型名称和工厂函数")
+	}
+	if _, exists := registry[adapterType]; exists {
+		panic(fmt.Sprintf("adapters: 适配器类型重复注册: %s", adapterType))
+	}
+	registry[adapterType] = factory
+}
+
+// Lookup 查找已注册的适配器工厂函数
+func Lookup(adapterType string) (Factory, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	factory, ok := registry[adapterType]
+	return factory, ok
+}
+
+// Registered 返回已注册的适配器类型(按名称排序)
+func Registered() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	adapterTypes := make([]string, 0, len(registry))
+	for adapterType := range registry {
+		adapterTypes = append(adapterTypes, adapterType)
+	}
+	sort.Strings(adapterTypes)
+	return adapterTypes
+}
+
+// New 按配置的适配器类型创建适配器
+// 适配器类型未注册时返回包装ErrUnknownAdapter的错误
+func New(config *types.ProviderConfig, logger *logrus.Logger) (ProviderAdapter, error) {
+	adapterType := config.AdapterType()
+	factory, ok := Lookup(adapterType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (聚合器 %s，已注册: %v)", ErrUnknownAdapter, adapterType, config.Name, Registered())
+	}
+	return factory(config, logger)
+}
+
+// constructor 将不返回错误的适配器构造函数转换为工厂函数
+func constructor(newAdapter func(config *types.ProviderConfig, logger *logrus.Logger) ProviderAdapter) Factory {
+	return func(config *types.ProviderConfig, logger *logrus.Logger) (ProviderAdapter, error) {
+		return newAdapter(config, logger), nil
+	}
+}
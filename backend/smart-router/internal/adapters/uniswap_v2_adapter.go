@@ -0,0 +1,113 @@
+// Package adapters Uniswap V2直连DEX适配器实现
+// 通过链JSON-RPC节点eth_call调用UniswapV2Factory.getPair查询交易对，调用Router02.getAmountsOut/getAmountsIn
+// 对直连和经中间代币的路径报价，不依赖第三方聚合器API；同样适用于SushiSwap等Uniswap V2分叉(通过DEX配置指定合约地址)
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UniswapV2Adapter Uniswap V2直连DEX适配器
+type UniswapV2Adapter struct {
+	*dexAdapter // 嵌入直连DEX适配器公共部分
+}
+
+// uniswapV2Deployments Uniswap V2官方部署的Factory和Router02合约地址
+var uniswapV2Deployments = map[uint]types.DEXContracts{
+	1: {Factory: "0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f", Quoter: "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"},
+}
+
+// Uniswap V2交易的Gas估算：单跳基础开销，每增加一跳增加的开销
+const (
+	uniswapV2BaseGas = 110000
+	uniswapV2HopGas  = 50000
+)
+
+// Uniswap V2合约方法选择器
+var (
+	selectorV2GetPair       = selector("e6a43905") // getPair(address,address)
+	selectorV2GetAmountsOut = selector("d06ca61f") // getAmountsOut(uint256,address[])
+	selectorV2GetAmountsIn  = selector("1f00ca74") // getAmountsIn(uint256,address[])
+)
+
+// NewUniswapV2Adapter 创建Uniswap V2适配器实例
+// BaseURL为默认的链JSON-RPC节点URL，各链节点和合约地址可通过DEX配置覆盖
+func NewUniswapV2Adapter(config *types.ProviderConfig, logger *logrus.Logger) ProviderAdapter {
+	return &UniswapV2Adapter{
+		dexAdapter: &dexAdapter{
+			BaseAdapter: NewBaseAdapter(config, logger).withDefaultAuth(types.AuthSchemeNone, ""),
+			provider:    types.ProviderUniswapV2,
+			protocol:    "UNISWAP_V2",
+			logTag:      "UniswapV2",
+			deployments: uniswapV2Deployments,
+		},
+	}
+}
+
+func init() {
+	Register(types.ProviderUniswapV2, constructor(NewUniswapV2Adapter))
+}
+
+// GetQuote 获取Uniswap V2报价
+func (a *UniswapV2Adapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error) {
+	return a.getQuote(ctx, req, a)
+}
+
+// ========================================
+// Uniswap V2合约调用编解码
+// ========================================
+
+// feeTiers Uniswap V2交易对只有固定的0.3%手续费，不区分档位，忽略配置的手续费档位
+func (a *UniswapV2Adapter) feeTiers() []uint32 {
+	return []uint32{0}
+}
+
+// encodePoolCall 编码UniswapV2Factory.getPair(tokenA, tokenB)
+func (a *UniswapV2Adapter) encodePoolCall(hop dexHop) ([]byte, error) {
+	tokenA, err := abiAddress(hop.tokenA)
+	if err != nil {
+		return nil, err
+	}
+	tokenB, err := abiAddress(hop.tokenB)
+	if err != nil {
+		return nil, err
+	}
+	return encodeCall(selectorV2GetPair, tokenA, tokenB), nil
+}
+
+// encodeQuoteCall 编码Router02.getAmountsOut(amountIn, path)或getAmountsIn(amountOut, path)
+func (a *UniswapV2Adapter) encodeQuoteCall(req *types.QuoteRequest, path dexPath, amount *big.Int) ([]byte, error) {
+	tokens, err := abiAddressArray(path.tokens)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsExactOutput() {
+		return encodeCall(selectorV2GetAmountsIn, abiUint(amount), tokens), nil
+	}
+	return encodeCall(selectorV2GetAmountsOut, abiUint(amount), tokens), nil
+}
+
+// decodeQuote 解码Router02报价结果
+// 返回值为路径上每个代币的数量：首个为输入数量，最后一个为输出数量
+func (a *UniswapV2Adapter) decodeQuote(req *types.QuoteRequest, path dexPath, amount *big.Int, data []byte) (*dexQuote, error) {
+	amounts, err := decodeUintArray(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(amounts) != len(path.tokens) {
+		return nil, fmt.Errorf("返回数量个数 %d 与路径长度 %d 不一致", len(amounts), len(path.tokens))
+	}
+
+	return &dexQuote{
+		path:        path,
+		amountIn:    amounts[0],
+		amountOut:   amounts[len(amounts)-1],
+		gasEstimate: uniswapV2BaseGas + uniswapV2HopGas*uint64(len(path.pools)-1),
+	}, nil
+}
@@ -0,0 +1,154 @@
+// Package adapters Uniswap V3直连DEX适配器实现
+// 通过链JSON-RPC节点eth_call调用UniswapV3Factory.getPool查询池子，调用QuoterV2.quoteExactInput/quoteExactOutput
+// 对多个手续费档位和中间代币组成的路径报价，不依赖第三方聚合器API
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UniswapV3Adapter Uniswap V3直连DEX适配器
+type UniswapV3Adapter struct {
+	*dexAdapter // 嵌入直连DEX适配器公共部分
+}
+
+// uniswapV3Deployments Uniswap V3官方部署的Factory和QuoterV2合约地址
+var uniswapV3Deployments = map[uint]types.DEXContracts{
+	1:        {Factory: "0x1F98431c8aD98523631AE4a59f267346ea31F984", Quoter: "0x61fFE014bA17989E743c5F6cB21bF9697530B21e"},
+	10:       {Factory: "0x1F98431c8aD98523631AE4a59f267346ea31F984", Quoter: "0x61fFE014bA17989E743c5F6cB21bF9697530B21e"},
+	137:      {Factory: "0x1F98431c8aD98523631AE4a59f267346ea31F984", Quoter: "0x61fFE014bA17989E743c5F6cB21bF9697530B21e"},
+	8453:     {Factory: "0x33128a8fC17869897dcE68Ed026d694621f6FDfD", Quoter: "0x3d4e44Eb1374240CE5F1B871ab261CD16335B76a"},
+	42161:    {Factory: "0x1F98431c8aD98523631AE4a59f267346ea31F984", Quoter: "0x61fFE014bA17989E743c5F6cB21bF9697530B21e"},
+	11155111: {Factory: "0x0227628f3F023bb0B980b67D528571c95c6DaC1c", Quoter: "0xEd1f6473345F45b75F8179591dd5bA1888cf2FB3"},
+}
+
+// uniswapV3DefaultFeeTiers 默认探索的手续费档位(0.05%、0.3%、1%)
+var uniswapV3DefaultFeeTiers = []uint32{500, 3000, 10000}
+
+// uniswapV3GasOverhead 交易的基础Gas开销(路由合约调用、代币转账等QuoterV2 gasEstimate未计入的部分)
+const uniswapV3GasOverhead = 60000
+
+// Uniswap V3合约方法选择器
+var (
+	selectorV3GetPool          = selector("1698ee82") // getPool(address,address,uint24)
+	selectorV3QuoteExactInput  = selector("cdca1753") // quoteExactInput(bytes,uint256)
+	selectorV3QuoteExactOutput = selector("2f80bb1d") // quoteExactOutput(bytes,uint256)
+)
+
+// NewUniswapV3Adapter 创建Uniswap V3适配器实例
+// BaseURL为默认的链JSON-RPC节点URL，各链节点和合约地址可通过DEX配置覆盖；
+// API密钥(如节点服务商密钥)未指定认证方式时不发送
+func NewUniswapV3Adapter(config *types.ProviderConfig, logger *logrus.Logger) ProviderAdapter {
+	return &UniswapV3Adapter{
+		dexAdapter: &dexAdapter{
+			BaseAdapter: NewBaseAdapter(config, logger).withDefaultAuth(types.AuthSchemeNone, ""),
+			provider:    types.ProviderUniswapV3,
+			protocol:    "UNISWAP_V3",
+			logTag:      "UniswapV3",
+			deployments: uniswapV3Deployments,
+		},
+	}
+}
+
+func init() {
+	Register(types.ProviderUniswapV3, constructor(NewUniswapV3Adapter))
+}
+
+// GetQuote 获取Uniswap V3报价
+func (a *UniswapV3Adapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error) {
+	return a.getQuote(ctx, req, a)
+}
+
+// ========================================
+// Uniswap V3合约调用编解码
+// ========================================
+
+// feeTiers 探索的手续费档位，未配置时使用默认档位
+func (a *UniswapV3Adapter) feeTiers() []uint32 {
+	return a.configuredFeeTiers(uniswapV3DefaultFeeTiers)
+}
+
+// encodePoolCall 编码UniswapV3Factory.getPool(tokenA, tokenB, fee)
+func (a *UniswapV3Adapter) encodePoolCall(hop dexHop) ([]byte, error) {
+	tokenA, err := abiAddress(hop.tokenA)
+	if err != nil {
+		return nil, err
+	}
+	tokenB, err := abiAddress(hop.tokenB)
+	if err != nil {
+		return nil, err
+	}
+	return encodeCall(selectorV3GetPool, tokenA, tokenB, abiUint(big.NewInt(int64(hop.fee)))), nil
+}
+
+// encodeQuoteCall 编码QuoterV2.quoteExactInput(path, amountIn)或quoteExactOutput(path, amountOut)
+// 买入报价的路径按输出代币到输入代币的反向顺序编码
+func (a *UniswapV3Adapter) encodeQuoteCall(req *types.QuoteRequest, path dexPath, amount *big.Int) ([]byte, error) {
+	encoded, err := a.encodePath(path, req.IsExactOutput())
+	if err != nil {
+		return nil, err
+	}
+	if req.IsExactOutput() {
+		return encodeCall(selectorV3QuoteExactOutput, abiBytes(encoded), abiUint(amount)), nil
+	}
+	return encodeCall(selectorV3QuoteExactInput, abiBytes(encoded), abiUint(amount)), nil
+}
+
+// decodeQuote 解码QuoterV2报价结果
+// 返回值为(amount, sqrtPriceX96AfterList, initializedTicksCrossedList, gasEstimate)
+func (a *UniswapV3Adapter) decodeQuote(req *types.QuoteRequest, path dexPath, amount *big.Int, data []byte) (*dexQuote, error) {
+	quoted, err := decodeUint(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	gasEstimate, err := decodeUint(data, 3)
+	if err != nil {
+		return nil, err
+	}
+	if !gasEstimate.IsUint64() {
+		return nil, fmt.Errorf("无效的gasEstimate: %s", gasEstimate.String())
+	}
+
+	quote := &dexQuote{path: path, gasEstimate: gasEstimate.Uint64() + uniswapV3GasOverhead}
+	if req.IsExactOutput() {
+		quote.amountIn, quote.amountOut = quoted, amount
+	} else {
+		quote.amountIn, quote.amountOut = amount, quoted
+	}
+	return quote, nil
+}
+
+// encodePath 编码Uniswap V3路径：token(20字节) + fee(3字节) + token + ...
+func (a *UniswapV3Adapter) encodePath(path dexPath, reverse bool) ([]byte, error) {
+	tokens := path.tokens
+	fees := path.fees
+	if reverse {
+		tokens = make([]string, len(path.tokens))
+		fees = make([]uint32, len(path.fees))
+		for i, token := range path.tokens {
+			tokens[len(tokens)-1-i] = token
+		}
+		for i, fee := range path.fees {
+			fees[len(fees)-1-i] = fee
+		}
+	}
+
+	var encoded []byte
+	for i, token := range tokens {
+		address, err := parseAddress(token)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, address...)
+		if i < len(fees) {
+			encoded = append(encoded, byte(fees[i]>>16), byte(fees[i]>>8), byte(fees[i]))
+		}
+	}
+	return encoded, nil
+}
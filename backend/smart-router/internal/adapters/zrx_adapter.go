@@ -24,12 +24,17 @@ type ZRXAdapter struct {
 }
 
 // NewZRXAdapter 创建0x Protocol适配器实例
+// 0x Protocol通过0x-api-key请求头认证
 func NewZRXAdapter(config *types.ProviderConfig, logger *logrus.Logger) ProviderAdapter {
 	return &ZRXAdapter{
-		BaseAdapter: NewBaseAdapter(config, logger),
+		BaseAdapter: NewBaseAdapter(config, logger).withDefaultAuth(types.AuthSchemeHeader, "0x-api-key"),
 	}
 }
 
+func init() {
+	Register(types.Provider0x, constructor(NewZRXAdapter))
+}
+
 // ========================================
 // 0x Protocol API响应结构定义
 // ========================================
@@ -103,6 +108,15 @@ func (a *ZRXAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 		}
 	}
 
+	// 检查报价方向支持
+	if !a.SupportsQuoteSide(req.Side) {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeUnsupportedQuoteSide,
+			Message:  "0x Protocol v2 /quote接口只支持卖出固定数量，不支持买入报价",
+			Provider: a.GetName(),
+		}
+	}
+
 	// 构建请求URL
 	apiURL, err := a.buildQuoteURL(req)
 	if err != nil {
@@ -121,9 +135,8 @@ func (a *ZRXAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 		return nil, fmt.Errorf("0x Protocol API Key未配置")
 	}
 
-	// 设置请求headers
+	// 设置请求headers（API Key由BaseAdapter按认证方式设置）
 	headers := map[string]string{
-		"0x-api-key": a.config.APIKey,
 		"0x-version": "v2",
 	}
 
@@ -136,7 +149,7 @@ func (a *ZRXAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 			Provider:     types.Provider0x,
 			Success:      false,
 			ResponseTime: time.Since(startTime),
-			ErrorCode:    types.ErrCodeProviderError,
+			ErrorCode:    httpErrorCode(err),
 			ErrorMessage: err.Error(),
 		}, nil
 	}
@@ -187,6 +200,84 @@ func (a *ZRXAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*ty
 	return quote, nil
 }
 
+// BuildSwap 构建0x Protocol交易
+// 使用v2 AllowanceHolder接口(对应v1 /swap/v1/quote带taker参数的用法)：
+// 返回的transaction可直接发送，无需像permit2接口那样额外签名
+func (a *ZRXAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	if err := a.validateSwapRequest(req); err != nil {
+		return nil, err
+	}
+	if a.config.APIKey == "" {
+		return nil, fmt.Errorf("0x Protocol API Key未配置")
+	}
+
+	apiURL, err := a.buildSwapURL(req)
+	if err != nil {
+		return nil, fmt.Errorf("构建请求URL失败: %w", err)
+	}
+	a.logger.Debugf("[0x] 交易构建URL: %s", apiURL)
+
+	headers := map[string]string{
+		"0x-version": "v2",
+	}
+
+	responseBody, err := a.makeHTTPRequest(ctx, "GET", apiURL, nil, headers)
+	if err != nil {
+		return nil, fmt.Errorf("0x交易构建请求失败: %w", err)
+	}
+
+	var zrxResponse ZRXQuoteResponse
+	if err := a.parseJSONResponse(responseBody, &zrxResponse); err != nil {
+		return nil, err
+	}
+	if !zrxResponse.LiquidityAvailable {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeInsufficientLiquidity,
+			Message:  "0x Protocol: 流动性不可用",
+			Provider: types.Provider0x,
+		}
+	}
+	if zrxResponse.Transaction.To == "" || zrxResponse.Transaction.Data == "" {
+		return nil, fmt.Errorf("0x返回的交易数据不完整")
+	}
+
+	amountOut, err := decimal.NewFromString(zrxResponse.BuyAmount)
+	if err != nil {
+		return nil, fmt.Errorf("解析buyAmount失败: %w", err)
+	}
+
+	// 0x已按slippageBps计算最小买入数量，缺失时自行计算
+	minAmountOut := a.minAmountOut(amountOut, req.Slippage)
+	if zrxResponse.MinBuyAmount != "" {
+		if minBuy, err := decimal.NewFromString(zrxResponse.MinBuyAmount); err == nil {
+			minAmountOut = minBuy
+		}
+	}
+
+	gas, _ := strconv.ParseUint(zrxResponse.Transaction.Gas, 10, 64)
+
+	a.logger.Infof("[0x] 交易构建成功: to=%s, gas=%d, buyAmount=%s",
+		zrxResponse.Transaction.To, gas, amountOut.String())
+
+	return &types.SwapTransaction{
+		RequestID:       req.RequestID,
+		Provider:        types.Provider0x,
+		Kind:            types.SwapKindTransaction,
+		ChainID:         req.ChainID,
+		From:            req.UserAddress,
+		To:              zrxResponse.Transaction.To,
+		Data:            zrxResponse.Transaction.Data,
+		Value:           zrxResponse.Transaction.Value,
+		Gas:             gas,
+		GasPrice:        zrxResponse.Transaction.GasPrice,
+		AllowanceTarget: zrxResponse.AllowanceTarget,
+		AmountIn:        req.AmountIn,
+		AmountOut:       amountOut,
+		MinAmountOut:    minAmountOut,
+		Timestamp:       time.Now(),
+	}, nil
+}
+
 // ========================================
 // 0x Protocol URL构建和数据转换
 // ========================================
@@ -226,6 +317,26 @@ func (a *ZRXAdapter) buildQuoteURL(req *types.QuoteRequest) (string, error) {
 	return apiURL, nil
 }
 
+// buildSwapURL 构建0x Protocol交易构建请求URL
+// taker必须为真实用户地址，滑点以基点传入
+func (a *ZRXAdapter) buildSwapURL(req *types.SwapRequest) (string, error) {
+	baseURL := a.config.BaseURL
+	if baseURL == "" {
+		return "", fmt.Errorf("0x Protocol API URL未配置")
+	}
+
+	params := url.Values{}
+	params.Set("chainId", strconv.FormatUint(uint64(req.ChainID), 10))
+	params.Set("sellToken", req.FromToken)
+	params.Set("buyToken", req.ToToken)
+	params.Set("sellAmount", req.AmountIn.String())
+	params.Set("taker", req.UserAddress)
+	params.Set("slippageBps", req.Slippage.Mul(decimal.NewFromInt(10000)).Round(0).String())
+
+	apiURL := fmt.Sprintf("%s/swap/allowance-holder/quote?%s", strings.TrimSuffix(baseURL, "/"), params.Encode())
+	return apiURL, nil
+}
+
 // convertToStandardQuote 将0x Protocol响应转换为标准报价格式
 func (a *ZRXAdapter) convertToStandardQuote(zrxResp *ZRXQuoteResponse, req *types.QuoteRequest, startTime time.Time) (*types.ProviderQuote, error) {
 	// 检查流动性可用性
@@ -239,6 +350,14 @@ func (a *ZRXAdapter) convertToStandardQuote(zrxResp *ZRXQuoteResponse, req *type
 		return nil, fmt.Errorf("解析buyAmount失败: %w", err)
 	}
 
+	// 解析卖出数量
+	sellAmount := decimal.Zero
+	if zrxResp.SellAmount != "" {
+		if sellAmount, err = decimal.NewFromString(zrxResp.SellAmount); err != nil {
+			return nil, fmt.Errorf("解析sellAmount失败: %w", err)
+		}
+	}
+
 	// 验证响应数据的一致性
 	if strings.ToLower(zrxResp.SellToken) != strings.ToLower(req.FromToken) {
 		a.logger.Warnf("[0x] 卖出代币地址不匹配: 请求=%s, 响应=%s", req.FromToken, zrxResp.SellToken)
@@ -255,9 +374,6 @@ func (a *ZRXAdapter) convertToStandardQuote(zrxResp *ZRXQuoteResponse, req *type
 		}
 	}
 
-	// 计算价格影响
-	priceImpact := a.calculatePriceImpact(req.AmountIn, buyAmount)
-
 	// 解析路由信息
 	var route []types.RouteStep
 	// 0x Protocol的route结构比较复杂，这里简化处理
@@ -274,35 +390,18 @@ func (a *ZRXAdapter) convertToStandardQuote(zrxResp *ZRXQuoteResponse, req *type
 		confidence = decimal.NewFromFloat(0.9) // 高置信度
 	}
 
-	return &types.ProviderQuote{
+	quote := &types.ProviderQuote{
 		Provider:     types.Provider0x,
 		Success:      true,
 		AmountOut:    buyAmount,
 		GasEstimate:  gasEstimate,
-		PriceImpact:  priceImpact,
 		Route:        route,
 		ResponseTime: time.Since(startTime),
 		Confidence:   confidence,
-	}, nil
-}
-
-// calculatePriceImpact 计算价格影响
-func (a *ZRXAdapter) calculatePriceImpact(amountIn, amountOut decimal.Decimal) decimal.Decimal {
-	if amountIn.IsZero() {
-		return decimal.Zero
-	}
-
-	// 简化的价格影响计算
-	// 实际应该基于市场价格，这里使用近似计算
-	expectedOut := amountIn // 假设1:1汇率作为基准
-	actualOut := amountOut
-
-	if expectedOut.IsZero() {
-		return decimal.Zero
+		RawResponse:  zrxResp, // 保存原始响应用于调试和报价溯源
 	}
-
-	impact := expectedOut.Sub(actualOut).Div(expectedOut).Abs()
-	return impact
+	a.setInputAmounts(quote, sellAmount, req)
+	return quote, nil
 }
 
 // ========================================
@@ -316,7 +415,6 @@ func (a *ZRXAdapter) HealthCheck(ctx context.Context) error {
 		a.config.BaseURL)
 
 	headers := map[string]string{
-		"0x-api-key": a.config.APIKey,
 		"0x-version": "v2",
 	}
 
@@ -333,9 +431,14 @@ func (a *ZRXAdapter) HealthCheck(ctx context.Context) error {
 // 工具方法
 // ========================================
 
-// GetName 返回适配器名称
+// SupportsQuoteSide 0x v2 /quote接口没有buyAmount参数，只支持卖出报价
+func (a *ZRXAdapter) SupportsQuoteSide(side string) bool {
+	return side == "" || side == types.QuoteSideSell
+}
+
+// GetName 返回配置中的适配器名称，与聚合器配置的name一致以便注册校验和按名称查找
 func (a *ZRXAdapter) GetName() string {
-	return string(types.Provider0x)
+	return a.config.Name
 }
 
 // GetDisplayName 返回显示名称
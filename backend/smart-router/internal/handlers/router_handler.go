@@ -4,8 +4,10 @@
 package handlers
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"defi-aggregator/smart-router/internal/services"
@@ -20,14 +22,16 @@ import (
 // RouterHandler 智能路由处理器
 // 处理报价聚合相关的HTTP请求
 type RouterHandler struct {
-	routerService *services.RouterService // 路由服务
-	logger        *logrus.Logger          // 日志记录器
+	routerService *services.RouterService    // 路由服务
+	reloader      *services.ProviderReloader // 聚合器配置热加载器(未启用时为nil)
+	logger        *logrus.Logger             // 日志记录器
 }
 
 // NewRouterHandler 创建路由处理器实例
-func NewRouterHandler(routerService *services.RouterService, logger *logrus.Logger) *RouterHandler {
+func NewRouterHandler(routerService *services.RouterService, reloader *services.ProviderReloader, logger *logrus.Logger) *RouterHandler {
 	return &RouterHandler{
 		routerService: routerService,
+		reloader:      reloader,
 		logger:        logger,
 	}
 }
@@ -45,10 +49,91 @@ func (h *RouterHandler) GetQuote(c *gin.Context) {
 
 	h.logger.Infof("[%s] 收到报价请求", requestID)
 
-	// 绑定请求参数
-	var req types.QuoteRequest
+	req, ok := h.bindQuoteRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	// 调用智能路由服务
+	ctx := c.Request.Context()
+	quote, err := h.routerService.GetOptimalQuote(ctx, req)
+	if err != nil {
+		h.handleRouterError(c, err, requestID)
+		return
+	}
+
+	// 返回成功响应
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    quote,
+		Meta: map[string]interface{}{
+			"processing_time": time.Since(startTime).Milliseconds(),
+			"cache_hit":       quote.CacheHit,
+			"providers_used":  quote.Performance.ProvidersQueried,
+		},
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+
+	h.logger.Infof("[%s] 报价请求处理完成: provider=%s, duration=%v",
+		requestID, quote.BestProvider, time.Since(startTime))
+}
+
+// StreamQuote 流式获取报价
+// POST /api/v1/quote/stream
+// 以Server-Sent Events推送报价：每个聚合器报价到达时发送一个quote事件，
+// 聚合完成后发送包含完整QuoteResponse的best事件，失败时发送error事件
+func (h *RouterHandler) StreamQuote(c *gin.Context) {
+	requestID := h.getOrGenerateRequestID(c)
+	startTime := time.Now()
+
+	h.logger.Infof("[%s] 收到流式报价请求", requestID)
+
+	req, ok := h.bindQuoteRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	// 设置SSE响应头，禁用代理缓冲
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Header("X-Request-ID", requestID)
+	c.Status(http.StatusOK)
+
+	quoteCount := 0
+	quote, err := h.routerService.StreamOptimalQuote(c.Request.Context(), req, func(providerQuote *types.ProviderQuote) {
+		quoteCount++
+		c.SSEvent(types.StreamEventQuote, providerQuote)
+		c.Writer.Flush()
+	})
+	if err != nil {
+		h.logger.Warnf("[%s] 流式报价失败: %v", requestID, err)
+		c.SSEvent(types.StreamEventError, h.toAPIError(err))
+		c.Writer.Flush()
+		return
+	}
+
+	c.SSEvent(types.StreamEventBest, quote)
+	c.Writer.Flush()
+
+	h.logger.Infof("[%s] 流式报价完成: provider=%s, events=%d, duration=%v",
+		requestID, quote.BestProvider, quoteCount+1, time.Since(startTime))
+}
+
+// BuildSwap 构建可执行交易
+// POST /api/v1/swap
+// 返回用户钱包可直接发送的交易数据(to/data/value/gas)，CoW Protocol返回待签名订单
+func (h *RouterHandler) BuildSwap(c *gin.Context) {
+	requestID := h.getOrGenerateRequestID(c)
+	startTime := time.Now()
+
+	h.logger.Infof("[%s] 收到交易构建请求", requestID)
+
+	var req types.SwapRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warnf("[%s] 报价请求参数无效: %v", requestID, err)
+		h.logger.Warnf("[%s] 交易构建请求参数无效: %v", requestID, err)
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
@@ -62,14 +147,12 @@ func (h *RouterHandler) GetQuote(c *gin.Context) {
 		return
 	}
 
-	// 设置请求ID
 	if req.RequestID == "" {
 		req.RequestID = requestID
 	}
 
-	// 验证请求参数
-	if err := h.validateQuoteRequest(&req); err != nil {
-		h.logger.Warnf("[%s] 报价请求验证失败: %v", requestID, err)
+	if err := h.validateSwapRequest(&req); err != nil {
+		h.logger.Warnf("[%s] 交易构建请求验证失败: %v", requestID, err)
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
@@ -82,29 +165,24 @@ func (h *RouterHandler) GetQuote(c *gin.Context) {
 		return
 	}
 
-	// 调用智能路由服务
-	ctx := c.Request.Context()
-	quote, err := h.routerService.GetOptimalQuote(ctx, &req)
+	tx, err := h.routerService.BuildSwap(c.Request.Context(), &req)
 	if err != nil {
 		h.handleRouterError(c, err, requestID)
 		return
 	}
 
-	// 返回成功响应
 	c.JSON(http.StatusOK, types.APIResponse{
 		Success: true,
-		Data:    quote,
+		Data:    tx,
 		Meta: map[string]interface{}{
 			"processing_time": time.Since(startTime).Milliseconds(),
-			"cache_hit":       quote.CacheHit,
-			"providers_used":  quote.Performance.ProvidersQueried,
 		},
 		Timestamp: time.Now().Unix(),
 		RequestID: requestID,
 	})
 
-	h.logger.Infof("[%s] 报价请求处理完成: provider=%s, duration=%v",
-		requestID, quote.BestProvider, time.Since(startTime))
+	h.logger.Infof("[%s] 交易构建请求处理完成: provider=%s, kind=%s, duration=%v",
+		requestID, tx.Provider, tx.Kind, time.Since(startTime))
 }
 
 // ========================================
@@ -121,15 +199,15 @@ func (h *RouterHandler) GetMetrics(c *gin.Context) {
 	routerMetrics := h.routerService.GetMetrics()
 
 	// 获取缓存统计
-	// cacheStats, err := h.routerService.GetCacheStats()
-	// if err != nil {
-	//     h.logger.Warnf("[%s] 获取缓存统计失败: %v", requestID, err)
-	// }
+	cacheStats, err := h.routerService.GetCacheStats()
+	if err != nil {
+		h.logger.Warnf("[%s] 获取缓存统计失败: %v", requestID, err)
+	}
 
 	// 构建响应
 	metrics := map[string]interface{}{
-		"router": routerMetrics,
-		// "cache":  cacheStats,
+		"router":    routerMetrics,
+		"cache":     cacheStats,
 		"timestamp": time.Now().Unix(),
 	}
 
@@ -150,15 +228,31 @@ func (h *RouterHandler) HealthCheck(c *gin.Context) {
 	requestID := h.getOrGenerateRequestID(c)
 
 	// TODO: 实现完整的健康检查
-	// 1. 检查各个聚合器的健康状态
-	// 2. 检查缓存连接
-	// 3. 检查系统资源
+	// 1. 检查缓存连接
+	// 2. 检查系统资源
+
+	// 聚合器健康状态来自熔断器：全部熔断时不健康，部分熔断或半开时降级
+	providers := h.routerService.GetProviderHealth()
+	status := types.StatusHealthy
+	unhealthy := 0
+	for _, provider := range providers {
+		if provider.Status != types.StatusHealthy {
+			status = types.StatusDegraded
+		}
+		if provider.Status == types.StatusUnhealthy {
+			unhealthy++
+		}
+	}
+	if len(providers) > 0 && unhealthy == len(providers) {
+		status = types.StatusUnhealthy
+	}
 
 	healthResponse := &types.HealthCheckResponse{
-		Status:    types.StatusHealthy,
+		Status:    status,
 		Timestamp: time.Now(),
 		Version:   "1.0.0",
 		Uptime:    time.Since(time.Now()), // TODO: 计算真实的运行时间
+		Providers: providers,
 	}
 
 	c.JSON(http.StatusOK, healthResponse)
@@ -167,16 +261,11 @@ func (h *RouterHandler) HealthCheck(c *gin.Context) {
 
 // GetProviderStatus 获取聚合器状态
 // GET /api/v1/providers/status
-// 返回所有聚合器的健康状态和性能指标
+// 返回所有聚合器的熔断状态和调用统计
 func (h *RouterHandler) GetProviderStatus(c *gin.Context) {
 	requestID := h.getOrGenerateRequestID(c)
 
-	// TODO: 实现聚合器状态查询
-	// 从RouterService获取各个适配器的状态
-
-	status := map[string]interface{}{
-		"message": "Provider status not implemented yet",
-	}
+	status := h.routerService.GetProviderStatus()
 
 	c.JSON(http.StatusOK, types.APIResponse{
 		Success:   true,
@@ -188,10 +277,121 @@ func (h *RouterHandler) GetProviderStatus(c *gin.Context) {
 	h.logger.Debugf("[%s] 聚合器状态查询完成", requestID)
 }
 
+// ========================================
+// 管理接口
+// ========================================
+
+// ReloadProviders 强制重新加载聚合器配置
+// POST /api/v1/admin/providers/reload
+// 立即从数据库加载聚合器配置并应用到运行中的适配器
+func (h *RouterHandler) ReloadProviders(c *gin.Context) {
+	requestID := h.getOrGenerateRequestID(c)
+
+	if h.reloader == nil {
+		c.JSON(http.StatusServiceUnavailable, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeReloadDisabled,
+				Message: "聚合器配置热加载未启用(PROVIDER_RELOAD_ENABLED=false)",
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	h.logger.Infof("[%s] 收到聚合器配置重新加载请求", requestID)
+
+	result, err := h.reloader.Reload()
+	if err != nil {
+		h.logger.Errorf("[%s] 聚合器配置重新加载失败: %v", requestID, err)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeReloadFailed,
+				Message: err.Error(),
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success:   true,
+		Data:      result,
+		Timestamp: time.Now().Unix(),
+		RequestID: requestID,
+	})
+}
+
+// AdminAuth 管理接口鉴权中间件
+// 校验X-Admin-Token请求头与ADMIN_TOKEN配置一致
+func (h *RouterHandler) AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Admin-Token")), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    types.ErrCodeUnauthorized,
+					Message: "管理接口令牌无效",
+				},
+				Timestamp: time.Now().Unix(),
+				RequestID: h.getOrGenerateRequestID(c),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
 // ========================================
 // 辅助方法
 // ========================================
 
+// bindQuoteRequest 绑定并验证报价请求
+// 参数无效时直接写入400响应并返回false
+func (h *RouterHandler) bindQuoteRequest(c *gin.Context, requestID string) (*types.QuoteRequest, bool) {
+	// 绑定请求参数
+	var req types.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("[%s] 报价请求参数无效: %v", requestID, err)
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeInvalidRequest,
+				Message: "请求参数无效",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return nil, false
+	}
+
+	// 设置请求ID
+	if req.RequestID == "" {
+		req.RequestID = requestID
+	}
+
+	// 验证请求参数
+	if err := h.validateQuoteRequest(&req); err != nil {
+		h.logger.Warnf("[%s] 报价请求验证失败: %v", requestID, err)
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    types.ErrCodeInvalidRequest,
+				Message: err.Error(),
+			},
+			Timestamp: time.Now().Unix(),
+			RequestID: requestID,
+		})
+		return nil, false
+	}
+
+	return &req, true
+}
+
 // validateQuoteRequest 验证报价请求参数
 func (h *RouterHandler) validateQuoteRequest(req *types.QuoteRequest) error {
 	if req.FromToken == "" {
@@ -202,12 +402,25 @@ func (h *RouterHandler) validateQuoteRequest(req *types.QuoteRequest) error {
 		return fmt.Errorf("目标代币地址不能为空")
 	}
 
-	if req.FromToken == req.ToToken {
+	// 原生代币的不同表示(零地址、0xEeee...等)视为同一代币
+	if strings.EqualFold(types.NormalizeTokenAddress(req.ChainID, req.FromToken), types.NormalizeTokenAddress(req.ChainID, req.ToToken)) {
 		return fmt.Errorf("源代币和目标代币不能相同")
 	}
 
-	if req.AmountIn.IsZero() || req.AmountIn.IsNegative() {
-		return fmt.Errorf("输入数量必须大于0")
+	switch req.Side {
+	case "", types.QuoteSideSell:
+		if req.AmountIn.IsZero() || req.AmountIn.IsNegative() {
+			return fmt.Errorf("输入数量必须大于0")
+		}
+	case types.QuoteSideBuy:
+		if req.AmountOut.IsZero() || req.AmountOut.IsNegative() {
+			return fmt.Errorf("买入报价的期望输出数量必须大于0")
+		}
+		if req.SplitRouting {
+			return fmt.Errorf("拆单路由只支持卖出报价")
+		}
+	default:
+		return fmt.Errorf("无效的报价方向: %s (可选: %s, %s)", req.Side, types.QuoteSideSell, types.QuoteSideBuy)
 	}
 
 	if req.ChainID == 0 {
@@ -218,6 +431,37 @@ func (h *RouterHandler) validateQuoteRequest(req *types.QuoteRequest) error {
 		return fmt.Errorf("滑点必须在0-50%%之间")
 	}
 
+	if req.RankingStrategy != "" && !h.routerService.SupportsRankingStrategy(req.RankingStrategy) {
+		return fmt.Errorf("不支持的排序策略: %s", req.RankingStrategy)
+	}
+
+	if req.SplitRouting && !h.routerService.SplitRoutingEnabled() {
+		return fmt.Errorf("拆单路由未启用")
+	}
+
+	for _, decimals := range []*int32{req.FromTokenDecimals, req.ToTokenDecimals} {
+		if decimals != nil && (*decimals < 0 || *decimals > types.MaxTokenDecimals) {
+			return fmt.Errorf("代币小数位数必须在0-%d之间", types.MaxTokenDecimals)
+		}
+	}
+
+	return nil
+}
+
+// validateSwapRequest 验证交易构建请求参数
+func (h *RouterHandler) validateSwapRequest(req *types.SwapRequest) error {
+	if err := h.validateQuoteRequest(&req.QuoteRequest); err != nil {
+		return err
+	}
+
+	if req.UserAddress == "" {
+		return fmt.Errorf("用户钱包地址不能为空")
+	}
+
+	if req.IsExactOutput() {
+		return fmt.Errorf("交易构建暂只支持卖出报价")
+	}
+
 	return nil
 }
 
@@ -237,6 +481,21 @@ func (h *RouterHandler) getOrGenerateRequestID(c *gin.Context) string {
 	return requestID
 }
 
+// toAPIError 将路由服务错误转换为API错误信息
+func (h *RouterHandler) toAPIError(err error) *types.APIError {
+	if routerErr, ok := err.(*types.RouterError); ok {
+		return &types.APIError{
+			Code:    routerErr.Code,
+			Message: routerErr.Message,
+			Details: routerErr.Details,
+		}
+	}
+	return &types.APIError{
+		Code:    types.ErrCodeInternalError,
+		Message: "内部服务错误",
+	}
+}
+
 // handleRouterError 处理路由服务错误
 func (h *RouterHandler) handleRouterError(c *gin.Context, err error, requestID string) {
 	// 检查是否为路由服务错误
@@ -245,12 +504,19 @@ func (h *RouterHandler) handleRouterError(c *gin.Context, err error, requestID s
 		switch routerErr.Code {
 		case types.ErrCodeInvalidRequest:
 			statusCode = http.StatusBadRequest
-		case types.ErrCodeUnsupportedChain:
+		case types.ErrCodeUnsupportedChain, types.ErrCodeProviderNotFound, types.ErrCodeSwapNotSupported,
+			types.ErrCodeUnsupportedQuoteSide, types.ErrCodeNativeTokenUnsupported:
 			statusCode = http.StatusBadRequest
 		case types.ErrCodeNoValidQuotes:
 			statusCode = http.StatusServiceUnavailable
 		case types.ErrCodeRateLimitExceeded:
 			statusCode = http.StatusTooManyRequests
+		case types.ErrCodeCircuitOpen, types.ErrCodeInsufficientLiquidity:
+			statusCode = http.StatusServiceUnavailable
+		case types.ErrCodeSwapBuildFailed:
+			statusCode = http.StatusBadGateway
+		case types.ErrCodePriceImpactTooHigh:
+			statusCode = http.StatusUnprocessableEntity
 		default:
 			statusCode = http.StatusInternalServerError
 		}
@@ -0,0 +1,164 @@
+// Package services 渐进式聚合策略
+// 根据AggregationStrategy配置的时间窗口、响应数量和综合评分
+// 决定何时停止等待慢速聚合器并返回结果
+package services
+
+import (
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// progressiveCheckInterval 渐进式决策的定时复核间隔
+// 综合评分中的时间因子随时间增长，需要在没有新报价到达时也重新评估
+const progressiveCheckInterval = 50 * time.Millisecond
+
+// aggregationDecision 渐进式聚合决策结果
+type aggregationDecision struct {
+	Rule           string          // 触发返回的决策规则
+	DecidedAt      time.Duration   // 决策耗时
+	CompositeScore decimal.Decimal // 决策时的综合评分
+	Cancelled      int             // 被取消的聚合器数量
+}
+
+// progressiveCollector 渐进式报价收集器
+// 记录已到达的报价，并根据策略配置判断是否可以提前返回
+type progressiveCollector struct {
+	strategy      *types.AggregationStrategy // 聚合策略配置
+	total         int                        // 调用的聚合器总数
+	quotes        []*types.ProviderQuote     // 已到达的报价
+	responded     map[string]bool            // 已响应的聚合器
+	successQuotes int                        // 成功报价数量
+	goodQuotes    int                        // 达到最低置信度的成功报价数量
+}
+
+// newProgressiveCollector 创建渐进式报价收集器
+func newProgressiveCollector(strategy *types.AggregationStrategy, total int) *progressiveCollector {
+	return &progressiveCollector{
+		strategy:  strategy,
+		total:     total,
+		responded: make(map[string]bool, total),
+	}
+}
+
+// add 记录一个到达的报价
+func (c *progressiveCollector) add(quote *types.ProviderQuote) {
+	c.quotes = append(c.quotes, quote)
+	c.responded[quote.Provider] = true
+
+	if !isUsableQuote(quote) {
+		return
+	}
+
+	c.successQuotes++
+	if quote.Confidence.GreaterThanOrEqual(c.strategy.MinConfidence) {
+		c.goodQuotes++
+	}
+}
+
+// decide 判断是否可以结束收集
+// 规则按优先级依次检查，返回触发的规则名称
+func (c *progressiveCollector) decide(elapsed time.Duration) (string, bool) {
+	strategy := c.strategy
+
+	if len(c.quotes) >= c.total {
+		return types.DecisionAllResponded, true
+	}
+
+	if strategy.EmergencyTimeout > 0 && elapsed >= strategy.EmergencyTimeout {
+		return types.DecisionEmergencyTimeout, true
+	}
+
+	// 最小等待时间内不做提前返回，给较慢但报价更优的聚合器机会
+	if elapsed < strategy.MinWaitTime {
+		return "", false
+	}
+
+	if strategy.OptimalProviders > 0 && c.goodQuotes >= strategy.OptimalProviders {
+		return types.DecisionOptimalProviders, true
+	}
+
+	if strategy.PreferredProviders > 0 && c.goodQuotes >= strategy.PreferredProviders &&
+		elapsed >= strategy.FastResponseTime {
+		return types.DecisionPreferredProviders, true
+	}
+
+	if c.goodQuotes >= strategy.MinProviders && c.goodQuotes > 0 &&
+		c.compositeScore(elapsed).GreaterThanOrEqual(strategy.CompositeScoreThreshold) {
+		return types.DecisionCompositeScore, true
+	}
+
+	if strategy.MaxWaitTime > 0 && elapsed >= strategy.MaxWaitTime && c.successQuotes > 0 {
+		return types.DecisionMaxWait, true
+	}
+
+	return "", false
+}
+
+// compositeScore 计算当前收集状态的综合评分
+// 时间因子：等待越久越倾向于返回；置信度因子：成功报价的平均置信度；
+// 聚合器因子：高质量报价数量相对理想数量的比例；市场因子：各报价输出金额的一致性
+func (c *progressiveCollector) compositeScore(elapsed time.Duration) decimal.Decimal {
+	strategy := c.strategy
+	one := decimal.NewFromInt(1)
+
+	// 时间因子
+	timeScore := one
+	if strategy.MaxWaitTime > 0 {
+		timeScore = decimal.NewFromInt(int64(elapsed)).Div(decimal.NewFromInt(int64(strategy.MaxWaitTime)))
+		if timeScore.GreaterThan(one) {
+			timeScore = one
+		}
+	}
+
+	// 置信度因子与市场因子
+	confidenceScore := decimal.Zero
+	marketScore := decimal.Zero
+	var maxAmount, minAmount decimal.Decimal
+	for _, quote := range c.quotes {
+		if !isUsableQuote(quote) {
+			continue
+		}
+		confidenceScore = confidenceScore.Add(quote.Confidence)
+		if maxAmount.IsZero() || quote.AmountOut.GreaterThan(maxAmount) {
+			maxAmount = quote.AmountOut
+		}
+		if minAmount.IsZero() || quote.AmountOut.LessThan(minAmount) {
+			minAmount = quote.AmountOut
+		}
+	}
+	if c.successQuotes > 0 {
+		confidenceScore = confidenceScore.Div(decimal.NewFromInt(int64(c.successQuotes)))
+	}
+	switch {
+	case c.successQuotes >= 2:
+		// 报价越集中说明市场价格越可信
+		marketScore = one.Sub(maxAmount.Sub(minAmount).Div(maxAmount))
+		if marketScore.IsNegative() {
+			marketScore = decimal.Zero
+		}
+	case c.successQuotes == 1:
+		marketScore = decimal.NewFromFloat(0.5) // 单一报价无法比较
+	}
+
+	// 聚合器因子
+	providerScore := one
+	if strategy.PreferredProviders > 0 {
+		providerScore = decimal.NewFromInt(int64(c.goodQuotes)).Div(decimal.NewFromInt(int64(strategy.PreferredProviders)))
+		if providerScore.GreaterThan(one) {
+			providerScore = one
+		}
+	}
+
+	return timeScore.Mul(strategy.TimeWeight).
+		Add(confidenceScore.Mul(strategy.ConfidenceWeight)).
+		Add(providerScore.Mul(strategy.ProviderWeight)).
+		Add(marketScore.Mul(strategy.MarketWeight))
+}
+
+// isUsableQuote 判断报价是否可用于决策
+func isUsableQuote(quote *types.ProviderQuote) bool {
+	return quote.Success && !quote.AmountOut.IsZero()
+}
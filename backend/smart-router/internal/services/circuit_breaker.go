@@ -0,0 +1,392 @@
+// Package services 聚合器熔断器实现
+// 为每个聚合器适配器包装closed/open/half-open三态熔断器：
+// 连续失败达到阈值后熔断并跳过该聚合器，冷却期结束后先通过HealthCheck探测，
+// 探测成功进入半开状态放行有限数量的试探请求，试探成功达到阈值后恢复。
+// 只有传输错误、超时、429和5xx计为失败；流动性不足、4xx、无路由等与交易对或请求相关的结果不影响熔断状态
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// callOutcome 一次报价调用对熔断器的影响
+type callOutcome int
+
+const (
+	outcomeSuccess callOutcome = iota // 报价成功
+	outcomeFailure                    // 聚合器故障(传输错误、超时、429、5xx)
+	outcomeNeutral                    // 与交易对或请求相关的失败，不计入成功也不计入失败
+)
+
+// circuitBreaker 单个聚合器的熔断器
+type circuitBreaker struct {
+	config *types.CircuitBreakerConfig // 熔断配置
+	mutex  sync.Mutex                  // 状态锁
+
+	state                string    // 当前状态
+	consecutiveFailures  int       // 连续失败次数
+	consecutiveSuccesses int       // 半开状态下的连续成功次数
+	openedAt             time.Time // 最近一次熔断(或探测失败)时间
+	probing              bool      // 是否正在执行探测
+	halfOpenInFlight     int       // 半开状态下进行中的试探请求数
+
+	totalRequests    int64         // 总请求数
+	failedRequests   int64         // 失败请求数
+	rejectedRequests int64         // 熔断拒绝的请求数
+	lastResponseTime time.Duration // 最近一次响应时间
+	lastError        string        // 最近一次错误
+	lastCheckedAt    time.Time     // 最近一次调用或探测时间
+}
+
+// newCircuitBreaker 创建熔断器，初始为闭合状态
+func newCircuitBreaker(config *types.CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		config: config,
+		state:  types.CircuitClosed,
+	}
+}
+
+// allow 判断当前是否放行请求(不占用半开试探名额)
+func (b *circuitBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.config.Enabled || b.state != types.CircuitOpen {
+		return true
+	}
+	b.rejectedRequests++
+	return false
+}
+
+// allowQuote 判断当前是否放行报价请求，半开状态下试探名额已满时同样拒绝
+func (b *circuitBreaker) allowQuote() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.quoteAllowed() {
+		return true
+	}
+	b.rejectedRequests++
+	return false
+}
+
+// acquire 放行报价请求，半开状态下占用一个试探名额
+// trial为true时调用方必须通过record或release归还名额
+func (b *circuitBreaker) acquire() (allowed, trial bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.quoteAllowed() {
+		b.rejectedRequests++
+		return false, false
+	}
+	if b.config.Enabled && b.state == types.CircuitHalfOpen {
+		b.halfOpenInFlight++
+		return true, true
+	}
+	return true, false
+}
+
+// release 归还未产生结果的试探名额(如请求被提前取消)
+func (b *circuitBreaker) release(trial bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.releaseTrial(trial)
+}
+
+// quoteAllowed 当前状态是否允许报价请求（调用方需持有锁）
+func (b *circuitBreaker) quoteAllowed() bool {
+	if !b.config.Enabled {
+		return true
+	}
+	switch b.state {
+	case types.CircuitOpen:
+		return false
+	case types.CircuitHalfOpen:
+		return b.halfOpenInFlight < b.config.HalfOpenMaxCalls
+	default:
+		return true
+	}
+}
+
+// releaseTrial 归还试探名额（调用方需持有锁）
+func (b *circuitBreaker) releaseTrial(trial bool) {
+	if trial && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+}
+
+// record 记录一次请求结果，返回状态变化(from==to表示未变化)
+func (b *circuitBreaker) record(outcome callOutcome, trial bool, responseTime time.Duration, errMsg string) (from, to string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	from = b.state
+	b.releaseTrial(trial)
+	b.totalRequests++
+	b.lastResponseTime = responseTime
+	b.lastCheckedAt = time.Now()
+
+	switch outcome {
+	case outcomeNeutral:
+		return from, b.state
+	case outcomeSuccess:
+		b.consecutiveFailures = 0
+		if b.state == types.CircuitHalfOpen {
+			b.consecutiveSuccesses++
+			if b.consecutiveSuccesses >= b.config.SuccessThreshold {
+				b.state = types.CircuitClosed
+				b.consecutiveSuccesses = 0
+			}
+		}
+		return from, b.state
+	}
+
+	b.failedRequests++
+	b.consecutiveFailures++
+	b.lastError = errMsg
+
+	if !b.config.Enabled {
+		return from, b.state
+	}
+
+	// 半开状态下任何失败都重新熔断
+	if b.state == types.CircuitHalfOpen || b.consecutiveFailures >= b.config.FailureThreshold {
+		b.state = types.CircuitOpen
+		b.openedAt = b.lastCheckedAt
+		b.consecutiveSuccesses = 0
+	}
+	return from, b.state
+}
+
+// startProbe 冷却期结束后抢占探测权，同一时间只允许一个探测
+func (b *circuitBreaker) startProbe(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.config.Enabled || b.state != types.CircuitOpen || b.probing {
+		return false
+	}
+	if now.Sub(b.openedAt) < b.config.CoolDown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// finishProbe 记录探测结果：成功进入半开状态，失败重新开始冷却
+func (b *circuitBreaker) finishProbe(err error) (from, to string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	from = b.state
+	b.probing = false
+	b.lastCheckedAt = time.Now()
+
+	if b.state != types.CircuitOpen {
+		return from, b.state
+	}
+
+	if err != nil {
+		b.openedAt = b.lastCheckedAt
+		b.lastError = fmt.Sprintf("健康检查失败: %v", err)
+		return from, b.state
+	}
+
+	b.state = types.CircuitHalfOpen
+	b.consecutiveSuccesses = 0
+	b.consecutiveFailures = 0
+	b.halfOpenInFlight = 0
+	return from, b.state
+}
+
+// fillStatus 将熔断器状态填充到聚合器状态中
+func (b *circuitBreaker) fillStatus(status *types.ProviderStatus) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	status.CircuitState = b.state
+	status.ConsecutiveFailures = b.consecutiveFailures
+	status.TotalRequests = b.totalRequests
+	status.FailedRequests = b.failedRequests
+	status.RejectedRequests = b.rejectedRequests
+	status.SuccessRate = b.successRate()
+	status.LastResponseTime = b.lastResponseTime
+	status.LastError = b.lastError
+	status.LastCheckedAt = b.lastCheckedAt
+
+	if b.state == types.CircuitOpen {
+		openedAt := b.openedAt
+		nextProbeAt := openedAt.Add(b.config.CoolDown)
+		status.OpenedAt = &openedAt
+		status.NextProbeAt = &nextProbeAt
+	}
+}
+
+// successRate 计算成功率（调用方需持有锁），无请求时视为1
+func (b *circuitBreaker) successRate() decimal.Decimal {
+	if b.totalRequests == 0 {
+		return decimal.NewFromInt(1)
+	}
+	success := b.totalRequests - b.failedRequests
+	return decimal.NewFromInt(success).Div(decimal.NewFromInt(b.totalRequests)).Round(4)
+}
+
+// ========================================
+// 熔断适配器包装
+// ========================================
+
+// circuitBreakerAdapter 带熔断器的聚合器适配器
+// 除GetQuote和BuildSwap外的方法直接透传给被包装的适配器
+type circuitBreakerAdapter struct {
+	ProviderAdapter                 // 被包装的适配器
+	breaker         *circuitBreaker // 熔断器
+	logger          *logrus.Logger  // 日志记录器
+}
+
+// newCircuitBreakerAdapter 为适配器包装熔断器
+func newCircuitBreakerAdapter(adapter ProviderAdapter, config *types.CircuitBreakerConfig, logger *logrus.Logger) *circuitBreakerAdapter {
+	return &circuitBreakerAdapter{
+		ProviderAdapter: adapter,
+		breaker:         newCircuitBreaker(config),
+		logger:          logger,
+	}
+}
+
+// GetQuote 经过熔断器获取报价
+// 按classifyQuote区分聚合器故障和交易对相关的失败；渐进式策略提前返回导致的取消不计入
+func (a *circuitBreakerAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error) {
+	allowed, trial := a.breaker.acquire()
+	if !allowed {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeCircuitOpen,
+			Message:  fmt.Sprintf("聚合器 %s 已熔断", a.GetName()),
+			Provider: a.GetName(),
+		}
+	}
+
+	startTime := time.Now()
+	quote, err := a.ProviderAdapter.GetQuote(ctx, req)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		a.breaker.release(trial)
+		return quote, err
+	}
+
+	outcome, errMsg := classifyQuote(quote, err)
+	from, to := a.breaker.record(outcome, trial, time.Since(startTime), errMsg)
+	a.logTransition(from, to)
+
+	return quote, err
+}
+
+// classifyQuote 判断报价结果对熔断器的影响
+// 传输错误、超时、429和5xx为聚合器故障；流动性不足、4xx、无路由及不支持的请求不计入失败
+func classifyQuote(quote *types.ProviderQuote, err error) (callOutcome, string) {
+	if err != nil {
+		var routerErr *types.RouterError
+		if errors.As(err, &routerErr) && !isProviderFault(routerErr.Code) {
+			return outcomeNeutral, err.Error()
+		}
+		return outcomeFailure, err.Error()
+	}
+	if quote == nil {
+		return outcomeFailure, "聚合器返回空报价"
+	}
+	if quote.Success {
+		return outcomeSuccess, ""
+	}
+	if isProviderFault(quote.ErrorCode) {
+		return outcomeFailure, quote.ErrorMessage
+	}
+	return outcomeNeutral, quote.ErrorMessage
+}
+
+// isProviderFault 错误代码是否表示聚合器自身故障
+func isProviderFault(code string) bool {
+	switch code {
+	case types.ErrCodeProviderError, types.ErrCodeProviderTimeout, types.ErrCodeRateLimitExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// BuildSwap 经过熔断器构建交易
+// 熔断中的聚合器直接拒绝；构建结果不计入熔断统计，熔断状态只由报价调用驱动
+func (a *circuitBreakerAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	if !a.breaker.allow() {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeCircuitOpen,
+			Message:  fmt.Sprintf("聚合器 %s 已熔断", a.GetName()),
+			Provider: a.GetName(),
+		}
+	}
+	return a.ProviderAdapter.BuildSwap(ctx, req)
+}
+
+// admit 判断是否将该聚合器加入本次聚合
+// 熔断中或半开试探名额已满的聚合器被跳过；冷却期结束时在后台发起HealthCheck探测
+func (a *circuitBreakerAdapter) admit(probeTimeout time.Duration) bool {
+	if a.breaker.allowQuote() {
+		return true
+	}
+
+	if a.breaker.startProbe(time.Now()) {
+		go a.probe(probeTimeout)
+	}
+	return false
+}
+
+// probe 执行健康检查探测
+func (a *circuitBreakerAdapter) probe(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	a.logger.Infof("🩺 熔断探测: %s", a.GetName())
+	err := a.ProviderAdapter.HealthCheck(ctx)
+	if err != nil {
+		a.logger.Warnf("🩺 熔断探测失败: %s - %v", a.GetName(), err)
+	}
+
+	from, to := a.breaker.finishProbe(err)
+	a.logTransition(from, to)
+}
+
+// status 获取聚合器运行状态
+func (a *circuitBreakerAdapter) status() *types.ProviderStatus {
+	config := a.GetConfig()
+	status := &types.ProviderStatus{
+		Name:            a.GetName(),
+		DisplayName:     a.GetDisplayName(),
+		SupportedChains: append([]uint{}, config.SupportedChains...),
+		Timeout:         config.Timeout,
+	}
+	a.breaker.fillStatus(status)
+	return status
+}
+
+// logTransition 记录熔断器状态变化
+func (a *circuitBreakerAdapter) logTransition(from, to string) {
+	if from == to {
+		return
+	}
+
+	switch to {
+	case types.CircuitOpen:
+		a.logger.Warnf("🔌 聚合器熔断: %s (%s -> %s)", a.GetName(), from, to)
+	case types.CircuitHalfOpen:
+		a.logger.Infof("🔌 聚合器进入半开状态: %s (%s -> %s)", a.GetName(), from, to)
+	case types.CircuitClosed:
+		a.logger.Infof("🔌 聚合器恢复: %s (%s -> %s)", a.GetName(), from, to)
+	}
+}
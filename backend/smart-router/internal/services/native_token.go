@@ -0,0 +1,51 @@
+// Package services 原生代币规范化
+// 请求中的原生代币(零地址、链特有别名等)在进入聚合流程前统一规范化为types.NativeTokenAddress，
+// 再由各适配器转换为聚合器要求的形式；不支持该代币组合的聚合器不参与聚合，以失败报价返回排除原因
+package services
+
+import (
+	"errors"
+
+	"defi-aggregator/smart-router/internal/types"
+)
+
+// normalizeNativeTokens 将请求中原生代币的各种表示规范化为types.NativeTokenAddress
+func (s *RouterService) normalizeNativeTokens(req *types.QuoteRequest) {
+	fromToken := types.NormalizeTokenAddress(req.ChainID, req.FromToken)
+	toToken := types.NormalizeTokenAddress(req.ChainID, req.ToToken)
+	if fromToken != req.FromToken || toToken != req.ToToken {
+		s.logger.Debugf("[%s] 原生代币地址规范化: %s->%s => %s->%s",
+			req.RequestID, req.FromToken, req.ToToken, fromToken, toToken)
+	}
+	req.FromToken, req.ToToken = fromToken, toToken
+}
+
+// filterTokenSupport 按代币支持情况筛选聚合器
+// 返回可参与聚合的聚合器，以及被排除聚合器的失败报价(错误码和原因来自适配器CheckTokens)
+func (s *RouterService) filterTokenSupport(req *types.QuoteRequest, adapters []ProviderAdapter) ([]ProviderAdapter, []*types.ProviderQuote) {
+	supported := make([]ProviderAdapter, 0, len(adapters))
+	var excluded []*types.ProviderQuote
+
+	for _, adapter := range adapters {
+		err := adapter.CheckTokens(req)
+		if err == nil {
+			supported = append(supported, adapter)
+			continue
+		}
+
+		errorCode := types.ErrCodeNativeTokenUnsupported
+		var routerErr *types.RouterError
+		if errors.As(err, &routerErr) {
+			errorCode = routerErr.Code
+		}
+		s.logger.Infof("[%s] ⏭️ 聚合器 %s 不参与聚合: %v", req.RequestID, adapter.GetName(), err)
+		excluded = append(excluded, &types.ProviderQuote{
+			Provider:     adapter.GetName(),
+			Success:      false,
+			ErrorCode:    errorCode,
+			ErrorMessage: err.Error(),
+		})
+	}
+
+	return supported, excluded
+}
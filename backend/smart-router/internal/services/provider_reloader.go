@@ -0,0 +1,96 @@
+// Package services 聚合器配置热加载
+// 定期从配置源(数据库aggregators/aggregator_chains表)重新加载聚合器配置，
+// 与运行中的适配器比对后增删或更新，运维无需重新部署即可启停聚合器或调整超时
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProviderConfigSource 聚合器配置源
+// config.AggregatorConfigManager实现该接口
+type ProviderConfigSource interface {
+	LoadActiveProviders() ([]types.ProviderConfig, error)
+}
+
+// ProviderReloader 聚合器配置热加载器
+type ProviderReloader struct {
+	source        ProviderConfigSource // 配置源
+	routerService *RouterService       // 路由服务
+	interval      time.Duration        // 轮询间隔
+	logger        *logrus.Logger       // 日志记录器
+
+	reloadMutex sync.Mutex    // 保证同一时间只有一次重新加载
+	stopChan    chan struct{} // 停止信号
+	stopOnce    sync.Once     // 保证只停止一次
+}
+
+// NewProviderReloader 创建聚合器配置热加载器
+func NewProviderReloader(source ProviderConfigSource, routerService *RouterService, interval time.Duration, logger *logrus.Logger) *ProviderReloader {
+	return &ProviderReloader{
+		source:        source,
+		routerService: routerService,
+		interval:      interval,
+		logger:        logger,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start 启动后台轮询协程
+func (r *ProviderReloader) Start() {
+	r.logger.Infof("🔄 聚合器配置热加载已启动: interval=%v", r.interval)
+	go r.loop()
+}
+
+// Stop 停止后台轮询
+func (r *ProviderReloader) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+// Reload 立即从配置源重新加载聚合器配置
+// 配置源加载失败时保留当前适配器不变
+func (r *ProviderReloader) Reload() (*types.ProviderReloadResult, error) {
+	r.reloadMutex.Lock()
+	defer r.reloadMutex.Unlock()
+
+	providers, err := r.source.LoadActiveProviders()
+	if err != nil {
+		return nil, fmt.Errorf("加载聚合器配置失败: %w", err)
+	}
+
+	result := r.routerService.ApplyProviderConfigs(providers)
+
+	if len(result.Added)+len(result.Removed)+len(result.Updated)+len(result.Failed) > 0 {
+		r.logger.Infof("🔄 聚合器配置已重新加载: 新增=%v, 移除=%v, 更新=%v, 失败=%v",
+			result.Added, result.Removed, result.Updated, result.Failed)
+	} else {
+		r.logger.Debugf("🔄 聚合器配置无变化: %v", result.Unchanged)
+	}
+
+	return result, nil
+}
+
+// loop 定期重新加载配置
+func (r *ProviderReloader) loop() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case <-ticker.C:
+			if _, err := r.Reload(); err != nil {
+				r.logger.Warnf("⚠️ 聚合器配置热加载失败，保留当前配置: %v", err)
+			}
+		}
+	}
+}
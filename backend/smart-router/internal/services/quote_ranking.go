@@ -0,0 +1,154 @@
+// Package services 报价Gas费用折算
+// 行情数据可用时将每个报价的Gas费用(gasEstimate × gasPrice)经原生代币和输出代币的USD价格
+// 折算为输出代币数量，得到NetAmountOut = AmountOut - Gas费用，随报价返回并供net_of_gas策略排序；
+// 买入报价折算为输入代币数量，得到NetAmountIn = AmountIn + Gas费用；
+// 折算所需的Gas价格或代币价格不可用时，该请求回退到weighted策略
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarketDataSource 行情数据源
+// config.MarketDataManager实现该接口
+type MarketDataSource interface {
+	GetGasPrice(ctx context.Context, chainID uint) (*types.ChainGasPrice, error)
+	GetNativeToken(ctx context.Context, chainID uint) (*types.TokenMarketData, error)
+	GetToken(ctx context.Context, chainID uint, address string) (*types.TokenMarketData, error)
+}
+
+// gasPricing 单次请求的Gas费用折算参数
+type gasPricing struct {
+	gasPrice       decimal.Decimal        // Gas价格(wei)
+	gasPriceSource string                 // Gas价格来源
+	nativeToken    *types.TokenMarketData // 链原生代币
+	quoteToken     *types.TokenMarketData // 计价代币：卖出为输出代币，买入为输入代币(为原生代币时与nativeToken相同)
+}
+
+// resolveGasPricingAsync 在聚合报价的同时获取Gas费用折算参数
+// 未配置行情数据源或折算参数不可用时通道中返回nil
+func (s *RouterService) resolveGasPricingAsync(ctx context.Context, req *types.QuoteRequest) <-chan *gasPricing {
+	result := make(chan *gasPricing, 1)
+
+	if s.marketData == nil {
+		result <- nil
+		return result
+	}
+
+	// 只有net_of_gas排序和拆单方案依赖Gas费用，其他情况下折算失败不影响报价
+	required := s.rankingStrategy(req) == types.RankingStrategyNetOfGas || s.splitRoutingRequested(req)
+
+	go func() {
+		pricing, err := s.resolveGasPricing(ctx, req)
+		if err != nil {
+			if required {
+				s.logger.Warnf("[%s] ⚠️ 无法计算Gas费用: %v", req.RequestID, err)
+			} else {
+				s.logger.Debugf("[%s] 无法计算Gas费用: %v", req.RequestID, err)
+			}
+			result <- nil
+			return
+		}
+		result <- pricing
+	}()
+
+	return result
+}
+
+// resolveGasPricing 获取Gas费用折算参数
+// 请求指定Gas价格时优先使用，否则使用链当前Gas价格
+func (s *RouterService) resolveGasPricing(ctx context.Context, req *types.QuoteRequest) (*gasPricing, error) {
+	if s.marketData == nil {
+		return nil, fmt.Errorf("未配置行情数据源")
+	}
+
+	pricing := &gasPricing{}
+	if req.GasPrice != nil && req.GasPrice.IsPositive() {
+		pricing.gasPrice = *req.GasPrice
+		pricing.gasPriceSource = types.GasPriceSourceRequest
+	} else {
+		gasPrice, err := s.marketData.GetGasPrice(ctx, req.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("获取链Gas价格失败: %w", err)
+		}
+		pricing.gasPrice = gasPrice.Wei
+		pricing.gasPriceSource = gasPrice.Source
+	}
+
+	nativeToken, err := s.marketData.GetNativeToken(ctx, req.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("获取原生代币信息失败: %w", err)
+	}
+	pricing.nativeToken = nativeToken
+
+	quoteTokenAddress := req.ToToken
+	if req.IsExactOutput() {
+		quoteTokenAddress = req.FromToken
+	}
+
+	// 计价代币为原生代币时Gas费用直接按原生代币数量计算，不依赖USD价格
+	if types.IsNativeToken(req.ChainID, quoteTokenAddress) || strings.EqualFold(quoteTokenAddress, nativeToken.Address) {
+		pricing.quoteToken = nativeToken
+		return pricing, nil
+	}
+
+	if !nativeToken.PriceUSD.IsPositive() {
+		return nil, fmt.Errorf("原生代币 %s 没有USD价格", nativeToken.Symbol)
+	}
+
+	quoteToken, err := s.marketData.GetToken(ctx, req.ChainID, quoteTokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("获取计价代币 %s 信息失败: %w", quoteTokenAddress, err)
+	}
+	if !quoteToken.PriceUSD.IsPositive() {
+		return nil, fmt.Errorf("计价代币 %s 没有USD价格", quoteToken.Symbol)
+	}
+	pricing.quoteToken = quoteToken
+
+	return pricing, nil
+}
+
+// gasCost 计算指定Gas估算的费用明细
+// 折算结果向上取整到计价代币最小单位，避免低估Gas费用
+func (p *gasPricing) gasCost(gasEstimate uint64) *types.GasCost {
+	costNative := decimal.NewFromBigInt(new(big.Int).SetUint64(gasEstimate), 0).Mul(p.gasPrice)
+	costUSD := costNative.Shift(-p.nativeToken.Decimals).Mul(p.nativeToken.PriceUSD)
+
+	costInOutput := costNative
+	if p.quoteToken != p.nativeToken {
+		costInOutput = costUSD.Div(p.quoteToken.PriceUSD).Shift(p.quoteToken.Decimals).Ceil()
+	}
+
+	return &types.GasCost{
+		GasPrice:            p.gasPrice,
+		GasPriceSource:      p.gasPriceSource,
+		CostNative:          costNative,
+		NativeTokenPriceUSD: p.nativeToken.PriceUSD,
+		OutputTokenPriceUSD: p.quoteToken.PriceUSD,
+		CostUSD:             costUSD,
+		CostInOutputToken:   costInOutput,
+	}
+}
+
+// applyGasCosts 为报价计算Gas费用明细和净输出数量(买入报价为净输入数量)
+// GasEstimate为0的报价(如CoW Protocol由solver承担Gas)不扣除Gas费用
+func (p *gasPricing) applyGasCosts(quotes []*types.ProviderQuote, side string) {
+	for _, quote := range quotes {
+		cost := p.gasCost(quote.GasEstimate)
+		quote.GasCost = cost
+		if side == types.QuoteSideBuy {
+			netAmountIn := quote.AmountIn.Add(cost.CostInOutputToken)
+			quote.NetAmountIn = &netAmountIn
+			continue
+		}
+		netAmountOut := quote.AmountOut.Sub(cost.CostInOutputToken)
+		quote.NetAmountOut = &netAmountOut
+	}
+}
@@ -0,0 +1,282 @@
+// Package services 报价排序策略
+// Ranker为每个有效报价计算评分(越高越好)并写入ProviderQuote.Score，RouterService按评分排序选出最优报价；
+// 内置max_output、net_of_gas、weighted、lowest_latency四种策略，请求可通过ranking_strategy指定。
+// 买入(exact-output)报价的输出数量固定，各策略改为比较所需输入数量，输入越少评分越高
+package services
+
+import (
+	"errors"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRankingUnavailable 排序策略所需数据不可用(如net_of_gas缺少Gas费用)
+var ErrRankingUnavailable = errors.New("排序策略所需数据不可用")
+
+// 评分组成名称
+const (
+	scoreComponentAmountOut    = "amount_out"       // 输出数量
+	scoreComponentAmountIn     = "amount_in"        // 输入数量(买入报价)
+	scoreComponentNetAmountIn  = "net_amount_in"    // 加上Gas费用后的净输入数量(买入报价)
+	scoreComponentGasCost      = "gas_cost"         // Gas费用(输出代币最小单位)
+	scoreComponentNetAmountOut = "net_amount_out"   // 扣除Gas费用后的净输出数量
+	scoreComponentPrice        = "price"            // 价格评分
+	scoreComponentGas          = "gas"              // Gas效率评分
+	scoreComponentConfidence   = "confidence"       // 置信度评分
+	scoreComponentTime         = "time"             // 响应时间评分
+	scoreComponentResponseMS   = "response_time_ms" // 响应时间(毫秒)
+)
+
+// Ranker 报价排序策略
+type Ranker interface {
+	// Name 策略名称
+	Name() string
+	// Score 为报价计算评分并写入quote.Score，quotes均为成功且输出数量大于0的报价
+	// side为报价方向，买入报价的输入数量也大于0；缺少所需数据时返回ErrRankingUnavailable
+	Score(quotes []*types.ProviderQuote, side string) error
+}
+
+// newRankers 创建内置排序策略
+func newRankers(strategy *types.AggregationStrategy) map[string]Ranker {
+	rankers := []Ranker{
+		&maxOutputRanker{},
+		&netOfGasRanker{},
+		&weightedRanker{strategy: strategy},
+		&lowestLatencyRanker{},
+	}
+
+	result := make(map[string]Ranker, len(rankers))
+	for _, ranker := range rankers {
+		result[ranker.Name()] = ranker
+	}
+	return result
+}
+
+// ========================================
+// max_output: 按输出数量排序(买入报价按输入数量)
+// ========================================
+
+type maxOutputRanker struct{}
+
+func (r *maxOutputRanker) Name() string { return types.RankingStrategyMaxOutput }
+
+// Score 卖出报价评分为输出数量，买入报价评分为输入数量的相反数
+func (r *maxOutputRanker) Score(quotes []*types.ProviderQuote, side string) error {
+	for _, quote := range quotes {
+		if side == types.QuoteSideBuy {
+			quote.Score = &types.QuoteScore{
+				Strategy:   r.Name(),
+				Total:      quote.AmountIn.Neg(),
+				Components: map[string]decimal.Decimal{scoreComponentAmountIn: quote.AmountIn},
+			}
+			continue
+		}
+		quote.Score = &types.QuoteScore{
+			Strategy:   r.Name(),
+			Total:      quote.AmountOut,
+			Components: map[string]decimal.Decimal{scoreComponentAmountOut: quote.AmountOut},
+		}
+	}
+	return nil
+}
+
+// ========================================
+// net_of_gas: 按扣除Gas费用后的净输出数量排序
+// ========================================
+
+type netOfGasRanker struct{}
+
+func (r *netOfGasRanker) Name() string { return types.RankingStrategyNetOfGas }
+
+// Score 使用RouterService预先计算的NetAmountOut(买入报价为NetAmountIn)，任一报价缺少Gas费用时整体不可用
+func (r *netOfGasRanker) Score(quotes []*types.ProviderQuote, side string) error {
+	if side == types.QuoteSideBuy {
+		return r.scoreExactOutput(quotes)
+	}
+
+	for _, quote := range quotes {
+		if quote.NetAmountOut == nil || quote.GasCost == nil {
+			return ErrRankingUnavailable
+		}
+	}
+
+	for _, quote := range quotes {
+		quote.Score = &types.QuoteScore{
+			Strategy: r.Name(),
+			Total:    *quote.NetAmountOut,
+			Components: map[string]decimal.Decimal{
+				scoreComponentAmountOut:    quote.AmountOut,
+				scoreComponentGasCost:      quote.GasCost.CostInOutputToken,
+				scoreComponentNetAmountOut: *quote.NetAmountOut,
+			},
+		}
+	}
+	return nil
+}
+
+// scoreExactOutput 买入报价评分为净输入数量的相反数
+func (r *netOfGasRanker) scoreExactOutput(quotes []*types.ProviderQuote) error {
+	for _, quote := range quotes {
+		if quote.NetAmountIn == nil || quote.GasCost == nil {
+			return ErrRankingUnavailable
+		}
+	}
+
+	for _, quote := range quotes {
+		quote.Score = &types.QuoteScore{
+			Strategy: r.Name(),
+			Total:    quote.NetAmountIn.Neg(),
+			Components: map[string]decimal.Decimal{
+				scoreComponentAmountIn:    quote.AmountIn,
+				scoreComponentGasCost:     quote.GasCost.CostInOutputToken,
+				scoreComponentNetAmountIn: *quote.NetAmountIn,
+			},
+		}
+	}
+	return nil
+}
+
+// ========================================
+// weighted: 价格、Gas、置信度、响应时间加权综合评分
+// ========================================
+
+type weightedRanker struct {
+	strategy *types.AggregationStrategy // 评分权重和响应时间分段
+}
+
+func (r *weightedRanker) Name() string { return types.RankingStrategyWeighted }
+
+func (r *weightedRanker) Score(quotes []*types.ProviderQuote, side string) error {
+	weights := map[string]decimal.Decimal{
+		scoreComponentPrice:      r.strategy.ScorePriceWeight,
+		scoreComponentGas:        r.strategy.ScoreGasWeight,
+		scoreComponentConfidence: r.strategy.ScoreConfidenceWeight,
+		scoreComponentTime:       r.strategy.ScoreTimeWeight,
+	}
+
+	for _, quote := range quotes {
+		components := map[string]decimal.Decimal{
+			scoreComponentPrice:      r.priceScore(quote, quotes, side), // 输出越多(买入时输入越少)评分越高
+			scoreComponentGas:        r.gasScore(quote, quotes),         // Gas费用越低评分越高
+			scoreComponentConfidence: quote.Confidence,                  // 直接使用置信度
+			scoreComponentTime:       r.timeScore(quote.ResponseTime),   // 响应越快评分越高
+		}
+
+		total := decimal.Zero
+		for name, score := range components {
+			total = total.Add(score.Mul(weights[name]))
+		}
+
+		quote.Score = &types.QuoteScore{
+			Strategy:   r.Name(),
+			Total:      total,
+			Components: components,
+			Weights:    weights,
+		}
+	}
+	return nil
+}
+
+// priceScore 价格评分：按输出数量在所有报价中的位置归一化到0-1
+// 买入报价按输入数量归一化，输入最少的报价得1
+func (r *weightedRanker) priceScore(quote *types.ProviderQuote, quotes []*types.ProviderQuote, side string) decimal.Decimal {
+	amount := func(q *types.ProviderQuote) decimal.Decimal {
+		if side == types.QuoteSideBuy {
+			return q.AmountIn
+		}
+		return q.AmountOut
+	}
+
+	maxAmount, minAmount := amount(quotes[0]), amount(quotes[0])
+	for _, q := range quotes[1:] {
+		if amount(q).GreaterThan(maxAmount) {
+			maxAmount = amount(q)
+		}
+		if amount(q).LessThan(minAmount) {
+			minAmount = amount(q)
+		}
+	}
+
+	if maxAmount.Equal(minAmount) {
+		return decimal.NewFromFloat(1.0)
+	}
+	if side == types.QuoteSideBuy {
+		return maxAmount.Sub(amount(quote)).Div(maxAmount.Sub(minAmount))
+	}
+	return amount(quote).Sub(minAmount).Div(maxAmount.Sub(minAmount))
+}
+
+// gasScore Gas效率评分：按Gas估算归一化到0-1，没有Gas估算或无法比较时为中等评分
+func (r *weightedRanker) gasScore(quote *types.ProviderQuote, quotes []*types.ProviderQuote) decimal.Decimal {
+	if len(quotes) == 1 {
+		return decimal.NewFromFloat(1.0)
+	}
+
+	var maxGas, minGas uint64
+	for _, q := range quotes {
+		if q.GasEstimate == 0 {
+			continue
+		}
+		if maxGas == 0 || q.GasEstimate > maxGas {
+			maxGas = q.GasEstimate
+		}
+		if minGas == 0 || q.GasEstimate < minGas {
+			minGas = q.GasEstimate
+		}
+	}
+
+	if maxGas == minGas || quote.GasEstimate == 0 {
+		return decimal.NewFromFloat(0.5)
+	}
+
+	gasRange := decimal.NewFromInt(int64(maxGas - minGas))
+	gasOffset := decimal.NewFromInt(int64(maxGas - quote.GasEstimate))
+	return gasOffset.Div(gasRange)
+}
+
+// timeScore 响应时间评分：取第一个不小于响应时间的分段评分
+func (r *weightedRanker) timeScore(responseTime time.Duration) decimal.Decimal {
+	for _, bucket := range r.strategy.ResponseTimeBuckets {
+		if responseTime <= bucket.MaxResponseTime {
+			return bucket.Score
+		}
+	}
+	return r.strategy.SlowResponseScore
+}
+
+// ========================================
+// lowest_latency: 按响应时间排序
+// ========================================
+
+type lowestLatencyRanker struct{}
+
+func (r *lowestLatencyRanker) Name() string { return types.RankingStrategyLowestLatency }
+
+// Score 评分为最快响应时间与该报价响应时间之比，最快的报价得1
+func (r *lowestLatencyRanker) Score(quotes []*types.ProviderQuote, side string) error {
+	fastest := quotes[0].ResponseTime
+	for _, q := range quotes[1:] {
+		if q.ResponseTime < fastest {
+			fastest = q.ResponseTime
+		}
+	}
+
+	for _, quote := range quotes {
+		total := decimal.NewFromFloat(1.0)
+		if quote.ResponseTime > 0 {
+			total = decimal.NewFromInt(int64(fastest)).Div(decimal.NewFromInt(int64(quote.ResponseTime)))
+		}
+
+		quote.Score = &types.QuoteScore{
+			Strategy: r.Name(),
+			Total:    total,
+			Components: map[string]decimal.Decimal{
+				scoreComponentResponseMS: decimal.NewFromInt(quote.ResponseTime.Milliseconds()),
+			},
+		}
+	}
+	return nil
+}
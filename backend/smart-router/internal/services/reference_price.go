@@ -0,0 +1,204 @@
+// Package services 参考价格与价格冲击
+// 每个交易对的参考汇率优先由tokens表USD价格计算，价格不可用时向聚合器获取小额报价作为近似现货价格；
+// 所有聚合器报价的PriceImpact都相对同一参考汇率计算，超过上限的报价按配置标记或拒绝
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+	"defi-aggregator/smart-router/pkg/cache"
+
+	"github.com/shopspring/decimal"
+)
+
+// resolveReferencePriceAsync 在聚合报价的同时获取参考价格
+// 未启用价格冲击计算或参考价格不可用时通道中返回nil
+func (s *RouterService) resolveReferencePriceAsync(ctx context.Context, req *types.QuoteRequest, adapters []ProviderAdapter) <-chan *types.ReferencePrice {
+	result := make(chan *types.ReferencePrice, 1)
+
+	if !s.config.PriceImpact.Enabled {
+		result <- nil
+		return result
+	}
+
+	go func() {
+		reference, err := s.resolveReferencePrice(ctx, req, adapters)
+		if err != nil {
+			s.logger.Warnf("[%s] ⚠️ 无法获取参考价格，跳过价格冲击计算: %v", req.RequestID, err)
+			result <- nil
+			return
+		}
+		result <- reference
+	}()
+
+	return result
+}
+
+// resolveReferencePrice 获取交易对参考价格
+// 依次尝试缓存、tokens表USD价格和聚合器小额报价
+func (s *RouterService) resolveReferencePrice(ctx context.Context, req *types.QuoteRequest, adapters []ProviderAdapter) (*types.ReferencePrice, error) {
+	cacheKey := fmt.Sprintf("%s%d_%s_%s", types.CacheKeyRefRate, req.ChainID,
+		strings.ToLower(req.FromToken), strings.ToLower(req.ToToken))
+
+	var cached types.ReferencePrice
+	if err := s.cache.Get(cacheKey, &cached); err == nil {
+		return &cached, nil
+	} else if !errors.Is(err, cache.ErrCacheMiss) {
+		s.logger.Debugf("参考价格缓存查询失败: %v", err)
+	}
+
+	reference, err := s.referenceFromUSDPrices(ctx, req)
+	if err != nil {
+		s.logger.Debugf("[%s] USD价格不可用，改用小额报价: %v", req.RequestID, err)
+		reference, err = s.referenceFromSpotQuote(ctx, req, adapters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.cache.Set(cacheKey, reference, s.config.PriceImpact.CacheTTL); err != nil {
+		s.logger.Warnf("缓存参考价格失败: %v", err)
+	}
+	return reference, nil
+}
+
+// referenceFromUSDPrices 由输入输出代币的USD价格和小数位数计算参考汇率
+func (s *RouterService) referenceFromUSDPrices(ctx context.Context, req *types.QuoteRequest) (*types.ReferencePrice, error) {
+	if s.marketData == nil {
+		return nil, fmt.Errorf("未配置行情数据源")
+	}
+
+	fromToken, err := s.marketData.GetToken(ctx, req.ChainID, req.FromToken)
+	if err != nil {
+		return nil, fmt.Errorf("获取输入代币信息失败: %w", err)
+	}
+	toToken, err := s.marketData.GetToken(ctx, req.ChainID, req.ToToken)
+	if err != nil {
+		return nil, fmt.Errorf("获取输出代币信息失败: %w", err)
+	}
+	if !fromToken.PriceUSD.IsPositive() || !toToken.PriceUSD.IsPositive() {
+		return nil, fmt.Errorf("代币 %s/%s 缺少USD价格", fromToken.Symbol, toToken.Symbol)
+	}
+
+	// 每单位输入(最小单位)对应的输出(最小单位) = 输入价格/输出价格 × 10^(输出小数位-输入小数位)
+	rate := fromToken.PriceUSD.Div(toToken.PriceUSD).Shift(toToken.Decimals - fromToken.Decimals)
+
+	return &types.ReferencePrice{
+		Rate:      rate,
+		Source:    types.ReferenceSourceUSDPrice,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// referenceFromSpotQuote 按请求金额的SpotSizeRatio向聚合器获取小额报价
+// 按优先级依次尝试，第一个成功的报价即作为参考汇率；买入报价按期望输出数量的比例获取小额买入报价
+func (s *RouterService) referenceFromSpotQuote(ctx context.Context, req *types.QuoteRequest, adapters []ProviderAdapter) (*types.ReferencePrice, error) {
+	requestAmount := req.AmountIn
+	if req.IsExactOutput() {
+		requestAmount = req.AmountOut
+	}
+	spotAmount := requestAmount.Mul(s.config.PriceImpact.SpotSizeRatio).Floor()
+	if !spotAmount.IsPositive() {
+		return nil, fmt.Errorf("请求金额过小，无法获取小额报价")
+	}
+
+	candidates := append([]ProviderAdapter(nil), adapters...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].GetConfig().Priority < candidates[j].GetConfig().Priority
+	})
+
+	spotCtx, cancel := context.WithTimeout(ctx, s.config.PriceImpact.SpotTimeout)
+	defer cancel()
+
+	spotReq := *req
+	spotReq.RequestID = req.RequestID + "-ref"
+	if req.IsExactOutput() {
+		spotReq.AmountOut = spotAmount
+	} else {
+		spotReq.AmountIn = spotAmount
+	}
+
+	var lastErr error
+	for _, adapter := range candidates {
+		quote, err := adapter.GetQuote(spotCtx, &spotReq)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		spotAmountIn := quoteAmountIn(quote, &spotReq)
+		if !quote.Success || !quote.AmountOut.IsPositive() || !spotAmountIn.IsPositive() {
+			lastErr = fmt.Errorf("聚合器 %s 小额报价无效", adapter.GetName())
+			continue
+		}
+
+		return &types.ReferencePrice{
+			Rate:         quote.AmountOut.Div(spotAmountIn),
+			Source:       types.ReferenceSourceSpotQuote,
+			Provider:     adapter.GetName(),
+			SpotAmountIn: &spotAmountIn,
+			UpdatedAt:    time.Now(),
+		}, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("没有可用的聚合器")
+	}
+	return nil, fmt.Errorf("获取小额报价失败: %w", lastErr)
+}
+
+// quoteAmountIn 报价的输入数量：卖出报价为请求的AmountIn，买入报价为聚合器返回的所需输入
+func quoteAmountIn(quote *types.ProviderQuote, req *types.QuoteRequest) decimal.Decimal {
+	if req.IsExactOutput() {
+		return quote.AmountIn
+	}
+	return req.AmountIn
+}
+
+// applyPriceImpact 相对参考汇率计算每个成功报价的价格冲击
+// 价格冲击 = (参考汇率 - 实际汇率) / 参考汇率，优于参考价格时记为0；
+// 超过上限时flag模式标记HighImpact，reject模式将报价置为失败，返回被拒绝的报价数量
+func (s *RouterService) applyPriceImpact(quotes []*types.ProviderQuote, reference *types.ReferencePrice, req *types.QuoteRequest) int {
+	maxImpact := s.config.PriceImpact.MaxImpact
+	rejected := 0
+
+	for _, quote := range quotes {
+		amountIn := quoteAmountIn(quote, req)
+		if !quote.Success || !amountIn.IsPositive() {
+			continue
+		}
+
+		actualRate := quote.AmountOut.Div(amountIn)
+		impact := reference.Rate.Sub(actualRate).Div(reference.Rate)
+		if impact.IsNegative() {
+			impact = decimal.Zero
+		}
+		quote.PriceImpact = impact
+
+		if maxImpact.IsZero() || impact.LessThanOrEqual(maxImpact) {
+			continue
+		}
+
+		if s.config.PriceImpact.Action == types.PriceImpactActionReject {
+			s.logger.Warnf("[%s] 🚫 拒绝聚合器 %s 报价: 价格冲击=%s, 上限=%s",
+				req.RequestID, quote.Provider, impact.StringFixed(4), maxImpact.String())
+			quote.Success = false
+			quote.ErrorCode = types.ErrCodePriceImpactTooHigh
+			quote.ErrorMessage = fmt.Sprintf("价格冲击%s%%超过上限%s%%",
+				impact.Shift(2).StringFixed(2), maxImpact.Shift(2).StringFixed(2))
+			rejected++
+			continue
+		}
+
+		s.logger.Warnf("[%s] ⚠️ 聚合器 %s 价格冲击过高: %s, 上限=%s",
+			req.RequestID, quote.Provider, impact.StringFixed(4), maxImpact.String())
+		quote.HighImpact = true
+	}
+
+	return rejected
+}
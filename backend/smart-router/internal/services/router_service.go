@@ -5,7 +5,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -22,20 +25,25 @@ type ProviderAdapter interface {
 	GetName() string
 	GetDisplayName() string
 	IsSupported(chainID uint) bool
+	SupportsQuoteSide(side string) bool
+	CheckTokens(req *types.QuoteRequest) error
 	GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error)
+	BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error)
 	HealthCheck(ctx context.Context) error
-	UpdateConfig(config *types.ProviderConfig) error
 	GetConfig() *types.ProviderConfig
 }
 
 // RouterService 智能路由服务
 // 核心聚合服务，协调多个聚合器适配器，实现智能报价聚合
 type RouterService struct {
-	adapters map[string]ProviderAdapter // 聚合器适配器集合
-	cache    cache.CacheManager         // 缓存管理器
-	config   *types.Config              // 服务配置
-	logger   *logrus.Logger             // 日志记录器
-	metrics  *RouterMetrics             // 服务指标
+	adapters      map[string]*circuitBreakerAdapter // 聚合器适配器集合(带熔断器)
+	adaptersMutex sync.RWMutex                      // 适配器集合读写锁(热加载时整体替换)
+	cache         cache.CacheManager                // 缓存管理器
+	marketData    MarketDataSource                  // 行情数据源(Gas费用折算使用，可为nil)
+	rankers       map[string]Ranker                 // 报价排序策略
+	config        *types.Config                     // 服务配置(Providers在adaptersMutex内整体替换)
+	logger        *logrus.Logger                    // 日志记录器
+	metrics       *RouterMetrics                    // 服务指标
 }
 
 // RouterMetrics 路由服务指标
@@ -49,20 +57,25 @@ type RouterMetrics struct {
 }
 
 // NewRouterService 创建智能路由服务实例
-// 初始化所有聚合器适配器和缓存管理器
-func NewRouterService(config *types.Config, cacheManager cache.CacheManager, logger *logrus.Logger) *RouterService {
+// 初始化所有聚合器适配器和缓存管理器，marketData为nil时net_of_gas策略回退到weighted策略；
+// 启用的聚合器配置了未注册的适配器类型时返回错误(开发模式MOCK_ADAPTERS_ENABLED下使用模拟适配器)
+func NewRouterService(config *types.Config, cacheManager cache.CacheManager, marketData MarketDataSource, logger *logrus.Logger) (*RouterService, error) {
 	service := &RouterService{
-		adapters: make(map[string]ProviderAdapter),
-		cache:    cacheManager,
-		config:   config,
-		logger:   logger,
-		metrics:  &RouterMetrics{},
+		adapters:   make(map[string]*circuitBreakerAdapter),
+		cache:      cacheManager,
+		marketData: marketData,
+		rankers:    newRankers(&config.Strategy),
+		config:     config,
+		logger:     logger,
+		metrics:    &RouterMetrics{},
 	}
 
 	// 初始化聚合器适配器
-	service.initializeAdapters()
+	if err := service.initializeAdapters(); err != nil {
+		return nil, err
+	}
 
-	return service
+	return service, nil
 }
 
 // ========================================
@@ -79,16 +92,40 @@ func NewRouterService(config *types.Config, cacheManager cache.CacheManager, log
 //   - *types.QuoteResponse: 聚合后的最优报价
 //   - error: 聚合过程中的错误
 func (s *RouterService) GetOptimalQuote(ctx context.Context, req *types.QuoteRequest) (*types.QuoteResponse, error) {
+	return s.aggregateQuote(ctx, req, nil)
+}
+
+// StreamOptimalQuote 流式获取最优报价
+// 与GetOptimalQuote使用相同的聚合流程，每个聚合器报价到达时立即回调onQuote，
+// 回调在调用方goroutine中执行；缓存命中时按缓存中的报价依次回调
+func (s *RouterService) StreamOptimalQuote(ctx context.Context, req *types.QuoteRequest, onQuote func(*types.ProviderQuote)) (*types.QuoteResponse, error) {
+	return s.aggregateQuote(ctx, req, onQuote)
+}
+
+// aggregateQuote 报价聚合主流程
+// onQuote为空时不推送单个聚合器报价
+func (s *RouterService) aggregateQuote(ctx context.Context, req *types.QuoteRequest, onQuote func(*types.ProviderQuote)) (*types.QuoteResponse, error) {
 	startTime := time.Now()
 	sessionID := req.RequestID
+	s.normalizeNativeTokens(req)
 
-	s.logger.Infof("[%s] 🚀 聚合请求: %s->%s, 金额=%s, 链=%d",
-		sessionID, req.FromToken, req.ToToken, req.AmountIn.String(), req.ChainID)
+	if req.IsExactOutput() {
+		s.logger.Infof("[%s] 🚀 聚合买入请求: %s->%s, 期望输出=%s, 链=%d",
+			sessionID, req.FromToken, req.ToToken, req.AmountOut.String(), req.ChainID)
+	} else {
+		s.logger.Infof("[%s] 🚀 聚合请求: %s->%s, 金额=%s, 链=%d",
+			sessionID, req.FromToken, req.ToToken, req.AmountIn.String(), req.ChainID)
+	}
 
 	// 1. 检查缓存
 	if cachedQuote := s.checkCache(req); cachedQuote != nil {
 		s.updateMetrics(true, time.Since(startTime), true)
 		s.logger.Infof("[%s] 缓存命中，直接返回结果", sessionID)
+		if onQuote != nil {
+			for _, quote := range cachedQuote.AllQuotes {
+				onQuote(quote)
+			}
+		}
 		return cachedQuote, nil
 	}
 
@@ -101,27 +138,81 @@ func (s *RouterService) GetOptimalQuote(ctx context.Context, req *types.QuoteReq
 		}
 	}
 
+	// 买入报价只使用支持exact-output的聚合器
+	side := s.quoteSide(req)
+	sideAdapters := activeAdapters[:0]
+	for _, adapter := range activeAdapters {
+		if adapter.SupportsQuoteSide(side) {
+			sideAdapters = append(sideAdapters, adapter)
+		}
+	}
+	activeAdapters = sideAdapters
+	if len(activeAdapters) == 0 {
+		return nil, &types.RouterError{
+			Code:    types.ErrCodeUnsupportedQuoteSide,
+			Message: fmt.Sprintf("没有聚合器支持%s报价", side),
+		}
+	}
+
+	// 不支持该代币组合(如原生代币)的聚合器不参与聚合，排除原因以失败报价返回
+	activeAdapters, excludedQuotes := s.filterTokenSupport(req, activeAdapters)
+	if len(activeAdapters) == 0 {
+		return nil, &types.RouterError{
+			Code:    types.ErrCodeNativeTokenUnsupported,
+			Message: fmt.Sprintf("没有聚合器支持该代币报价: %s", excludedQuotes[0].ErrorMessage),
+		}
+	}
+	if onQuote != nil {
+		for _, quote := range excludedQuotes {
+			onQuote(quote)
+		}
+	}
+
 	s.logger.Infof("[%s] 🔍 找到 %d 个支持的聚合器", sessionID, len(activeAdapters))
 
-	// 3. 执行并发聚合
-	quotes := s.executeParallelAggregation(ctx, req, activeAdapters)
+	// 3. 执行并发聚合（渐进式策略），同时获取Gas费用折算参数、参考价格和代币元数据
+	pricingChan := s.resolveGasPricingAsync(ctx, req)
+	referenceChan := s.resolveReferencePriceAsync(ctx, req, activeAdapters)
+	metadataChan := s.resolveTokenMetadataAsync(ctx, req)
+	quotes, decision := s.executeParallelAggregation(ctx, req, activeAdapters, onQuote)
+	quotes = append(quotes, excludedQuotes...)
+	pricing := <-pricingChan
+	reference := <-referenceChan
+	metadata := <-metadataChan
+
+	// 4. 计算价格冲击，超过上限的报价按配置标记或拒绝
+	rejected := 0
+	if reference != nil {
+		rejected = s.applyPriceImpact(quotes, reference, req)
+	}
 
-	// 4. 选择最优报价
-	bestQuote, allQuotes := s.selectBestQuote(quotes, req)
+	// 5. 选择最优报价
+	bestQuote, allQuotes, rankingStrategy := s.selectBestQuote(quotes, req, pricing)
 	if bestQuote == nil {
+		if rejected > 0 {
+			return nil, &types.RouterError{
+				Code:    types.ErrCodePriceImpactTooHigh,
+				Message: fmt.Sprintf("%d 个聚合器报价的价格冲击超过上限%s%%", rejected, s.config.PriceImpact.MaxImpact.Shift(2).String()),
+			}
+		}
 		return nil, &types.RouterError{
 			Code:    types.ErrCodeNoValidQuotes,
 			Message: "所有聚合器都返回失败",
 		}
 	}
 
-	// 5. 构建聚合响应
-	response := s.buildAggregationResponse(req, bestQuote, allQuotes, startTime)
+	// 6. 构建聚合响应
+	response := s.buildAggregationResponse(req, bestQuote, allQuotes, rankingStrategy, decision, startTime)
+	response.ReferencePrice = reference
+	if s.splitRoutingRequested(req) {
+		response.SplitPlan = s.buildSplitPlan(ctx, req, activeAdapters, quotes, pricing)
+	}
+	s.applyTokenMetadata(response, metadata)
 
-	// 6. 缓存结果
+	// 7. 缓存结果
 	s.cacheResult(req, response)
 
-	// 7. 更新指标
+	// 8. 更新指标
 	s.updateMetrics(true, time.Since(startTime), false)
 
 	s.logger.Infof("[%s] 🎉 智能路由聚合完成: 最优聚合器=%s, amountOut=%s, gasEstimate=%d, priceImpact=%s, 总耗时=%v",
@@ -131,16 +222,72 @@ func (s *RouterService) GetOptimalQuote(ctx context.Context, req *types.QuoteReq
 	return response, nil
 }
 
+// BuildSwap 构建可执行交易
+// 指定聚合器时直接由该聚合器构建；未指定时先聚合报价，使用最优聚合器构建
+func (s *RouterService) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	startTime := time.Now()
+	sessionID := req.RequestID
+	s.normalizeNativeTokens(&req.QuoteRequest)
+
+	provider := req.Provider
+	if provider == "" {
+		quote, err := s.GetOptimalQuote(ctx, &req.QuoteRequest)
+		if err != nil {
+			return nil, err
+		}
+		provider = quote.BestProvider
+	}
+
+	s.adaptersMutex.RLock()
+	adapter, ok := s.adapters[provider]
+	s.adaptersMutex.RUnlock()
+	if !ok {
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeProviderNotFound,
+			Message:  fmt.Sprintf("聚合器不存在或未启用: %s", provider),
+			Provider: provider,
+		}
+	}
+
+	s.logger.Infof("[%s] 🔨 构建交易: provider=%s, %s->%s, 金额=%s, 用户=%s",
+		sessionID, provider, req.FromToken, req.ToToken, req.AmountIn.String(), req.UserAddress)
+
+	tx, err := adapter.BuildSwap(ctx, req)
+	if err != nil {
+		var routerErr *types.RouterError
+		if errors.As(err, &routerErr) {
+			return nil, routerErr
+		}
+		return nil, &types.RouterError{
+			Code:     types.ErrCodeSwapBuildFailed,
+			Message:  fmt.Sprintf("聚合器 %s 构建交易失败: %v", provider, err),
+			Provider: provider,
+		}
+	}
+
+	s.logger.Infof("[%s] ✅ 交易构建完成: provider=%s, kind=%s, minAmountOut=%s, 耗时=%v",
+		sessionID, provider, tx.Kind, tx.MinAmountOut.String(), time.Since(startTime))
+
+	return tx, nil
+}
+
 // ========================================
 // 并发聚合实现
 // ========================================
 
 // executeParallelAggregation 执行并发聚合
-// 同时调用多个聚合器API，收集所有报价结果
-func (s *RouterService) executeParallelAggregation(ctx context.Context, req *types.QuoteRequest, adapters []ProviderAdapter) []*types.ProviderQuote {
+// 同时调用多个聚合器API，按渐进式策略收集报价：
+// 满足策略条件后立即返回并取消仍在等待的聚合器
+// onQuote不为空时，每个报价（包括被取消的聚合器）都会实时回调
+func (s *RouterService) executeParallelAggregation(ctx context.Context, req *types.QuoteRequest, adapters []ProviderAdapter, onQuote func(*types.ProviderQuote)) ([]*types.ProviderQuote, *aggregationDecision) {
+	startTime := time.Now()
 	quoteChan := make(chan *types.ProviderQuote, len(adapters))
 	var wg sync.WaitGroup
 
+	// 聚合上下文：做出决策后取消所有未完成的聚合器请求
+	aggCtx, cancelAggregation := context.WithCancel(ctx)
+	defer cancelAggregation()
+
 	s.logger.Infof("[%s] 🚀 并发调用 %d 个聚合器", req.RequestID, len(adapters))
 
 	// 为每个聚合器启动独立的goroutine
@@ -153,7 +300,7 @@ func (s *RouterService) executeParallelAggregation(ctx context.Context, req *typ
 			s.logger.Infof("[%s] 📞 调用: %s", req.RequestID, adp.GetName())
 
 			// 创建带超时的上下文
-			adapterCtx, cancel := context.WithTimeout(ctx, adp.GetConfig().Timeout)
+			adapterCtx, cancel := context.WithTimeout(aggCtx, adp.GetConfig().Timeout)
 			defer cancel()
 
 			// 调用聚合器获取报价
@@ -163,11 +310,16 @@ func (s *RouterService) executeParallelAggregation(ctx context.Context, req *typ
 					req.RequestID, adp.GetName(), err, time.Since(adapterStartTime))
 
 				// 即使出错也要发送结果到channel
+				errorCode := types.ErrCodeProviderError
+				var routerErr *types.RouterError
+				if errors.As(err, &routerErr) {
+					errorCode = routerErr.Code
+				}
 				quote = &types.ProviderQuote{
 					Provider:     adp.GetName(),
 					Success:      false,
 					ResponseTime: time.Since(adapterStartTime),
-					ErrorCode:    types.ErrCodeProviderError,
+					ErrorCode:    errorCode,
 					ErrorMessage: err.Error(),
 				}
 			} else {
@@ -175,12 +327,8 @@ func (s *RouterService) executeParallelAggregation(ctx context.Context, req *typ
 					req.RequestID, adp.GetName(), quote.Success, time.Since(adapterStartTime))
 			}
 
-			// 发送结果到channel
-			select {
-			case quoteChan <- quote:
-			case <-adapterCtx.Done():
-				s.logger.Warnf("[%s] ⏰ 聚合器 %s 上下文已取消", req.RequestID, adp.GetName())
-			}
+			// 发送结果到channel（缓冲区足够，提前返回后也不会阻塞）
+			quoteChan <- quote
 		}(i, adapter)
 	}
 
@@ -190,21 +338,75 @@ func (s *RouterService) executeParallelAggregation(ctx context.Context, req *typ
 		close(quoteChan)
 	}()
 
-	// 收集所有报价结果
-	var quotes []*types.ProviderQuote
-	for quote := range quoteChan {
-		quotes = append(quotes, quote)
+	// 按渐进式策略收集报价结果
+	collector := newProgressiveCollector(&s.config.Strategy, len(adapters))
+	ticker := time.NewTicker(progressiveCheckInterval)
+	defer ticker.Stop()
+
+	var rule string
+collect:
+	for {
+		select {
+		case quote, ok := <-quoteChan:
+			if !ok {
+				rule = types.DecisionAllResponded
+				break collect
+			}
+			collector.add(quote)
+			if onQuote != nil {
+				onQuote(quote)
+			}
+
+			if quote.Success {
+				s.logger.Infof("[%s] ✅ %s: 报价=%s, Gas=%d, 耗时=%v",
+					req.RequestID, quote.Provider, quote.AmountOut.String(), quote.GasEstimate, quote.ResponseTime)
+			} else {
+				s.logger.Warnf("[%s] ❌ %s: %s",
+					req.RequestID, quote.Provider, quote.ErrorMessage)
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			rule = types.DecisionContextCancelled
+			break collect
+		}
 
-		if quote.Success {
-			s.logger.Infof("[%s] ✅ %s: 报价=%s, Gas=%d, 耗时=%v",
-				req.RequestID, quote.Provider, quote.AmountOut.String(), quote.GasEstimate, quote.ResponseTime)
-		} else {
-			s.logger.Warnf("[%s] ❌ %s: %s",
-				req.RequestID, quote.Provider, quote.ErrorMessage)
+		if decided, ok := collector.decide(time.Since(startTime)); ok {
+			rule = decided
+			break collect
+		}
+	}
+
+	elapsed := time.Since(startTime)
+	decision := &aggregationDecision{
+		Rule:           rule,
+		DecidedAt:      elapsed,
+		CompositeScore: collector.compositeScore(elapsed),
+	}
+
+	// 取消仍未响应的聚合器，并记录为被取消的报价
+	quotes := collector.quotes
+	for _, adapter := range adapters {
+		if collector.responded[adapter.GetName()] {
+			continue
+		}
+		decision.Cancelled++
+		cancelledQuote := &types.ProviderQuote{
+			Provider:     adapter.GetName(),
+			Success:      false,
+			ResponseTime: elapsed,
+			ErrorCode:    types.ErrCodeProviderCancelled,
+			ErrorMessage: fmt.Sprintf("渐进式策略已提前返回(%s)，请求被取消", rule),
+		}
+		quotes = append(quotes, cancelledQuote)
+		if onQuote != nil {
+			onQuote(cancelledQuote)
 		}
 	}
 
-	return quotes
+	s.logger.Infof("[%s] 🧭 聚合决策: rule=%s, 耗时=%v, 已响应=%d/%d, 综合评分=%.4f",
+		req.RequestID, rule, elapsed, len(collector.quotes), len(adapters), decision.CompositeScore.InexactFloat64())
+
+	return quotes, decision
 }
 
 // ========================================
@@ -212,148 +414,93 @@ func (s *RouterService) executeParallelAggregation(ctx context.Context, req *typ
 // ========================================
 
 // selectBestQuote 选择最优报价
-// 基于价格、Gas费用、置信度等因素选择最佳报价
-func (s *RouterService) selectBestQuote(quotes []*types.ProviderQuote, req *types.QuoteRequest) (*types.ProviderQuote, []*types.ProviderQuote) {
+// 按请求指定(或服务默认)的排序策略为有效报价评分并排名，策略所需数据不可用时回退到weighted策略
+// 返回最优报价、所有报价和实际使用的排序策略
+func (s *RouterService) selectBestQuote(quotes []*types.ProviderQuote, req *types.QuoteRequest, pricing *gasPricing) (*types.ProviderQuote, []*types.ProviderQuote, string) {
 	if len(quotes) == 0 {
-		return nil, quotes
+		return nil, quotes, ""
 	}
 
-	// 筛选成功的报价
+	// 筛选成功的报价，买入报价还要求返回了所需输入数量
+	side := s.quoteSide(req)
 	var validQuotes []*types.ProviderQuote
 	for _, quote := range quotes {
-		if quote.Success && !quote.AmountOut.IsZero() {
-			validQuotes = append(validQuotes, quote)
+		if !quote.Success || quote.AmountOut.IsZero() {
+			continue
 		}
+		if side == types.QuoteSideBuy && !quote.AmountIn.IsPositive() {
+			continue
+		}
+		validQuotes = append(validQuotes, quote)
 	}
 
 	if len(validQuotes) == 0 {
-		return nil, quotes
+		return nil, quotes, ""
 	}
 
-	// 计算每个报价的综合评分
-	var bestQuote *types.ProviderQuote
-	var bestScore decimal.Decimal
-
-	for i, quote := range validQuotes {
-		score := s.calculateQuoteScore(quote, validQuotes)
-
-		if i == 0 || score.GreaterThan(bestScore) {
-			bestQuote = quote
-			bestScore = score
-		}
-
-		// 设置排名
-		quote.Rank = i + 1
-
-		s.logger.Infof("[%s] 📊 聚合器 %s 评分: %.4f, amountOut=%s, gas=%d",
-			req.RequestID, quote.Provider, score.InexactFloat64(), quote.AmountOut.String(), quote.GasEstimate)
+	if pricing != nil {
+		pricing.applyGasCosts(validQuotes, side)
 	}
 
-	s.logger.Infof("最优聚合器: %s, 评分: %.4f", bestQuote.Provider, bestScore.InexactFloat64())
-	return bestQuote, quotes
-}
-
-// calculateQuoteScore 计算报价综合评分
-// 基于多个维度计算报价的综合评分
-func (s *RouterService) calculateQuoteScore(quote *types.ProviderQuote, allQuotes []*types.ProviderQuote) decimal.Decimal {
-	// 价格评分 (权重50%)：输出数量越多评分越高
-	priceScore := s.calculatePriceScore(quote, allQuotes)
-
-	// Gas效率评分 (权重20%)：Gas费用越低评分越高
-	gasScore := s.calculateGasScore(quote, allQuotes)
-
-	// 置信度评分 (权重20%)：直接使用置信度
-	confidenceScore := quote.Confidence
-
-	// 响应时间评分 (权重10%)：响应越快评分越高
-	timeScore := s.calculateTimeScore(quote)
-
-	// 计算加权综合评分
-	totalScore := priceScore.Mul(decimal.NewFromFloat(0.5)).
-		Add(gasScore.Mul(decimal.NewFromFloat(0.2))).
-		Add(confidenceScore.Mul(decimal.NewFromFloat(0.2))).
-		Add(timeScore.Mul(decimal.NewFromFloat(0.1)))
-
-	return totalScore
-}
-
-// calculatePriceScore 计算价格评分
-func (s *RouterService) calculatePriceScore(quote *types.ProviderQuote, allQuotes []*types.ProviderQuote) decimal.Decimal {
-	if len(allQuotes) == 1 {
-		return decimal.NewFromFloat(1.0)
+	ranker := s.rankers[s.rankingStrategy(req)]
+	if err := ranker.Score(validQuotes, side); err != nil {
+		s.logger.Warnf("[%s] ⚠️ 排序策略 %s 不可用(%v)，回退到 %s",
+			req.RequestID, ranker.Name(), err, types.RankingStrategyWeighted)
+		ranker = s.rankers[types.RankingStrategyWeighted]
+		if err := ranker.Score(validQuotes, side); err != nil {
+			s.logger.Errorf("[%s] 💥 排序策略 %s 评分失败: %v", req.RequestID, ranker.Name(), err)
+			return nil, quotes, ""
+		}
 	}
 
-	// 找到最高和最低输出金额
-	var maxAmount, minAmount decimal.Decimal
-	for i, q := range allQuotes {
-		if !q.Success {
-			continue
+	// 评分越高越靠前；评分相同时输出数量多(买入时输入数量少)、响应快的报价优先
+	ranked := append([]*types.ProviderQuote(nil), validQuotes...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if !a.Score.Total.Equal(b.Score.Total) {
+			return a.Score.Total.GreaterThan(b.Score.Total)
 		}
-		if i == 0 || q.AmountOut.GreaterThan(maxAmount) {
-			maxAmount = q.AmountOut
+		if side == types.QuoteSideBuy && !a.AmountIn.Equal(b.AmountIn) {
+			return a.AmountIn.LessThan(b.AmountIn)
 		}
-		if i == 0 || q.AmountOut.LessThan(minAmount) {
-			minAmount = q.AmountOut
+		if !a.AmountOut.Equal(b.AmountOut) {
+			return a.AmountOut.GreaterThan(b.AmountOut)
 		}
-	}
+		return a.ResponseTime < b.ResponseTime
+	})
 
-	// 计算相对评分
-	if maxAmount.Equal(minAmount) {
-		return decimal.NewFromFloat(1.0)
+	for i, quote := range ranked {
+		quote.Rank = i + 1
+		s.logger.Infof("[%s] 📊 聚合器 %s 排名: %d, 评分=%s, amountOut=%s, gas=%d",
+			req.RequestID, quote.Provider, quote.Rank, quote.Score.Total.String(), quote.AmountOut.String(), quote.GasEstimate)
 	}
 
-	score := quote.AmountOut.Sub(minAmount).Div(maxAmount.Sub(minAmount))
-	return score
+	bestQuote := ranked[0]
+	s.logger.Infof("[%s] 最优聚合器: %s, 排序策略=%s, 评分=%s",
+		req.RequestID, bestQuote.Provider, ranker.Name(), bestQuote.Score.Total.String())
+	return bestQuote, quotes, ranker.Name()
 }
 
-// calculateGasScore 计算Gas效率评分
-func (s *RouterService) calculateGasScore(quote *types.ProviderQuote, allQuotes []*types.ProviderQuote) decimal.Decimal {
-	if len(allQuotes) == 1 {
-		return decimal.NewFromFloat(1.0)
-	}
-
-	// 找到最高和最低Gas估算
-	var maxGas, minGas uint64
-	for i, q := range allQuotes {
-		if !q.Success || q.GasEstimate == 0 {
-			continue
-		}
-		if i == 0 || q.GasEstimate > maxGas {
-			maxGas = q.GasEstimate
-		}
-		if i == 0 || q.GasEstimate < minGas {
-			minGas = q.GasEstimate
-		}
+// rankingStrategy 请求使用的排序策略，未指定时使用服务默认策略
+func (s *RouterService) rankingStrategy(req *types.QuoteRequest) string {
+	if req.RankingStrategy != "" {
+		return req.RankingStrategy
 	}
+	return s.config.Ranking.DefaultStrategy
+}
 
-	if maxGas == minGas || quote.GasEstimate == 0 {
-		return decimal.NewFromFloat(0.5) // 中等评分
+// quoteSide 请求的报价方向，未指定时为卖出
+func (s *RouterService) quoteSide(req *types.QuoteRequest) string {
+	if req.IsExactOutput() {
+		return types.QuoteSideBuy
 	}
-
-	// Gas越低评分越高
-	gasRange := decimal.NewFromInt(int64(maxGas - minGas))
-	gasOffset := decimal.NewFromInt(int64(maxGas - quote.GasEstimate))
-	score := gasOffset.Div(gasRange)
-
-	return score
+	return types.QuoteSideSell
 }
 
-// calculateTimeScore 计算响应时间评分
-func (s *RouterService) calculateTimeScore(quote *types.ProviderQuote) decimal.Decimal {
-	// 基于响应时间计算评分，越快评分越高
-	responseMs := float64(quote.ResponseTime.Milliseconds())
-
-	if responseMs <= 200 {
-		return decimal.NewFromFloat(1.0) // 极快
-	} else if responseMs <= 500 {
-		return decimal.NewFromFloat(0.8) // 快
-	} else if responseMs <= 1000 {
-		return decimal.NewFromFloat(0.6) // 中等
-	} else if responseMs <= 2000 {
-		return decimal.NewFromFloat(0.4) // 慢
-	} else {
-		return decimal.NewFromFloat(0.2) // 很慢
-	}
+// SupportsRankingStrategy 是否支持指定的排序策略
+func (s *RouterService) SupportsRankingStrategy(name string) bool {
+	_, ok := s.rankers[name]
+	return ok
 }
 
 // ========================================
@@ -365,26 +512,22 @@ func (s *RouterService) calculateTimeScore(quote *types.ProviderQuote) decimal.D
 func (s *RouterService) checkCache(req *types.QuoteRequest) *types.QuoteResponse {
 	cacheKey := s.generateCacheKey(req)
 
-	cachedData, err := s.cache.Get(cacheKey)
-	if err != nil {
-		s.logger.Debugf("缓存查询失败: %v", err)
+	var cachedQuote types.QuoteResponse
+	if err := s.cache.Get(cacheKey, &cachedQuote); err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			s.logger.Debugf("缓存查询失败: %v", err)
+		}
 		return nil
 	}
 
-	if cachedData == nil {
+	// 检查缓存是否过期
+	if !time.Now().Before(cachedQuote.ValidUntil) {
 		return nil
 	}
 
-	// 尝试转换为QuoteResponse
-	if cachedQuote, ok := cachedData.(*types.QuoteResponse); ok {
-		// 检查缓存是否过期
-		if time.Now().Before(cachedQuote.ValidUntil) {
-			cachedQuote.CacheHit = true
-			return cachedQuote
-		}
-	}
-
-	return nil
+	cachedQuote.RequestID = req.RequestID
+	cachedQuote.CacheHit = true
+	return &cachedQuote
 }
 
 // cacheResult 缓存聚合结果
@@ -402,15 +545,36 @@ func (s *RouterService) cacheResult(req *types.QuoteRequest, response *types.Quo
 }
 
 // generateCacheKey 生成缓存键
+// 服务级前缀(Cache.PrefixKey)由缓存管理器统一添加
+// 报价方向、排序策略和请求指定的Gas价格会影响最优报价，请求指定的小数位数会影响可读数量，拆单方案只在请求时计算，因此计入缓存键
 func (s *RouterService) generateCacheKey(req *types.QuoteRequest) string {
-	return fmt.Sprintf("%s%s_%s_%s_%d_%s",
-		s.config.Cache.PrefixKey,
+	amount := req.AmountIn.String()
+	if req.IsExactOutput() {
+		amount = "buy" + req.AmountOut.String()
+	}
+
+	key := fmt.Sprintf("%s%s_%s_%s_%d_%s_%s",
+		types.CacheKeyQuote,
 		req.FromToken,
 		req.ToToken,
-		req.AmountIn.String(),
+		amount,
 		req.ChainID,
 		req.Slippage.String(),
+		s.rankingStrategy(req),
 	)
+	if req.GasPrice != nil && req.GasPrice.IsPositive() {
+		key += "_" + req.GasPrice.String()
+	}
+	if s.splitRoutingRequested(req) {
+		key += "_split"
+	}
+	if req.FromTokenDecimals != nil {
+		key += fmt.Sprintf("_fd%d", *req.FromTokenDecimals)
+	}
+	if req.ToTokenDecimals != nil {
+		key += fmt.Sprintf("_td%d", *req.ToTokenDecimals)
+	}
+	return key
 }
 
 // ========================================
@@ -419,12 +583,13 @@ func (s *RouterService) generateCacheKey(req *types.QuoteRequest) string {
 
 // initializeAdapters 初始化聚合器适配器
 // 优雅的适配器初始化：确保配置正确传递，避免映射错误
-func (s *RouterService) initializeAdapters() {
+// 适配器类型未注册属于部署错误，直接返回错误终止启动；其他创建失败的适配器记录日志后跳过
+func (s *RouterService) initializeAdapters() error {
 	s.logger.Infof("🚀 开始初始化聚合器适配器系统...")
 	s.logger.Infof("📊 总配置数量: %d", len(s.config.Providers))
 
 	// 清空现有适配器
-	s.adapters = make(map[string]ProviderAdapter)
+	s.adapters = make(map[string]*circuitBreakerAdapter)
 
 	activeCount := 0
 
@@ -439,18 +604,7 @@ func (s *RouterService) initializeAdapters() {
 		}
 
 		// 创建独立的配置副本，避免引用污染
-		config := types.ProviderConfig{
-			Name:            providerConfig.Name,
-			DisplayName:     providerConfig.DisplayName,
-			BaseURL:         providerConfig.BaseURL,
-			APIKey:          providerConfig.APIKey,
-			Timeout:         providerConfig.Timeout,
-			RetryCount:      providerConfig.RetryCount,
-			Priority:        providerConfig.Priority,
-			Weight:          providerConfig.Weight,
-			IsActive:        providerConfig.IsActive,
-			SupportedChains: append([]uint{}, providerConfig.SupportedChains...), // 深拷贝
-		}
+		config := copyProviderConfig(providerConfig)
 
 		s.logger.Infof("🔧 聚合器配置详情: name=%s, display=%s, url=%s, apiKey=%s, chains=%v",
 			config.Name, config.DisplayName, config.BaseURL,
@@ -462,16 +616,12 @@ func (s *RouterService) initializeAdapters() {
 			}(),
 			config.SupportedChains)
 
-		// 根据聚合器名称创建对应的适配器
-		adapter, err := s.createAdapter(config)
-		if err != nil {
-			s.logger.Errorf("❌ 创建适配器失败: %s - %v", config.Name, err)
-			continue
+		adapter, err := s.buildAdapter(config)
+		if errors.Is(err, adapters.ErrUnknownAdapter) {
+			return err
 		}
-
-		// 验证适配器配置
-		if err := s.validateAdapter(adapter, config); err != nil {
-			s.logger.Errorf("❌ 适配器验证失败: %s - %v", config.Name, err)
+		if err != nil {
+			s.logger.Errorf("❌ %v", err)
 			continue
 		}
 
@@ -489,27 +639,156 @@ func (s *RouterService) initializeAdapters() {
 	for name, adapter := range s.adapters {
 		s.logger.Infof("📋 最终映射: %s -> %s (%s)", name, adapter.GetName(), adapter.GetDisplayName())
 	}
+	return nil
+}
+
+// buildAdapter 创建、验证适配器并包装熔断器
+func (s *RouterService) buildAdapter(config types.ProviderConfig) (*circuitBreakerAdapter, error) {
+	// 根据适配器类型创建对应的适配器
+	adapter, err := s.createAdapter(config)
+	if err != nil {
+		return nil, fmt.Errorf("创建适配器失败: %s - %w", config.Name, err)
+	}
+
+	// 验证适配器配置
+	if err := s.validateAdapter(adapter, config); err != nil {
+		return nil, fmt.Errorf("适配器验证失败: %s - %w", config.Name, err)
+	}
+
+	return newCircuitBreakerAdapter(adapter, &s.config.CircuitBreaker, s.logger), nil
+}
+
+// ApplyProviderConfigs 应用新的聚合器配置集合
+// 与当前适配器逐个比对：新增的创建适配器，配置变化的重新创建适配器并沿用原熔断器状态，
+// 未启用或已不存在的移除；全部处理完成后在锁内整体替换适配器集合。
+// 适配器创建后不再修改，处理中的请求继续使用旧适配器实例，不会与热加载产生数据竞争
+func (s *RouterService) ApplyProviderConfigs(providers []types.ProviderConfig) *types.ProviderReloadResult {
+	s.adaptersMutex.Lock()
+	defer s.adaptersMutex.Unlock()
+
+	result := &types.ProviderReloadResult{ReloadedAt: time.Now()}
+	next := make(map[string]*circuitBreakerAdapter, len(providers))
+
+	for _, providerConfig := range providers {
+		if !providerConfig.IsActive {
+			continue
+		}
+		config := copyProviderConfig(providerConfig)
+
+		existing, ok := s.adapters[config.Name]
+		if !ok {
+			adapter, err := s.buildAdapter(config)
+			if err != nil {
+				s.logger.Errorf("❌ 热加载新增聚合器失败: %v", err)
+				result.Failed = append(result.Failed, config.Name)
+				continue
+			}
+			next[config.Name] = adapter
+			result.Added = append(result.Added, config.Name)
+			continue
+		}
+
+		if providerConfigEqual(existing.GetConfig(), &config) {
+			next[config.Name] = existing
+			result.Unchanged = append(result.Unchanged, config.Name)
+			continue
+		}
+
+		// 配置变化(包括适配器类型变化)时创建新适配器替换，失败时保留旧适配器继续服务
+		adapter, err := s.buildAdapter(config)
+		if err != nil {
+			s.logger.Errorf("❌ 热加载更新聚合器配置失败: %v", err)
+			next[config.Name] = existing
+			result.Failed = append(result.Failed, config.Name)
+			continue
+		}
+		adapter.breaker = existing.breaker
+		next[config.Name] = adapter
+		result.Updated = append(result.Updated, config.Name)
+	}
+
+	for name := range s.adapters {
+		if _, ok := next[name]; !ok {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Updated)
+	sort.Strings(result.Unchanged)
+	sort.Strings(result.Failed)
+
+	s.adapters = next
+	s.config.Providers = append([]types.ProviderConfig(nil), providers...)
+
+	return result
+}
+
+// copyProviderConfig 创建独立的聚合器配置副本
+func copyProviderConfig(providerConfig types.ProviderConfig) types.ProviderConfig {
+	return types.ProviderConfig{
+		Name:            providerConfig.Name,
+		DisplayName:     providerConfig.DisplayName,
+		BaseURL:         providerConfig.BaseURL,
+		APIKey:          providerConfig.APIKey,
+		Timeout:         providerConfig.Timeout,
+		RetryCount:      providerConfig.RetryCount,
+		Priority:        providerConfig.Priority,
+		Weight:          providerConfig.Weight,
+		IsActive:        providerConfig.IsActive,
+		SupportedChains: append([]uint{}, providerConfig.SupportedChains...), // 深拷贝
+		AuthScheme:      providerConfig.AuthScheme,
+		AuthHeader:      providerConfig.AuthHeader,
+		Retry:           providerConfig.Retry,
+		DEX:             providerConfig.DEX.Clone(),
+		Adapter:         providerConfig.Adapter,
+		HTTPJSON:        providerConfig.HTTPJSON.Clone(),
+	}
+}
+
+// providerConfigEqual 比较影响适配器行为的配置字段
+func providerConfigEqual(a, b *types.ProviderConfig) bool {
+	if a.DisplayName != b.DisplayName || a.BaseURL != b.BaseURL || a.APIKey != b.APIKey ||
+		a.Timeout != b.Timeout || a.RetryCount != b.RetryCount || a.Priority != b.Priority ||
+		!a.Weight.Equal(b.Weight) || a.IsActive != b.IsActive ||
+		a.AuthScheme != b.AuthScheme || a.AuthHeader != b.AuthHeader || a.Retry != b.Retry ||
+		a.Adapter != b.Adapter || !reflect.DeepEqual(a.DEX, b.DEX) || !reflect.DeepEqual(a.HTTPJSON, b.HTTPJSON) {
+		return false
+	}
+
+	if len(a.SupportedChains) != len(b.SupportedChains) {
+		return false
+	}
+	chains := make(map[uint]bool, len(a.SupportedChains))
+	for _, chainID := range a.SupportedChains {
+		chains[chainID] = true
+	}
+	for _, chainID := range b.SupportedChains {
+		if !chains[chainID] {
+			return false
+		}
+	}
+	return true
 }
 
 // createAdapter 创建聚合器适配器
+// 按配置的适配器类型从适配器注册表创建；类型未注册时仅在开发模式(MOCK_ADAPTERS_ENABLED)下使用模拟适配器
 func (s *RouterService) createAdapter(config types.ProviderConfig) (ProviderAdapter, error) {
-	switch config.Name {
-	case "cowswap":
-		return adapters.NewCowAdapter(&config, s.logger), nil
-	case "1inch":
-		return adapters.NewOneInchAdapter(&config, s.logger), nil
-	case "paraswap":
-		return adapters.NewParaSwapAdapter(&config, s.logger), nil
-	case "0x":
-		return adapters.NewZRXAdapter(&config, s.logger), nil
-	default:
-		// 创建模拟适配器
-		return &MockAdapter{
-			name:   config.Name,
-			config: &config,
-			logger: s.logger,
-		}, nil
+	adapter, err := adapters.New(&config, s.logger)
+	if err == nil {
+		return adapter, nil
+	}
+	if !errors.Is(err, adapters.ErrUnknownAdapter) || !s.config.Server.MockAdapters {
+		return nil, err
 	}
+
+	s.logger.Warnf("⚠️ 开发模式: 聚合器 %s 的适配器类型 %s 未注册，使用模拟适配器", config.Name, config.AdapterType())
+	return &MockAdapter{
+		name:   config.Name,
+		config: &config,
+		logger: s.logger,
+	}, nil
 }
 
 // validateAdapter 验证适配器配置
@@ -528,7 +807,7 @@ func (s *RouterService) validateAdapter(adapter ProviderAdapter, expectedConfig
 	return nil
 }
 
-// MockAdapter 模拟适配器（临时实现）
+// MockAdapter 模拟适配器(仅开发模式下代替未注册的适配器类型)
 type MockAdapter struct {
 	name   string
 	config *types.ProviderConfig
@@ -545,12 +824,19 @@ func (m *MockAdapter) IsSupported(chainID uint) bool {
 	}
 	return false
 }
+func (m *MockAdapter) SupportsQuoteSide(side string) bool        { return true }
+func (m *MockAdapter) CheckTokens(req *types.QuoteRequest) error { return nil }
 func (m *MockAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*types.ProviderQuote, error) {
-	// 模拟报价响应
+	// 模拟报价响应：按1:0.99兑换
+	amountIn, amountOut := req.AmountIn, req.AmountIn.Mul(decimal.NewFromFloat(0.99))
+	if req.IsExactOutput() {
+		amountIn, amountOut = req.AmountOut.Div(decimal.NewFromFloat(0.99)).Ceil(), req.AmountOut
+	}
 	return &types.ProviderQuote{
 		Provider:     m.name,
 		Success:      true,
-		AmountOut:    req.AmountIn.Mul(decimal.NewFromFloat(0.99)), // 模拟1%的价格冲击
+		AmountIn:     amountIn,
+		AmountOut:    amountOut, // 模拟1%的价格冲击
 		GasEstimate:  180000,
 		PriceImpact:  decimal.NewFromFloat(0.01),
 		Route:        []types.RouteStep{},
@@ -558,61 +844,123 @@ func (m *MockAdapter) GetQuote(ctx context.Context, req *types.QuoteRequest) (*t
 		Confidence:   decimal.NewFromFloat(0.8),
 	}, nil
 }
-func (m *MockAdapter) HealthCheck(ctx context.Context) error { return nil }
-func (m *MockAdapter) UpdateConfig(config *types.ProviderConfig) error {
-	m.config = config
-	return nil
+func (m *MockAdapter) BuildSwap(ctx context.Context, req *types.SwapRequest) (*types.SwapTransaction, error) {
+	// 模拟聚合器没有真实合约，不能返回可签名的交易
+	return nil, &types.RouterError{
+		Code:     types.ErrCodeSwapNotSupported,
+		Message:  fmt.Sprintf("模拟聚合器 %s 不支持构建交易", m.name),
+		Provider: m.name,
+	}
 }
-func (m *MockAdapter) GetConfig() *types.ProviderConfig { return m.config }
+func (m *MockAdapter) HealthCheck(ctx context.Context) error { return nil }
+func (m *MockAdapter) GetConfig() *types.ProviderConfig      { return m.config }
 
 // getActiveAdapters 获取支持指定链的活跃适配器
+// 熔断中的适配器被跳过，冷却期结束的适配器在后台探测后重新放行
 func (s *RouterService) getActiveAdapters(chainID uint) []ProviderAdapter {
+	s.adaptersMutex.RLock()
+	defer s.adaptersMutex.RUnlock()
+
 	var activeAdapters []ProviderAdapter
 
 	for _, adapter := range s.adapters {
-		if adapter.IsSupported(chainID) {
-			activeAdapters = append(activeAdapters, adapter)
+		if !adapter.IsSupported(chainID) {
+			continue
 		}
+		if !adapter.admit(s.config.CircuitBreaker.HealthCheckTimeout) {
+			s.logger.Debugf("⏭️ 跳过已熔断的聚合器: %s", adapter.GetName())
+			continue
+		}
+		activeAdapters = append(activeAdapters, adapter)
 	}
 
 	return activeAdapters
 }
 
+// GetProviderStatus 获取所有聚合器的运行状态（按名称排序）
+func (s *RouterService) GetProviderStatus() []*types.ProviderStatus {
+	s.adaptersMutex.RLock()
+	defer s.adaptersMutex.RUnlock()
+
+	statuses := make([]*types.ProviderStatus, 0, len(s.adapters))
+	for _, adapter := range s.adapters {
+		statuses = append(statuses, adapter.status())
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// GetProviderHealth 获取聚合器健康状态
+// 熔断闭合为healthy，半开为degraded，熔断中为unhealthy
+func (s *RouterService) GetProviderHealth() map[string]types.ProviderHealth {
+	statuses := s.GetProviderStatus()
+	health := make(map[string]types.ProviderHealth, len(statuses))
+	for _, status := range statuses {
+		healthStatus := types.StatusHealthy
+		switch status.CircuitState {
+		case types.CircuitHalfOpen:
+			healthStatus = types.StatusDegraded
+		case types.CircuitOpen:
+			healthStatus = types.StatusUnhealthy
+		}
+
+		health[status.Name] = types.ProviderHealth{
+			Status:       healthStatus,
+			LastChecked:  status.LastCheckedAt,
+			ResponseTime: status.LastResponseTime,
+			SuccessRate:  status.SuccessRate,
+			ErrorMessage: status.LastError,
+			CircuitState: status.CircuitState,
+		}
+	}
+	return health
+}
+
 // buildAggregationResponse 构建聚合响应
 func (s *RouterService) buildAggregationResponse(
 	req *types.QuoteRequest,
 	bestQuote *types.ProviderQuote,
 	allQuotes []*types.ProviderQuote,
+	rankingStrategy string,
+	decision *aggregationDecision,
 	startTime time.Time,
 ) *types.QuoteResponse {
 	// 计算性能指标
-	performance := s.calculatePerformance(allQuotes, startTime)
+	performance := s.calculatePerformance(allQuotes, decision, startTime)
 
-	// 计算汇率
-	exchangeRate := decimal.Zero
-	if !req.AmountIn.IsZero() {
-		exchangeRate = bestQuote.AmountOut.Div(req.AmountIn)
+	// 买入报价的输入数量取最优报价所需输入
+	amountIn := req.AmountIn
+	if req.IsExactOutput() {
+		amountIn = bestQuote.AmountIn
 	}
 
 	return &types.QuoteResponse{
-		RequestID:       req.RequestID,
-		Success:         true,
-		BestProvider:    bestQuote.Provider,
-		BestPrice:       bestQuote.AmountOut,
-		BestGasEstimate: bestQuote.GasEstimate,
-		PriceImpact:     bestQuote.PriceImpact,
-		ExchangeRate:    exchangeRate,
-		Route:           bestQuote.Route,
-		AllQuotes:       allQuotes,
-		Performance:     performance,
-		ValidUntil:      time.Now().Add(s.config.Cache.DefaultTTL),
-		CacheHit:        false,
-		Timestamp:       time.Now(),
+		RequestID:        req.RequestID,
+		Success:          true,
+		Side:             s.quoteSide(req),
+		AmountIn:         amountIn,
+		MaxAmountIn:      bestQuote.MaxAmountIn,
+		BestProvider:     bestQuote.Provider,
+		BestPrice:        bestQuote.AmountOut,
+		BestGasEstimate:  bestQuote.GasEstimate,
+		BestNetAmountOut: bestQuote.NetAmountOut,
+		BestGasCost:      bestQuote.GasCost,
+		RankingStrategy:  rankingStrategy,
+		PriceImpact:      bestQuote.PriceImpact,
+		Route:            bestQuote.Route,
+		AllQuotes:        allQuotes,
+		Performance:      performance,
+		ValidUntil:       time.Now().Add(s.config.Cache.DefaultTTL),
+		CacheHit:         false,
+		Timestamp:        time.Now(),
 	}
 }
 
 // calculatePerformance 计算聚合性能指标
-func (s *RouterService) calculatePerformance(quotes []*types.ProviderQuote, startTime time.Time) types.AggregationPerformance {
+func (s *RouterService) calculatePerformance(quotes []*types.ProviderQuote, decision *aggregationDecision, startTime time.Time) types.AggregationPerformance {
 	totalDuration := time.Since(startTime)
 	successCount := 0
 	var totalResponseTime time.Duration
@@ -655,6 +1003,11 @@ func (s *RouterService) calculatePerformance(quotes []*types.ProviderQuote, star
 		CacheHitRate:     decimal.Zero, // 由缓存管理器计算
 		StrategyUsed:     types.StrategyProgressive,
 		QualityScore:     qualityScore,
+
+		DecisionRule:       decision.Rule,
+		DecisionTime:       decision.DecidedAt,
+		CompositeScore:     decision.CompositeScore,
+		ProvidersCancelled: decision.Cancelled,
 	}
 }
 
@@ -687,6 +1040,11 @@ func (s *RouterService) updateMetrics(success bool, duration time.Duration, cach
 	}
 }
 
+// GetCacheStats 获取缓存统计
+func (s *RouterService) GetCacheStats() (*cache.CacheStats, error) {
+	return s.cache.GetStats()
+}
+
 // GetMetrics 获取服务指标
 func (s *RouterService) GetMetrics() *RouterMetrics {
 	s.metrics.mutex.RLock()
@@ -0,0 +1,282 @@
+// Package services 跨聚合器拆单路由
+// 请求split_routing时，向已成功报价的聚合器按AmountIn的1/Parts、2/Parts...获取分量报价(100%复用聚合报价)，
+// 再以动态规划求解各聚合器分配份数，使各腿输出之和(Gas价格可用时扣除各腿Gas费用)最大；
+// 只有拆给多个聚合器且优于最优单一聚合器时才返回拆单方案
+package services
+
+import (
+	"context"
+	"sync"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// splitCandidate 单个聚合器在各份数下的报价
+// quotes[k]为k份输入的报价，不可用时为nil
+type splitCandidate struct {
+	adapter ProviderAdapter
+	quotes  []*types.ProviderQuote
+	values  []decimal.Decimal // quotes[k]参与求解的价值(净输出或输出)
+}
+
+// splitRoutingRequested 请求是否需要计算拆单方案，拆单只支持卖出报价
+func (s *RouterService) splitRoutingRequested(req *types.QuoteRequest) bool {
+	return req.SplitRouting && s.config.SplitRouting.Enabled && !req.IsExactOutput()
+}
+
+// SplitRoutingEnabled 服务是否允许拆单路由
+func (s *RouterService) SplitRoutingEnabled() bool {
+	return s.config.SplitRouting.Enabled
+}
+
+// buildSplitPlan 计算拆单方案
+// quotes为聚合阶段(已计算价格冲击)的全部报价，pricing不可用时按输出数量求解；拆单无收益时返回nil
+func (s *RouterService) buildSplitPlan(ctx context.Context, req *types.QuoteRequest, adapters []ProviderAdapter, quotes []*types.ProviderQuote, pricing *gasPricing) *types.SplitPlan {
+	parts := s.config.SplitRouting.Parts
+
+	candidates := s.collectSplitQuotes(ctx, req, adapters, quotes)
+	if len(candidates) < 2 {
+		s.logger.Infof("[%s] ✂️ 可用聚合器不足2个，跳过拆单", req.RequestID)
+		return nil
+	}
+	if pricing == nil {
+		s.logger.Warnf("[%s] ⚠️ Gas费用不可用，拆单方案按输出数量求解", req.RequestID)
+	}
+
+	for _, candidate := range candidates {
+		candidate.values = make([]decimal.Decimal, parts+1)
+		for k, quote := range candidate.quotes {
+			if quote == nil {
+				continue
+			}
+			candidate.values[k] = quote.AmountOut
+			if pricing != nil {
+				candidate.values[k] = quote.AmountOut.Sub(pricing.gasCost(quote.GasEstimate).CostInOutputToken)
+			}
+		}
+	}
+
+	allocation, planValue := solveSplit(candidates, parts, s.config.SplitRouting.MaxLegs)
+
+	// 最优单一聚合器的同口径价值
+	singleValue, hasSingle := decimal.Zero, false
+	for _, candidate := range candidates {
+		if candidate.quotes[parts] != nil && (!hasSingle || candidate.values[parts].GreaterThan(singleValue)) {
+			singleValue, hasSingle = candidate.values[parts], true
+		}
+	}
+
+	legCount := 0
+	for _, units := range allocation {
+		if units > 0 {
+			legCount++
+		}
+	}
+	if allocation == nil || legCount < 2 || !hasSingle || !planValue.GreaterThan(singleValue) {
+		s.logger.Infof("[%s] ✂️ 拆单方案不优于单一聚合器，不返回拆单", req.RequestID)
+		return nil
+	}
+
+	plan := &types.SplitPlan{
+		Parts:           parts,
+		SingleAmountOut: singleValue,
+	}
+	if singleValue.IsPositive() {
+		plan.Improvement = planValue.Sub(singleValue).Div(singleValue)
+	}
+
+	// 各腿输入数量向下取整，余数(不超过腿数-1个最小单位)计入最后一腿，保证各腿之和等于AmountIn
+	remaining := req.AmountIn
+	totalGasCost := decimal.Zero
+	for i, units := range allocation {
+		if units == 0 {
+			continue
+		}
+		quote := candidates[i].quotes[units]
+		legCount--
+
+		leg := types.SplitLeg{
+			Provider:    quote.Provider,
+			Percentage:  decimal.NewFromInt(int64(units)).Div(decimal.NewFromInt(int64(parts))),
+			AmountIn:    splitAmount(req.AmountIn, units, parts),
+			AmountOut:   quote.AmountOut,
+			GasEstimate: quote.GasEstimate,
+			Route:       quote.Route,
+		}
+		if legCount == 0 {
+			leg.AmountIn = remaining
+		}
+		remaining = remaining.Sub(leg.AmountIn)
+
+		if pricing != nil {
+			leg.GasCost = pricing.gasCost(quote.GasEstimate)
+			totalGasCost = totalGasCost.Add(leg.GasCost.CostInOutputToken)
+		}
+
+		plan.Legs = append(plan.Legs, leg)
+		plan.TotalAmountOut = plan.TotalAmountOut.Add(leg.AmountOut)
+		plan.TotalGasEstimate += leg.GasEstimate
+		plan.Route = append(plan.Route, scaleRoute(leg.Route, leg.Provider, leg.Percentage)...)
+	}
+	if pricing != nil {
+		netAmountOut := plan.TotalAmountOut.Sub(totalGasCost)
+		plan.NetAmountOut = &netAmountOut
+	}
+
+	s.logger.Infof("[%s] ✂️ 拆单方案: %d 腿, 总输出=%s, 提升=%s%%",
+		req.RequestID, len(plan.Legs), plan.TotalAmountOut.String(), plan.Improvement.Shift(2).StringFixed(4))
+	return plan
+}
+
+// collectSplitQuotes 并发获取各聚合器的分量报价
+// 只向聚合阶段报价成功的聚合器请求，100%份数直接复用聚合阶段的报价
+func (s *RouterService) collectSplitQuotes(ctx context.Context, req *types.QuoteRequest, adapters []ProviderAdapter, quotes []*types.ProviderQuote) []*splitCandidate {
+	parts := s.config.SplitRouting.Parts
+
+	fullQuotes := make(map[string]*types.ProviderQuote, len(quotes))
+	for _, quote := range quotes {
+		if quote.Success && quote.AmountOut.IsPositive() {
+			fullQuotes[quote.Provider] = quote
+		}
+	}
+
+	var candidates []*splitCandidate
+	for _, adapter := range adapters {
+		if fullQuote, ok := fullQuotes[adapter.GetName()]; ok {
+			candidate := &splitCandidate{adapter: adapter, quotes: make([]*types.ProviderQuote, parts+1)}
+			candidate.quotes[parts] = fullQuote
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) < 2 {
+		return candidates
+	}
+
+	splitCtx, cancel := context.WithTimeout(ctx, s.config.SplitRouting.Timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, candidate := range candidates {
+		for units := 1; units < parts; units++ {
+			amountIn := splitAmount(req.AmountIn, units, parts)
+			if !amountIn.IsPositive() {
+				continue
+			}
+
+			wg.Add(1)
+			go func(candidate *splitCandidate, units int, amountIn decimal.Decimal) {
+				defer wg.Done()
+
+				partReq := *req
+				partReq.AmountIn = amountIn
+
+				quote, err := candidate.adapter.GetQuote(splitCtx, &partReq)
+				if err != nil || !quote.Success || !quote.AmountOut.IsPositive() {
+					s.logger.Debugf("[%s] 聚合器 %s %d/%d 分量报价不可用: %v",
+						req.RequestID, candidate.adapter.GetName(), units, parts, err)
+					return
+				}
+				// 每个goroutine只写入自己的份数下标，无需加锁
+				candidate.quotes[units] = quote
+			}(candidate, units, amountIn)
+		}
+	}
+	wg.Wait()
+
+	return candidates
+}
+
+// solveSplit 求解最优拆单分配
+// 分组背包：每个聚合器最多选一个份数，总份数恰好为parts且最多maxLegs腿，最大化价值之和；
+// 返回每个候选聚合器分配的份数(0表示不参与)和总价值，无可行解时返回nil
+func solveSplit(candidates []*splitCandidate, parts, maxLegs int) ([]int, decimal.Decimal) {
+	// best[l][u]: 使用l腿、共u份时的最大价值
+	newTable := func() ([][]decimal.Decimal, [][]bool) {
+		values := make([][]decimal.Decimal, maxLegs+1)
+		reachable := make([][]bool, maxLegs+1)
+		for l := range values {
+			values[l] = make([]decimal.Decimal, parts+1)
+			reachable[l] = make([]bool, parts+1)
+		}
+		return values, reachable
+	}
+
+	best, reachable := newTable()
+	reachable[0][0] = true
+	choices := make([][][]int, len(candidates)) // choices[i][l][u]: 第i个聚合器在该状态下选取的份数
+
+	for i, candidate := range candidates {
+		nextBest, nextReachable := newTable()
+		choices[i] = make([][]int, maxLegs+1)
+		for l := 0; l <= maxLegs; l++ {
+			copy(nextBest[l], best[l])
+			copy(nextReachable[l], reachable[l])
+			choices[i][l] = make([]int, parts+1)
+		}
+
+		for l := 0; l < maxLegs; l++ {
+			for u := 0; u < parts; u++ {
+				if !reachable[l][u] {
+					continue
+				}
+				for k := 1; u+k <= parts; k++ {
+					if candidate.quotes[k] == nil {
+						continue
+					}
+					value := best[l][u].Add(candidate.values[k])
+					if !nextReachable[l+1][u+k] || value.GreaterThan(nextBest[l+1][u+k]) {
+						nextBest[l+1][u+k] = value
+						nextReachable[l+1][u+k] = true
+						choices[i][l+1][u+k] = k
+					}
+				}
+			}
+		}
+		best, reachable = nextBest, nextReachable
+	}
+
+	bestLegs := -1
+	for l := 1; l <= maxLegs; l++ {
+		if reachable[l][parts] && (bestLegs < 0 || best[l][parts].GreaterThan(best[bestLegs][parts])) {
+			bestLegs = l
+		}
+	}
+	if bestLegs < 0 {
+		return nil, decimal.Zero
+	}
+
+	// 从最后一个聚合器回溯各自的份数
+	allocation := make([]int, len(candidates))
+	l, u := bestLegs, parts
+	for i := len(candidates) - 1; i >= 0 && l > 0; i-- {
+		if k := choices[i][l][u]; k > 0 {
+			allocation[i] = k
+			l, u = l-1, u-k
+		}
+	}
+	return allocation, best[bestLegs][parts]
+}
+
+// splitAmount 计算units份对应的输入数量(向下取整到最小单位)
+func splitAmount(amountIn decimal.Decimal, units, parts int) decimal.Decimal {
+	if units == parts {
+		return amountIn
+	}
+	return amountIn.Mul(decimal.NewFromInt(int64(units))).Div(decimal.NewFromInt(int64(parts))).Floor()
+}
+
+// scaleRoute 将单腿交易路径按该腿比例折算为整体比例，聚合器未返回路径时以聚合器名称作为协议
+func scaleRoute(route []types.RouteStep, provider string, percentage decimal.Decimal) []types.RouteStep {
+	if len(route) == 0 {
+		return []types.RouteStep{{Protocol: provider, Percentage: percentage}}
+	}
+
+	scaled := make([]types.RouteStep, len(route))
+	for i, step := range route {
+		scaled[i] = step
+		scaled[i].Percentage = step.Percentage.Mul(percentage)
+	}
+	return scaled
+}
@@ -0,0 +1,115 @@
+// Package services 代币元数据与数量换算
+// 聚合器报价的数量均为最小单位整数，路由服务按输入输出代币的小数位数换算可读数量和汇率；
+// 小数位数优先取请求中指定的值，其次取tokens表(由business-logic维护)登记的代币信息
+package services
+
+import (
+	"context"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// pairMetadata 交易对代币元数据，无法获取的代币为nil
+type pairMetadata struct {
+	fromToken *types.TokenInfo // 输入代币
+	toToken   *types.TokenInfo // 输出代币
+}
+
+// resolveTokenMetadataAsync 在聚合报价的同时获取输入输出代币元数据
+func (s *RouterService) resolveTokenMetadataAsync(ctx context.Context, req *types.QuoteRequest) <-chan *pairMetadata {
+	result := make(chan *pairMetadata, 1)
+
+	go func() {
+		result <- &pairMetadata{
+			fromToken: s.resolveTokenInfo(ctx, req, req.FromToken, req.FromTokenDecimals, req.FromTokenSymbol),
+			toToken:   s.resolveTokenInfo(ctx, req, req.ToToken, req.ToTokenDecimals, req.ToTokenSymbol),
+		}
+	}()
+
+	return result
+}
+
+// resolveTokenInfo 获取单个代币元数据
+// 请求指定小数位数时直接使用(未指定符号时尝试从tokens表补全)，否则查询tokens表，均不可用时返回nil
+func (s *RouterService) resolveTokenInfo(ctx context.Context, req *types.QuoteRequest, address string, decimals *int32, symbol string) *types.TokenInfo {
+	var registered *types.TokenMarketData
+	if s.marketData != nil && (decimals == nil || symbol == "") {
+		token, err := s.marketData.GetToken(ctx, req.ChainID, address)
+		if err != nil {
+			s.logger.Debugf("[%s] tokens表中没有代币 %s: %v", req.RequestID, address, err)
+		} else {
+			registered = token
+		}
+	}
+
+	if decimals != nil {
+		if symbol == "" && registered != nil {
+			symbol = registered.Symbol
+		}
+		return &types.TokenInfo{
+			Address:  address,
+			Symbol:   symbol,
+			Decimals: *decimals,
+			Source:   types.TokenMetadataSourceRequest,
+		}
+	}
+
+	if registered == nil {
+		s.logger.Warnf("[%s] ⚠️ 代币 %s 小数位数未知，返回最小单位数量", req.RequestID, address)
+		return nil
+	}
+	return &types.TokenInfo{
+		Address:  address,
+		Symbol:   registered.Symbol,
+		Decimals: registered.Decimals,
+		Source:   types.TokenMetadataSourceRegistry,
+	}
+}
+
+// applyTokenMetadata 为聚合响应填充代币信息、可读数量和汇率
+// 两个代币的小数位数均已知时按可读单位计算汇率，否则汇率为最小单位之比
+func (s *RouterService) applyTokenMetadata(response *types.QuoteResponse, metadata *pairMetadata) {
+	if metadata == nil {
+		metadata = &pairMetadata{}
+	}
+	response.FromToken = metadata.fromToken
+	response.ToToken = metadata.toToken
+
+	amountIn, amountOut := response.AmountIn, response.BestPrice
+	if metadata.fromToken != nil {
+		amountIn = formatAmount(response.AmountIn, metadata.fromToken.Decimals)
+		response.AmountInFormatted = &amountIn
+	}
+	if metadata.toToken != nil {
+		amountOut = formatAmount(response.BestPrice, metadata.toToken.Decimals)
+		response.BestPriceFormatted = &amountOut
+
+		for _, quote := range response.AllQuotes {
+			if quote.Success {
+				formatted := formatAmount(quote.AmountOut, metadata.toToken.Decimals)
+				quote.AmountOutFormatted = &formatted
+			}
+		}
+	}
+
+	// 只有一侧小数位数已知时无法换算，汇率保持最小单位之比
+	if metadata.fromToken == nil || metadata.toToken == nil {
+		amountIn, amountOut = response.AmountIn, response.BestPrice
+	}
+
+	response.ExchangeRate = decimal.Zero
+	response.InverseExchangeRate = decimal.Zero
+	if amountIn.IsPositive() {
+		response.ExchangeRate = amountOut.Div(amountIn)
+	}
+	if amountOut.IsPositive() {
+		response.InverseExchangeRate = amountIn.Div(amountOut)
+	}
+}
+
+// formatAmount 将最小单位数量按小数位数换算为可读数量
+func formatAmount(amount decimal.Decimal, decimals int32) decimal.Decimal {
+	return amount.Shift(-decimals)
+}
@@ -0,0 +1,76 @@
+// Package types 原生代币(ETH/MATIC/BNB等)地址约定
+// 路由服务内部统一使用NativeTokenAddress表示链原生代币，请求中的零地址和各链特有表示(如Polygon的0x...1010)
+// 在进入聚合流程前规范化；各适配器再将规范地址转换为聚合器要求的形式(原样传递或改用包装代币)
+package types
+
+import "strings"
+
+// 原生代币地址
+const (
+	NativeTokenAddress = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE" // 原生代币规范地址(1inch/ParaSwap/0x约定)
+	ZeroAddress        = "0x0000000000000000000000000000000000000000" // 零地址，部分钱包和前端用于表示原生代币
+)
+
+// 包装/解包原生代币的路径步骤协议名称
+const (
+	RouteProtocolWrapNative   = "WRAP_NATIVE"   // 原生代币包装为包装代币(如ETH->WETH)
+	RouteProtocolUnwrapNative = "UNWRAP_NATIVE" // 包装代币解包为原生代币(如WETH->ETH)
+)
+
+// ChainNativeToken 链原生代币信息
+type ChainNativeToken struct {
+	Symbol         string   // 原生代币符号
+	WrappedSymbol  string   // 包装代币符号
+	WrappedAddress string   // 包装代币合约地址
+	Aliases        []string // 该链原生代币的其他地址表示
+}
+
+// chainNativeTokens 已知链的原生代币信息
+var chainNativeTokens = map[uint]ChainNativeToken{
+	1:        {Symbol: "ETH", WrappedSymbol: "WETH", WrappedAddress: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"},
+	10:       {Symbol: "ETH", WrappedSymbol: "WETH", WrappedAddress: "0x4200000000000000000000000000000000000006"},
+	56:       {Symbol: "BNB", WrappedSymbol: "WBNB", WrappedAddress: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"},
+	100:      {Symbol: "XDAI", WrappedSymbol: "WXDAI", WrappedAddress: "0xe91D153E0b41518A2Ce8Dd3D7944Fa863463a97d"},
+	137:      {Symbol: "MATIC", WrappedSymbol: "WMATIC", WrappedAddress: "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", Aliases: []string{"0x0000000000000000000000000000000000001010"}},
+	8453:     {Symbol: "ETH", WrappedSymbol: "WETH", WrappedAddress: "0x4200000000000000000000000000000000000006"},
+	42161:    {Symbol: "ETH", WrappedSymbol: "WETH", WrappedAddress: "0x82aF49447D8a07e3bd95BD0d56f35241523fBab1"},
+	43114:    {Symbol: "AVAX", WrappedSymbol: "WAVAX", WrappedAddress: "0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7"},
+	80001:    {Symbol: "MATIC", WrappedSymbol: "WMATIC", WrappedAddress: "0x9c3C9283D3e44854697Cd22D3Faa240Cfb032889", Aliases: []string{"0x0000000000000000000000000000000000001010"}},
+	11155111: {Symbol: "SepoliaETH", WrappedSymbol: "WETH", WrappedAddress: "0xfFf9976782d46CC05630D1f6eBAb18b2324d6B14"},
+}
+
+// GetChainNativeToken 获取链原生代币信息，未知链返回false
+func GetChainNativeToken(chainID uint) (ChainNativeToken, bool) {
+	token, ok := chainNativeTokens[chainID]
+	return token, ok
+}
+
+// IsNativeToken 判断地址是否表示链原生代币(规范地址、零地址或该链的原生代币别名，不区分大小写)
+func IsNativeToken(chainID uint, address string) bool {
+	if strings.EqualFold(address, NativeTokenAddress) || strings.EqualFold(address, ZeroAddress) {
+		return true
+	}
+	for _, alias := range chainNativeTokens[chainID].Aliases {
+		if strings.EqualFold(address, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTokenAddress 将原生代币的各种表示规范化为NativeTokenAddress，其他地址原样返回
+func NormalizeTokenAddress(chainID uint, address string) string {
+	if IsNativeToken(chainID, address) {
+		return NativeTokenAddress
+	}
+	return address
+}
+
+// WrappedNativeToken 获取链包装原生代币合约地址，未知链返回false
+func WrappedNativeToken(chainID uint) (string, bool) {
+	token, ok := chainNativeTokens[chainID]
+	if !ok || token.WrappedAddress == "" {
+		return "", false
+	}
+	return token.WrappedAddress, true
+}
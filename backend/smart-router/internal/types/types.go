@@ -16,34 +16,98 @@ import (
 // QuoteRequest 报价请求
 // 前端或业务逻辑层发送给智能路由的报价请求
 type QuoteRequest struct {
-	RequestID   string           `json:"request_id" validate:"required"`     // 唯一请求ID
-	FromToken   string           `json:"from_token" validate:"required"`     // 源代币合约地址
-	ToToken     string           `json:"to_token" validate:"required"`       // 目标代币合约地址
-	AmountIn    decimal.Decimal  `json:"amount_in" validate:"required,gt=0"` // 输入数量(wei格式)
-	ChainID     uint             `json:"chain_id" validate:"required"`       // 区块链ID
-	Slippage    decimal.Decimal  `json:"slippage" validate:"gte=0,lte=0.5"`  // 滑点容忍度
-	UserAddress string           `json:"user_address,omitempty"`             // 用户钱包地址(可选)
-	GasPrice    *decimal.Decimal `json:"gas_price,omitempty"`                // 指定Gas价格(可选)
-	Deadline    *time.Time       `json:"deadline,omitempty"`                 // 交易截止时间(可选)
+	RequestID   string           `json:"request_id" validate:"required"`    // 唯一请求ID
+	FromToken   string           `json:"from_token" validate:"required"`    // 源代币合约地址
+	ToToken     string           `json:"to_token" validate:"required"`      // 目标代币合约地址
+	AmountIn    decimal.Decimal  `json:"amount_in"`                         // 输入数量(输入代币最小单位，side=sell时必填)
+	AmountOut   decimal.Decimal  `json:"amount_out"`                        // 期望输出数量(输出代币最小单位，side=buy时必填)
+	Side        string           `json:"side,omitempty"`                    // 报价方向(sell: 卖出固定AmountIn，默认; buy: 买入固定AmountOut)
+	ChainID     uint             `json:"chain_id" validate:"required"`      // 区块链ID
+	Slippage    decimal.Decimal  `json:"slippage" validate:"gte=0,lte=0.5"` // 滑点容忍度
+	UserAddress string           `json:"user_address,omitempty"`            // 用户钱包地址(可选)
+	GasPrice    *decimal.Decimal `json:"gas_price,omitempty"`               // 指定Gas价格(可选)
+	Deadline    *time.Time       `json:"deadline,omitempty"`                // 交易截止时间(可选)
+
+	RankingStrategy string `json:"ranking_strategy,omitempty"` // 报价排序策略(可选，为空时使用服务默认策略)
+	SplitRouting    bool   `json:"split_routing,omitempty"`    // 是否计算跨聚合器拆单方案(需服务启用SPLIT_ROUTING_ENABLED)
+
+	// 代币元数据(可选)，指定时优先于tokens表登记的代币信息
+	FromTokenDecimals *int32 `json:"from_token_decimals,omitempty"` // 源代币小数位数
+	ToTokenDecimals   *int32 `json:"to_token_decimals,omitempty"`   // 目标代币小数位数
+	FromTokenSymbol   string `json:"from_token_symbol,omitempty"`   // 源代币符号
+	ToTokenSymbol     string `json:"to_token_symbol,omitempty"`     // 目标代币符号
+}
+
+// IsExactOutput 是否为买入(exact-output)报价
+func (r *QuoteRequest) IsExactOutput() bool {
+	return r.Side == QuoteSideBuy
 }
 
 // QuoteResponse 聚合报价响应
-// 智能路由返回的最优报价结果
+// 智能路由返回的最优报价结果；AmountIn、BestPrice等为最小单位整数，*Formatted为按小数位数换算的可读数量。
+// 两个代币的小数位数均已知时ExchangeRate为每1个输入代币可换得的输出代币数量，
+// 否则FromToken/ToToken为空，ExchangeRate退化为最小单位之比
 type QuoteResponse struct {
-	RequestID       string                 `json:"request_id"`              // 请求ID
-	Success         bool                   `json:"success"`                 // 是否成功
-	BestProvider    string                 `json:"best_provider"`           // 最佳聚合器
-	BestPrice       decimal.Decimal        `json:"best_price"`              // 最佳价格(输出数量)
-	BestGasEstimate uint64                 `json:"best_gas_estimate"`       // 最佳Gas估算
-	PriceImpact     decimal.Decimal        `json:"price_impact"`            // 价格冲击
-	ExchangeRate    decimal.Decimal        `json:"exchange_rate"`           // 汇率
-	Route           []RouteStep            `json:"route,omitempty"`         // 交易路径
-	AllQuotes       []*ProviderQuote       `json:"all_quotes"`              // 所有聚合器报价
-	Performance     AggregationPerformance `json:"performance"`             // 聚合性能指标
-	ValidUntil      time.Time              `json:"valid_until"`             // 报价有效期
-	CacheHit        bool                   `json:"cache_hit"`               // 是否命中缓存
-	ErrorMessage    string                 `json:"error_message,omitempty"` // 错误信息
-	Timestamp       time.Time              `json:"timestamp"`               // 响应时间戳
+	RequestID           string                 `json:"request_id"`                     // 请求ID
+	Success             bool                   `json:"success"`                        // 是否成功
+	FromToken           *TokenInfo             `json:"from_token,omitempty"`           // 源代币信息
+	ToToken             *TokenInfo             `json:"to_token,omitempty"`             // 目标代币信息
+	Side                string                 `json:"side"`                           // 报价方向(sell/buy)
+	AmountIn            decimal.Decimal        `json:"amount_in"`                      // 输入数量(最小单位，买入报价为最优报价所需输入)
+	MaxAmountIn         *decimal.Decimal       `json:"max_amount_in,omitempty"`        // 按滑点上浮的最大输入数量(买入报价)
+	AmountInFormatted   *decimal.Decimal       `json:"amount_in_formatted,omitempty"`  // 输入数量(可读单位)
+	BestProvider        string                 `json:"best_provider"`                  // 最佳聚合器
+	BestPrice           decimal.Decimal        `json:"best_price"`                     // 最佳价格(输出数量，最小单位)
+	BestPriceFormatted  *decimal.Decimal       `json:"best_price_formatted,omitempty"` // 最佳输出数量(可读单位)
+	BestGasEstimate     uint64                 `json:"best_gas_estimate"`              // 最佳Gas估算
+	BestNetAmountOut    *decimal.Decimal       `json:"best_net_amount_out,omitempty"`  // 最佳报价扣除Gas费用后的净输出数量
+	BestGasCost         *GasCost               `json:"best_gas_cost,omitempty"`        // 最佳报价的Gas费用明细
+	RankingStrategy     string                 `json:"ranking_strategy"`               // 实际使用的排序策略
+	PriceImpact         decimal.Decimal        `json:"price_impact"`                   // 价格冲击
+	ReferencePrice      *ReferencePrice        `json:"reference_price,omitempty"`      // 计算价格冲击使用的参考价格
+	ExchangeRate        decimal.Decimal        `json:"exchange_rate"`                  // 汇率(每1个输入代币的输出数量)
+	InverseExchangeRate decimal.Decimal        `json:"inverse_exchange_rate"`          // 反向汇率(每1个输出代币的输入数量)
+	Route               []RouteStep            `json:"route,omitempty"`                // 交易路径
+	SplitPlan           *SplitPlan             `json:"split_plan,omitempty"`           // 拆单方案(请求split_routing且拆单优于单一聚合器时返回)
+	AllQuotes           []*ProviderQuote       `json:"all_quotes"`                     // 所有聚合器报价
+	Performance         AggregationPerformance `json:"performance"`                    // 聚合性能指标
+	ValidUntil          time.Time              `json:"valid_until"`                    // 报价有效期
+	CacheHit            bool                   `json:"cache_hit"`                      // 是否命中缓存
+	ErrorMessage        string                 `json:"error_message,omitempty"`        // 错误信息
+	Timestamp           time.Time              `json:"timestamp"`                      // 响应时间戳
+}
+
+// SplitPlan 跨聚合器拆单方案
+// 将AmountIn按Parts等分后分配给多个聚合器，使各腿输出(Gas价格可用时扣除各腿Gas费用)之和最大
+type SplitPlan struct {
+	Legs             []SplitLeg       `json:"legs"`                     // 各腿交易
+	Parts            int              `json:"parts"`                    // 拆分粒度(AmountIn等分份数)
+	TotalAmountOut   decimal.Decimal  `json:"total_amount_out"`         // 各腿输出数量之和
+	TotalGasEstimate uint64           `json:"total_gas_estimate"`       // 各腿Gas估算之和
+	NetAmountOut     *decimal.Decimal `json:"net_amount_out,omitempty"` // 扣除各腿Gas费用后的净输出数量(Gas价格可用时计算)
+	SingleAmountOut  decimal.Decimal  `json:"single_amount_out"`        // 最优单一聚合器的同口径输出数量
+	Improvement      decimal.Decimal  `json:"improvement"`              // 相对最优单一聚合器的提升比例
+	Route            []RouteStep      `json:"route"`                    // 合并交易路径(各腿路径按拆分比例折算)
+}
+
+// SplitLeg 拆单方案中的单腿交易
+type SplitLeg struct {
+	Provider    string          `json:"provider"`           // 聚合器名称
+	Percentage  decimal.Decimal `json:"percentage"`         // 占AmountIn的比例
+	AmountIn    decimal.Decimal `json:"amount_in"`          // 输入数量
+	AmountOut   decimal.Decimal `json:"amount_out"`         // 输出数量
+	GasEstimate uint64          `json:"gas_estimate"`       // Gas估算
+	GasCost     *GasCost        `json:"gas_cost,omitempty"` // Gas费用明细(Gas价格可用时计算)
+	Route       []RouteStep     `json:"route,omitempty"`    // 该腿交易路径
+}
+
+// TokenInfo 报价代币元数据
+// 来自请求指定的小数位数(request)或tokens表登记的代币信息(registry)
+type TokenInfo struct {
+	Address  string `json:"address"`          // 合约地址
+	Symbol   string `json:"symbol,omitempty"` // 代币符号
+	Decimals int32  `json:"decimals"`         // 小数位数
+	Source   string `json:"source"`           // 元数据来源(request/registry)
 }
 
 // RouteStep 交易路径步骤
@@ -57,18 +121,108 @@ type RouteStep struct {
 // ProviderQuote 单个聚合器的报价
 // 记录每个聚合器的响应结果和性能指标
 type ProviderQuote struct {
-	Provider     string          `json:"provider"`                // 聚合器名称
-	Success      bool            `json:"success"`                 // 是否成功响应
-	AmountOut    decimal.Decimal `json:"amount_out"`              // 输出数量
-	GasEstimate  uint64          `json:"gas_estimate"`            // Gas估算
-	PriceImpact  decimal.Decimal `json:"price_impact"`            // 价格冲击
-	Route        []RouteStep     `json:"route,omitempty"`         // 交易路径
-	ResponseTime time.Duration   `json:"response_time"`           // 响应时间
-	Confidence   decimal.Decimal `json:"confidence"`              // 置信度评分
-	Rank         int             `json:"rank"`                    // 价格排名
-	ErrorCode    string          `json:"error_code,omitempty"`    // 错误代码
-	ErrorMessage string          `json:"error_message,omitempty"` // 错误信息
-	RawResponse  interface{}     `json:"raw_response,omitempty"`  // 原始响应(调试用)
+	Provider           string           `json:"provider"`                       // 聚合器名称
+	Success            bool             `json:"success"`                        // 是否成功响应
+	AmountIn           decimal.Decimal  `json:"amount_in"`                      // 输入数量(最小单位，买入报价为所需输入)
+	MaxAmountIn        *decimal.Decimal `json:"max_amount_in,omitempty"`        // 按滑点上浮的最大输入数量(买入报价)
+	NetAmountIn        *decimal.Decimal `json:"net_amount_in,omitempty"`        // 加上Gas费用后的净输入数量(买入报价net_of_gas排序时计算)
+	AmountOut          decimal.Decimal  `json:"amount_out"`                     // 输出数量(最小单位)
+	AmountOutFormatted *decimal.Decimal `json:"amount_out_formatted,omitempty"` // 输出数量(可读单位，输出代币小数位数已知时计算)
+	GasEstimate        uint64           `json:"gas_estimate"`                   // Gas估算
+	PriceImpact        decimal.Decimal  `json:"price_impact"`                   // 价格冲击(相对参考价格，由路由服务统一计算)
+	Route              []RouteStep      `json:"route,omitempty"`                // 交易路径
+	ResponseTime       time.Duration    `json:"response_time"`                  // 响应时间
+	Confidence         decimal.Decimal  `json:"confidence"`                     // 置信度评分
+	Rank               int              `json:"rank"`                           // 排名(按排序策略评分)
+	Score              *QuoteScore      `json:"score,omitempty"`                // 排序评分明细
+	NetAmountOut       *decimal.Decimal `json:"net_amount_out,omitempty"`       // 扣除Gas费用后的净输出数量(Gas价格可用时计算)
+	GasCost            *GasCost         `json:"gas_cost,omitempty"`             // Gas费用明细(Gas价格可用时计算)
+	HighImpact         bool             `json:"high_impact,omitempty"`          // 价格冲击超过上限(flag模式下标记)
+	ErrorCode          string           `json:"error_code,omitempty"`           // 错误代码
+	ErrorMessage       string           `json:"error_message,omitempty"`        // 错误信息
+	RawResponse        interface{}      `json:"raw_response,omitempty"`         // 原始响应(调试用)
+}
+
+// GasCost 报价的Gas费用明细
+// Gas费用先按原生代币USD价格折算为USD，再按计价代币USD价格折算为计价代币数量；
+// 计价代币为原生代币时直接按原生代币数量计算。卖出报价的计价代币为输出代币(从输出中扣除)，
+// 买入报价的计价代币为输入代币(计入所需输入)，output_token_*字段在买入报价中表示输入代币
+type GasCost struct {
+	GasPrice            decimal.Decimal `json:"gas_price"`              // Gas价格(wei)
+	GasPriceSource      string          `json:"gas_price_source"`       // Gas价格来源(request/rpc/chain_default)
+	CostNative          decimal.Decimal `json:"cost_native"`            // Gas费用(原生代币最小单位)
+	NativeTokenPriceUSD decimal.Decimal `json:"native_token_price_usd"` // 原生代币USD价格
+	OutputTokenPriceUSD decimal.Decimal `json:"output_token_price_usd"` // 输出代币USD价格
+	CostUSD             decimal.Decimal `json:"cost_usd"`               // Gas费用USD
+	CostInOutputToken   decimal.Decimal `json:"cost_in_output_token"`   // Gas费用折算的输出代币数量(最小单位)
+}
+
+// ReferencePrice 交易对参考价格
+// Rate为每单位输入代币(最小单位)可换得的输出代币数量(最小单位)，
+// 来自tokens表USD价格，或按小额报价得到的近似现货价格
+type ReferencePrice struct {
+	Rate         decimal.Decimal  `json:"rate"`                     // 参考汇率
+	Source       string           `json:"source"`                   // 来源(usd_price/spot_quote)
+	Provider     string           `json:"provider,omitempty"`       // 提供小额报价的聚合器
+	SpotAmountIn *decimal.Decimal `json:"spot_amount_in,omitempty"` // 小额报价的输入数量
+	UpdatedAt    time.Time        `json:"updated_at"`               // 获取时间
+}
+
+// QuoteScore 报价排序评分明细
+// Total越高排名越靠前，Components记录各评分维度的原始得分，Weights为加权策略使用的权重
+type QuoteScore struct {
+	Strategy   string                     `json:"strategy"`          // 排序策略
+	Total      decimal.Decimal            `json:"total"`             // 综合评分
+	Components map[string]decimal.Decimal `json:"components"`        // 评分组成
+	Weights    map[string]decimal.Decimal `json:"weights,omitempty"` // 评分权重(weighted策略)
+}
+
+// ChainGasPrice 链当前Gas价格
+type ChainGasPrice struct {
+	ChainID uint            `json:"chain_id"` // 区块链ID
+	Wei     decimal.Decimal `json:"wei"`      // Gas价格(wei)
+	Source  string          `json:"source"`   // 来源(rpc/chain_default)
+}
+
+// TokenMarketData 代币行情数据
+// 来自tokens表，PriceUSD为零表示没有可用价格
+type TokenMarketData struct {
+	ChainID  uint            `json:"chain_id"`  // 区块链ID
+	Address  string          `json:"address"`   // 合约地址
+	Symbol   string          `json:"symbol"`    // 代币符号
+	Decimals int32           `json:"decimals"`  // 小数位数
+	IsNative bool            `json:"is_native"` // 是否为原生代币
+	PriceUSD decimal.Decimal `json:"price_usd"` // USD价格
+}
+
+// SwapRequest 交易构建请求
+// 在报价参数基础上构建可执行交易，UserAddress必填
+type SwapRequest struct {
+	QuoteRequest
+	Provider string `json:"provider,omitempty"` // 指定聚合器(为空时先聚合报价选择最优聚合器)
+}
+
+// SwapTransaction 可执行的交易数据
+// Kind为transaction时由钱包直接发送To/Data/Value；
+// Kind为order时(CoW Protocol)由用户对Order进行EIP-712签名后提交到OrderURL
+type SwapTransaction struct {
+	RequestID       string                 `json:"request_id"`                 // 请求ID
+	Provider        string                 `json:"provider"`                   // 聚合器名称
+	Kind            string                 `json:"kind"`                       // 交易类型(transaction/order)
+	ChainID         uint                   `json:"chain_id"`                   // 区块链ID
+	From            string                 `json:"from"`                       // 发送方地址
+	To              string                 `json:"to,omitempty"`               // 目标合约地址
+	Data            string                 `json:"data,omitempty"`             // 交易数据(calldata)
+	Value           string                 `json:"value,omitempty"`            // 发送的原生代币数量(wei)
+	Gas             uint64                 `json:"gas"`                        // Gas限制
+	GasPrice        string                 `json:"gas_price,omitempty"`        // Gas价格(wei)
+	AllowanceTarget string                 `json:"allowance_target,omitempty"` // 需要授权的合约地址
+	AmountIn        decimal.Decimal        `json:"amount_in"`                  // 输入数量
+	AmountOut       decimal.Decimal        `json:"amount_out"`                 // 预期输出数量
+	MinAmountOut    decimal.Decimal        `json:"min_amount_out"`             // 滑点保护后的最小输出数量
+	Order           map[string]interface{} `json:"order,omitempty"`            // 待签名的链下订单(CoW Protocol)
+	OrderURL        string                 `json:"order_url,omitempty"`        // 订单提交地址(CoW Protocol)
+	Timestamp       time.Time              `json:"timestamp"`                  // 构建时间
 }
 
 // AggregationPerformance 聚合性能指标
@@ -83,6 +237,12 @@ type AggregationPerformance struct {
 	CacheHitRate     decimal.Decimal `json:"cache_hit_rate"`    // 缓存命中率
 	StrategyUsed     string          `json:"strategy_used"`     // 使用的聚合策略
 	QualityScore     decimal.Decimal `json:"quality_score"`     // 聚合质量评分
+
+	// 渐进式决策信息
+	DecisionRule       string          `json:"decision_rule"`       // 触发返回的决策规则
+	DecisionTime       time.Duration   `json:"decision_time"`       // 做出决策的耗时
+	CompositeScore     decimal.Decimal `json:"composite_score"`     // 决策时的综合评分
+	ProvidersCancelled int             `json:"providers_cancelled"` // 被提前取消的聚合器数量
 }
 
 // ========================================
@@ -92,16 +252,22 @@ type AggregationPerformance struct {
 // ProviderConfig 聚合器配置
 // 定义每个第三方聚合器的连接和行为配置
 type ProviderConfig struct {
-	Name            string          `json:"name"`             // 聚合器名称
-	DisplayName     string          `json:"display_name"`     // 显示名称
-	BaseURL         string          `json:"base_url"`         // API基础URL
-	APIKey          string          `json:"api_key"`          // API密钥
-	Timeout         time.Duration   `json:"timeout"`          // 请求超时时间
-	RetryCount      int             `json:"retry_count"`      // 重试次数
-	Priority        int             `json:"priority"`         // 优先级(1最高)
-	Weight          decimal.Decimal `json:"weight"`           // 权重系数
-	IsActive        bool            `json:"is_active"`        // 是否启用
-	SupportedChains []uint          `json:"supported_chains"` // 支持的链ID列表
+	Name            string          `json:"name"`                // 聚合器名称
+	DisplayName     string          `json:"display_name"`        // 显示名称
+	BaseURL         string          `json:"base_url"`            // API基础URL
+	APIKey          string          `json:"api_key"`             // API密钥
+	Timeout         time.Duration   `json:"timeout"`             // 请求超时时间
+	RetryCount      int             `json:"retry_count"`         // 重试次数
+	Priority        int             `json:"priority"`            // 优先级(1最高)
+	Weight          decimal.Decimal `json:"weight"`              // 权重系数
+	IsActive        bool            `json:"is_active"`           // 是否启用
+	SupportedChains []uint          `json:"supported_chains"`    // 支持的链ID列表
+	AuthScheme      string          `json:"auth_scheme"`         // API密钥认证方式(bearer/header/none，为空时使用适配器默认方式)
+	AuthHeader      string          `json:"auth_header"`         // header认证方式使用的请求头名称
+	Retry           RetryPolicy     `json:"retry"`               // 重试退避策略
+	DEX             *DEXConfig      `json:"dex,omitempty"`       // 直连DEX配置(仅直连DEX适配器使用)
+	Adapter         string          `json:"adapter,omitempty"`   // 适配器类型(适配器注册表中的名称，为空时使用Name)
+	HTTPJSON        *HTTPJSONConfig `json:"http_json,omitempty"` // 声明式HTTP JSON适配器配置(仅http_json适配器使用)
 
 	// 性能统计
 	SuccessRate     decimal.Decimal `json:"success_rate"`      // 成功率
@@ -109,6 +275,107 @@ type ProviderConfig struct {
 	LastHealthCheck time.Time       `json:"last_health_check"` // 最后健康检查
 }
 
+// RetryPolicy 聚合器请求重试退避策略
+// 最多重试ProviderConfig.RetryCount次，仅重试网络错误、429和5xx响应；
+// 第n次重试前等待 min(InitialBackoff × Multiplier^(n-1), MaxBackoff)，并叠加±Jitter比例的随机抖动，
+// 响应携带Retry-After时按其等待，超过MaxRetryAfter则不再重试；等待后剩余时间不足时放弃重试
+type RetryPolicy struct {
+	InitialBackoff time.Duration `json:"initial_backoff"` // 首次重试等待时间
+	MaxBackoff     time.Duration `json:"max_backoff"`     // 单次等待时间上限
+	Multiplier     float64       `json:"multiplier"`      // 等待时间增长倍数
+	Jitter         float64       `json:"jitter"`          // 随机抖动比例(0-1)
+	MaxRetryAfter  time.Duration `json:"max_retry_after"` // 可接受的Retry-After上限
+}
+
+// DEXConfig 直连DEX适配器配置
+// 直连DEX适配器不调用第三方聚合器API，而是通过链JSON-RPC节点eth_call调用DEX合约获取报价，
+// 在配置的手续费档位和中间代币组成的路径中选择最优路径
+type DEXConfig struct {
+	RPCURLs            map[uint]string       `json:"rpc_urls,omitempty"`            // 各链JSON-RPC节点URL，未配置的链使用BaseURL
+	Contracts          map[uint]DEXContracts `json:"contracts,omitempty"`           // 各链合约地址，未配置的链使用内置的官方部署地址
+	FeeTiers           []uint32              `json:"fee_tiers,omitempty"`           // 探索的池子手续费档位(百万分之一，如3000=0.3%，仅Uniswap V3)
+	IntermediateTokens map[uint][]string     `json:"intermediate_tokens,omitempty"` // 各链两跳路径的中间代币，未配置的链使用包装原生代币
+}
+
+// Clone 创建独立的DEX配置副本
+func (c *DEXConfig) Clone() *DEXConfig {
+	if c == nil {
+		return nil
+	}
+	clone := &DEXConfig{FeeTiers: append([]uint32(nil), c.FeeTiers...)}
+	if c.RPCURLs != nil {
+		clone.RPCURLs = make(map[uint]string, len(c.RPCURLs))
+		for chainID, url := range c.RPCURLs {
+			clone.RPCURLs[chainID] = url
+		}
+	}
+	if c.Contracts != nil {
+		clone.Contracts = make(map[uint]DEXContracts, len(c.Contracts))
+		for chainID, contracts := range c.Contracts {
+			clone.Contracts[chainID] = contracts
+		}
+	}
+	if c.IntermediateTokens != nil {
+		clone.IntermediateTokens = make(map[uint][]string, len(c.IntermediateTokens))
+		for chainID, tokens := range c.IntermediateTokens {
+			clone.IntermediateTokens[chainID] = append([]string(nil), tokens...)
+		}
+	}
+	return clone
+}
+
+// AdapterType 获取适配器类型，未单独配置时与聚合器名称相同
+func (c *ProviderConfig) AdapterType() string {
+	if c.Adapter != "" {
+		return c.Adapter
+	}
+	return c.Name
+}
+
+// HTTPJSONConfig 声明式HTTP JSON适配器配置
+// 适用于只需一次HTTP请求即可报价的简单聚合器：按模板构建请求，按JSONPath从响应中提取输出数量和Gas估算。
+// URLTemplate、Params和Headers的值支持占位符 {base_url} {chain_id} {from_token} {to_token} {amount_in}
+// {slippage}(小数，如0.005) {slippage_percent}(百分比，如0.5) {slippage_bps}(基点，如50) {user_address}；
+// JSONPath支持 $.a.b[0].c 形式的字段和数组下标访问
+type HTTPJSONConfig struct {
+	Method        string            `json:"method,omitempty"`      // HTTP方法(GET/POST，默认GET)
+	URLTemplate   string            `json:"url_template"`          // 请求URL模板，如"{base_url}/v1/{chain_id}/quote"
+	Params        map[string]string `json:"params,omitempty"`      // 请求参数模板：GET请求作为查询参数，POST请求作为JSON请求体字段
+	Headers       map[string]string `json:"headers,omitempty"`     // 额外请求头模板(API密钥使用AuthScheme/AuthHeader配置)
+	AmountOutPath string            `json:"amount_out_path"`       // 响应中输出数量(最小单位整数)的JSONPath
+	GasPath       string            `json:"gas_path,omitempty"`    // 响应中Gas估算的JSONPath，为空或缺失时使用DefaultGas
+	ErrorPath     string            `json:"error_path,omitempty"`  // 错误响应中错误信息的JSONPath
+	DefaultGas    uint64            `json:"default_gas,omitempty"` // 默认Gas估算
+	HealthURL     string            `json:"health_url,omitempty"`  // 健康检查URL模板(支持{base_url}和{chain_id})，为空时不做健康检查
+}
+
+// Clone 创建独立的HTTP JSON适配器配置副本
+func (c *HTTPJSONConfig) Clone() *HTTPJSONConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	if c.Params != nil {
+		clone.Params = make(map[string]string, len(c.Params))
+		for name, value := range c.Params {
+			clone.Params[name] = value
+		}
+	}
+	if c.Headers != nil {
+		clone.Headers = make(map[string]string, len(c.Headers))
+		for name, value := range c.Headers {
+			clone.Headers[name] = value
+		}
+	}
+	return &clone
+}
+
+// DEXContracts DEX合约地址
+type DEXContracts struct {
+	Factory string `json:"factory"` // 工厂合约(查询池子地址)
+	Quoter  string `json:"quoter"`  // 报价合约(Uniswap V3为QuoterV2，Uniswap V2为Router02)
+}
+
 // ========================================
 // 聚合策略配置
 // ========================================
@@ -136,6 +403,21 @@ type AggregationStrategy struct {
 
 	// 决策阈值
 	CompositeScoreThreshold decimal.Decimal `json:"composite_score_threshold"` // 综合评分阈值
+
+	// 报价评分配置(weighted排序策略)
+	ScorePriceWeight      decimal.Decimal      `json:"score_price_weight"`      // 价格评分权重
+	ScoreGasWeight        decimal.Decimal      `json:"score_gas_weight"`        // Gas效率评分权重
+	ScoreConfidenceWeight decimal.Decimal      `json:"score_confidence_weight"` // 置信度评分权重
+	ScoreTimeWeight       decimal.Decimal      `json:"score_time_weight"`       // 响应时间评分权重
+	ResponseTimeBuckets   []ResponseTimeBucket `json:"response_time_buckets"`   // 响应时间评分分段(按MaxResponseTime升序)
+	SlowResponseScore     decimal.Decimal      `json:"slow_response_score"`     // 超出所有分段时的响应时间评分
+}
+
+// ResponseTimeBucket 响应时间评分分段
+// 响应时间不超过MaxResponseTime时得分为Score
+type ResponseTimeBucket struct {
+	MaxResponseTime time.Duration   `json:"max_response_time"` // 分段上限
+	Score           decimal.Decimal `json:"score"`             // 分段评分
 }
 
 // ========================================
@@ -213,15 +495,27 @@ func (e *RouterError) Error() string {
 
 // 预定义错误代码
 const (
-	ErrCodeInvalidRequest        = "INVALID_REQUEST"        // 无效请求
-	ErrCodeProviderTimeout       = "PROVIDER_TIMEOUT"       // 聚合器超时
-	ErrCodeProviderError         = "PROVIDER_ERROR"         // 聚合器错误
-	ErrCodeNoValidQuotes         = "NO_VALID_QUOTES"        // 无有效报价
-	ErrCodeCacheError            = "CACHE_ERROR"            // 缓存错误
-	ErrCodeInternalError         = "INTERNAL_ERROR"         // 内部错误
-	ErrCodeRateLimitExceeded     = "RATE_LIMIT_EXCEEDED"    // 频率限制
-	ErrCodeUnsupportedChain      = "UNSUPPORTED_CHAIN"      // 不支持的链
-	ErrCodeInsufficientLiquidity = "INSUFFICIENT_LIQUIDITY" // 流动性不足
+	ErrCodeInvalidRequest         = "INVALID_REQUEST"          // 无效请求
+	ErrCodeProviderTimeout        = "PROVIDER_TIMEOUT"         // 聚合器超时
+	ErrCodeProviderError          = "PROVIDER_ERROR"           // 聚合器错误
+	ErrCodeProviderRejected       = "PROVIDER_REJECTED"        // 聚合器拒绝请求(4xx，如交易对无路由)
+	ErrCodeNoValidQuotes          = "NO_VALID_QUOTES"          // 无有效报价
+	ErrCodeCacheError             = "CACHE_ERROR"              // 缓存错误
+	ErrCodeInternalError          = "INTERNAL_ERROR"           // 内部错误
+	ErrCodeRateLimitExceeded      = "RATE_LIMIT_EXCEEDED"      // 频率限制
+	ErrCodeUnsupportedChain       = "UNSUPPORTED_CHAIN"        // 不支持的链
+	ErrCodeInsufficientLiquidity  = "INSUFFICIENT_LIQUIDITY"   // 流动性不足
+	ErrCodeProviderCancelled      = "PROVIDER_CANCELLED"       // 聚合器请求被提前取消
+	ErrCodeCircuitOpen            = "CIRCUIT_OPEN"             // 聚合器已熔断
+	ErrCodeUnauthorized           = "UNAUTHORIZED"             // 未授权
+	ErrCodeReloadFailed           = "RELOAD_FAILED"            // 配置重新加载失败
+	ErrCodeReloadDisabled         = "RELOAD_DISABLED"          // 配置热加载未启用
+	ErrCodeProviderNotFound       = "PROVIDER_NOT_FOUND"       // 聚合器不存在或未启用
+	ErrCodeSwapNotSupported       = "SWAP_NOT_SUPPORTED"       // 聚合器不支持构建交易
+	ErrCodeSwapBuildFailed        = "SWAP_BUILD_FAILED"        // 交易构建失败
+	ErrCodePriceImpactTooHigh     = "PRICE_IMPACT_TOO_HIGH"    // 价格冲击超过上限
+	ErrCodeUnsupportedQuoteSide   = "UNSUPPORTED_QUOTE_SIDE"   // 聚合器不支持该报价方向
+	ErrCodeNativeTokenUnsupported = "NATIVE_TOKEN_UNSUPPORTED" // 聚合器不支持该原生代币报价
 )
 
 // ========================================
@@ -230,20 +524,27 @@ const (
 
 // Config 智能路由服务配置
 type Config struct {
-	Server     ServerConfig        `json:"server"`     // 服务器配置
-	Redis      RedisConfig         `json:"redis"`      // Redis配置
-	Providers  []ProviderConfig    `json:"providers"`  // 聚合器配置
-	Strategy   AggregationStrategy `json:"strategy"`   // 聚合策略
-	Cache      CacheConfig         `json:"cache"`      // 缓存配置
-	Monitoring MonitoringConfig    `json:"monitoring"` // 监控配置
+	Server         ServerConfig         `json:"server"`          // 服务器配置
+	Redis          RedisConfig          `json:"redis"`           // Redis配置
+	Providers      []ProviderConfig     `json:"providers"`       // 聚合器配置
+	Strategy       AggregationStrategy  `json:"strategy"`        // 聚合策略
+	Cache          CacheConfig          `json:"cache"`           // 缓存配置
+	Monitoring     MonitoringConfig     `json:"monitoring"`      // 监控配置
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker"` // 熔断器配置
+	ProviderReload ProviderReloadConfig `json:"provider_reload"` // 聚合器配置热加载
+	Ranking        RankingConfig        `json:"ranking"`         // 报价排序配置
+	PriceImpact    PriceImpactConfig    `json:"price_impact"`    // 价格冲击配置
+	SplitRouting   SplitRoutingConfig   `json:"split_routing"`   // 拆单路由配置
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port        int    `json:"port"`        // 监听端口
-	Environment string `json:"environment"` // 运行环境
-	LogLevel    string `json:"log_level"`   // 日志级别
-	Debug       bool   `json:"debug"`       // 调试模式
+	Port         int    `json:"port"`          // 监听端口
+	Environment  string `json:"environment"`   // 运行环境
+	LogLevel     string `json:"log_level"`     // 日志级别
+	Debug        bool   `json:"debug"`         // 调试模式
+	AdminToken   string `json:"-"`             // 管理接口令牌(为空时不开放管理接口)
+	MockAdapters bool   `json:"mock_adapters"` // 开发模式：未注册的适配器类型使用模拟适配器代替启动失败(生产环境禁止)
 }
 
 // RedisConfig Redis配置
@@ -257,12 +558,60 @@ type RedisConfig struct {
 
 // CacheConfig 缓存配置
 type CacheConfig struct {
+	Backend         string        `json:"backend"`          // 缓存后端 (redis/memory)
 	DefaultTTL      time.Duration `json:"default_ttl"`      // 默认TTL
 	MaxEntries      int           `json:"max_entries"`      // 最大缓存条目
 	CleanupInterval time.Duration `json:"cleanup_interval"` // 清理间隔
 	PrefixKey       string        `json:"prefix_key"`       // 缓存键前缀
 }
 
+// CircuitBreakerConfig 聚合器熔断器配置
+// 连续失败达到阈值后熔断，冷却期结束后通过HealthCheck探测再放行试探请求
+type CircuitBreakerConfig struct {
+	Enabled            bool          `json:"enabled"`              // 是否启用熔断
+	FailureThreshold   int           `json:"failure_threshold"`    // 触发熔断的连续失败次数
+	SuccessThreshold   int           `json:"success_threshold"`    // 半开状态下恢复所需的连续成功次数
+	HalfOpenMaxCalls   int           `json:"half_open_max_calls"`  // 半开状态下同时放行的试探请求数
+	CoolDown           time.Duration `json:"cool_down"`            // 熔断冷却时间
+	HealthCheckTimeout time.Duration `json:"health_check_timeout"` // 探测健康检查超时
+}
+
+// ProviderReloadConfig 聚合器配置热加载配置
+// 定期从数据库aggregators/aggregator_chains表重新加载聚合器配置
+type ProviderReloadConfig struct {
+	Enabled  bool          `json:"enabled"`  // 是否启用数据库热加载
+	Interval time.Duration `json:"interval"` // 轮询间隔
+}
+
+// RankingConfig 报价排序配置
+// net_of_gas策略需要数据库中的chains/tokens数据(RPC节点、建议Gas价格、代币USD价格)，
+// 数据不可用时该请求回退到weighted策略
+type RankingConfig struct {
+	DefaultStrategy  string        `json:"default_strategy"`    // 默认排序策略
+	GasPriceTimeout  time.Duration `json:"gas_price_timeout"`   // 从RPC节点获取Gas价格的超时时间
+	GasPriceCacheTTL time.Duration `json:"gas_price_cache_ttl"` // 链Gas价格缓存时间
+	PriceCacheTTL    time.Duration `json:"price_cache_ttl"`     // 代币价格和链信息缓存时间
+}
+
+// PriceImpactConfig 价格冲击配置
+// 参考价格优先由tokens表USD价格计算，价格不可用时按请求金额的SpotSizeRatio向聚合器获取小额报价
+type PriceImpactConfig struct {
+	Enabled       bool            `json:"enabled"`         // 是否计算价格冲击
+	SpotSizeRatio decimal.Decimal `json:"spot_size_ratio"` // 小额报价金额占请求金额的比例
+	SpotTimeout   time.Duration   `json:"spot_timeout"`    // 小额报价超时时间
+	CacheTTL      time.Duration   `json:"cache_ttl"`       // 参考价格缓存时间
+	MaxImpact     decimal.Decimal `json:"max_impact"`      // 价格冲击上限(0表示不限制)
+	Action        string          `json:"action"`          // 超过上限时的处理方式(flag/reject)
+}
+
+// SplitRoutingConfig 拆单路由配置
+type SplitRoutingConfig struct {
+	Enabled bool          `json:"enabled"`  // 是否允许请求拆单路由
+	Parts   int           `json:"parts"`    // 拆分粒度，如4表示按25%/50%/75%/100%报价
+	MaxLegs int           `json:"max_legs"` // 最多拆分给几个聚合器
+	Timeout time.Duration `json:"timeout"`  // 分量报价超时时间
+}
+
 // MonitoringConfig 监控配置
 type MonitoringConfig struct {
 	MetricsEnabled  bool          `json:"metrics_enabled"`   // 是否启用指标
@@ -310,6 +659,37 @@ type ProviderHealth struct {
 	ResponseTime time.Duration   `json:"response_time"`           // 响应时间
 	SuccessRate  decimal.Decimal `json:"success_rate"`            // 成功率
 	ErrorMessage string          `json:"error_message,omitempty"` // 错误信息
+	CircuitState string          `json:"circuit_state"`           // 熔断器状态
+}
+
+// ProviderStatus 聚合器运行状态
+// 包含配置摘要和熔断器的实时状态
+type ProviderStatus struct {
+	Name                string          `json:"name"`                    // 聚合器名称
+	DisplayName         string          `json:"display_name"`            // 显示名称
+	SupportedChains     []uint          `json:"supported_chains"`        // 支持的链ID列表
+	Timeout             time.Duration   `json:"timeout"`                 // 请求超时时间
+	CircuitState        string          `json:"circuit_state"`           // 熔断器状态
+	ConsecutiveFailures int             `json:"consecutive_failures"`    // 连续失败次数
+	TotalRequests       int64           `json:"total_requests"`          // 总请求数
+	FailedRequests      int64           `json:"failed_requests"`         // 失败请求数
+	RejectedRequests    int64           `json:"rejected_requests"`       // 熔断拒绝的请求数
+	SuccessRate         decimal.Decimal `json:"success_rate"`            // 成功率
+	LastResponseTime    time.Duration   `json:"last_response_time"`      // 最近一次响应时间
+	LastError           string          `json:"last_error,omitempty"`    // 最近一次错误
+	LastCheckedAt       time.Time       `json:"last_checked_at"`         // 最近一次调用或探测时间
+	OpenedAt            *time.Time      `json:"opened_at,omitempty"`     // 熔断开启时间
+	NextProbeAt         *time.Time      `json:"next_probe_at,omitempty"` // 下次探测时间
+}
+
+// ProviderReloadResult 聚合器配置重新加载结果
+type ProviderReloadResult struct {
+	Added      []string  `json:"added"`            // 新增的聚合器
+	Removed    []string  `json:"removed"`          // 移除的聚合器
+	Updated    []string  `json:"updated"`          // 配置已更新的聚合器
+	Unchanged  []string  `json:"unchanged"`        // 配置未变化的聚合器
+	Failed     []string  `json:"failed,omitempty"` // 创建或更新失败的聚合器
+	ReloadedAt time.Time `json:"reloaded_at"`      // 重新加载时间
 }
 
 // CacheHealth 缓存健康状态
@@ -338,6 +718,36 @@ const (
 	ProviderParaswap = "paraswap" // ParaSwap聚合器
 	Provider0x       = "0x"       // 0x Protocol
 	ProviderCowswap  = "cowswap"  // CoW Protocol
+
+	// 直连DEX(通过链RPC节点eth_call报价)
+	ProviderUniswapV3 = "uniswap_v3" // Uniswap V3 (QuoterV2)
+	ProviderUniswapV2 = "uniswap_v2" // Uniswap V2 (Router02)
+)
+
+// 通用适配器类型(ProviderConfig.Adapter)，可用于任意名称的聚合器
+const (
+	AdapterHTTPJSON = "http_json" // 声明式HTTP JSON适配器
+)
+
+// IsDEXProvider 是否为直连DEX适配器
+func IsDEXProvider(name string) bool {
+	return name == ProviderUniswapV3 || name == ProviderUniswapV2
+}
+
+// 聚合器API密钥认证方式
+const (
+	AuthSchemeBearer = "bearer" // Authorization: Bearer <key>
+	AuthSchemeHeader = "header" // <AuthHeader>: <key>
+	AuthSchemeNone   = "none"   // 不发送API密钥
+)
+
+// 默认重试退避参数
+const (
+	DefaultRetryInitialBackoff = 100 * time.Millisecond
+	DefaultRetryMaxBackoff     = 2 * time.Second
+	DefaultRetryMultiplier     = 2.0
+	DefaultRetryJitter         = 0.2
+	DefaultRetryAfterMax       = 3 * time.Second
 )
 
 // 聚合策略类型
@@ -347,11 +757,85 @@ const (
 	StrategyFast        = "fast"        // 快速响应策略
 )
 
+// 渐进式聚合决策规则
+const (
+	DecisionAllResponded       = "all_responded"       // 所有聚合器均已响应
+	DecisionOptimalProviders   = "optimal_providers"   // 达到最优响应数量
+	DecisionPreferredProviders = "preferred_providers" // 快速响应窗口后达到理想响应数量
+	DecisionCompositeScore     = "composite_score"     // 综合评分超过阈值
+	DecisionMaxWait            = "max_wait"            // 达到最大等待时间
+	DecisionEmergencyTimeout   = "emergency_timeout"   // 达到紧急超时时间
+	DecisionContextCancelled   = "context_cancelled"   // 请求上下文被取消
+)
+
+// 熔断器状态
+const (
+	CircuitClosed   = "closed"    // 正常放行
+	CircuitOpen     = "open"      // 熔断中，跳过该聚合器
+	CircuitHalfOpen = "half_open" // 探测通过，放行试探请求
+)
+
+// 流式报价事件类型(SSE event字段)
+const (
+	StreamEventQuote = "quote" // 单个聚合器报价
+	StreamEventBest  = "best"  // 最终聚合结果
+	StreamEventError = "error" // 聚合失败
+)
+
+// 报价排序策略
+const (
+	RankingStrategyMaxOutput     = "max_output"     // 按输出数量排序
+	RankingStrategyNetOfGas      = "net_of_gas"     // 按扣除Gas费用后的净输出数量排序
+	RankingStrategyWeighted      = "weighted"       // 价格、Gas、置信度、响应时间加权综合评分
+	RankingStrategyLowestLatency = "lowest_latency" // 按响应时间排序
+)
+
+// 价格冲击超过上限时的处理方式
+const (
+	PriceImpactActionFlag   = "flag"   // 标记报价但仍参与排序
+	PriceImpactActionReject = "reject" // 拒绝报价，不参与排序
+)
+
+// 参考价格来源
+const (
+	ReferenceSourceUSDPrice  = "usd_price"  // tokens表USD价格
+	ReferenceSourceSpotQuote = "spot_quote" // 聚合器小额报价
+)
+
+// 报价方向
+const (
+	QuoteSideSell = "sell" // 卖出固定数量(exact-input)
+	QuoteSideBuy  = "buy"  // 买入固定数量(exact-output)
+)
+
+// 代币元数据来源
+const (
+	TokenMetadataSourceRequest  = "request"  // 请求中指定的小数位数
+	TokenMetadataSourceRegistry = "registry" // tokens表登记的代币信息
+)
+
+// MaxTokenDecimals 请求可指定的最大代币小数位数
+const MaxTokenDecimals = 36
+
+// Gas价格来源
+const (
+	GasPriceSourceRequest      = "request"       // 请求中指定的Gas价格
+	GasPriceSourceRPC          = "rpc"           // 链RPC节点eth_gasPrice
+	GasPriceSourceChainDefault = "chain_default" // chains表中的建议Gas价格
+)
+
+// 交易构建结果类型
+const (
+	SwapKindTransaction = "transaction" // 链上交易，钱包直接发送
+	SwapKindOrder       = "order"       // 链下订单，签名后提交给聚合器
+)
+
 // 缓存键前缀
 const (
 	CacheKeyQuote   = "quote:"   // 报价缓存前缀
 	CacheKeyMetrics = "metrics:" // 指标缓存前缀
 	CacheKeyHealth  = "health:"  // 健康状态缓存前缀
+	CacheKeyRefRate = "refrate:" // 参考价格缓存前缀
 )
 
 // HTTP状态码
@@ -0,0 +1,63 @@
+// Package cache 智能路由缓存管理
+// 定义统一的缓存管理接口，提供Redis和进程内LRU两种实现
+// 所有值均以JSON序列化存储，保证跨进程读写的类型一致性
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrCacheMiss 缓存未命中
+// Get在键不存在或已过期时返回该错误
+var ErrCacheMiss = errors.New("cache: key not found")
+
+// CacheManager 缓存管理器接口
+// RouterService只依赖该接口，具体后端由启动配置决定
+type CacheManager interface {
+	// Get 读取缓存并反序列化到dest（dest必须为指针）
+	// 键不存在时返回ErrCacheMiss
+	Get(key string, dest interface{}) error
+
+	// Set 序列化value并写入缓存，ttl<=0表示不过期
+	Set(key string, value interface{}, ttl time.Duration) error
+
+	// Delete 删除缓存键，键不存在时不返回错误
+	Delete(key string) error
+
+	// GetStats 获取缓存运行统计
+	GetStats() (*CacheStats, error)
+
+	// Close 释放缓存资源
+	Close() error
+}
+
+// CacheStats 缓存统计信息
+type CacheStats struct {
+	Backend      string          `json:"backend"`       // 缓存后端 (redis/memory)
+	Hits         int64           `json:"hits"`          // 命中次数
+	Misses       int64           `json:"misses"`        // 未命中次数
+	Sets         int64           `json:"sets"`          // 写入次数
+	Deletes      int64           `json:"deletes"`       // 删除次数
+	Errors       int64           `json:"errors"`        // 错误次数
+	HitRate      decimal.Decimal `json:"hit_rate"`      // 命中率
+	TotalEntries int             `json:"total_entries"` // 当前条目数
+	MemoryUsage  uint64          `json:"memory_usage"`  // 内存使用量(字节)
+}
+
+// 缓存后端类型
+const (
+	BackendRedis  = "redis"  // Redis缓存
+	BackendMemory = "memory" // 进程内LRU缓存
+)
+
+// calculateHitRate 计算命中率
+func calculateHitRate(hits, misses int64) decimal.Decimal {
+	total := hits + misses
+	if total == 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(hits).Div(decimal.NewFromInt(total)).Round(4)
+}
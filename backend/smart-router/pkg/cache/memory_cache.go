@@ -0,0 +1,226 @@
+// Package cache 进程内LRU缓存实现
+// 用于本地开发和测试，与Redis实现保持相同的JSON序列化语义
+package cache
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryCache 进程内LRU缓存管理器
+// 超过最大条目数时淘汰最久未使用的条目，过期条目由后台协程定期清理
+type MemoryCache struct {
+	maxEntries int                      // 最大条目数(<=0表示不限制)
+	prefix     string                   // 缓存键前缀
+	entries    map[string]*list.Element // 键 -> LRU链表节点
+	lru        *list.List               // LRU链表，表头为最近使用
+	mutex      sync.Mutex               // 读写锁
+	logger     *logrus.Logger           // 日志记录器
+	stopChan   chan struct{}            // 清理协程停止信号
+	closeOnce  sync.Once                // 保证只关闭一次
+
+	hits    int64 // 命中次数
+	misses  int64 // 未命中次数
+	sets    int64 // 写入次数
+	deletes int64 // 删除次数
+	errors  int64 // 错误次数
+	memory  int64 // 已用内存(序列化数据字节数)
+}
+
+// memoryEntry LRU缓存条目
+type memoryEntry struct {
+	key       string    // 完整缓存键
+	data      []byte    // JSON序列化数据
+	expiresAt time.Time // 过期时间(零值表示不过期)
+}
+
+// NewMemoryCache 创建进程内LRU缓存管理器
+// cleanupInterval>0时启动后台过期清理协程
+func NewMemoryCache(maxEntries int, cleanupInterval time.Duration, prefix string, logger *logrus.Logger) *MemoryCache {
+	c := &MemoryCache{
+		maxEntries: maxEntries,
+		prefix:     prefix,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		logger:     logger,
+		stopChan:   make(chan struct{}),
+	}
+
+	if cleanupInterval > 0 {
+		go c.cleanupLoop(cleanupInterval)
+	}
+
+	logger.Infof("内存LRU缓存初始化完成: maxEntries=%d, cleanupInterval=%v", maxEntries, cleanupInterval)
+	return c
+}
+
+// Get 读取缓存
+func (c *MemoryCache) Get(key string, dest interface{}) error {
+	c.mutex.Lock()
+	element, ok := c.entries[c.prefix+key]
+	if !ok {
+		c.misses++
+		c.mutex.Unlock()
+		return ErrCacheMiss
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if entry.expired(time.Now()) {
+		c.removeElement(element)
+		c.misses++
+		c.mutex.Unlock()
+		return ErrCacheMiss
+	}
+
+	c.lru.MoveToFront(element)
+	data := entry.data
+	c.mutex.Unlock()
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		c.mutex.Lock()
+		c.errors++
+		c.mutex.Unlock()
+		return fmt.Errorf("反序列化缓存数据失败: %w", err)
+	}
+
+	c.mutex.Lock()
+	c.hits++
+	c.mutex.Unlock()
+	return nil
+}
+
+// Set 写入缓存
+func (c *MemoryCache) Set(key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.mutex.Lock()
+		c.errors++
+		c.mutex.Unlock()
+		return fmt.Errorf("序列化缓存数据失败: %w", err)
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fullKey := c.prefix + key
+	if element, ok := c.entries[fullKey]; ok {
+		entry := element.Value.(*memoryEntry)
+		c.memory += int64(len(data)) - int64(len(entry.data))
+		entry.data = data
+		entry.expiresAt = expiresAt
+		c.lru.MoveToFront(element)
+	} else {
+		element := c.lru.PushFront(&memoryEntry{key: fullKey, data: data, expiresAt: expiresAt})
+		c.entries[fullKey] = element
+		c.memory += int64(len(data))
+	}
+	c.sets++
+
+	// 超出容量时淘汰最久未使用的条目
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+
+	return nil
+}
+
+// Delete 删除缓存
+func (c *MemoryCache) Delete(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[c.prefix+key]; ok {
+		c.removeElement(element)
+	}
+	c.deletes++
+	return nil
+}
+
+// GetStats 获取缓存统计
+func (c *MemoryCache) GetStats() (*CacheStats, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return &CacheStats{
+		Backend:      BackendMemory,
+		Hits:         c.hits,
+		Misses:       c.misses,
+		Sets:         c.sets,
+		Deletes:      c.deletes,
+		Errors:       c.errors,
+		HitRate:      calculateHitRate(c.hits, c.misses),
+		TotalEntries: c.lru.Len(),
+		MemoryUsage:  uint64(c.memory),
+	}, nil
+}
+
+// Close 停止后台清理协程并清空缓存
+func (c *MemoryCache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stopChan)
+
+		c.mutex.Lock()
+		c.entries = make(map[string]*list.Element)
+		c.lru.Init()
+		c.memory = 0
+		c.mutex.Unlock()
+	})
+	return nil
+}
+
+// cleanupLoop 定期清理过期条目
+func (c *MemoryCache) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		case now := <-ticker.C:
+			if removed := c.removeExpired(now); removed > 0 {
+				c.logger.Debugf("内存缓存清理过期条目: %d", removed)
+			}
+		}
+	}
+}
+
+// removeExpired 删除所有过期条目，返回删除数量
+func (c *MemoryCache) removeExpired(now time.Time) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	removed := 0
+	for element := c.lru.Back(); element != nil; {
+		prev := element.Prev()
+		if element.Value.(*memoryEntry).expired(now) {
+			c.removeElement(element)
+			removed++
+		}
+		element = prev
+	}
+	return removed
+}
+
+// removeElement 移除LRU节点（调用方需持有锁）
+func (c *MemoryCache) removeElement(element *list.Element) {
+	entry := element.Value.(*memoryEntry)
+	c.lru.Remove(element)
+	delete(c.entries, entry.key)
+	c.memory -= int64(len(entry.data))
+}
+
+// expired 判断条目是否过期
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
@@ -0,0 +1,179 @@
+// Package cache Redis缓存实现
+// 基于go-redis的分布式缓存，多个智能路由实例共享报价缓存
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// redisOperationTimeout 单次Redis操作超时时间
+// 缓存不可用时不能拖慢报价主流程
+const redisOperationTimeout = 500 * time.Millisecond
+
+// RedisCache Redis缓存管理器
+type RedisCache struct {
+	client *redis.Client  // Redis客户端
+	prefix string         // 缓存键前缀
+	logger *logrus.Logger // 日志记录器
+
+	hits    int64 // 命中次数
+	misses  int64 // 未命中次数
+	sets    int64 // 写入次数
+	deletes int64 // 删除次数
+	errors  int64 // 错误次数
+}
+
+// NewRedisCache 创建Redis缓存管理器
+// 建立连接后立即执行PING，连接失败时返回错误
+func NewRedisCache(cfg *types.RedisConfig, prefix string, logger *logrus.Logger) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接Redis失败: %w", err)
+	}
+
+	logger.Infof("Redis缓存连接成功: %s:%d, db=%d", cfg.Host, cfg.Port, cfg.DB)
+
+	return &RedisCache{
+		client: client,
+		prefix: prefix,
+		logger: logger,
+	}, nil
+}
+
+// Get 读取缓存
+func (c *RedisCache) Get(key string, dest interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		atomic.AddInt64(&c.misses, 1)
+		return ErrCacheMiss
+	}
+	if err != nil {
+		atomic.AddInt64(&c.errors, 1)
+		return fmt.Errorf("读取Redis缓存失败: %w", err)
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		atomic.AddInt64(&c.errors, 1)
+		return fmt.Errorf("反序列化缓存数据失败: %w", err)
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+	return nil
+}
+
+// Set 写入缓存
+func (c *RedisCache) Set(key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		atomic.AddInt64(&c.errors, 1)
+		return fmt.Errorf("序列化缓存数据失败: %w", err)
+	}
+
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, c.prefix+key, data, ttl).Err(); err != nil {
+		atomic.AddInt64(&c.errors, 1)
+		return fmt.Errorf("写入Redis缓存失败: %w", err)
+	}
+
+	atomic.AddInt64(&c.sets, 1)
+	return nil
+}
+
+// Delete 删除缓存
+func (c *RedisCache) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		atomic.AddInt64(&c.errors, 1)
+		return fmt.Errorf("删除Redis缓存失败: %w", err)
+	}
+
+	atomic.AddInt64(&c.deletes, 1)
+	return nil
+}
+
+// GetStats 获取缓存统计
+// 条目数和内存使用量来自Redis服务端，该DB专供智能路由使用
+func (c *RedisCache) GetStats() (*CacheStats, error) {
+	hits := atomic.LoadInt64(&c.hits)
+	misses := atomic.LoadInt64(&c.misses)
+
+	stats := &CacheStats{
+		Backend: BackendRedis,
+		Hits:    hits,
+		Misses:  misses,
+		Sets:    atomic.LoadInt64(&c.sets),
+		Deletes: atomic.LoadInt64(&c.deletes),
+		Errors:  atomic.LoadInt64(&c.errors),
+		HitRate: calculateHitRate(hits, misses),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	size, err := c.client.DBSize(ctx).Result()
+	if err != nil {
+		return stats, fmt.Errorf("查询Redis条目数失败: %w", err)
+	}
+	stats.TotalEntries = int(size)
+
+	info, err := c.client.Info(ctx, "memory").Result()
+	if err != nil {
+		return stats, fmt.Errorf("查询Redis内存信息失败: %w", err)
+	}
+	stats.MemoryUsage = parseUsedMemory(info)
+
+	return stats, nil
+}
+
+// Close 关闭Redis连接
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+// parseUsedMemory 从INFO memory输出中解析used_memory字段
+func parseUsedMemory(info string) uint64 {
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "used_memory:") {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimPrefix(line, "used_memory:"), 10, 64)
+		if err == nil {
+			return value
+		}
+	}
+	return 0
+}
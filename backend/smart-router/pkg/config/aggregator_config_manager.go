@@ -4,6 +4,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -36,16 +37,21 @@ type DatabaseAggregator struct {
 	RetryCount    int     `gorm:"column:retry_count"`
 	SuccessRate   float64 `gorm:"column:success_rate"`
 	AvgResponseMS int     `gorm:"column:avg_response_ms"`
+	AdapterType   string  `gorm:"column:adapter_type"`   // 适配器类型，为空时使用name
+	AdapterConfig string  `gorm:"column:adapter_config"` // 适配器配置JSON(如http_json适配器的请求模板和JSONPath)
 }
 
 func (DatabaseAggregator) TableName() string { return "aggregators" }
 
 // DatabaseChain 数据库链模型
 type DatabaseChain struct {
-	ID       uint   `gorm:"primaryKey"`
-	ChainID  uint   `gorm:"column:chain_id"`
-	Name     string `gorm:"column:name"`
-	IsActive bool   `gorm:"column:is_active"`
+	ID           uint   `gorm:"primaryKey"`
+	ChainID      uint   `gorm:"column:chain_id"`
+	Name         string `gorm:"column:name"`
+	Symbol       string `gorm:"column:symbol"`         // 原生代币符号
+	RPCURL       string `gorm:"column:rpc_url"`        // RPC节点URL
+	GasPriceGwei int    `gorm:"column:gas_price_gwei"` // 建议Gas价格
+	IsActive     bool   `gorm:"column:is_active"`
 }
 
 func (DatabaseChain) TableName() string { return "chains" }
@@ -104,20 +110,26 @@ func (mgr *AggregatorConfigManager) LoadActiveProviders() ([]types.ProviderConfi
 			RetryCount:    dbAgg.RetryCount,
 			SuccessRate:   dbAgg.SuccessRate,
 			AvgResponseMS: dbAgg.AvgResponseMS,
+			AdapterType:   dbAgg.AdapterType,
+			AdapterConfig: dbAgg.AdapterConfig,
 		}
 
 		mgr.logger.Infof("📦 处理聚合器 %d/%d: ID=%d, Name=%s, DisplayName=%s, URL=%s",
 			i+1, len(dbAggregators), aggregator.ID, aggregator.Name, aggregator.DisplayName, aggregator.APIURL)
 
 		// 2. 查询支持的链（使用明确的ID）
-		supportedChains, err := mgr.loadSupportedChains(aggregator.ID, aggregator.Name)
+		supportedChains, chainRPCURLs, err := mgr.loadSupportedChains(aggregator.ID, aggregator.Name)
 		if err != nil {
 			mgr.logger.Warnf("⚠️ 跳过聚合器 %s (ID=%d): 加载支持链失败 - %v", aggregator.Name, aggregator.ID, err)
 			continue
 		}
 
 		// 3. 从环境变量加载敏感配置
-		envConfig := mgr.loadEnvironmentConfig(aggregator.Name)
+		adapterType := aggregator.AdapterType
+		if adapterType == "" {
+			adapterType = aggregator.Name
+		}
+		envConfig := mgr.loadEnvironmentConfig(aggregator.Name, adapterType)
 
 		// 4. 合并数据库配置 + 环境变量配置（使用独立的变量）
 		provider := types.ProviderConfig{
@@ -127,10 +139,36 @@ func (mgr *AggregatorConfigManager) LoadActiveProviders() ([]types.ProviderConfi
 			APIKey:          mgr.selectAPIKey(aggregator.APIKey, envConfig.APIKey),                 // 优先环境变量
 			Timeout:         mgr.selectTimeout(aggregator.TimeoutMS, envConfig.TimeoutMS),          // 优先环境变量
 			RetryCount:      mgr.selectRetryCount(aggregator.RetryCount, envConfig.RetryCount),     // 优先环境变量
+			AuthScheme:      envConfig.AuthScheme,                                                  // 环境变量，为空时使用适配器默认认证方式
+			AuthHeader:      envConfig.AuthHeader,                                                  // 环境变量
+			Retry:           envConfig.Retry,                                                       // 环境变量
 			Priority:        aggregator.Priority,                                                   // 数据库
 			Weight:          mgr.calculateWeight(aggregator.SuccessRate, aggregator.AvgResponseMS), // 数据库计算
 			IsActive:        aggregator.IsActive,                                                   // 数据库控制
 			SupportedChains: append([]uint{}, supportedChains...),                                  // 深拷贝，避免slice引用问题
+			Adapter:         aggregator.AdapterType,                                                // 数据库，为空时使用名称对应的适配器
+		}
+
+		// 5. 直连DEX适配器：环境变量未单独配置RPC节点的链使用chains表的rpc_url
+		if types.IsDEXProvider(adapterType) {
+			provider.DEX = envConfig.DEX
+			if provider.DEX.RPCURLs == nil {
+				provider.DEX.RPCURLs = make(map[uint]string)
+			}
+			for chainID, rpcURL := range chainRPCURLs {
+				if _, ok := provider.DEX.RPCURLs[chainID]; !ok && rpcURL != "" {
+					provider.DEX.RPCURLs[chainID] = rpcURL
+				}
+			}
+		}
+
+		// 6. 声明式HTTP JSON适配器：请求模板和JSONPath来自数据库adapter_config
+		if adapterType == types.AdapterHTTPJSON {
+			provider.HTTPJSON, err = mgr.parseHTTPJSONConfig(aggregator.AdapterConfig)
+			if err != nil {
+				mgr.logger.Warnf("⚠️ 跳过聚合器 %s (ID=%d): %v", aggregator.Name, aggregator.ID, err)
+				continue
+			}
 		}
 
 		providers = append(providers, provider)
@@ -146,15 +184,15 @@ func (mgr *AggregatorConfigManager) LoadActiveProviders() ([]types.ProviderConfi
 	return providers, nil
 }
 
-// loadSupportedChains 加载聚合器支持的链
-func (mgr *AggregatorConfigManager) loadSupportedChains(aggregatorID uint, aggregatorName string) ([]uint, error) {
+// loadSupportedChains 加载聚合器支持的链，同时返回各链的RPC节点URL(供直连DEX适配器使用)
+func (mgr *AggregatorConfigManager) loadSupportedChains(aggregatorID uint, aggregatorName string) ([]uint, map[uint]string, error) {
 	var chainRelations []DatabaseAggregatorChain
 	if err := mgr.db.Where("aggregator_id = ? AND is_active = ?", aggregatorID, true).Find(&chainRelations).Error; err != nil {
-		return nil, fmt.Errorf("查询聚合器链关系失败: %w", err)
+		return nil, nil, fmt.Errorf("查询聚合器链关系失败: %w", err)
 	}
 
 	if len(chainRelations) == 0 {
-		return nil, fmt.Errorf("聚合器 %s 没有配置支持的链", aggregatorName)
+		return nil, nil, fmt.Errorf("聚合器 %s 没有配置支持的链", aggregatorName)
 	}
 
 	// 获取链的外部ChainID
@@ -165,16 +203,18 @@ func (mgr *AggregatorConfigManager) loadSupportedChains(aggregatorID uint, aggre
 
 	var chains []DatabaseChain
 	if err := mgr.db.Where("id IN ? AND is_active = ?", chainIDs, true).Find(&chains).Error; err != nil {
-		return nil, fmt.Errorf("查询链信息失败: %w", err)
+		return nil, nil, fmt.Errorf("查询链信息失败: %w", err)
 	}
 
 	var supportedChains []uint
+	rpcURLs := make(map[uint]string, len(chains))
 	for _, chain := range chains {
 		supportedChains = append(supportedChains, chain.ChainID) // 使用外部ChainID
+		rpcURLs[chain.ChainID] = chain.RPCURL
 	}
 
 	mgr.logger.Debugf("📊 聚合器 %s 支持 %d 条链: %v", aggregatorName, len(supportedChains), supportedChains)
-	return supportedChains, nil
+	return supportedChains, rpcURLs, nil
 }
 
 // EnvironmentConfig 环境变量配置
@@ -183,10 +223,14 @@ type EnvironmentConfig struct {
 	TimeoutMS  int
 	RetryCount int
 	Enabled    bool
+	AuthScheme string
+	AuthHeader string
+	Retry      types.RetryPolicy
+	DEX        *types.DEXConfig // 直连DEX配置(仅直连DEX适配器加载)
 }
 
 // loadEnvironmentConfig 从环境变量加载聚合器配置
-func (mgr *AggregatorConfigManager) loadEnvironmentConfig(aggregatorName string) EnvironmentConfig {
+func (mgr *AggregatorConfigManager) loadEnvironmentConfig(aggregatorName, adapterType string) EnvironmentConfig {
 	// 根据聚合器名称确定环境变量前缀
 	var envPrefix string
 	switch aggregatorName {
@@ -207,6 +251,12 @@ func (mgr *AggregatorConfigManager) loadEnvironmentConfig(aggregatorName string)
 		TimeoutMS:  getEnvAsInt(envPrefix+"_TIMEOUT_MS", 0),
 		RetryCount: getEnvAsInt(envPrefix+"_RETRY_COUNT", 0),
 		Enabled:    getEnvAsBool(envPrefix+"_ENABLED", false),
+		AuthScheme: getEnv(envPrefix+"_AUTH_SCHEME", ""),
+		AuthHeader: getEnv(envPrefix+"_AUTH_HEADER", ""),
+		Retry:      loadRetryPolicy(envPrefix),
+	}
+	if types.IsDEXProvider(adapterType) {
+		config.DEX = loadDEXConfig(envPrefix)
 	}
 
 	mgr.logger.Debugf("🔧 环境变量配置 %s: APIKey=%s, Timeout=%dms, Retry=%d, Enabled=%t",
@@ -222,6 +272,19 @@ func (mgr *AggregatorConfigManager) loadEnvironmentConfig(aggregatorName string)
 	return config
 }
 
+// parseHTTPJSONConfig 解析数据库中http_json适配器的adapter_config
+// 模板和JSONPath的详细校验在创建适配器时进行
+func (mgr *AggregatorConfigManager) parseHTTPJSONConfig(adapterConfig string) (*types.HTTPJSONConfig, error) {
+	if strings.TrimSpace(adapterConfig) == "" {
+		return nil, fmt.Errorf("http_json适配器缺少adapter_config")
+	}
+	var config types.HTTPJSONConfig
+	if err := json.Unmarshal([]byte(adapterConfig), &config); err != nil {
+		return nil, fmt.Errorf("解析adapter_config失败: %w", err)
+	}
+	return &config, nil
+}
+
 // 配置选择器：优先使用环境变量，回退到数据库
 func (mgr *AggregatorConfigManager) selectAPIKey(dbKey, envKey string) string {
 	if envKey != "" {
@@ -280,8 +343,8 @@ func (mgr *AggregatorConfigManager) formatProviderSummary(provider types.Provide
 		apiKeyStatus = "已配置"
 	}
 
-	return fmt.Sprintf("%s(%s) | URL: %s | API Key: %s | 支持链: %d条 | 权重: %.2f",
-		provider.DisplayName, provider.Name, provider.BaseURL, apiKeyStatus,
+	return fmt.Sprintf("%s(%s) | 适配器: %s | URL: %s | API Key: %s | 支持链: %d条 | 权重: %.2f",
+		provider.DisplayName, provider.Name, provider.AdapterType(), provider.BaseURL, apiKeyStatus,
 		len(provider.SupportedChains), provider.Weight.InexactFloat64())
 }
 
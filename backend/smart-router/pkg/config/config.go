@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"defi-aggregator/smart-router/internal/types"
@@ -29,10 +30,12 @@ func Load() (*types.Config, error) {
 
 	config := &types.Config{
 		Server: types.ServerConfig{
-			Port:        getEnvAsInt("PORT", 0),  // 必填
-			Environment: getEnv("APP_ENV", ""),   // 必填
-			LogLevel:    getEnv("LOG_LEVEL", ""), // 必填
-			Debug:       getEnvAsBool("DEBUG", false),
+			Port:         getEnvAsInt("PORT", 0),  // 必填
+			Environment:  getEnv("APP_ENV", ""),   // 必填
+			LogLevel:     getEnv("LOG_LEVEL", ""), // 必填
+			Debug:        getEnvAsBool("DEBUG", false),
+			AdminToken:   getEnv("ADMIN_TOKEN", ""),
+			MockAdapters: getEnvAsBool("MOCK_ADAPTERS_ENABLED", false),
 		},
 		Redis: types.RedisConfig{
 			Host:     getEnv("REDIS_HOST", ""),     // 必填
@@ -44,6 +47,7 @@ func Load() (*types.Config, error) {
 		Providers: loadProviderConfigs(),
 		Strategy:  loadAggregationStrategy(),
 		Cache: types.CacheConfig{
+			Backend:         getEnv("CACHE_BACKEND", "redis"),
 			DefaultTTL:      getEnvAsDuration("CACHE_DEFAULT_TTL", 10*time.Second),
 			MaxEntries:      getEnvAsInt("CACHE_MAX_ENTRIES", 10000),
 			CleanupInterval: getEnvAsDuration("CACHE_CLEANUP_INTERVAL", 5*time.Minute),
@@ -55,6 +59,38 @@ func Load() (*types.Config, error) {
 			HealthCheckPath: getEnv("HEALTH_CHECK_PATH", "/health"),
 			StatsInterval:   getEnvAsDuration("STATS_INTERVAL", 1*time.Minute),
 		},
+		CircuitBreaker: types.CircuitBreakerConfig{
+			Enabled:            getEnvAsBool("CIRCUIT_BREAKER_ENABLED", true),
+			FailureThreshold:   getEnvAsInt("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 5),
+			SuccessThreshold:   getEnvAsInt("CIRCUIT_BREAKER_SUCCESS_THRESHOLD", 2),
+			HalfOpenMaxCalls:   getEnvAsInt("CIRCUIT_BREAKER_HALF_OPEN_MAX_CALLS", 2),
+			CoolDown:           getEnvAsDuration("CIRCUIT_BREAKER_COOL_DOWN", 30*time.Second),
+			HealthCheckTimeout: getEnvAsDuration("CIRCUIT_BREAKER_HEALTH_CHECK_TIMEOUT", 3*time.Second),
+		},
+		ProviderReload: types.ProviderReloadConfig{
+			Enabled:  getEnvAsBool("PROVIDER_RELOAD_ENABLED", false),
+			Interval: getEnvAsDuration("PROVIDER_RELOAD_INTERVAL", 30*time.Second),
+		},
+		Ranking: types.RankingConfig{
+			DefaultStrategy:  getEnv("QUOTE_RANKING_STRATEGY", types.RankingStrategyNetOfGas),
+			GasPriceTimeout:  getEnvAsDuration("GAS_PRICE_TIMEOUT", 2*time.Second),
+			GasPriceCacheTTL: getEnvAsDuration("GAS_PRICE_CACHE_TTL", 15*time.Second),
+			PriceCacheTTL:    getEnvAsDuration("TOKEN_PRICE_CACHE_TTL", time.Minute),
+		},
+		PriceImpact: types.PriceImpactConfig{
+			Enabled:       getEnvAsBool("PRICE_IMPACT_ENABLED", true),
+			SpotSizeRatio: decimal.NewFromFloat(getEnvAsFloat("PRICE_IMPACT_SPOT_SIZE_RATIO", 0.001)),
+			SpotTimeout:   getEnvAsDuration("PRICE_IMPACT_SPOT_TIMEOUT", 2*time.Second),
+			CacheTTL:      getEnvAsDuration("REFERENCE_PRICE_CACHE_TTL", 30*time.Second),
+			MaxImpact:     decimal.NewFromFloat(getEnvAsFloat("PRICE_IMPACT_MAX", 0.05)),
+			Action:        getEnv("PRICE_IMPACT_ACTION", types.PriceImpactActionFlag),
+		},
+		SplitRouting: types.SplitRoutingConfig{
+			Enabled: getEnvAsBool("SPLIT_ROUTING_ENABLED", false),
+			Parts:   getEnvAsInt("SPLIT_ROUTING_PARTS", 4),
+			MaxLegs: getEnvAsInt("SPLIT_ROUTING_MAX_LEGS", 3),
+			Timeout: getEnvAsDuration("SPLIT_ROUTING_TIMEOUT", 3*time.Second),
+		},
 	}
 
 	// 验证配置
@@ -76,6 +112,9 @@ func loadProviderConfigs() []types.ProviderConfig {
 			BaseURL:         getEnv("ONEINCH_API_URL", ""), // 必填，从全局配置读取
 			APIKey:          getEnv("ONEINCH_API_KEY", ""), // 从全局配置读取
 			Timeout:         getEnvAsDuration("ONEINCH_TIMEOUT", 3*time.Second),
+			AuthScheme:      getEnv("ONEINCH_AUTH_SCHEME", ""), // 为空时使用适配器默认认证方式
+			AuthHeader:      getEnv("ONEINCH_AUTH_HEADER", ""),
+			Retry:           loadRetryPolicy("ONEINCH"),
 			RetryCount:      getEnvAsInt("ONEINCH_RETRY_COUNT", 2),
 			Priority:        1,
 			Weight:          decimal.NewFromFloat(1.0),
@@ -90,6 +129,9 @@ func loadProviderConfigs() []types.ProviderConfig {
 			BaseURL:         getEnv("PARASWAP_API_URL", ""), // 必填，从全局配置读取
 			APIKey:          getEnv("PARASWAP_API_KEY", ""), // 从全局配置读取
 			Timeout:         getEnvAsDuration("PARASWAP_TIMEOUT", 4*time.Second),
+			AuthScheme:      getEnv("PARASWAP_AUTH_SCHEME", ""), // 为空时使用适配器默认认证方式
+			AuthHeader:      getEnv("PARASWAP_AUTH_HEADER", ""),
+			Retry:           loadRetryPolicy("PARASWAP"),
 			RetryCount:      getEnvAsInt("PARASWAP_RETRY_COUNT", 2),
 			Priority:        2,
 			Weight:          decimal.NewFromFloat(0.9),
@@ -104,6 +146,9 @@ func loadProviderConfigs() []types.ProviderConfig {
 			BaseURL:         getEnv("ZRX_API_URL", ""), // 必填，从全局配置读取
 			APIKey:          getEnv("ZRX_API_KEY", ""), // 从全局配置读取
 			Timeout:         getEnvAsDuration("ZRX_TIMEOUT", 5*time.Second),
+			AuthScheme:      getEnv("ZRX_AUTH_SCHEME", ""), // 为空时使用适配器默认认证方式
+			AuthHeader:      getEnv("ZRX_AUTH_HEADER", ""),
+			Retry:           loadRetryPolicy("ZRX"),
 			RetryCount:      getEnvAsInt("ZRX_RETRY_COUNT", 2),
 			Priority:        3,
 			Weight:          decimal.NewFromFloat(0.8),
@@ -118,17 +163,161 @@ func loadProviderConfigs() []types.ProviderConfig {
 			BaseURL:         getEnv("COW_API_URL", ""), // 必填，从全局配置读取
 			APIKey:          getEnv("COW_API_KEY", ""), // 从全局配置读取
 			Timeout:         getEnvAsDuration("COW_TIMEOUT", 6*time.Second),
+			AuthScheme:      getEnv("COW_AUTH_SCHEME", ""), // 为空时使用适配器默认认证方式
+			AuthHeader:      getEnv("COW_AUTH_HEADER", ""),
+			Retry:           loadRetryPolicy("COW"),
 			RetryCount:      getEnvAsInt("COW_RETRY_COUNT", 1),
 			Priority:        4,
 			Weight:          decimal.NewFromFloat(0.7),
 			IsActive:        getEnvAsBool("COW_ENABLED", false),
 			SupportedChains: []uint{1, 11155111}, // Ethereum, Sepolia
 		},
+
+		// Uniswap V3直连DEX配置（BaseURL为默认链RPC节点）
+		{
+			Name:            types.ProviderUniswapV3,
+			DisplayName:     "Uniswap V3",
+			BaseURL:         getEnv("UNISWAP_V3_RPC_URL", ""), // 可选，未配置时需通过UNISWAP_V3_RPC_URLS按链配置
+			APIKey:          getEnv("UNISWAP_V3_API_KEY", ""), // RPC节点服务商密钥(可选)
+			Timeout:         getEnvAsDuration("UNISWAP_V3_TIMEOUT", 3*time.Second),
+			AuthScheme:      getEnv("UNISWAP_V3_AUTH_SCHEME", ""),
+			AuthHeader:      getEnv("UNISWAP_V3_AUTH_HEADER", ""),
+			Retry:           loadRetryPolicy("UNISWAP_V3"),
+			RetryCount:      getEnvAsInt("UNISWAP_V3_RETRY_COUNT", 1),
+			Priority:        5,
+			Weight:          decimal.NewFromFloat(0.6),
+			IsActive:        getEnvAsBool("UNISWAP_V3_ENABLED", false),
+			SupportedChains: []uint{1, 10, 137, 8453, 42161, 11155111}, // Ethereum, Optimism, Polygon, Base, Arbitrum, Sepolia
+			DEX:             loadDEXConfig("UNISWAP_V3"),
+		},
+
+		// Uniswap V2直连DEX配置（BaseURL为默认链RPC节点）
+		{
+			Name:            types.ProviderUniswapV2,
+			DisplayName:     "Uniswap V2",
+			BaseURL:         getEnv("UNISWAP_V2_RPC_URL", ""), // 可选，未配置时需通过UNISWAP_V2_RPC_URLS按链配置
+			APIKey:          getEnv("UNISWAP_V2_API_KEY", ""), // RPC节点服务商密钥(可选)
+			Timeout:         getEnvAsDuration("UNISWAP_V2_TIMEOUT", 3*time.Second),
+			AuthScheme:      getEnv("UNISWAP_V2_AUTH_SCHEME", ""),
+			AuthHeader:      getEnv("UNISWAP_V2_AUTH_HEADER", ""),
+			Retry:           loadRetryPolicy("UNISWAP_V2"),
+			RetryCount:      getEnvAsInt("UNISWAP_V2_RETRY_COUNT", 1),
+			Priority:        6,
+			Weight:          decimal.NewFromFloat(0.5),
+			IsActive:        getEnvAsBool("UNISWAP_V2_ENABLED", false),
+			SupportedChains: []uint{1}, // Ethereum(其他链需通过UNISWAP_V2_CONTRACTS配置合约地址)
+			DEX:             loadDEXConfig("UNISWAP_V2"),
+		},
 	}
 
 	return providers
 }
 
+// loadRetryPolicy 加载聚合器重试退避策略
+// 环境变量前缀与聚合器其他配置相同，如ONEINCH_RETRY_INITIAL_BACKOFF
+func loadRetryPolicy(envPrefix string) types.RetryPolicy {
+	return types.RetryPolicy{
+		InitialBackoff: getEnvAsDuration(envPrefix+"_RETRY_INITIAL_BACKOFF", types.DefaultRetryInitialBackoff),
+		MaxBackoff:     getEnvAsDuration(envPrefix+"_RETRY_MAX_BACKOFF", types.DefaultRetryMaxBackoff),
+		Multiplier:     getEnvAsFloat(envPrefix+"_RETRY_MULTIPLIER", types.DefaultRetryMultiplier),
+		Jitter:         getEnvAsFloat(envPrefix+"_RETRY_JITTER", types.DefaultRetryJitter),
+		MaxRetryAfter:  getEnvAsDuration(envPrefix+"_RETRY_AFTER_MAX", types.DefaultRetryAfterMax),
+	}
+}
+
+// loadDEXConfig 加载直连DEX适配器配置
+// 环境变量格式:
+//   - <PREFIX>_RPC_URLS: 各链RPC节点，如"1=https://eth.example,137=https://polygon.example"
+//   - <PREFIX>_FEE_TIERS: 手续费档位，如"500,3000,10000"
+//   - <PREFIX>_INTERMEDIATE_TOKENS: 各链中间代币，如"1=0xC02a...|0xA0b8...,137=0x0d50..."
+//   - <PREFIX>_CONTRACTS: 各链合约地址(工厂|报价合约)，如"1=0x1F98...|0x61fF..."
+func loadDEXConfig(envPrefix string) *types.DEXConfig {
+	dex := &types.DEXConfig{}
+
+	if value := os.Getenv(envPrefix + "_RPC_URLS"); value != "" {
+		urls, err := parseChainValues(value)
+		if err != nil {
+			logrus.Warnf("无法解析环境变量 %s_RPC_URLS(%v)，忽略", envPrefix, err)
+		}
+		dex.RPCURLs = make(map[uint]string, len(urls))
+		for chainID, values := range urls {
+			dex.RPCURLs[chainID] = values[0]
+		}
+	}
+
+	if value := os.Getenv(envPrefix + "_FEE_TIERS"); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			fee, err := strconv.ParseUint(strings.TrimSpace(item), 10, 32)
+			if err != nil {
+				logrus.Warnf("无法解析环境变量 %s_FEE_TIERS 中的手续费档位 %q，忽略", envPrefix, item)
+				continue
+			}
+			dex.FeeTiers = append(dex.FeeTiers, uint32(fee))
+		}
+	}
+
+	if value := os.Getenv(envPrefix + "_INTERMEDIATE_TOKENS"); value != "" {
+		tokens, err := parseChainValues(value)
+		if err != nil {
+			logrus.Warnf("无法解析环境变量 %s_INTERMEDIATE_TOKENS(%v)，忽略", envPrefix, err)
+		}
+		dex.IntermediateTokens = tokens
+	}
+
+	if value := os.Getenv(envPrefix + "_CONTRACTS"); value != "" {
+		contracts, err := parseChainValues(value)
+		if err != nil {
+			logrus.Warnf("无法解析环境变量 %s_CONTRACTS(%v)，忽略", envPrefix, err)
+		}
+		dex.Contracts = make(map[uint]types.DEXContracts, len(contracts))
+		for chainID, addresses := range contracts {
+			if len(addresses) != 2 {
+				logrus.Warnf("环境变量 %s_CONTRACTS 中链%d的合约地址格式应为\"工厂|报价合约\"，忽略", envPrefix, chainID)
+				continue
+			}
+			dex.Contracts[chainID] = types.DEXContracts{Factory: addresses[0], Quoter: addresses[1]}
+		}
+	}
+
+	return dex
+}
+
+// parseChainValues 解析"链ID=值1|值2"逗号分隔列表，解析失败的条目被跳过并返回错误
+func parseChainValues(value string) (map[uint][]string, error) {
+	result := make(map[uint][]string)
+	var invalid []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, "=", 2)
+		chainID, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 64)
+		if len(parts) != 2 || err != nil {
+			invalid = append(invalid, item)
+			continue
+		}
+
+		var values []string
+		for _, v := range strings.Split(parts[1], "|") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			invalid = append(invalid, item)
+			continue
+		}
+		result[uint(chainID)] = values
+	}
+
+	if len(invalid) > 0 {
+		return result, fmt.Errorf("无效的条目: %s", strings.Join(invalid, ", "))
+	}
+	return result, nil
+}
+
 // loadAggregationStrategy 加载聚合策略配置
 // 配置智能路由的决策算法参数
 func loadAggregationStrategy() types.AggregationStrategy {
@@ -153,6 +342,14 @@ func loadAggregationStrategy() types.AggregationStrategy {
 
 		// 决策阈值
 		CompositeScoreThreshold: decimal.NewFromFloat(getEnvAsFloat("STRATEGY_COMPOSITE_THRESHOLD", 0.8)),
+
+		// 报价评分配置(weighted排序策略)
+		ScorePriceWeight:      decimal.NewFromFloat(getEnvAsFloat("STRATEGY_SCORE_PRICE_WEIGHT", 0.5)),
+		ScoreGasWeight:        decimal.NewFromFloat(getEnvAsFloat("STRATEGY_SCORE_GAS_WEIGHT", 0.2)),
+		ScoreConfidenceWeight: decimal.NewFromFloat(getEnvAsFloat("STRATEGY_SCORE_CONFIDENCE_WEIGHT", 0.2)),
+		ScoreTimeWeight:       decimal.NewFromFloat(getEnvAsFloat("STRATEGY_SCORE_TIME_WEIGHT", 0.1)),
+		ResponseTimeBuckets:   getEnvAsResponseTimeBuckets("STRATEGY_SCORE_TIME_BUCKETS", "200ms:1.0,500ms:0.8,1s:0.6,2s:0.4"),
+		SlowResponseScore:     decimal.NewFromFloat(getEnvAsFloat("STRATEGY_SCORE_SLOW_RESPONSE", 0.2)),
 	}
 }
 
@@ -171,13 +368,23 @@ func validateConfig(cfg *types.Config) error {
 	if cfg.Server.LogLevel == "" {
 		return fmt.Errorf("LOG_LEVEL环境变量是必填项")
 	}
-
-	// 验证必填的Redis配置
-	if cfg.Redis.Host == "" {
-		return fmt.Errorf("REDIS_HOST环境变量是必填项")
+	if cfg.Server.MockAdapters && cfg.Server.Environment == "production" {
+		return fmt.Errorf("生产环境不能启用MOCK_ADAPTERS_ENABLED")
 	}
-	if cfg.Redis.Port == 0 {
-		return fmt.Errorf("REDIS_PORT环境变量是必填项")
+
+	// 验证缓存后端配置
+	switch cfg.Cache.Backend {
+	case "redis":
+		// 使用Redis缓存时Redis配置为必填项
+		if cfg.Redis.Host == "" {
+			return fmt.Errorf("REDIS_HOST环境变量是必填项")
+		}
+		if cfg.Redis.Port == 0 {
+			return fmt.Errorf("REDIS_PORT环境变量是必填项")
+		}
+	case "memory":
+	default:
+		return fmt.Errorf("无效的缓存后端: %s (可选: redis, memory)", cfg.Cache.Backend)
 	}
 
 	// 验证第三方API配置
@@ -203,6 +410,9 @@ func validateConfig(cfg *types.Config) error {
 		if provider.IsActive {
 			activeProviders++
 		}
+		if err := validateProviderConfig(&provider); err != nil {
+			return fmt.Errorf("聚合器 %s 配置无效: %w", provider.Name, err)
+		}
 	}
 	if activeProviders == 0 {
 		return fmt.Errorf("至少需要一个活跃的聚合器")
@@ -217,6 +427,81 @@ func validateConfig(cfg *types.Config) error {
 		return fmt.Errorf("最小聚合器数不能大于首选聚合器数")
 	}
 
+	// 验证熔断器配置
+	if cfg.CircuitBreaker.Enabled {
+		if cfg.CircuitBreaker.FailureThreshold < 1 {
+			return fmt.Errorf("熔断失败阈值必须大于0")
+		}
+		if cfg.CircuitBreaker.SuccessThreshold < 1 {
+			return fmt.Errorf("熔断恢复成功阈值必须大于0")
+		}
+		if cfg.CircuitBreaker.HalfOpenMaxCalls < 1 {
+			return fmt.Errorf("半开状态试探请求数必须大于0")
+		}
+	}
+
+	// 验证热加载配置
+	if cfg.ProviderReload.Enabled && cfg.ProviderReload.Interval <= 0 {
+		return fmt.Errorf("聚合器配置热加载间隔必须大于0")
+	}
+
+	// 验证报价排序配置
+	switch cfg.Ranking.DefaultStrategy {
+	case types.RankingStrategyMaxOutput, types.RankingStrategyNetOfGas,
+		types.RankingStrategyWeighted, types.RankingStrategyLowestLatency:
+	default:
+		return fmt.Errorf("无效的报价排序策略: %s (可选: %s, %s, %s, %s)", cfg.Ranking.DefaultStrategy,
+			types.RankingStrategyMaxOutput, types.RankingStrategyNetOfGas, types.RankingStrategyWeighted, types.RankingStrategyLowestLatency)
+	}
+	if cfg.Ranking.GasPriceTimeout <= 0 {
+		return fmt.Errorf("Gas价格获取超时时间必须大于0")
+	}
+
+	// 验证价格冲击配置
+	if cfg.PriceImpact.Enabled {
+		if !cfg.PriceImpact.SpotSizeRatio.IsPositive() || cfg.PriceImpact.SpotSizeRatio.GreaterThan(decimal.NewFromFloat(1.0)) {
+			return fmt.Errorf("小额报价比例必须在0-1之间: %s", cfg.PriceImpact.SpotSizeRatio.String())
+		}
+		if cfg.PriceImpact.SpotTimeout <= 0 {
+			return fmt.Errorf("小额报价超时时间必须大于0")
+		}
+		if cfg.PriceImpact.MaxImpact.IsNegative() || cfg.PriceImpact.MaxImpact.GreaterThan(decimal.NewFromFloat(1.0)) {
+			return fmt.Errorf("价格冲击上限必须在0-1之间: %s", cfg.PriceImpact.MaxImpact.String())
+		}
+		if cfg.PriceImpact.Action != types.PriceImpactActionFlag && cfg.PriceImpact.Action != types.PriceImpactActionReject {
+			return fmt.Errorf("无效的价格冲击处理方式: %s (可选: %s, %s)", cfg.PriceImpact.Action,
+				types.PriceImpactActionFlag, types.PriceImpactActionReject)
+		}
+	}
+
+	// 验证拆单路由配置
+	if cfg.SplitRouting.Enabled {
+		if cfg.SplitRouting.Parts < 2 || cfg.SplitRouting.Parts > 20 {
+			return fmt.Errorf("拆单粒度必须在2-20之间: %d", cfg.SplitRouting.Parts)
+		}
+		if cfg.SplitRouting.MaxLegs < 2 {
+			return fmt.Errorf("拆单最大腿数必须大于1: %d", cfg.SplitRouting.MaxLegs)
+		}
+		if cfg.SplitRouting.Timeout <= 0 {
+			return fmt.Errorf("拆单分量报价超时时间必须大于0")
+		}
+	}
+
+	// 验证报价评分权重和响应时间分段
+	scoreWeight := cfg.Strategy.ScorePriceWeight.Add(cfg.Strategy.ScoreGasWeight).
+		Add(cfg.Strategy.ScoreConfidenceWeight).Add(cfg.Strategy.ScoreTimeWeight)
+	if !scoreWeight.Equal(decimal.NewFromFloat(1.0)) {
+		return fmt.Errorf("报价评分权重总和必须为1.0，当前为: %s", scoreWeight.String())
+	}
+	for i, bucket := range cfg.Strategy.ResponseTimeBuckets {
+		if i > 0 && bucket.MaxResponseTime <= cfg.Strategy.ResponseTimeBuckets[i-1].MaxResponseTime {
+			return fmt.Errorf("响应时间评分分段必须按时间升序排列")
+		}
+		if bucket.Score.IsNegative() || bucket.Score.GreaterThan(decimal.NewFromFloat(1.0)) {
+			return fmt.Errorf("响应时间评分必须在0-1之间: %s", bucket.Score.String())
+		}
+	}
+
 	// 验证权重总和
 	totalWeight := cfg.Strategy.TimeWeight.Add(cfg.Strategy.ConfidenceWeight).
 		Add(cfg.Strategy.ProviderWeight).Add(cfg.Strategy.MarketWeight)
@@ -229,6 +514,44 @@ func validateConfig(cfg *types.Config) error {
 	return nil
 }
 
+// validateProviderConfig 验证聚合器认证方式和重试策略
+func validateProviderConfig(provider *types.ProviderConfig) error {
+	switch provider.AuthScheme {
+	case "", types.AuthSchemeBearer, types.AuthSchemeNone:
+	case types.AuthSchemeHeader:
+		if provider.AuthHeader == "" {
+			return fmt.Errorf("认证方式为%s时必须指定请求头名称", types.AuthSchemeHeader)
+		}
+	default:
+		return fmt.Errorf("不支持的认证方式: %s", provider.AuthScheme)
+	}
+
+	if provider.Retry.Multiplier < 1 {
+		return fmt.Errorf("重试等待增长倍数不能小于1")
+	}
+	if provider.Retry.Jitter < 0 || provider.Retry.Jitter > 1 {
+		return fmt.Errorf("重试抖动比例必须在0到1之间")
+	}
+	if provider.Retry.InitialBackoff <= 0 || provider.Retry.MaxBackoff < provider.Retry.InitialBackoff {
+		return fmt.Errorf("重试等待时间上限不能小于首次等待时间")
+	}
+
+	// 直连DEX适配器通过链RPC节点报价，启用时必须有可用的节点
+	if types.IsDEXProvider(provider.AdapterType()) && provider.IsActive {
+		if provider.BaseURL == "" && (provider.DEX == nil || len(provider.DEX.RPCURLs) == 0) {
+			return fmt.Errorf("直连DEX适配器启用时必须配置RPC节点URL")
+		}
+		if provider.DEX != nil {
+			for _, fee := range provider.DEX.FeeTiers {
+				if fee == 0 || fee >= 1000000 {
+					return fmt.Errorf("无效的手续费档位: %d (单位为百万分之一)", fee)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // ========================================
 // 环境变量辅助函数
 // ========================================
@@ -280,6 +603,47 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+// getEnvAsResponseTimeBuckets 解析响应时间评分分段，格式: "200ms:1.0,500ms:0.8"
+func getEnvAsResponseTimeBuckets(key, defaultValue string) []types.ResponseTimeBucket {
+	if value := os.Getenv(key); value != "" {
+		buckets, err := parseResponseTimeBuckets(value)
+		if err == nil {
+			return buckets
+		}
+		logrus.Warnf("无法解析环境变量 %s 为响应时间评分分段(%v)，使用默认值 %s", key, err, defaultValue)
+	}
+
+	buckets, _ := parseResponseTimeBuckets(defaultValue)
+	return buckets
+}
+
+// parseResponseTimeBuckets 解析"时长:评分"逗号分隔列表
+func parseResponseTimeBuckets(value string) ([]types.ResponseTimeBucket, error) {
+	var buckets []types.ResponseTimeBucket
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("无效的分段: %s", item)
+		}
+		maxResponseTime, err := time.ParseDuration(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("无效的分段时长: %s", parts[0])
+		}
+		score, err := decimal.NewFromString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("无效的分段评分: %s", parts[1])
+		}
+
+		buckets = append(buckets, types.ResponseTimeBucket{MaxResponseTime: maxResponseTime, Score: score})
+	}
+	return buckets, nil
+}
+
 // LoadConfigWithDatabase 加载包含数据库聚合器配置的完整配置
 // 使用优雅的配置管理器：数据库控制启用状态，环境变量提供敏感信息
 func LoadConfigWithDatabase() (*types.Config, error) {
@@ -289,18 +653,8 @@ func LoadConfigWithDatabase() (*types.Config, error) {
 		return nil, fmt.Errorf("加载基础配置失败: %w", err)
 	}
 
-	// 构建数据库连接URL（复用业务逻辑服务的配置方式）
-	dbURL := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s?sslmode=%s",
-		getEnv("DB_USER", "admin"),
-		getEnv("DB_PASSWORD", "password"),
-		getEnv("DB_HOST", "localhost"),
-		getEnvAsInt("DB_PORT", 5432),
-		getEnv("DB_NAME", "defi_aggregator"),
-		getEnv("DB_SSL_MODE", "disable"),
-	)
-
 	// 创建优雅的聚合器配置管理器
-	configManager, err := NewAggregatorConfigManager(dbURL, logrus.New())
+	configManager, err := NewAggregatorConfigManager(DatabaseURL(), logrus.New())
 	if err != nil {
 		logrus.Warnf("创建聚合器配置管理器失败: %v，使用环境变量配置", err)
 		return config, nil // 使用环境变量配置作为后备
@@ -320,3 +674,15 @@ func LoadConfigWithDatabase() (*types.Config, error) {
 
 	return config, nil
 }
+
+// DatabaseURL 构建数据库连接URL（复用业务逻辑服务的配置方式）
+func DatabaseURL() string {
+	return fmt.Sprintf("postgresql://%s:%s@%s:%d/%s?sslmode=%s",
+		getEnv("DB_USER", "admin"),
+		getEnv("DB_PASSWORD", "password"),
+		getEnv("DB_HOST", "localhost"),
+		getEnvAsInt("DB_PORT", 5432),
+		getEnv("DB_NAME", "defi_aggregator"),
+		getEnv("DB_SSL_MODE", "disable"),
+	)
+}
@@ -0,0 +1,301 @@
+// Package config 行情数据管理器
+// 为net_of_gas报价排序提供链Gas价格和代币USD价格：
+// Gas价格优先通过chains表rpc_url调用eth_gasPrice获取，失败时回退到chains表gas_price_gwei；
+// 代币小数位数和USD价格取自tokens表，所有数据按TTL缓存在进程内
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"defi-aggregator/smart-router/internal/types"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// ErrMarketDataNotFound 数据库中没有对应的链或代币记录
+var ErrMarketDataNotFound = errors.New("行情数据不存在")
+
+// gweiInWei 1 Gwei对应的wei数量
+var gweiInWei = decimal.New(1, 9)
+
+// DatabaseToken 数据库代币模型
+type DatabaseToken struct {
+	ID              uint                `gorm:"primaryKey"`
+	ChainID         uint                `gorm:"column:chain_id"` // chains表内部ID
+	ContractAddress string              `gorm:"column:contract_address"`
+	Symbol          string              `gorm:"column:symbol"`
+	Decimals        int32               `gorm:"column:decimals"`
+	IsNative        bool                `gorm:"column:is_native"`
+	IsActive        bool                `gorm:"column:is_active"`
+	PriceUSD        decimal.NullDecimal `gorm:"column:price_usd"`
+}
+
+func (DatabaseToken) TableName() string { return "tokens" }
+
+// marketCacheEntry 行情数据缓存项
+// value为nil表示数据库中不存在该记录(负缓存)
+type marketCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// MarketDataManager 行情数据管理器
+type MarketDataManager struct {
+	db     *gorm.DB
+	config *types.RankingConfig
+	client *http.Client
+	logger *logrus.Logger
+
+	cache map[string]*marketCacheEntry // 缓存键 -> 缓存项
+	mutex sync.RWMutex                 // 保护cache
+}
+
+// NewMarketDataManager 创建行情数据管理器
+func NewMarketDataManager(dbURL string, config *types.RankingConfig, logger *logrus.Logger) (*MarketDataManager, error) {
+	db, err := gorm.Open(postgres.Open(dbURL), &gorm.Config{
+		Logger: nil, // 使用默认日志
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	return &MarketDataManager{
+		db:     db,
+		config: config,
+		client: &http.Client{Timeout: config.GasPriceTimeout},
+		logger: logger,
+		cache:  make(map[string]*marketCacheEntry),
+	}, nil
+}
+
+// ========================================
+// 行情数据查询
+// ========================================
+
+// GetGasPrice 获取链当前Gas价格
+// RPC节点不可用时回退到chains表的建议Gas价格
+func (mgr *MarketDataManager) GetGasPrice(ctx context.Context, chainID uint) (*types.ChainGasPrice, error) {
+	cacheKey := fmt.Sprintf("gas:%d", chainID)
+	if value, ok := mgr.getCached(cacheKey); ok {
+		return value.(*types.ChainGasPrice), nil
+	}
+
+	chain, err := mgr.getChain(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice := &types.ChainGasPrice{ChainID: chainID}
+	if wei, err := mgr.fetchGasPrice(ctx, chain.RPCURL); err == nil {
+		gasPrice.Wei = wei
+		gasPrice.Source = types.GasPriceSourceRPC
+	} else {
+		if chain.GasPriceGwei <= 0 {
+			return nil, fmt.Errorf("获取链 %d Gas价格失败且未配置建议Gas价格: %w", chainID, err)
+		}
+		mgr.logger.Warnf("⚠️ 链 %d 获取实时Gas价格失败，使用建议Gas价格 %d Gwei: %v", chainID, chain.GasPriceGwei, err)
+		gasPrice.Wei = decimal.NewFromInt(int64(chain.GasPriceGwei)).Mul(gweiInWei)
+		gasPrice.Source = types.GasPriceSourceChainDefault
+	}
+
+	mgr.setCached(cacheKey, gasPrice, mgr.config.GasPriceCacheTTL)
+	return gasPrice, nil
+}
+
+// GetNativeToken 获取链原生代币行情
+func (mgr *MarketDataManager) GetNativeToken(ctx context.Context, chainID uint) (*types.TokenMarketData, error) {
+	cacheKey := fmt.Sprintf("native:%d", chainID)
+	return mgr.getToken(ctx, cacheKey, chainID, func(db *gorm.DB) *gorm.DB {
+		return db.Where("is_native = ?", true)
+	})
+}
+
+// GetToken 获取代币行情，地址不区分大小写
+// 原生代币的各种表示(types.NativeTokenAddress、零地址等)按该链is_native记录查询，与tokens表登记的地址无关
+func (mgr *MarketDataManager) GetToken(ctx context.Context, chainID uint, address string) (*types.TokenMarketData, error) {
+	if types.IsNativeToken(chainID, address) {
+		return mgr.GetNativeToken(ctx, chainID)
+	}
+
+	address = strings.ToLower(address)
+	cacheKey := fmt.Sprintf("token:%d:%s", chainID, address)
+	return mgr.getToken(ctx, cacheKey, chainID, func(db *gorm.DB) *gorm.DB {
+		return db.Where("LOWER(contract_address) = ?", address)
+	})
+}
+
+// Close 关闭数据库连接
+func (mgr *MarketDataManager) Close() error {
+	if sqlDB, err := mgr.db.DB(); err == nil {
+		return sqlDB.Close()
+	}
+	return nil
+}
+
+// ========================================
+// 数据库查询
+// ========================================
+
+// getChain 根据外部ChainID获取链信息
+func (mgr *MarketDataManager) getChain(ctx context.Context, chainID uint) (*DatabaseChain, error) {
+	cacheKey := fmt.Sprintf("chain:%d", chainID)
+	if value, ok := mgr.getCached(cacheKey); ok {
+		if value == nil {
+			return nil, ErrMarketDataNotFound
+		}
+		return value.(*DatabaseChain), nil
+	}
+
+	var chain DatabaseChain
+	err := mgr.db.WithContext(ctx).Where("chain_id = ? AND is_active = ?", chainID, true).First(&chain).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		mgr.setCached(cacheKey, nil, mgr.config.PriceCacheTTL)
+		return nil, ErrMarketDataNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询链信息失败: %w", err)
+	}
+
+	mgr.setCached(cacheKey, &chain, mgr.config.PriceCacheTTL)
+	return &chain, nil
+}
+
+// getToken 按条件查询链上的启用代币
+func (mgr *MarketDataManager) getToken(ctx context.Context, cacheKey string, chainID uint, scope func(*gorm.DB) *gorm.DB) (*types.TokenMarketData, error) {
+	if value, ok := mgr.getCached(cacheKey); ok {
+		if value == nil {
+			return nil, ErrMarketDataNotFound
+		}
+		return value.(*types.TokenMarketData), nil
+	}
+
+	chain, err := mgr.getChain(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var token DatabaseToken
+	query := mgr.db.WithContext(ctx).Where("chain_id = ? AND is_active = ?", chain.ID, true)
+	err = scope(query).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		mgr.setCached(cacheKey, nil, mgr.config.PriceCacheTTL)
+		return nil, ErrMarketDataNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询代币信息失败: %w", err)
+	}
+
+	data := &types.TokenMarketData{
+		ChainID:  chainID,
+		Address:  token.ContractAddress,
+		Symbol:   token.Symbol,
+		Decimals: token.Decimals,
+		IsNative: token.IsNative,
+	}
+	if token.PriceUSD.Valid {
+		data.PriceUSD = token.PriceUSD.Decimal
+	}
+
+	mgr.setCached(cacheKey, data, mgr.config.PriceCacheTTL)
+	return data, nil
+}
+
+// ========================================
+// RPC调用
+// ========================================
+
+// fetchGasPrice 调用eth_gasPrice获取实时Gas价格(wei)
+func (mgr *MarketDataManager) fetchGasPrice(ctx context.Context, rpcURL string) (decimal.Decimal, error) {
+	if rpcURL == "" {
+		return decimal.Zero, fmt.Errorf("未配置RPC节点")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_gasPrice",
+		"params":  []interface{}{},
+	})
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("序列化RPC请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("创建RPC请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := mgr.client.Do(req)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("请求RPC节点失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return decimal.Zero, fmt.Errorf("解析RPC响应失败: status=%d, error=%w", resp.StatusCode, err)
+	}
+	if result.Error != nil {
+		return decimal.Zero, fmt.Errorf("RPC错误: code=%d, message=%s", result.Error.Code, result.Error.Message)
+	}
+
+	wei, ok := new(big.Int).SetString(strings.TrimPrefix(result.Result, "0x"), 16)
+	if !ok || wei.Sign() <= 0 {
+		return decimal.Zero, fmt.Errorf("无效的Gas价格: %q", result.Result)
+	}
+	return decimal.NewFromBigInt(wei, 0), nil
+}
+
+// ========================================
+// 缓存
+// ========================================
+
+// getCached 读取未过期的缓存项
+func (mgr *MarketDataManager) getCached(cacheKey string) (interface{}, bool) {
+	mgr.mutex.RLock()
+	defer mgr.mutex.RUnlock()
+
+	entry, ok := mgr.cache[cacheKey]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// setCached 写入缓存项，ttl<=0时不缓存
+// 顺带清理已过期的缓存项，缓存键数量受链和代币数量限制
+func (mgr *MarketDataManager) setCached(cacheKey string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	mgr.mutex.Lock()
+	defer mgr.mutex.Unlock()
+
+	now := time.Now()
+	for key, entry := range mgr.cache {
+		if now.After(entry.expiresAt) {
+			delete(mgr.cache, key)
+		}
+	}
+	mgr.cache[cacheKey] = &marketCacheEntry{value: value, expiresAt: now.Add(ttl)}
+}
@@ -0,0 +1,35 @@
+-- Migration: 002_api_keys.sql
+-- Description: 创建API Key表，支持程序化接入方通过X-API-Key访问报价、交易和统计接口
+-- Version: 1.1.0
+
+BEGIN;
+
+-- ========================================
+-- API Key表
+-- ========================================
+
+-- 用户API Key (只保存SHA-256哈希，明文仅在创建/轮换时返回一次)
+CREATE TABLE api_keys (
+    id              SERIAL PRIMARY KEY,
+    user_id         INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name            VARCHAR(100) NOT NULL,                  -- 名称 (便于用户区分用途)
+    key_prefix      VARCHAR(16) NOT NULL,                   -- 明文前缀 (用于展示和识别)
+    key_hash        VARCHAR(64) UNIQUE NOT NULL,            -- SHA-256哈希 (十六进制)
+    scopes          VARCHAR(100) NOT NULL DEFAULT 'quote',  -- 权限范围: quote,swap,stats (逗号分隔)
+    tier            VARCHAR(20) NOT NULL DEFAULT 'free',    -- 配额等级: free/standard/premium
+    is_active       BOOLEAN DEFAULT true,                   -- 是否有效 (撤销后为false)
+    last_used_at    TIMESTAMP,                              -- 最后使用时间
+    expires_at      TIMESTAMP,                              -- 过期时间 (为空表示不过期)
+    revoked_at      TIMESTAMP,                              -- 撤销时间
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+CREATE TRIGGER update_api_keys_updated_at BEFORE UPDATE ON api_keys
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
+
+SELECT 'Migration 002_api_keys.sql completed successfully' as status;
@@ -0,0 +1,26 @@
+-- Migration: 003_aggregator_adapters.sql
+-- Description: 聚合器适配器类型和声明式适配器配置，新聚合器可通过配置接入而无需修改路由服务代码
+-- Version: 1.2.0
+
+BEGIN;
+
+-- ========================================
+-- 聚合器适配器配置
+-- ========================================
+
+-- adapter_type: 适配器注册表中的类型名称，为空时使用name (1inch, uniswap_v3, http_json等)
+-- adapter_config: 适配器配置 (http_json适配器的请求模板和JSONPath)
+ALTER TABLE aggregators
+    ADD COLUMN adapter_type   VARCHAR(50),
+    ADD COLUMN adapter_config JSONB;
+
+-- 示例：通过http_json适配器接入简单聚合器 (需同时配置aggregator_chains)
+-- INSERT INTO aggregators (name, display_name, api_url, is_active, priority, timeout_ms, retry_count, adapter_type, adapter_config) VALUES
+-- ('example_dex', 'Example DEX', 'https://api.example.com', true, 7, 3000, 1, 'http_json',
+--  '{"url_template": "{base_url}/v1/{chain_id}/quote",
+--    "params": {"tokenIn": "{from_token}", "tokenOut": "{to_token}", "amountIn": "{amount_in}", "slippageBps": "{slippage_bps}"},
+--    "amount_out_path": "$.data.amountOut", "gas_path": "$.data.gas", "error_path": "$.error.message", "default_gas": 200000}');
+
+COMMIT;
+
+SELECT 'Migration 003_aggregator_adapters.sql completed successfully' as status;
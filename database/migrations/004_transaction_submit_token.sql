@@ -0,0 +1,17 @@
+-- Migration: 004_transaction_submit_token.sql
+-- Description: 交易提交凭证，匿名交易提交交易哈希时需出示创建交易时返回的凭证，防止他人抢先关联交易哈希
+-- Version: 1.3.0
+
+BEGIN;
+
+-- ========================================
+-- 交易提交凭证
+-- ========================================
+
+-- submit_token_hash: 创建交易时生成的提交凭证SHA-256哈希，凭证明文只在创建交易的响应中返回一次
+ALTER TABLE transactions
+    ADD COLUMN submit_token_hash VARCHAR(64);
+
+COMMIT;
+
+SELECT 'Migration 004_transaction_submit_token.sql completed successfully' as status;
@@ -0,0 +1,21 @@
+-- Migration: 005_quote_response_gas_cost.sql
+-- Description: 聚合器报价响应记录智能路由计算的Gas费用和净输出，报价对比直接使用报价时的实时Gas价格
+-- Version: 1.4.0
+
+BEGIN;
+
+-- ========================================
+-- 报价响应Gas费用
+-- ========================================
+
+-- gas_price: 智能路由折算Gas费用使用的Gas价格(wei)，保留L2低于1 Gwei的精度
+-- gas_cost: Gas费用折算的输出代币数量(最小单位)
+-- net_amount_out: 扣除Gas费用后的净输出数量(最小单位)
+ALTER TABLE quote_responses
+    ADD COLUMN gas_price DECIMAL(30,0),
+    ADD COLUMN gas_cost DECIMAL(78,0),
+    ADD COLUMN net_amount_out DECIMAL(78,0);
+
+COMMIT;
+
+SELECT 'Migration 005_quote_response_gas_cost.sql completed successfully' as status;
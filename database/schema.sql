@@ -39,6 +39,23 @@ CREATE TABLE user_preferences (
     UNIQUE(user_id)
 );
 
+-- 用户API Key (只保存SHA-256哈希，明文仅在创建/轮换时返回一次)
+CREATE TABLE api_keys (
+    id              SERIAL PRIMARY KEY,
+    user_id         INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name            VARCHAR(100) NOT NULL,                  -- 名称 (便于用户区分用途)
+    key_prefix      VARCHAR(16) NOT NULL,                   -- 明文前缀 (用于展示和识别)
+    key_hash        VARCHAR(64) UNIQUE NOT NULL,            -- SHA-256哈希 (十六进制)
+    scopes          VARCHAR(100) NOT NULL DEFAULT 'quote',  -- 权限范围: quote,swap,stats (逗号分隔)
+    tier            VARCHAR(20) NOT NULL DEFAULT 'free',    -- 配额等级: free/standard/premium
+    is_active       BOOLEAN DEFAULT true,                   -- 是否有效 (撤销后为false)
+    last_used_at    TIMESTAMP,                              -- 最后使用时间
+    expires_at      TIMESTAMP,                              -- 过期时间 (为空表示不过期)
+    revoked_at      TIMESTAMP,                              -- 撤销时间
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- ========================================
 -- 2. 代币和链信息管理
 -- ========================================
@@ -104,6 +121,8 @@ CREATE TABLE aggregators (
     success_rate    DECIMAL(5,4) DEFAULT 1.0000,          -- 成功率统计
     avg_response_ms INTEGER DEFAULT 1000,                  -- 平均响应时间
     last_health_check TIMESTAMP,                          -- 最后健康检查时间
+    adapter_type    VARCHAR(50),                           -- 适配器类型 (为空时使用name，如http_json)
+    adapter_config  JSONB,                                 -- 适配器配置 (http_json适配器的请求模板和JSONPath)
     created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -157,6 +176,9 @@ CREATE TABLE quote_responses (
     success             BOOLEAN NOT NULL,                  -- 是否成功
     amount_out          DECIMAL(78,0),                     -- 输出数量
     gas_estimate        BIGINT,                            -- Gas估算
+    gas_price           DECIMAL(30,0),                     -- 折算Gas费用使用的Gas价格(wei)
+    gas_cost            DECIMAL(78,0),                     -- Gas费用折算的输出代币数量
+    net_amount_out      DECIMAL(78,0),                     -- 扣除Gas费用后的净输出数量
     price_impact        DECIMAL(8,6),                      -- 价格冲击
     confidence_score    DECIMAL(3,2),                      -- 置信度分数
     price_rank          INTEGER,                           -- 价格排名
@@ -213,6 +235,7 @@ CREATE TABLE transactions (
     -- 元数据
     route_data          JSONB,                             -- 交易路径详情
     error_reason        TEXT,                              -- 失败原因
+    submit_token_hash   VARCHAR(64),                       -- 提交凭证SHA-256哈希 (匿名交易提交交易哈希时校验)
     created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     confirmed_at        TIMESTAMP                          -- 确认时间
@@ -298,6 +321,7 @@ CREATE TABLE quote_cache (
 CREATE INDEX idx_users_wallet_address ON users(wallet_address);
 CREATE INDEX idx_users_created_at ON users(created_at DESC);
 CREATE INDEX idx_users_last_login ON users(last_login_at DESC);
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
 
 -- 代币相关索引
 CREATE INDEX idx_tokens_chain_address ON tokens(chain_id, contract_address);
@@ -428,6 +452,9 @@ CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
 CREATE TRIGGER update_user_preferences_updated_at BEFORE UPDATE ON user_preferences
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+CREATE TRIGGER update_api_keys_updated_at BEFORE UPDATE ON api_keys
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
 CREATE TRIGGER update_chains_updated_at BEFORE UPDATE ON chains
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
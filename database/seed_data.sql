@@ -24,7 +24,10 @@ INSERT INTO aggregators (name, display_name, api_url, logo_url, is_active, prior
 ('1inch', '1inch', 'https://api.1inch.io/v5.0', 'https://app.1inch.io/assets/images/1inch_logo.svg', true, 1, 3000, 3),
 ('paraswap', 'ParaSwap', 'https://apiv5.paraswap.io', 'https://paraswap.io/paraswap.svg', true, 2, 4000, 3),
 ('0x', '0x Protocol', 'https://api.0x.org', 'https://0x.org/images/favicon.png', true, 3, 5000, 2),
-('cowswap', 'CoW Protocol', 'https://api.cow.fi/mainnet/api/v1', 'https://cow.fi/favicon.ico', true, 4, 6000, 2);
+('cowswap', 'CoW Protocol', 'https://api.cow.fi/mainnet/api/v1', 'https://cow.fi/favicon.ico', true, 4, 6000, 2),
+-- 直连DEX：api_url为空时使用chains表的rpc_url，默认不启用
+('uniswap_v3', 'Uniswap V3', '', 'https://app.uniswap.org/favicon.png', false, 5, 3000, 1),
+('uniswap_v2', 'Uniswap V2', '', 'https://app.uniswap.org/favicon.png', false, 6, 3000, 1);
 
 -- ========================================
 -- 3. 聚合器支持的链配置
@@ -52,6 +55,17 @@ INSERT INTO aggregator_chains (aggregator_id, chain_id, is_active, gas_multiplie
 INSERT INTO aggregator_chains (aggregator_id, chain_id, is_active, gas_multiplier) VALUES
 ((SELECT id FROM aggregators WHERE name = 'cowswap'), (SELECT id FROM chains WHERE chain_id = 1), true, 1.0);
 
+-- Uniswap V3 支持的链
+INSERT INTO aggregator_chains (aggregator_id, chain_id, is_active, gas_multiplier) VALUES
+((SELECT id FROM aggregators WHERE name = 'uniswap_v3'), (SELECT id FROM chains WHERE chain_id = 1), true, 1.0),
+((SELECT id FROM aggregators WHERE name = 'uniswap_v3'), (SELECT id FROM chains WHERE chain_id = 137), true, 1.0),
+((SELECT id FROM aggregators WHERE name = 'uniswap_v3'), (SELECT id FROM chains WHERE chain_id = 42161), true, 1.0),
+((SELECT id FROM aggregators WHERE name = 'uniswap_v3'), (SELECT id FROM chains WHERE chain_id = 10), true, 1.0);
+
+-- Uniswap V2 支持的链 (官方部署只在以太坊)
+INSERT INTO aggregator_chains (aggregator_id, chain_id, is_active, gas_multiplier) VALUES
+((SELECT id FROM aggregators WHERE name = 'uniswap_v2'), (SELECT id FROM chains WHERE chain_id = 1), true, 1.0);
+
 -- ========================================
 -- 4. 主要代币信息 (以太坊主网)
 -- ========================================
@@ -132,10 +132,13 @@ export interface QuoteResponse {
   to_token: Token;
   amount_in: string;
   amount_out: string;
+  amount_in_formatted?: string;
+  amount_out_formatted?: string;
   best_aggregator: string;
   gas_estimate: number;
   price_impact: string;
   exchange_rate: string;
+  inverse_exchange_rate: string;
   route?: RouteStep[];
   valid_until: string;
   total_duration_ms: number;